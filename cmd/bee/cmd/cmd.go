@@ -35,6 +35,10 @@ const (
 	optionNameP2PWSEnable                = "p2p-ws-enable"
 	optionNameDebugAPIEnable             = "debug-api-enable"
 	optionNameDebugAPIAddr               = "debug-api-addr"
+	optionNameS3Addr                     = "s3-addr"
+	optionNameS3PostageBatchID           = "s3-postage-batch-id"
+	optionNameDAVAddr                    = "webdav-addr"
+	optionNameDAVPostageBatchID          = "webdav-postage-batch-id"
 	optionNameBootnodes                  = "bootnode"
 	optionNameNetworkID                  = "network-id"
 	optionWelcomeMessage                 = "welcome-message"
@@ -45,15 +49,24 @@ const (
 	optionNameTracingPort                = "tracing-port"
 	optionNameTracingServiceName         = "tracing-service-name"
 	optionNameVerbosity                  = "verbosity"
+	optionNameLogFormat                  = "log-format"
+	optionNameLogLevels                  = "log-levels"
+	optionNameLogSampling                = "log-sampling"
 	optionNamePaymentThreshold           = "payment-threshold"
 	optionNamePaymentTolerance           = "payment-tolerance-percent"
 	optionNamePaymentEarly               = "payment-early-percent"
 	optionNameResolverEndpoints          = "resolver-options"
+	optionNameResolverCacheTTL           = "resolver-cache-ttl"
+	optionNameResolverNegativeCacheTTL   = "resolver-negative-cache-ttl"
+	optionNamePssMiningWorkers           = "pss-mining-workers"
 	optionNameBootnodeMode               = "bootnode-mode"
 	optionNameGatewayMode                = "gateway-mode"
 	optionNameClefSignerEnable           = "clef-signer-enable"
 	optionNameClefSignerEndpoint         = "clef-signer-endpoint"
 	optionNameClefSignerEthereumAddress  = "clef-signer-ethereum-address"
+	optionNameRemoteSignerEndpoint       = "remote-signer-endpoint"
+	optionNameRemoteSignerTimeout        = "remote-signer-timeout"
+	optionNameRemoteSignerAllow          = "remote-signer-allow"
 	optionNameSwapEndpoint               = "swap-endpoint"
 	optionNameSwapFactoryAddress         = "swap-factory-address"
 	optionNameSwapLegacyFactoryAddresses = "swap-legacy-factory-addresses"
@@ -63,6 +76,7 @@ const (
 	optionNameChainEnable                = "chain-enable"
 	optionNameTransactionHash            = "transaction"
 	optionNameBlockHash                  = "block-hash"
+	optionNameTargetNeighbourhood        = "target-neighbourhood"
 	optionNameSwapDeploymentGasPrice     = "swap-deployment-gas-price"
 	optionNameFullNode                   = "full-node"
 	optionNamePostageContractAddress     = "postage-stamp-address"
@@ -147,6 +161,7 @@ func newCommand(opts ...option) (c *command, err error) {
 
 	c.initVersionCmd()
 	c.initDBCmd()
+	c.initKeysCmd()
 
 	if err := c.initConfigurateOptionsCmd(); err != nil {
 		return nil, err
@@ -233,24 +248,37 @@ func (c *command) setAllFlags(cmd *cobra.Command) {
 	cmd.Flags().StringSlice(optionNameBootnodes, []string{""}, "initial nodes to connect to")
 	cmd.Flags().Bool(optionNameDebugAPIEnable, false, "enable debug HTTP API")
 	cmd.Flags().String(optionNameDebugAPIAddr, ":1635", "debug HTTP API listen address")
+	cmd.Flags().String(optionNameS3Addr, "", "S3 gateway listen address, disabled if empty")
+	cmd.Flags().String(optionNameS3PostageBatchID, "", "ID of the postage batch which stamps the data of the S3 gateway")
+	cmd.Flags().String(optionNameDAVAddr, "", "WebDAV gateway listen address, disabled if empty")
+	cmd.Flags().String(optionNameDAVPostageBatchID, "", "ID of the postage batch which stamps the data of the WebDAV gateway, read-only if empty")
 	cmd.Flags().Uint64(optionNameNetworkID, 10, "ID of the Swarm network")
 	cmd.Flags().StringSlice(optionCORSAllowedOrigins, []string{}, "origins with CORS headers enabled")
 	cmd.Flags().Bool(optionNameTracingEnabled, false, "enable tracing")
-	cmd.Flags().String(optionNameTracingEndpoint, "127.0.0.1:6831", "endpoint to send tracing data")
+	cmd.Flags().String(optionNameTracingEndpoint, "127.0.0.1:4318", "endpoint of the OTLP HTTP receiver to send tracing data")
 	cmd.Flags().String(optionNameTracingHost, "", "host to send tracing data")
 	cmd.Flags().String(optionNameTracingPort, "", "port to send tracing data")
 	cmd.Flags().String(optionNameTracingServiceName, "bee", "service name identifier for tracing")
 	cmd.Flags().String(optionNameVerbosity, "info", "log verbosity level 0=silent, 1=error, 2=warn, 3=info, 4=debug, 5=trace")
+	cmd.Flags().String(optionNameLogFormat, "text", "log format, text or json")
+	cmd.Flags().StringSlice(optionNameLogLevels, []string{}, "log levels of components overriding the verbosity, format component=level")
+	cmd.Flags().Int(optionNameLogSampling, 0, "number of debug and trace messages of a component logged every second before only every hundredth is logged, 0 to log all")
 	cmd.Flags().String(optionWelcomeMessage, "", "send a welcome message string during handshakes")
 	cmd.Flags().String(optionNamePaymentThreshold, "100000000", "threshold in BZZ where you expect to get paid from your peers")
 	cmd.Flags().Int64(optionNamePaymentTolerance, 25, "excess debt above payment threshold in percentages where you disconnect from your peer")
 	cmd.Flags().Int64(optionNamePaymentEarly, 50, "percentage below the peers payment threshold when we initiate settlement")
-	cmd.Flags().StringSlice(optionNameResolverEndpoints, []string{}, "ENS compatible API endpoint for a TLD and with contract address, can be repeated, format [tld:][contract-addr@]url")
+	cmd.Flags().StringSlice(optionNameResolverEndpoints, []string{}, "name resolver for a TLD, can be repeated, format [tld:][contract-addr@]url[#ttl=duration], where url is an ENS compatible API endpoint, dnslink://[dns-server] for DNSLink TXT records, file://path for a static name to reference mapping or feed://path for a name to feed owner and topic mapping")
+	cmd.Flags().Duration(optionNameResolverCacheTTL, 5*time.Minute, "time to cache resolved names for resolvers without a ttl option")
+	cmd.Flags().Duration(optionNameResolverNegativeCacheTTL, time.Minute, "time to cache names that failed to resolve")
+	cmd.Flags().Int(optionNamePssMiningWorkers, 0, "number of workers mining pss messages, all cores if 0")
 	cmd.Flags().Bool(optionNameGatewayMode, false, "disable a set of sensitive features in the api")
 	cmd.Flags().Bool(optionNameBootnodeMode, false, "cause the node to always accept incoming connections")
 	cmd.Flags().Bool(optionNameClefSignerEnable, false, "enable clef signer")
 	cmd.Flags().String(optionNameClefSignerEndpoint, "", "clef signer endpoint")
 	cmd.Flags().String(optionNameClefSignerEthereumAddress, "", "ethereum address to use from clef signer")
+	cmd.Flags().String(optionNameRemoteSignerEndpoint, "", "JSON-RPC endpoint of a remote signing service, enables the remote signer")
+	cmd.Flags().Duration(optionNameRemoteSignerTimeout, 30*time.Second, "timeout of a remote signer request")
+	cmd.Flags().StringSlice(optionNameRemoteSignerAllow, []string{}, "what the remote signer may sign, can be repeated, one of data, tx, tx:<address>, typed-data or typed-data:<domain name>; everything if empty")
 	cmd.Flags().String(optionNameSwapEndpoint, "ws://localhost:8546", "swap ethereum blockchain endpoint")
 	cmd.Flags().String(optionNameSwapFactoryAddress, "", "swap factory addresses")
 	cmd.Flags().StringSlice(optionNameSwapLegacyFactoryAddresses, nil, "legacy swap factory addresses")
@@ -279,23 +307,66 @@ func (c *command) setAllFlags(cmd *cobra.Command) {
 	cmd.Flags().Bool(optionNameUsePostageSnapshot, false, "bootstrap node using postage snapshot from the network")
 }
 
-func newLogger(cmd *cobra.Command, verbosity string) (logging.Logger, error) {
-	var logger logging.Logger
-	switch verbosity {
-	case "0", "silent":
-		logger = logging.New(io.Discard, 0)
-	case "1", "error":
-		logger = logging.New(cmd.OutOrStdout(), logrus.ErrorLevel)
-	case "2", "warn":
-		logger = logging.New(cmd.OutOrStdout(), logrus.WarnLevel)
-	case "3", "info":
-		logger = logging.New(cmd.OutOrStdout(), logrus.InfoLevel)
-	case "4", "debug":
-		logger = logging.New(cmd.OutOrStdout(), logrus.DebugLevel)
-	case "5", "trace":
-		logger = logging.New(cmd.OutOrStdout(), logrus.TraceLevel)
+func newLogger(cmd *cobra.Command, verbosity string, opts ...logging.Option) (logging.Logger, error) {
+	level, silent, err := parseVerbosity(verbosity)
+	if err != nil {
+		return nil, err
+	}
+	if silent {
+		return logging.New(io.Discard, 0, opts...), nil
+	}
+	return logging.New(cmd.OutOrStdout(), level, opts...), nil
+}
+
+// logOptions returns the options of the node logger set by the log flags.
+func (c *command) logOptions() ([]logging.Option, error) {
+	var opts []logging.Option
+	switch f := strings.ToLower(c.config.GetString(optionNameLogFormat)); f {
+	case "", "text":
+	case "json":
+		opts = append(opts, logging.WithJSONFormat())
 	default:
-		return nil, fmt.Errorf("unknown verbosity level %q", verbosity)
+		return nil, fmt.Errorf("unknown log format %q", f)
+	}
+	if n := c.config.GetInt(optionNameLogSampling); n > 0 {
+		opts = append(opts, logging.WithSampling(n, 100))
 	}
-	return logger, nil
+	return opts, nil
+}
+
+// networkID returns the ID of the Swarm network of the node, which is the
+// main net unless it is disabled.
+func (c *command) networkID() (uint64, error) {
+	networkID := c.config.GetUint64(optionNameNetworkID)
+	if c.config.GetBool(optionNameMainNet) {
+		if c.config.IsSet(optionNameNetworkID) && networkID != 1 {
+			return 0, errors.New("provided network ID does not match mainnet")
+		}
+		networkID = 1
+	}
+	return networkID, nil
+}
+
+// setLogLevels sets the levels of the component loggers from the values
+// in the component=level format.
+func setLogLevels(logger logging.Logger, levels []string) error {
+	for _, v := range levels {
+		name, l := v, ""
+		if i := strings.LastIndex(v, "="); i >= 0 {
+			name, l = v[:i], v[i+1:]
+		}
+		level, err := logrus.ParseLevel(l)
+		if err != nil {
+			return fmt.Errorf("log level %q: %w", v, err)
+		}
+		// create the logger so that the level is kept when the component
+		// gets it
+		if name != logging.RootName {
+			logger.Named(name)
+		}
+		if err := logger.SetNamedLevel(name, level); err != nil {
+			return fmt.Errorf("log level %q: %w", v, err)
+		}
+	}
+	return nil
 }
@@ -4,7 +4,14 @@
 
 package cmd
 
-import "io"
+import (
+	"io"
+
+	"github.com/ethersphere/bee/pkg/debugapi"
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
 
 type (
 	Command        = command
@@ -63,3 +70,18 @@ func WithPasswordReader(r PasswordReader) func(c *Command) {
 		c.passwordReader = r
 	}
 }
+
+type ReconfigurableNode = reconfigurableNode
+
+type ConfigReloader interface {
+	debugapi.ConfigInspector
+	debugapi.ConfigReloader
+}
+
+// NewConfigReloader returns a config reloader which applies the changed
+// options to the node.
+func NewConfigReloader(config *viper.Viper, flags *pflag.FlagSet, logger logging.Logger, n ReconfigurableNode) ConfigReloader {
+	r := newConfigReloader(config, flags, logger)
+	r.setNode(n)
+	return r
+}
@@ -5,10 +5,16 @@
 package cmd
 
 import (
+	"crypto/ecdsa"
+	"errors"
 	"fmt"
+	"os"
 	"strings"
 
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/logging"
 	"github.com/ethersphere/bee/pkg/node"
+	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/spf13/cobra"
 )
 
@@ -26,7 +32,7 @@ func (c *command) initInitCmd() (err error) {
 			if err != nil {
 				return fmt.Errorf("new logger: %w", err)
 			}
-			_, err = c.configureSigner(cmd, logger)
+			signerConfig, err := c.configureSigner(cmd, logger)
 			if err != nil {
 				return err
 			}
@@ -39,6 +45,10 @@ func (c *command) initInitCmd() (err error) {
 
 			defer stateStore.Close()
 
+			if target := c.config.GetString(optionNameTargetNeighbourhood); target != "" {
+				return c.mineOverlay(cmd, logger, signerConfig.publicKey, stateStore, target)
+			}
+
 			return nil
 		},
 		PreRunE: func(cmd *cobra.Command, args []string) error {
@@ -47,6 +57,60 @@ func (c *command) initInitCmd() (err error) {
 	}
 
 	c.setAllFlags(cmd)
+	cmd.Flags().String(optionNameTargetNeighbourhood, "", "mine an overlay address within the neighbourhood given as a string of bits, for example 0110")
 	c.root.AddCommand(cmd)
 	return nil
 }
+
+// mineOverlay mines a nonce for an overlay address within the target
+// neighbourhood and persists it with the keys. The node must not have an
+// overlay address yet, unless it was mined for the same neighbourhood.
+func (c *command) mineOverlay(cmd *cobra.Command, logger logging.Logger, publicKey *ecdsa.PublicKey, stateStore storage.StateStorer, target string) error {
+	dataDir := c.config.GetString(optionNameDataDir)
+	if dataDir == "" {
+		return errors.New("mining an overlay address requires a data directory")
+	}
+
+	n, err := crypto.ParseNeighbourhood(target)
+	if err != nil {
+		return err
+	}
+
+	networkID, err := c.networkID()
+	if err != nil {
+		return err
+	}
+
+	nonce, err := readOverlayNonce(dataDir)
+	switch {
+	case err == nil:
+		overlay, err := crypto.NewOverlayAddress(*publicKey, networkID, nonce)
+		if err != nil {
+			return err
+		}
+		if !n.Contains(overlay) {
+			return fmt.Errorf("mined overlay address %s is not within neighbourhood %s", overlay, n)
+		}
+		logger.Infof("using mined overlay address %s", overlay)
+		return nil
+	case !errors.Is(err, os.ErrNotExist):
+		return err
+	}
+
+	logger.Infof("mining overlay address within neighbourhood %s", n)
+	nonce, overlay, err := crypto.MineOverlayNonce(cmd.Context(), *publicKey, networkID, n)
+	if err != nil {
+		return fmt.Errorf("mine overlay address: %w", err)
+	}
+
+	// fails if the node already has a different overlay address
+	if err := node.CheckOverlayWithStore(overlay, stateStore); err != nil {
+		return err
+	}
+	if err := writeOverlayNonce(dataDir, nonce); err != nil {
+		return fmt.Errorf("write overlay nonce: %w", err)
+	}
+
+	logger.Infof("mined overlay address %s", overlay)
+	return nil
+}
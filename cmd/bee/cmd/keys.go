@@ -0,0 +1,320 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/keystore"
+	filekeystore "github.com/ethersphere/bee/pkg/keystore/file"
+	"github.com/spf13/cobra"
+)
+
+const (
+	optionNameNewPasswordFile = "new-password-file"
+
+	// overlayNonceFilename is the name of the file in the keys directory
+	// that holds the hex encoded nonce of a mined overlay address.
+	overlayNonceFilename = "overlay.nonce"
+)
+
+func (c *command) initKeysCmd() {
+	cmd := &cobra.Command{
+		Use:   "keys",
+		Short: "Manage the keys of a Swarm node without starting it",
+	}
+
+	c.keysListCmd(cmd)
+	c.keysChangePasswordCmd(cmd)
+	c.keysExportCmd(cmd)
+	c.keysImportCmd(cmd)
+	c.keysAddressCmd(cmd)
+
+	c.root.AddCommand(cmd)
+}
+
+func (c *command) keysListCmd(cmd *cobra.Command) {
+	kc := &cobra.Command{
+		Use:   "list",
+		Short: "List the names of the keys",
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if len(args) > 0 {
+				return cmd.Help()
+			}
+
+			ks, err := c.keystore()
+			if err != nil {
+				return err
+			}
+			names, err := ks.List()
+			if err != nil {
+				return fmt.Errorf("list keys: %w", err)
+			}
+			for _, name := range names {
+				cmd.Println(name)
+			}
+			return nil
+		},
+	}
+	c.keysCommand(cmd, kc)
+}
+
+func (c *command) keysChangePasswordCmd(cmd *cobra.Command) {
+	kc := &cobra.Command{
+		Use:   "change-password [name...]",
+		Short: "Change the password of the keys, all keys if no name is given",
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			ks, err := c.keystore()
+			if err != nil {
+				return err
+			}
+
+			names := args
+			if len(names) == 0 {
+				if names, err = ks.List(); err != nil {
+					return fmt.Errorf("list keys: %w", err)
+				}
+			}
+
+			password, err := c.keysPassword(cmd)
+			if err != nil {
+				return err
+			}
+
+			var newPassword string
+			if pf := c.config.GetString(optionNameNewPasswordFile); pf != "" {
+				b, err := os.ReadFile(pf)
+				if err != nil {
+					return err
+				}
+				newPassword = strings.TrimRight(string(b), "\n")
+			} else {
+				if newPassword, err = terminalPromptPassword(cmd, c.passwordReader, "New password"); err != nil {
+					return err
+				}
+				confirm, err := terminalPromptPassword(cmd, c.passwordReader, "Confirm new password")
+				if err != nil {
+					return err
+				}
+				if newPassword != confirm {
+					return errors.New("passwords are not the same")
+				}
+			}
+			if newPassword == "" {
+				return errors.New("new password is empty")
+			}
+
+			for _, name := range names {
+				if err := ks.ChangePassword(name, password, newPassword); err != nil {
+					return fmt.Errorf("change password of key %s: %w", name, err)
+				}
+				cmd.Printf("changed password of key %s\n", name)
+			}
+			return nil
+		},
+	}
+	kc.Flags().String(optionNameNewPasswordFile, "", "path to a file that contains the new password")
+	c.keysCommand(cmd, kc)
+}
+
+func (c *command) keysExportCmd(cmd *cobra.Command) {
+	kc := &cobra.Command{
+		Use:   "export <name> <filename>",
+		Short: "Export a key as encrypted JSON. Use \"-\" as filename in order to write to STDOUT",
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if len(args) != 2 {
+				return cmd.Help()
+			}
+
+			ks, err := c.keystore()
+			if err != nil {
+				return err
+			}
+			password, err := c.keysPassword(cmd)
+			if err != nil {
+				return err
+			}
+			data, err := ks.Export(args[0], password)
+			if err != nil {
+				return fmt.Errorf("export key %s: %w", args[0], err)
+			}
+
+			if args[1] == "-" {
+				cmd.Println(string(data))
+				return nil
+			}
+			return os.WriteFile(args[1], data, 0600)
+		},
+	}
+	c.keysCommand(cmd, kc)
+}
+
+func (c *command) keysImportCmd(cmd *cobra.Command) {
+	kc := &cobra.Command{
+		Use:   "import <name> <filename>",
+		Short: "Import a key from encrypted JSON, which is stored with the same password. Use \"-\" as filename in order to read from STDIN",
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if len(args) != 2 {
+				return cmd.Help()
+			}
+
+			var data []byte
+			if args[1] == "-" {
+				data, err = io.ReadAll(cmd.InOrStdin())
+			} else {
+				data, err = os.ReadFile(args[1])
+			}
+			if err != nil {
+				return fmt.Errorf("read key: %w", err)
+			}
+
+			ks, err := c.keystore()
+			if err != nil {
+				return err
+			}
+			password, err := c.keysPassword(cmd)
+			if err != nil {
+				return err
+			}
+			if err := ks.Import(args[0], password, data); err != nil {
+				return fmt.Errorf("import key %s: %w", args[0], err)
+			}
+			cmd.Printf("imported key %s\n", args[0])
+			return nil
+		},
+	}
+	c.keysCommand(cmd, kc)
+}
+
+func (c *command) keysAddressCmd(cmd *cobra.Command) {
+	kc := &cobra.Command{
+		Use:   "address [name]",
+		Short: "Print the public key and the ethereum address of a key, the swarm key by default, and the overlay address if the block hash is given or the overlay was mined",
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if len(args) > 1 {
+				return cmd.Help()
+			}
+			name := "swarm"
+			if len(args) == 1 {
+				name = args[0]
+			}
+
+			ks, err := c.keystore()
+			if err != nil {
+				return err
+			}
+			if exists, err := ks.Exists(name); err != nil {
+				return err
+			} else if !exists {
+				return fmt.Errorf("key %s: %w", name, keystore.ErrKeyNotFound)
+			}
+			password, err := c.keysPassword(cmd)
+			if err != nil {
+				return err
+			}
+			pk, _, err := ks.Key(name, password)
+			if err != nil {
+				return fmt.Errorf("key %s: %w", name, err)
+			}
+
+			ethAddress, err := crypto.NewEthereumAddress(pk.PublicKey)
+			if err != nil {
+				return err
+			}
+			cmd.Printf("public key: %x\n", crypto.EncodeSecp256k1PublicKey(&pk.PublicKey))
+			cmd.Printf("ethereum address: 0x%x\n", ethAddress)
+
+			// the overlay is derived from the block hash if it is given,
+			// otherwise from the mined nonce if there is one
+			blockHash, err := readOverlayNonce(c.config.GetString(optionNameDataDir))
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+			if bh := c.config.GetString(optionNameBlockHash); bh != "" {
+				blockHash, err = hex.DecodeString(strings.TrimPrefix(bh, "0x"))
+				if err != nil || len(blockHash) != 32 {
+					return errors.New("invalid block hash")
+				}
+			}
+			if blockHash != nil {
+				networkID, err := c.networkID()
+				if err != nil {
+					return err
+				}
+				overlay, err := crypto.NewOverlayAddress(pk.PublicKey, networkID, blockHash)
+				if err != nil {
+					return err
+				}
+				cmd.Printf("overlay address: %s\n", overlay)
+			}
+			return nil
+		},
+	}
+	kc.Flags().Uint64(optionNameNetworkID, 10, "ID of the Swarm network")
+	kc.Flags().Bool(optionNameMainNet, true, "use the ID of the main net")
+	kc.Flags().String(optionNameBlockHash, "", "block hash of the block following the chequebook deployment transaction")
+	c.keysCommand(cmd, kc)
+}
+
+// keysCommand adds the flags shared by all keys subcommands and adds the
+// subcommand to the keys command.
+func (c *command) keysCommand(cmd, kc *cobra.Command) {
+	kc.Flags().String(optionNameDataDir, filepath.Join(c.homeDir, ".bee"), "data directory")
+	kc.Flags().String(optionNamePassword, "", "password for decrypting keys")
+	kc.Flags().String(optionNamePasswordFile, "", "path to a file that contains password for decrypting keys")
+	kc.PreRunE = func(cmd *cobra.Command, args []string) error {
+		return c.config.BindPFlags(cmd.Flags())
+	}
+	cmd.AddCommand(kc)
+}
+
+// keystore returns the keystore in the data directory.
+func (c *command) keystore() (keystore.Service, error) {
+	dataDir := c.config.GetString(optionNameDataDir)
+	if dataDir == "" {
+		return nil, errors.New("no data-dir provided")
+	}
+	return filekeystore.New(filepath.Join(dataDir, "keys")), nil
+}
+
+// keysPassword returns the configured password or prompts for it.
+func (c *command) keysPassword(cmd *cobra.Command) (string, error) {
+	password, err := c.configuredPassword()
+	if err != nil || password != "" {
+		return password, err
+	}
+	return terminalPromptPassword(cmd, c.passwordReader, "Password")
+}
+
+// readOverlayNonce reads the nonce of a mined overlay address from the keys
+// directory in the data directory.
+func readOverlayNonce(dataDir string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(dataDir, "keys", overlayNonceFilename))
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(nonce) != crypto.NonceSize {
+		return nil, fmt.Errorf("invalid overlay nonce in %s", overlayNonceFilename)
+	}
+	return nonce, nil
+}
+
+// writeOverlayNonce writes the nonce of a mined overlay address to the keys
+// directory in the data directory.
+func writeOverlayNonce(dataDir string, nonce []byte) error {
+	dir := filepath.Join(dataDir, "keys")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, overlayNonceFilename), []byte(hex.EncodeToString(nonce)), 0600)
+}
@@ -0,0 +1,146 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/cmd/bee/cmd"
+	"github.com/ethersphere/bee/pkg/crypto"
+	filekeystore "github.com/ethersphere/bee/pkg/keystore/file"
+)
+
+const keysPassword = "password"
+
+// newKeysDataDir returns a data directory with the swarm and the pss keys.
+func newKeysDataDir(t *testing.T) (dataDir string, ks *filekeystore.Service) {
+	t.Helper()
+
+	dataDir = t.TempDir()
+	ks = filekeystore.New(filepath.Join(dataDir, "keys"))
+	for _, name := range []string{"swarm", "pss"} {
+		if _, _, err := ks.Key(name, keysPassword); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dataDir, ks
+}
+
+// runKeysCmd runs the keys subcommand with the arguments on the data
+// directory and returns its output.
+func runKeysCmd(t *testing.T, dataDir string, args ...string) string {
+	t.Helper()
+
+	var out bytes.Buffer
+	args = append([]string{"keys"}, append(args, "--data-dir", dataDir, "--password", keysPassword)...)
+	if err := newCommand(t,
+		cmd.WithArgs(args...),
+		cmd.WithOutput(&out),
+	).Execute(); err != nil {
+		t.Fatal(err)
+	}
+	return out.String()
+}
+
+func TestKeysList(t *testing.T) {
+	dataDir, _ := newKeysDataDir(t)
+
+	if got, want := runKeysCmd(t, dataDir, "list"), "pss\nswarm\n"; got != want {
+		t.Fatalf("got output %q, want %q", got, want)
+	}
+}
+
+func TestKeysChangePassword(t *testing.T) {
+	dataDir, ks := newKeysDataDir(t)
+
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(passwordFile, []byte("new password\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	got := runKeysCmd(t, dataDir, "change-password", "swarm", "--new-password-file", passwordFile)
+	if want := "changed password of key swarm\n"; got != want {
+		t.Fatalf("got output %q, want %q", got, want)
+	}
+
+	if _, _, err := ks.Key("swarm", "new password"); err != nil {
+		t.Fatalf("swarm key with new password: %v", err)
+	}
+	if _, _, err := ks.Key("pss", keysPassword); err != nil {
+		t.Fatalf("pss key with old password: %v", err)
+	}
+}
+
+func TestKeysExportImport(t *testing.T) {
+	dataDir, ks := newKeysDataDir(t)
+
+	file := filepath.Join(t.TempDir(), "swarm.json")
+	runKeysCmd(t, dataDir, "export", "swarm", file)
+
+	otherDir := t.TempDir()
+	got := runKeysCmd(t, otherDir, "import", "imported", file)
+	if want := "imported key imported\n"; got != want {
+		t.Fatalf("got output %q, want %q", got, want)
+	}
+
+	want, _, err := ks.Key("swarm", keysPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported, created, err := filekeystore.New(filepath.Join(otherDir, "keys")).Key("imported", keysPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created || !want.Equal(imported) {
+		t.Fatal("imported key does not match the exported key")
+	}
+}
+
+func TestKeysAddress(t *testing.T) {
+	dataDir, ks := newKeysDataDir(t)
+
+	pk, _, err := ks.Key("swarm", keysPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ethAddress, err := crypto.NewEthereumAddress(pk.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blockHash := bytes.Repeat([]byte{1}, 32)
+	overlay := func(networkID uint64) string {
+		addr, err := crypto.NewOverlayAddress(pk.PublicKey, networkID, blockHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return addr.String()
+	}
+	keyLines := fmt.Sprintf("public key: %x\nethereum address: 0x%x\n", crypto.EncodeSecp256k1PublicKey(&pk.PublicKey), ethAddress)
+
+	if got := runKeysCmd(t, dataDir, "address"); got != keyLines {
+		t.Fatalf("got output %q, want %q", got, keyLines)
+	}
+
+	// the network is the same as the one of the started node
+	for _, tc := range []struct {
+		args      []string
+		networkID uint64
+	}{
+		{args: nil, networkID: 1},
+		{args: []string{"--mainnet=false"}, networkID: 10},
+		{args: []string{"--mainnet=false", "--network-id", "5"}, networkID: 5},
+	} {
+		args := append([]string{"address", "--block-hash", fmt.Sprintf("%x", blockHash)}, tc.args...)
+		got := runKeysCmd(t, dataDir, args...)
+		if want := keyLines + "overlay address: " + overlay(tc.networkID) + "\n"; got != want {
+			t.Fatalf("%s: got output %q, want %q", strings.Join(tc.args, " "), got, want)
+		}
+	}
+}
@@ -0,0 +1,375 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/debugapi"
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/node"
+	"github.com/ethersphere/bee/pkg/resolver/multiresolver"
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// configWatchDelay is the time without further changes of the config file
+// after which it is reloaded, as editors write files in multiple steps.
+const configWatchDelay = time.Second
+
+// reloadableOptions are the options which are applied to the running node
+// when they change in the config file.
+var reloadableOptions = []string{
+	optionNamePaymentThreshold,
+	optionNameCacheCapacity,
+	optionNameResolverEndpoints,
+	optionCORSAllowedOrigins,
+	optionNameVerbosity,
+}
+
+// redactedOptions are the options whose values are secret. The endpoints are
+// among them, as their URLs often carry credentials or API keys.
+var redactedOptions = map[string]bool{
+	optionNamePassword:             true,
+	optionNameTokenEncryptionKey:   true,
+	optionNameAdminPasswordHash:    true,
+	optionNameSwapEndpoint:         true,
+	optionNameResolverEndpoints:    true,
+	optionNameClefSignerEndpoint:   true,
+	optionNameRemoteSignerEndpoint: true,
+}
+
+const redactedValue = "[redacted]"
+
+var errNodeNotStarted = errors.New("node not started")
+
+// reconfigurableNode is the node to which the config reloader applies the
+// changed options.
+type reconfigurableNode interface {
+	SetPaymentThreshold(ctx context.Context, paymentThreshold string) error
+	SetCacheCapacity(capacity uint64) error
+	SetResolverConnectionConfigs(cfgs []multiresolver.ConnectionConfig) error
+	SetCORSAllowedOrigins(origins []string)
+}
+
+// configReloader reloads the config file and applies the changed options
+// which can be changed while the node runs. The options which can not are
+// reported as requiring a restart. It also reports the options in effect.
+type configReloader struct {
+	mu     sync.Mutex
+	config *viper.Viper
+	flags  *pflag.FlagSet
+	logger logging.Logger
+	node   reconfigurableNode
+	values map[string]interface{} // values of the options in effect
+}
+
+func newConfigReloader(config *viper.Viper, flags *pflag.FlagSet, logger logging.Logger) *configReloader {
+	r := &configReloader{
+		config: config,
+		flags:  flags,
+		logger: logger,
+		values: make(map[string]interface{}),
+	}
+	for _, key := range config.AllKeys() {
+		r.values[key] = config.Get(key)
+	}
+	return r
+}
+
+// setNode sets the node to which the changed options are applied.
+func (r *configReloader) setNode(n reconfigurableNode) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.node = n
+}
+
+// ConfigOptions returns the options with the values in effect, which are the
+// values the node started with or the values of the applied changes.
+func (r *configReloader) ConfigOptions() []debugapi.ConfigOption {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reloadable := make(map[string]bool, len(reloadableOptions))
+	for _, key := range reloadableOptions {
+		reloadable[key] = true
+	}
+
+	keys := make([]string, 0, len(r.values))
+	for key := range r.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	options := make([]debugapi.ConfigOption, 0, len(keys))
+	for _, key := range keys {
+		v := r.values[key]
+		if d, ok := v.(time.Duration); ok {
+			v = d.String()
+		}
+		if redactedOptions[key] && !isEmptyValue(v) {
+			v = redactedValue
+		}
+		options = append(options, debugapi.ConfigOption{
+			Name:            key,
+			Value:           v,
+			Source:          r.source(key),
+			RestartRequired: !reloadable[key],
+		})
+	}
+	return options
+}
+
+// source returns where the value of the option comes from, in the order of
+// precedence of the config.
+func (r *configReloader) source(key string) string {
+	if r.flags != nil {
+		if f := r.flags.Lookup(key); f != nil && f.Changed {
+			return debugapi.ConfigSourceFlag
+		}
+	}
+	if _, ok := os.LookupEnv(envName(key)); ok {
+		return debugapi.ConfigSourceEnv
+	}
+	if r.config.InConfig(key) {
+		return debugapi.ConfigSourceFile
+	}
+	return debugapi.ConfigSourceDefault
+}
+
+// envName returns the name of the environment variable of the option.
+func envName(key string) string {
+	return "BEE_" + strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+}
+
+// ReloadConfig reads the config file and applies the changed options which
+// can be changed while the node runs. None of the options is applied if any
+// of them is invalid.
+func (r *configReloader) ReloadConfig() (*debugapi.ConfigReloadResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.node == nil {
+		return nil, errNodeNotStarted
+	}
+
+	if err := r.config.ReadInConfig(); err != nil {
+		var e viper.ConfigFileNotFoundError
+		if !errors.As(err, &e) {
+			return nil, fmt.Errorf("read config: %v: %w", err, debugapi.ErrInvalidConfig)
+		}
+	}
+
+	res := &debugapi.ConfigReloadResult{
+		Applied:         []string{},
+		RestartRequired: []string{},
+	}
+
+	changed := make(map[string]interface{})
+	for _, key := range r.config.AllKeys() {
+		if v := r.config.Get(key); !reflect.DeepEqual(v, r.values[key]) {
+			changed[key] = v
+		}
+	}
+
+	var apply []func() error
+	for _, key := range reloadableOptions {
+		if _, ok := changed[key]; !ok {
+			continue
+		}
+		f, err := r.prepare(key)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v: %w", key, err, debugapi.ErrInvalidConfig)
+		}
+		if f == nil {
+			continue
+		}
+		apply = append(apply, f)
+		res.Applied = append(res.Applied, key)
+		delete(changed, key)
+	}
+
+	for i, f := range apply {
+		key := res.Applied[i]
+		if err := f(); err != nil {
+			return nil, fmt.Errorf("apply %s: %w", key, err)
+		}
+		r.values[key] = r.config.Get(key)
+		r.logger.Infof("config reload: applied %s", key)
+	}
+
+	for key := range changed {
+		res.RestartRequired = append(res.RestartRequired, key)
+	}
+	sort.Strings(res.Applied)
+	sort.Strings(res.RestartRequired)
+	if len(res.RestartRequired) > 0 {
+		r.logger.Warningf("config reload: changes of %s are applied when the node is restarted", strings.Join(res.RestartRequired, ", "))
+	}
+
+	return res, nil
+}
+
+// prepare validates the changed option and returns the function which
+// applies it, or nil if the change requires a restart.
+func (r *configReloader) prepare(key string) (func() error, error) {
+	switch key {
+	case optionNamePaymentThreshold:
+		v := r.config.GetString(key)
+		if _, err := node.ParsePaymentThreshold(v); err != nil {
+			return nil, err
+		}
+		return func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			return r.node.SetPaymentThreshold(ctx, v)
+		}, nil
+	case optionNameCacheCapacity:
+		v := r.config.GetUint64(key)
+		if v == 0 {
+			return nil, errors.New("cache capacity must be greater than 0")
+		}
+		return func() error {
+			return r.node.SetCacheCapacity(v)
+		}, nil
+	case optionNameResolverEndpoints:
+		var cfgs []multiresolver.ConnectionConfig
+		if endpoints := r.config.GetStringSlice(key); len(endpoints) > 0 {
+			var err error
+			cfgs, err = multiresolver.ParseConnectionStrings(endpoints)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return func() error {
+			return r.node.SetResolverConnectionConfigs(cfgs)
+		}, nil
+	case optionCORSAllowedOrigins:
+		v := r.config.GetStringSlice(key)
+		return func() error {
+			r.node.SetCORSAllowedOrigins(v)
+			return nil
+		}, nil
+	case optionNameVerbosity:
+		level, silent, err := parseVerbosity(strings.ToLower(r.config.GetString(key)))
+		if err != nil {
+			return nil, err
+		}
+		_, wasSilent, _ := parseVerbosity(strings.ToLower(fmt.Sprint(r.values[key])))
+		if silent || wasSilent {
+			// the output of a silent logger is discarded
+			return nil, nil
+		}
+		return func() error {
+			for name := range r.logger.NamedLevels() {
+				if err := r.logger.SetNamedLevel(name, level); err != nil {
+					return err
+				}
+			}
+			// keep the levels of the components overriding the verbosity
+			return setLogLevels(r.logger, r.config.GetStringSlice(optionNameLogLevels))
+		}, nil
+	}
+	return nil, nil
+}
+
+// watch reloads the config when the config file changes until the context is
+// done.
+func (r *configReloader) watch(ctx context.Context, file string) error {
+	if file == "" {
+		return nil
+	}
+	file = filepath.Clean(file)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	// the directory is watched as editors replace the file
+	if err := watcher.Add(filepath.Dir(file)); err != nil {
+		_ = watcher.Close()
+		return err
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		timer := time.NewTimer(configWatchDelay)
+		timer.Stop()
+		defer timer.Stop()
+
+		for {
+			select {
+			case e, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(e.Name) != file || e.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+					continue
+				}
+				timer.Reset(configWatchDelay)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				r.logger.Debugf("config reload: watch %s: %v", file, err)
+			case <-timer.C:
+				r.logger.Infof("config reload: %s changed", file)
+				r.reload()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// reload reloads the config and logs the error, if any.
+func (r *configReloader) reload() {
+	if _, err := r.ReloadConfig(); err != nil {
+		r.logger.Errorf("config reload: %v", err)
+	}
+}
+
+// parseVerbosity returns the log level of the verbosity and whether the log
+// output is discarded.
+func parseVerbosity(verbosity string) (level logrus.Level, silent bool, err error) {
+	switch verbosity {
+	case "0", "silent":
+		return 0, true, nil
+	case "1", "error":
+		return logrus.ErrorLevel, false, nil
+	case "2", "warn":
+		return logrus.WarnLevel, false, nil
+	case "3", "info":
+		return logrus.InfoLevel, false, nil
+	case "4", "debug":
+		return logrus.DebugLevel, false, nil
+	case "5", "trace":
+		return logrus.TraceLevel, false, nil
+	}
+	return 0, false, fmt.Errorf("unknown verbosity level %q", verbosity)
+}
+
+// isEmptyValue reports whether the option value is not set, so that unset
+// secret options are not reported as redacted.
+func isEmptyValue(v interface{}) bool {
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice {
+		return rv.Len() == 0
+	}
+	return fmt.Sprint(v) == ""
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/ethersphere/bee"
 	"github.com/ethersphere/bee/pkg/crypto"
 	"github.com/ethersphere/bee/pkg/crypto/clef"
+	"github.com/ethersphere/bee/pkg/crypto/remote"
 	"github.com/ethersphere/bee/pkg/keystore"
 	filekeystore "github.com/ethersphere/bee/pkg/keystore/file"
 	memkeystore "github.com/ethersphere/bee/pkg/keystore/mem"
@@ -53,10 +54,17 @@ func (c *command) initStartCmd() (err error) {
 			}
 
 			v := strings.ToLower(c.config.GetString(optionNameVerbosity))
-			logger, err := newLogger(cmd, v)
+			logOptions, err := c.logOptions()
+			if err != nil {
+				return err
+			}
+			logger, err := newLogger(cmd, v, logOptions...)
 			if err != nil {
 				return fmt.Errorf("new logger: %w", err)
 			}
+			if err := setLogLevels(logger, c.config.GetStringSlice(optionNameLogLevels)); err != nil {
+				return err
+			}
 
 			go startTimeBomb(logger)
 
@@ -107,15 +115,9 @@ func (c *command) initStartCmd() (err error) {
 				return errors.New("boot node must be started as a full node")
 			}
 
-			mainnet := c.config.GetBool(optionNameMainNet)
-			networkID := c.config.GetUint64(optionNameNetworkID)
-
-			if mainnet {
-				userHasSetNetworkID := c.config.IsSet(optionNameNetworkID)
-				if userHasSetNetworkID && networkID != 1 {
-					return errors.New("provided network ID does not match mainnet")
-				}
-				networkID = 1
+			networkID, err := c.networkID()
+			if err != nil {
+				return err
 			}
 
 			bootnodes := c.config.GetStringSlice(optionNameBootnodes)
@@ -151,6 +153,16 @@ func (c *command) initStartCmd() (err error) {
 				return errors.New("static nodes can only be configured on bootnodes")
 			}
 
+			var overlayNonce []byte
+			if dataDir := c.config.GetString(optionNameDataDir); dataDir != "" {
+				overlayNonce, err = readOverlayNonce(dataDir)
+				if err != nil && !errors.Is(err, os.ErrNotExist) {
+					return err
+				}
+			}
+
+			reloader := newConfigReloader(c.config, cmd.Flags(), logger)
+
 			b, err := node.NewBee(c.config.GetString(optionNameP2PAddr), signerConfig.publicKey, signerConfig.signer, networkID, logger, signerConfig.libp2pPrivateKey, signerConfig.pssPrivateKey, &node.Options{
 				DataDir:                    c.config.GetString(optionNameDataDir),
 				CacheCapacity:              c.config.GetUint64(optionNameCacheCapacity),
@@ -160,6 +172,10 @@ func (c *command) initStartCmd() (err error) {
 				DBDisableSeeksCompaction:   c.config.GetBool(optionNameDBDisableSeeksCompaction),
 				APIAddr:                    c.config.GetString(optionNameAPIAddr),
 				DebugAPIAddr:               debugAPIAddr,
+				S3Addr:                     c.config.GetString(optionNameS3Addr),
+				S3PostageBatchID:           c.config.GetString(optionNameS3PostageBatchID),
+				DAVAddr:                    c.config.GetString(optionNameDAVAddr),
+				DAVPostageBatchID:          c.config.GetString(optionNameDAVPostageBatchID),
 				Addr:                       c.config.GetString(optionNameP2PAddr),
 				NATAddr:                    c.config.GetString(optionNameNATAddr),
 				EnableWS:                   c.config.GetBool(optionNameP2PWSEnable),
@@ -174,6 +190,9 @@ func (c *command) initStartCmd() (err error) {
 				PaymentTolerance:           c.config.GetInt64(optionNamePaymentTolerance),
 				PaymentEarly:               c.config.GetInt64(optionNamePaymentEarly),
 				ResolverConnectionCfgs:     resolverCfgs,
+				ResolverCacheTTL:           c.config.GetDuration(optionNameResolverCacheTTL),
+				ResolverNegativeCacheTTL:   c.config.GetDuration(optionNameResolverNegativeCacheTTL),
+				PssMiningWorkers:           c.config.GetInt(optionNamePssMiningWorkers),
 				GatewayMode:                c.config.GetBool(optionNameGatewayMode),
 				BootnodeMode:               bootNode,
 				SwapEndpoint:               c.config.GetString(optionNameSwapEndpoint),
@@ -186,6 +205,7 @@ func (c *command) initStartCmd() (err error) {
 				FullNodeMode:               fullNode,
 				Transaction:                c.config.GetString(optionNameTransactionHash),
 				BlockHash:                  c.config.GetString(optionNameBlockHash),
+				OverlayNonce:               overlayNonce,
 				PostageContractAddress:     c.config.GetString(optionNamePostageContractAddress),
 				PriceOracleAddress:         c.config.GetString(optionNamePriceOracleAddress),
 				BlockTime:                  networkConfig.blockTime,
@@ -202,11 +222,37 @@ func (c *command) initStartCmd() (err error) {
 				TokenEncryptionKey:         c.config.GetString(optionNameTokenEncryptionKey),
 				AdminPasswordHash:          c.config.GetString(optionNameAdminPasswordHash),
 				UsePostageSnapshot:         c.config.GetBool(optionNameUsePostageSnapshot),
+				ConfigInspector:            reloader,
+				ConfigReloader:             reloader,
 			})
 			if err != nil {
 				return err
 			}
 
+			// Reload the config when the config file changes or a hangup
+			// signal is received.
+			reloader.setNode(b)
+			reloadCtx, reloadCancel := context.WithCancel(context.Background())
+			defer reloadCancel()
+			if err := reloader.watch(reloadCtx, c.cfgFile); err != nil {
+				logger.Debugf("config reload: watch %s: %v", c.cfgFile, err)
+				logger.Warning("config file changes are not watched")
+			}
+			hangupChannel := make(chan os.Signal, 1)
+			signal.Notify(hangupChannel, syscall.SIGHUP)
+			defer signal.Stop(hangupChannel)
+			go func() {
+				for {
+					select {
+					case <-hangupChannel:
+						logger.Info("received hangup signal, reloading config")
+						reloader.reload()
+					case <-reloadCtx.Done():
+						return
+					}
+				}
+			}()
+
 			// Wait for termination or interrupt signals.
 			// We want to clean up things at the end.
 			interruptChannel := make(chan os.Signal, 1)
@@ -324,17 +370,12 @@ func (c *command) configureSigner(cmd *cobra.Command, logger logging.Logger) (co
 	}
 
 	var signer crypto.Signer
-	var password string
 	var publicKey *ecdsa.PublicKey
-	if p := c.config.GetString(optionNamePassword); p != "" {
-		password = p
-	} else if pf := c.config.GetString(optionNamePasswordFile); pf != "" {
-		b, err := os.ReadFile(pf)
-		if err != nil {
-			return nil, err
-		}
-		password = string(bytes.Trim(b, "\n"))
-	} else {
+	password, err := c.configuredPassword()
+	if err != nil {
+		return nil, err
+	}
+	if password == "" {
 		// if libp2p key exists we can assume all required keys exist
 		// so prompt for a password to unlock them
 		// otherwise prompt for new password with confirmation to create them
@@ -355,7 +396,29 @@ func (c *command) configureSigner(cmd *cobra.Command, logger logging.Logger) (co
 		}
 	}
 
-	if c.config.GetBool(optionNameClefSignerEnable) {
+	remoteEndpoint := c.config.GetString(optionNameRemoteSignerEndpoint)
+	if remoteEndpoint != "" && c.config.GetBool(optionNameClefSignerEnable) {
+		return nil, errors.New("clef and remote signer can not be enabled at the same time")
+	}
+
+	if remoteEndpoint != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), c.config.GetDuration(optionNameRemoteSignerTimeout))
+		remoteRPC, err := rpc.DialContext(ctx, remoteEndpoint)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("remote signer: %w", err)
+		}
+
+		signer, err = remote.NewSigner(remoteRPC, c.config.GetStringSlice(optionNameRemoteSignerAllow), remote.WithTimeout(c.config.GetDuration(optionNameRemoteSignerTimeout)))
+		if err != nil {
+			return nil, fmt.Errorf("remote signer: %w", err)
+		}
+
+		publicKey, err = signer.PublicKey()
+		if err != nil {
+			return nil, err
+		}
+	} else if c.config.GetBool(optionNameClefSignerEnable) {
 		endpoint := c.config.GetString(optionNameClefSignerEndpoint)
 		if endpoint == "" {
 			endpoint, err = clef.DefaultIpcPath()
@@ -440,6 +503,22 @@ func (c *command) configureSigner(cmd *cobra.Command, logger logging.Logger) (co
 	}, nil
 }
 
+// configuredPassword returns the password for decrypting keys from the
+// password or password-file option, or an empty string if neither is set.
+func (c *command) configuredPassword() (string, error) {
+	if p := c.config.GetString(optionNamePassword); p != "" {
+		return p, nil
+	}
+	if pf := c.config.GetString(optionNamePasswordFile); pf != "" {
+		b, err := os.ReadFile(pf)
+		if err != nil {
+			return "", err
+		}
+		return string(bytes.Trim(b, "\n")), nil
+	}
+	return "", nil
+}
+
 type networkConfig struct {
 	bootNodes []string
 	blockTime uint64
@@ -96,3 +96,18 @@ func (l *windowsEventLogger) WriterLevel(level logrus.Level) *io.PipeWriter {
 func (l *windowsEventLogger) NewEntry() *logrus.Entry {
 	return l.logger.NewEntry()
 }
+
+func (l *windowsEventLogger) Named(name string) logging.Logger {
+	return &windowsEventLogger{
+		logger: l.logger.Named(name),
+		winlog: l.winlog,
+	}
+}
+
+func (l *windowsEventLogger) NamedLevels() map[string]logrus.Level {
+	return l.logger.NamedLevels()
+}
+
+func (l *windowsEventLogger) SetNamedLevel(name string, level logrus.Level) error {
+	return l.logger.SetNamedLevel(name, level)
+}
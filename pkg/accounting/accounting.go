@@ -106,6 +106,9 @@ type Accounting struct {
 	accountingPeers   map[string]*accountingPeer
 	logger            logging.Logger
 	store             storage.StateStorer
+	// Mutex for the payment threshold and the disconnect limit, which can be
+	// changed while the node runs.
+	thresholdMu sync.RWMutex
 	// The payment threshold in BZZ we communicate to our peers.
 	paymentThreshold *big.Int
 	// The amount in percent we let peers exceed the payment threshold before we
@@ -515,6 +518,26 @@ func originatedBalanceKey(peer swarm.Address) string {
 	return fmt.Sprintf("%s%s", balancesOriginatedPrefix, peer.String())
 }
 
+// SetPaymentThreshold sets the payment threshold of the node and the
+// disconnect limit derived from it. The peers have to be notified of the new
+// threshold separately.
+func (a *Accounting) SetPaymentThreshold(paymentThreshold *big.Int) {
+	a.thresholdMu.Lock()
+	defer a.thresholdMu.Unlock()
+
+	a.paymentThreshold = new(big.Int).Set(paymentThreshold)
+	a.disconnectLimit = new(big.Int).Div(new(big.Int).Mul(paymentThreshold, big.NewInt(100+a.paymentTolerance)), big.NewInt(100))
+}
+
+// thresholds returns the payment threshold and the disconnect limit, which
+// must not be modified.
+func (a *Accounting) thresholds() (paymentThreshold, disconnectLimit *big.Int) {
+	a.thresholdMu.RLock()
+	defer a.thresholdMu.RUnlock()
+
+	return a.paymentThreshold, a.disconnectLimit
+}
+
 // getAccountingPeer returns the accountingPeer for a given swarm address.
 // If not found in memory it will initialize it.
 func (a *Accounting) getAccountingPeer(peer swarm.Address) *accountingPeer {
@@ -523,13 +546,14 @@ func (a *Accounting) getAccountingPeer(peer swarm.Address) *accountingPeer {
 
 	peerData, ok := a.accountingPeers[peer.String()]
 	if !ok {
+		paymentThreshold, _ := a.thresholds()
 		peerData = &accountingPeer{
 			reservedBalance:       big.NewInt(0),
 			shadowReservedBalance: big.NewInt(0),
 			ghostBalance:          big.NewInt(0),
 			// initially assume the peer has the same threshold as us
-			paymentThreshold: new(big.Int).Set(a.paymentThreshold),
-			earlyPayment:     new(big.Int).Div(new(big.Int).Mul(a.paymentThreshold, big.NewInt(100-a.earlyPayment)), big.NewInt(100)),
+			paymentThreshold: new(big.Int).Set(paymentThreshold),
+			earlyPayment:     new(big.Int).Div(new(big.Int).Mul(paymentThreshold, big.NewInt(100-a.earlyPayment)), big.NewInt(100)),
 			connected:        false,
 		}
 		a.accountingPeers[peer.String()] = peerData
@@ -1016,7 +1040,7 @@ func (d *debitAction) Apply() error {
 	a.metrics.TotalDebitedAmount.Add(tot)
 	a.metrics.DebitEventsCount.Inc()
 
-	if nextBalance.Cmp(a.disconnectLimit) >= 0 {
+	if _, disconnectLimit := a.thresholds(); nextBalance.Cmp(disconnectLimit) >= 0 {
 		// peer too much in debt
 		a.metrics.AccountingDisconnectsOverdrawCount.Inc()
 
@@ -1042,7 +1066,7 @@ func (d *debitAction) Cleanup() {
 	a := d.accounting
 	d.accountingPeer.shadowReservedBalance = new(big.Int).Sub(d.accountingPeer.shadowReservedBalance, d.price)
 	d.accountingPeer.ghostBalance = new(big.Int).Add(d.accountingPeer.ghostBalance, d.price)
-	if d.accountingPeer.ghostBalance.Cmp(a.disconnectLimit) > 0 {
+	if _, disconnectLimit := a.thresholds(); d.accountingPeer.ghostBalance.Cmp(disconnectLimit) > 0 {
 		a.metrics.AccountingDisconnectsGhostOverdrawCount.Inc()
 		_ = a.blocklist(d.peer, 1, "ghost overdraw")
 	}
@@ -1059,7 +1083,8 @@ func (a *Accounting) blocklistUntil(peer swarm.Address, multiplier int64) (int64
 		debt.Set(a.refreshRate)
 	}
 
-	additionalDebt := new(big.Int).Add(debt, a.paymentThreshold)
+	paymentThreshold, _ := a.thresholds()
+	additionalDebt := new(big.Int).Add(debt, paymentThreshold)
 
 	multiplyDebt := new(big.Int).Mul(additionalDebt, big.NewInt(multiplier))
 
@@ -377,6 +377,57 @@ func TestAccountingDisconnect(t *testing.T) {
 	}
 }
 
+// TestAccountingSetPaymentThreshold tests that the disconnect limit follows the
+// payment threshold set while the node runs.
+func TestAccountingSetPaymentThreshold(t *testing.T) {
+	logger := logging.New(io.Discard, 0)
+
+	store := mock.NewStateStore()
+	defer store.Close()
+
+	acc, err := accounting.NewAccounting(testPaymentThreshold, testPaymentTolerance, testPaymentEarly, logger, store, nil, big.NewInt(testRefreshRate), p2pmock.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paymentThreshold := new(big.Int).Div(testPaymentThreshold, big.NewInt(2))
+	acc.SetPaymentThreshold(paymentThreshold)
+
+	peer1Addr, err := swarm.ParseHexAddress("00112233")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acc.Connect(peer1Addr)
+
+	// put the peer 1 unit away from disconnect with the new threshold
+	debitAction, err := acc.PrepareDebit(peer1Addr, (paymentThreshold.Uint64()*(100+uint64(testPaymentTolerance))/100)-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = debitAction.Apply()
+	if err != nil {
+		t.Fatal("expected no error while still within tolerance")
+	}
+	debitAction.Cleanup()
+
+	// put the peer over the new threshold
+	debitAction, err = acc.PrepareDebit(peer1Addr, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = debitAction.Apply()
+	if err == nil {
+		t.Fatal("expected Add to return error")
+	}
+	debitAction.Cleanup()
+
+	var e *p2p.BlockPeerError
+	if !errors.As(err, &e) {
+		t.Fatalf("expected BlockPeerError, got %v", err)
+	}
+}
+
 // TestAccountingCallSettlement tests that settlement is called correctly if the payment threshold is hit
 func TestAccountingCallSettlement(t *testing.T) {
 	logger := logging.New(io.Discard, 0)
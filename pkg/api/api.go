@@ -24,11 +24,13 @@ import (
 
 	"github.com/ethersphere/bee/pkg/auth"
 	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/encryption"
 	"github.com/ethersphere/bee/pkg/feeds"
 	"github.com/ethersphere/bee/pkg/file/pipeline"
 	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/manifest"
 	m "github.com/ethersphere/bee/pkg/metrics"
 	"github.com/ethersphere/bee/pkg/pinning"
 	"github.com/ethersphere/bee/pkg/postage"
@@ -36,6 +38,7 @@ import (
 	"github.com/ethersphere/bee/pkg/pss"
 	"github.com/ethersphere/bee/pkg/pusher"
 	"github.com/ethersphere/bee/pkg/resolver"
+	"github.com/ethersphere/bee/pkg/resumable"
 	"github.com/ethersphere/bee/pkg/steward"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
@@ -57,6 +60,8 @@ const (
 	SwarmCollectionHeader     = "Swarm-Collection"
 	SwarmPostageBatchIdHeader = "Swarm-Postage-Batch-Id"
 	SwarmDeferredUploadHeader = "Swarm-Deferred-Upload"
+	SwarmChunkingHeader       = "Swarm-Chunking"
+	SwarmEncryptionKeyHeader  = "Swarm-Encryption-Key"
 )
 
 // The size of buffer used for prefetching content with Langos.
@@ -87,6 +92,7 @@ var (
 	errDirectoryStore       = errors.New("could not store directory")
 	errFileStore            = errors.New("could not store file")
 	errInvalidPostageBatch  = errors.New("invalid postage batch id")
+	errInvalidEncryptionKey = errors.New("invalid encryption key")
 )
 
 // Service is the API service interface.
@@ -94,6 +100,9 @@ type Service interface {
 	http.Handler
 	m.Collector
 	io.Closer
+	// SetCORSAllowedOrigins replaces the origins which are allowed to make
+	// cross-origin requests.
+	SetCORSAllowedOrigins(origins []string)
 }
 
 type authenticator interface {
@@ -106,6 +115,7 @@ type authenticator interface {
 type server struct {
 	auth            authenticator
 	tags            *tags.Tags
+	uploads         *resumable.Sessions
 	storer          storage.Storer
 	resolver        resolver.Interface
 	pss             pss.Interface
@@ -125,6 +135,11 @@ type server struct {
 
 	wsWg sync.WaitGroup // wait for all websockets to close on exit
 	quit chan struct{}
+
+	pssBuffersMu sync.Mutex
+	pssBuffers   map[pss.Topic]*pssBuffer
+
+	corsAllowedOriginsMu sync.RWMutex
 }
 
 type Options struct {
@@ -140,10 +155,11 @@ const (
 )
 
 // New will create a and initialize a new API service.
-func New(tags *tags.Tags, storer storage.Storer, resolver resolver.Interface, pss pss.Interface, traversalService traversal.Traverser, pinning pinning.Interface, feedFactory feeds.Factory, post postage.Service, postageContract postagecontract.Interface, steward steward.Interface, signer crypto.Signer, auth authenticator, logger logging.Logger, tracer *tracing.Tracer, o Options) (Service, <-chan *pusher.Op) {
+func New(tags *tags.Tags, uploads *resumable.Sessions, storer storage.Storer, resolver resolver.Interface, pss pss.Interface, traversalService traversal.Traverser, pinning pinning.Interface, feedFactory feeds.Factory, post postage.Service, postageContract postagecontract.Interface, steward steward.Interface, signer crypto.Signer, auth authenticator, logger logging.Logger, tracer *tracing.Tracer, o Options) (Service, <-chan *pusher.Op) {
 	s := &server{
 		auth:            auth,
 		tags:            tags,
+		uploads:         uploads,
 		storer:          storer,
 		resolver:        resolver,
 		pss:             pss,
@@ -164,6 +180,10 @@ func New(tags *tags.Tags, storer storage.Storer, resolver resolver.Interface, ps
 
 	s.setupRouting()
 
+	if uploads != nil {
+		go s.sweepUploads()
+	}
+
 	return s, s.chunkPushC
 }
 
@@ -172,6 +192,16 @@ func (s *server) Close() error {
 	s.logger.Info("api shutting down")
 	close(s.quit)
 
+	s.pssBuffersMu.Lock()
+	for _, b := range s.pssBuffers {
+		if b.idle != nil {
+			b.idle.Stop()
+		}
+		b.cleanup()
+	}
+	s.pssBuffers = nil
+	s.pssBuffersMu.Unlock()
+
 	done := make(chan struct{})
 	go func() {
 		defer close(done)
@@ -245,7 +275,28 @@ func requestModePut(r *http.Request) storage.ModePut {
 }
 
 func requestEncrypt(r *http.Request) bool {
-	return strings.ToLower(r.Header.Get(SwarmEncryptHeader)) == "true"
+	return strings.ToLower(r.Header.Get(SwarmEncryptHeader)) == "true" ||
+		r.Header.Get(SwarmEncryptionKeyHeader) != ""
+}
+
+// requestEncryptionKey returns the root key for convergent encryption or
+// nil if no key is set in the request.
+func requestEncryptionKey(r *http.Request) (encryption.Key, error) {
+	h := r.Header.Get(SwarmEncryptionKeyHeader)
+	if h == "" {
+		return nil, nil
+	}
+	key, err := hex.DecodeString(h)
+	if err != nil || len(key) != encryption.KeyLength {
+		return nil, errInvalidEncryptionKey
+	}
+	return key, nil
+}
+
+// requestCDC returns true if the content should be uploaded or downloaded
+// with content defined chunking.
+func requestCDC(r *http.Request) bool {
+	return strings.ToLower(r.Header.Get(SwarmChunkingHeader)) == manifest.ChunkingCDC
 }
 
 func requestDeferred(r *http.Request) (bool, error) {
@@ -270,11 +321,11 @@ func requestPostageBatchId(r *http.Request) ([]byte, error) {
 	return nil, errInvalidPostageBatch
 }
 
-type securityTokenRsp struct {
+type SecurityTokenResponse struct {
 	Key string `json:"key"`
 }
 
-type securityTokenReq struct {
+type SecurityTokenRequest struct {
 	Role   string `json:"role"`
 	Expiry int    `json:"expiry"`
 }
@@ -304,7 +355,7 @@ func (s *server) authHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var payload securityTokenReq
+	var payload SecurityTokenRequest
 	if err = json.Unmarshal(body, &payload); err != nil {
 		s.logger.Debugf("api: auth handler: unmarshal request body: %v", err)
 		s.logger.Error("api: auth handler: unmarshal request body")
@@ -326,7 +377,7 @@ func (s *server) authHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	jsonhttp.Created(w, securityTokenRsp{
+	jsonhttp.Created(w, SecurityTokenResponse{
 		Key: key,
 	})
 }
@@ -355,7 +406,7 @@ func (s *server) refreshHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var payload securityTokenReq
+	var payload SecurityTokenRequest
 	if err = json.Unmarshal(body, &payload); err != nil {
 		s.logger.Debugf("api: auth handler: unmarshal request body: %v", err)
 		s.logger.Error("api: auth handler: unmarshal request body")
@@ -378,7 +429,7 @@ func (s *server) refreshHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	jsonhttp.Created(w, securityTokenRsp{
+	jsonhttp.Created(w, SecurityTokenResponse{
 		Key: key,
 	})
 }
@@ -456,8 +507,14 @@ func (s *server) checkOrigin(r *http.Request) bool {
 	if r.TLS != nil {
 		scheme = "https"
 	}
-	hosts := append(s.CORSAllowedOrigins, scheme+"://"+r.Host)
-	for _, v := range hosts {
+	if equalASCIIFold(origin[0], scheme+"://"+r.Host) {
+		return true
+	}
+
+	s.corsAllowedOriginsMu.RLock()
+	defer s.corsAllowedOriginsMu.RUnlock()
+
+	for _, v := range s.CORSAllowedOrigins {
 		if equalASCIIFold(origin[0], v) || v == "*" {
 			return true
 		}
@@ -466,6 +523,13 @@ func (s *server) checkOrigin(r *http.Request) bool {
 	return false
 }
 
+func (s *server) SetCORSAllowedOrigins(origins []string) {
+	s.corsAllowedOriginsMu.Lock()
+	defer s.corsAllowedOriginsMu.Unlock()
+
+	s.CORSAllowedOrigins = origins
+}
+
 // equalASCIIFold returns true if s is equal to t with ASCII case folding as
 // defined in RFC 4790.
 func equalASCIIFold(s, t string) bool {
@@ -505,8 +569,14 @@ func (s *server) newStamperPutter(r *http.Request) (storage.Storer, func() error
 		return nil, noopWaitFn, fmt.Errorf("request deferred: %w", err)
 	}
 
+	return s.newBatchStamperPutter(batch, deferred)
+}
+
+// newBatchStamperPutter returns the putter of newStamperPutter for the
+// postage batch and the deferred upload setting of the request.
+func (s *server) newBatchStamperPutter(batch []byte, deferred bool) (storage.Storer, func() error, error) {
 	if deferred {
-		p, err := newStoringStamperPutter(s.storer, s.post, s.signer, batch)
+		p, err := postage.NewStamperPutter(s.storer, s.post, s.signer, batch)
 		return p, noopWaitFn, err
 	}
 	p, err := newPushStamperPutter(s.storer, s.post, s.signer, batch, s.chunkPushC)
@@ -583,69 +653,28 @@ func (p *pushStamperPutter) Put(ctx context.Context, mode storage.ModePut, chs .
 	return exists, nil
 }
 
-type stamperPutter struct {
-	storage.Storer
-	stamper postage.Stamper
-}
-
-func newStoringStamperPutter(s storage.Storer, post postage.Service, signer crypto.Signer, batch []byte) (*stamperPutter, error) {
-	i, err := post.GetStampIssuer(batch)
-	if err != nil {
-		return nil, fmt.Errorf("stamp issuer: %w", err)
-	}
-
-	stamper := postage.NewStamper(i, signer)
-	return &stamperPutter{Storer: s, stamper: stamper}, nil
-}
-
-func (p *stamperPutter) Put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk) (exists []bool, err error) {
-	var (
-		ctp []swarm.Chunk
-		idx []int
-	)
-	exists = make([]bool, len(chs))
-
-	for i, c := range chs {
-		has, err := p.Storer.Has(ctx, c.Address())
-		if err != nil {
-			return nil, err
-		}
-		if has || containsChunk(c.Address(), chs[:i]...) {
-			exists[i] = true
-			continue
-		}
-		stamp, err := p.stamper.Stamp(c.Address())
-		if err != nil {
-			return nil, err
-		}
-		chs[i] = c.WithStamp(stamp)
-		ctp = append(ctp, chs[i])
-		idx = append(idx, i)
-	}
-
-	exists2, err := p.Storer.Put(ctx, mode, ctp...)
-	if err != nil {
-		return nil, err
-	}
-	for i, v := range idx {
-		exists[v] = exists2[i]
-	}
-	return exists, nil
-}
-
 type pipelineFunc func(context.Context, io.Reader) (swarm.Address, error)
 
 func requestPipelineFn(s storage.Putter, r *http.Request) pipelineFunc {
-	mode, encrypt := requestModePut(r), requestEncrypt(r)
-	return func(ctx context.Context, r io.Reader) (swarm.Address, error) {
-		pipe := builder.NewPipelineBuilder(ctx, s, mode, encrypt)
-		return builder.FeedPipeline(ctx, pipe, r)
+	cdc := requestCDC(r)
+	return func(ctx context.Context, reader io.Reader) (swarm.Address, error) {
+		newPipeline := requestPipelineFactory(ctx, s, r)
+		pipe := newPipeline()
+		if cdc {
+			pipe = builder.NewCDCPipelineBuilder(newPipeline)
+		}
+		return builder.FeedPipeline(ctx, pipe, reader)
 	}
 }
 
 func requestPipelineFactory(ctx context.Context, s storage.Putter, r *http.Request) func() pipeline.Interface {
 	mode, encrypt := requestModePut(r), requestEncrypt(r)
+	// the key is validated by the upload handlers
+	key, _ := requestEncryptionKey(r)
 	return func() pipeline.Interface {
+		if key != nil {
+			return builder.NewEncryptionPipelineBuilder(ctx, s, mode, encryption.NewConvergentChunkEncrypter(key))
+		}
 		return builder.NewPipelineBuilder(ctx, s, mode, encrypt)
 	}
 }
@@ -35,6 +35,7 @@ import (
 	"github.com/ethersphere/bee/pkg/pusher"
 	"github.com/ethersphere/bee/pkg/resolver"
 	resolverMock "github.com/ethersphere/bee/pkg/resolver/mock"
+	"github.com/ethersphere/bee/pkg/resumable"
 	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
 	"github.com/ethersphere/bee/pkg/steward"
 	"github.com/ethersphere/bee/pkg/storage"
@@ -68,6 +69,7 @@ type testServerOptions struct {
 	Pinning            pinning.Interface
 	WsPath             string
 	Tags               *tags.Tags
+	Uploads            *resumable.Sessions
 	GatewayMode        bool
 	WsPingPeriod       time.Duration
 	Logger             logging.Logger
@@ -103,8 +105,11 @@ func newTestServer(t *testing.T, o testServerOptions) (*http.Client, *websocket.
 	if o.Authenticator == nil {
 		o.Authenticator = &mockauth.Auth{}
 	}
+	if o.Uploads == nil {
+		o.Uploads = resumable.New(statestore.NewStateStore(), nil)
+	}
 	var chanStore *chanStorer
-	s, chC := api.New(o.Tags, o.Storer, o.Resolver, o.Pss, o.Traversal, o.Pinning, o.Feeds, o.Post, o.PostageContract, o.Steward, signer, o.Authenticator, o.Logger, nil, api.Options{
+	s, chC := api.New(o.Tags, o.Uploads, o.Storer, o.Resolver, o.Pss, o.Traversal, o.Pinning, o.Feeds, o.Post, o.PostageContract, o.Steward, signer, o.Authenticator, o.Logger, nil, api.Options{
 		CORSAllowedOrigins: o.CORSAllowedOrigins,
 		GatewayMode:        o.GatewayMode,
 		WsPingPeriod:       o.WsPingPeriod,
@@ -234,7 +239,7 @@ func TestParseName(t *testing.T) {
 		signer := crypto.NewDefaultSigner(pk)
 		mockPostage := mockpost.New()
 
-		s, _ := api.New(nil, nil, tC.res, nil, nil, nil, nil, mockPostage, nil, nil, signer, nil, log, nil, api.Options{})
+		s, _ := api.New(nil, nil, nil, tC.res, nil, nil, nil, nil, mockPostage, nil, nil, signer, nil, log, nil, api.Options{})
 
 		t.Run(tC.desc, func(t *testing.T) {
 			got, err := s.(*api.Server).ResolveNameOrAddress(tC.name)
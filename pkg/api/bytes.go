@@ -19,7 +19,7 @@ import (
 	"github.com/gorilla/mux"
 )
 
-type bytesPostResponse struct {
+type BytesPostResponse struct {
 	Reference swarm.Address `json:"reference"`
 }
 
@@ -27,6 +27,13 @@ type bytesPostResponse struct {
 func (s *server) bytesUploadHandler(w http.ResponseWriter, r *http.Request) {
 	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger)
 
+	if _, err := requestEncryptionKey(r); err != nil {
+		logger.Debugf("bytes upload: encryption key: %v", err)
+		logger.Error("bytes upload: encryption key")
+		jsonhttp.BadRequest(w, err)
+		return
+	}
+
 	putter, wait, err := s.newStamperPutter(r)
 	if err != nil {
 		logger.Debugf("bytes upload: get putter:%v", err)
@@ -99,7 +106,7 @@ func (s *server) bytesUploadHandler(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set(SwarmTagHeader, fmt.Sprint(tag.Uid))
 	w.Header().Set("Access-Control-Expose-Headers", SwarmTagHeader)
-	jsonhttp.Created(w, bytesPostResponse{
+	jsonhttp.Created(w, BytesPostResponse{
 		Reference: address,
 	})
 }
@@ -121,5 +128,5 @@ func (s *server) bytesGetHandler(w http.ResponseWriter, r *http.Request) {
 		"Content-Type": {"application/octet-stream"},
 	}
 
-	s.downloadHandler(w, r, address, additionalHeaders, true)
+	s.downloadHandler(w, r, address, additionalHeaders, true, requestCDC(r))
 }
@@ -46,6 +46,13 @@ func (s *server) bzzUploadHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if _, err := requestEncryptionKey(r); err != nil {
+		logger.Debugf("bzz upload: encryption key: %v", err)
+		logger.Error("bzz upload: encryption key")
+		jsonhttp.BadRequest(w, err)
+		return
+	}
+
 	putter, wait, err := s.newStamperPutter(r)
 	if err != nil {
 		logger.Debugf("bzz upload: putter: %v", err)
@@ -69,8 +76,8 @@ func (s *server) bzzUploadHandler(w http.ResponseWriter, r *http.Request) {
 	s.fileUploadHandler(w, r, putter, wait)
 }
 
-// fileUploadResponse is returned when an HTTP request to upload a file is successful
-type bzzUploadResponse struct {
+// BzzUploadResponse is returned when an HTTP request to upload a file is successful
+type BzzUploadResponse struct {
 	Reference swarm.Address `json:"reference"`
 }
 
@@ -162,6 +169,9 @@ func (s *server) fileUploadHandler(w http.ResponseWriter, r *http.Request, store
 		manifest.EntryMetadataContentTypeKey: contentType,
 		manifest.EntryMetadataFilenameKey:    fileName,
 	}
+	if requestCDC(r) {
+		fileMtdt[manifest.EntryMetadataChunkingKey] = manifest.ChunkingCDC
+	}
 
 	err = m.Add(ctx, fileName, manifest.NewEntry(fr, fileMtdt))
 	if err != nil {
@@ -232,7 +242,7 @@ func (s *server) fileUploadHandler(w http.ResponseWriter, r *http.Request, store
 	w.Header().Set("ETag", fmt.Sprintf("%q", manifestReference.String()))
 	w.Header().Set(SwarmTagHeader, fmt.Sprint(tag.Uid))
 	w.Header().Set("Access-Control-Expose-Headers", SwarmTagHeader)
-	jsonhttp.Created(w, bzzUploadResponse{
+	jsonhttp.Created(w, BzzUploadResponse{
 		Reference: manifestReference,
 	})
 }
@@ -320,6 +330,16 @@ FETCH:
 		}
 	}
 
+	if format := r.URL.Query().Get("list"); format != "" && isDirectoryPath(pathVar) {
+		s.bzzListingHandler(w, r, address, pathVar, format)
+		return
+	}
+
+	if format := r.URL.Query().Get("archive"); format != "" && isDirectoryPath(pathVar) {
+		s.bzzArchiveHandler(w, r, address, pathVar, format)
+		return
+	}
+
 	if pathVar == "" {
 		logger.Tracef("bzz download: handle empty path %s", address)
 
@@ -330,7 +350,7 @@ FETCH:
 				// index document exists
 				logger.Debugf("bzz download: serving path: %s", pathWithIndex)
 
-				s.serveManifestEntry(w, r, address, indexDocumentManifestEntry, !feedDereferenced)
+				s.serveManifestEntry(w, r, address, m, pathWithIndex, indexDocumentManifestEntry, !feedDereferenced)
 				return
 			}
 		}
@@ -370,7 +390,7 @@ FETCH:
 						// index document exists
 						logger.Debugf("bzz download: serving path: %s", pathWithIndex)
 
-						s.serveManifestEntry(w, r, address, indexDocumentManifestEntry, !feedDereferenced)
+						s.serveManifestEntry(w, r, address, m, pathWithIndex, indexDocumentManifestEntry, !feedDereferenced)
 						return
 					}
 				}
@@ -384,7 +404,7 @@ FETCH:
 						// error document exists
 						logger.Debugf("bzz download: serving path: %s", errorDocumentPath)
 
-						s.serveManifestEntry(w, r, address, errorDocumentManifestEntry, !feedDereferenced)
+						s.serveManifestEntry(w, r, address, m, errorDocumentPath, errorDocumentManifestEntry, !feedDereferenced)
 						return
 					}
 				}
@@ -398,16 +418,23 @@ FETCH:
 	}
 
 	// serve requested path
-	s.serveManifestEntry(w, r, address, me, !feedDereferenced)
+	s.serveManifestEntry(w, r, address, m, pathVar, me, !feedDereferenced)
 }
 
 func (s *server) serveManifestEntry(
 	w http.ResponseWriter,
 	r *http.Request,
 	address swarm.Address,
+	m manifest.Interface,
+	entryPath string,
 	manifestEntry manifest.Entry,
 	etag bool,
 ) {
+	if _, _, ok := manifest.RedirectTarget(manifestEntry); ok {
+		s.serveManifestRedirect(w, r, address, m, entryPath, manifestEntry, etag)
+		return
+	}
+
 	additionalHeaders := http.Header{}
 	mtdt := manifestEntry.Metadata()
 	if fname, ok := mtdt[manifest.EntryMetadataFilenameKey]; ok {
@@ -419,14 +446,22 @@ func (s *server) serveManifestEntry(
 		additionalHeaders["Content-Type"] = []string{mimeType}
 	}
 
-	s.downloadHandler(w, r, manifestEntry.Reference(), additionalHeaders, etag)
+	cdc := mtdt[manifest.EntryMetadataChunkingKey] == manifest.ChunkingCDC
+
+	s.downloadHandler(w, r, manifestEntry.Reference(), additionalHeaders, etag, cdc)
 }
 
-// downloadHandler contains common logic for dowloading Swarm file from API
-func (s *server) downloadHandler(w http.ResponseWriter, r *http.Request, reference swarm.Address, additionalHeaders http.Header, etag bool) {
+// downloadHandler contains common logic for dowloading Swarm file from API.
+// The cdc flag selects the joiner for content uploaded with content defined
+// chunking.
+func (s *server) downloadHandler(w http.ResponseWriter, r *http.Request, reference swarm.Address, additionalHeaders http.Header, etag, cdc bool) {
 	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger)
 
-	reader, l, err := joiner.New(r.Context(), s.storer, reference)
+	newJoiner := joiner.New
+	if cdc {
+		newJoiner = joiner.NewCDC
+	}
+	reader, l, err := newJoiner(r.Context(), s.storer, reference)
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
 			logger.Debugf("api download: not found %s: %v", reference, err)
@@ -0,0 +1,407 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/file/joiner"
+	"github.com/ethersphere/bee/pkg/file/loadsave"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/manifest"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tracing"
+	"github.com/ethersphere/langos"
+)
+
+const (
+	archiveFormatTar = "tar"
+	archiveFormatZip = "zip"
+
+	contentTypeZip = "application/zip"
+
+	// archiveMissingFile is the name of the file appended to the archive
+	// which lists the entries that could not be retrieved.
+	archiveMissingFile = ".swarm-missing"
+	// archivePAXPrefix is the prefix of the PAX records of the tar headers
+	// which hold the metadata of the manifest entries.
+	archivePAXPrefix = "SWARM."
+
+	// archiveParallelism is the number of files which are retrieved
+	// concurrently while the archive is written.
+	archiveParallelism = 8
+	// archivePrefetchSize is the size up to which the files are retrieved
+	// whole before they are written to the archive. Larger files are
+	// streamed.
+	archivePrefetchSize = 1024 * 1024
+)
+
+// archiveFile is a file of the manifest which is retrieved to be written to
+// the archive. The fields are set when done is closed.
+type archiveFile struct {
+	path  string
+	entry manifest.Entry
+	done  chan struct{}
+
+	reader io.Reader
+	size   int64
+	err    error
+}
+
+// archiveMissing is an entry which could not be retrieved.
+type archiveMissing struct {
+	path string
+	err  error
+}
+
+var errArchivePath = errors.New("path outside of the archive root")
+
+// archiveReadError is returned by the archive writers when the content of a
+// file could not be read. The archive remains valid, as opposed to the other
+// errors of the writers.
+type archiveReadError struct {
+	err error
+}
+
+func (e *archiveReadError) Error() string {
+	return fmt.Sprintf("read: %v", e.err)
+}
+
+func (e *archiveReadError) Unwrap() error {
+	return e.err
+}
+
+// archiveWriter writes the files to an archive format.
+type archiveWriter interface {
+	// WriteFile writes the file of the size to the archive. If the reader
+	// returns an error, the file is completed so that the archive remains
+	// valid and an archiveReadError is returned.
+	WriteFile(path string, metadata map[string]string, size int64, r io.Reader) error
+	Close() error
+}
+
+// bzzArchiveHandler streams the files of the manifest directory dir and its
+// subdirectories as a tar or zip archive. The files are retrieved
+// concurrently and written in lexicographical order of their paths, relative
+// to dir. The files which can not be retrieved are listed in a file at the
+// end of the archive instead of failing the download, as well as the files
+// whose paths would be outside of the directory the archive is extracted to.
+func (s *server) bzzArchiveHandler(w http.ResponseWriter, r *http.Request, address swarm.Address, dir, format string) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger)
+
+	if format != archiveFormatTar && format != archiveFormatZip {
+		jsonhttp.BadRequest(w, "invalid archive format")
+		return
+	}
+
+	if dir == "/" {
+		dir = ""
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	files := make(chan *archiveFile, archiveParallelism)
+	walkErrC := make(chan error, 1)
+	go func() {
+		defer close(files)
+		walkErrC <- s.retrieveArchiveFiles(ctx, address, dir, files)
+	}()
+
+	// the response status depends on whether the directory has any files
+	first, ok := <-files
+	if !ok {
+		err := <-walkErrC
+		switch {
+		case err == nil && dir == "":
+			// an empty manifest results in an empty archive
+		case err == nil:
+			jsonhttp.NotFound(w, "path address not found")
+			return
+		default:
+			logger.Debugf("bzz archive: walk %s/%s: %v", address, dir, err)
+			logger.Error("bzz archive: walk")
+			if errors.Is(err, storage.ErrNotFound) {
+				jsonhttp.NotFound(w, nil)
+				return
+			}
+			jsonhttp.InternalServerError(w, nil)
+			return
+		}
+	}
+
+	name := address.String()
+	if dir != "" {
+		name += "-" + strings.ReplaceAll(strings.TrimSuffix(dir, "/"), "/", "-")
+	}
+
+	var aw archiveWriter
+	switch format {
+	case archiveFormatTar:
+		w.Header().Set(contentTypeHeader, contentTypeTar)
+		aw = newTarArchiveWriter(w)
+	case archiveFormatZip:
+		w.Header().Set(contentTypeHeader, contentTypeZip)
+		aw = newZipArchiveWriter(w)
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.%s\"", name, format))
+	w.WriteHeader(http.StatusOK)
+
+	var missing []archiveMissing
+	write := func(f *archiveFile) error {
+		select {
+		case <-f.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if f.err != nil {
+			missing = append(missing, archiveMissing{path: f.path, err: f.err})
+			return nil
+		}
+		name, ok := archivePath(strings.TrimPrefix(f.path, dir))
+		if !ok {
+			missing = append(missing, archiveMissing{path: f.path, err: errArchivePath})
+			return nil
+		}
+		err := aw.WriteFile(name, f.entry.Metadata(), f.size, f.reader)
+		var readErr *archiveReadError
+		switch {
+		case errors.As(err, &readErr) && ctx.Err() == nil:
+			missing = append(missing, archiveMissing{path: f.path, err: readErr.err})
+		case err != nil:
+			// the archive can not be completed
+			return err
+		}
+		return nil
+	}
+
+	if first != nil {
+		if err := write(first); err != nil {
+			logger.Debugf("bzz archive: write %s/%s: %v", address, dir, err)
+			return
+		}
+	}
+	for f := range files {
+		if err := write(f); err != nil {
+			logger.Debugf("bzz archive: write %s/%s: %v", address, dir, err)
+			return
+		}
+	}
+	if err := <-walkErrC; err != nil && ctx.Err() == nil {
+		// the files of the parts of the manifest that could not be walked
+		missing = append(missing, archiveMissing{path: dir, err: err})
+	}
+
+	if len(missing) > 0 {
+		logger.Debugf("bzz archive: %d entries of %s/%s missing", len(missing), address, dir)
+		var b bytes.Buffer
+		for _, m := range missing {
+			fmt.Fprintf(&b, "%s\t%v\n", m.path, m.err)
+		}
+		if err := aw.WriteFile(archiveMissingFile, nil, int64(b.Len()), &b); err != nil {
+			logger.Debugf("bzz archive: write missing entries: %v", err)
+			return
+		}
+	}
+
+	if err := aw.Close(); err != nil {
+		logger.Debugf("bzz archive: close: %v", err)
+	}
+}
+
+// archivePath returns the path of the file in the archive, relative to the
+// root of the archive, and reports whether it stays within the root when the
+// archive is extracted.
+func archivePath(p string) (string, bool) {
+	p = path.Clean(strings.TrimLeft(p, "/"))
+	if p == "." || p == ".." || strings.HasPrefix(p, "../") {
+		return "", false
+	}
+	return p, true
+}
+
+// retrieveArchiveFiles sends the files of the manifest directory to the
+// channel in lexicographical order of their paths, while at most
+// archiveParallelism files are retrieved concurrently.
+func (s *server) retrieveArchiveFiles(ctx context.Context, address swarm.Address, dir string, files chan<- *archiveFile) error {
+	sem := make(chan struct{}, archiveParallelism)
+	return manifest.WalkFiles(ctx, loadsave.NewReadonly(s.storer), address, dir, func(path string, entry manifest.Entry) error {
+		if entry.Reference().IsZero() {
+			// entries without content, such as redirects
+			return nil
+		}
+
+		f := &archiveFile{
+			path:  path,
+			entry: entry,
+			done:  make(chan struct{}),
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		go func() {
+			defer func() { <-sem }()
+			defer close(f.done)
+			f.reader, f.size, f.err = s.retrieveArchiveFile(ctx, entry)
+		}()
+
+		select {
+		case files <- f:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		return nil
+	})
+}
+
+// retrieveArchiveFile returns the reader of the content of the file entry.
+// The content of small files is retrieved whole.
+func (s *server) retrieveArchiveFile(ctx context.Context, entry manifest.Entry) (io.Reader, int64, error) {
+	newJoiner := joiner.New
+	if entry.Metadata()[manifest.EntryMetadataChunkingKey] == manifest.ChunkingCDC {
+		newJoiner = joiner.NewCDC
+	}
+	reader, size, err := newJoiner(ctx, s.storer, entry.Reference())
+	if err != nil {
+		return nil, 0, err
+	}
+	if size > archivePrefetchSize {
+		return langos.NewBufferedLangos(reader, lookaheadBufferSize(size)), size, nil
+	}
+	data, err := io.ReadAll(io.NewSectionReader(reader, 0, size))
+	if err != nil {
+		return nil, 0, err
+	}
+	return bytes.NewReader(data), size, nil
+}
+
+type tarArchiveWriter struct {
+	tw *tar.Writer
+}
+
+func newTarArchiveWriter(w io.Writer) *tarArchiveWriter {
+	return &tarArchiveWriter{tw: tar.NewWriter(w)}
+}
+
+// WriteFile writes the file with the metadata of the manifest entry in PAX
+// records. The metadata keys which are not valid PAX keywords are skipped, so
+// that they do not fail the archive. If the reader fails, the rest of the file
+// is filled with zeros.
+func (a *tarArchiveWriter) WriteFile(path string, metadata map[string]string, size int64, r io.Reader) error {
+	hdr := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     path,
+		Mode:     0644,
+		Size:     size,
+		ModTime:  time.Unix(0, 0),
+	}
+	if len(metadata) > 0 {
+		hdr.PAXRecords = make(map[string]string, len(metadata))
+		for k, v := range metadata {
+			if strings.ContainsAny(k, "=\x00") {
+				continue
+			}
+			hdr.PAXRecords[archivePAXPrefix+k] = v
+		}
+		hdr.Format = tar.FormatPAX
+	}
+	if err := a.tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+
+	er := &archiveErrReader{r: io.LimitReader(r, size)}
+	n, err := io.Copy(a.tw, er)
+	if er.err == nil && err == nil && n < size {
+		er.err = io.ErrUnexpectedEOF
+	}
+	if er.err != nil {
+		if _, err := io.CopyN(a.tw, zeroReader{}, size-n); err != nil {
+			return err
+		}
+		return &archiveReadError{err: er.err}
+	}
+	return err
+}
+
+func (a *tarArchiveWriter) Close() error {
+	return a.tw.Close()
+}
+
+type zipArchiveWriter struct {
+	zw *zip.Writer
+}
+
+func newZipArchiveWriter(w io.Writer) *zipArchiveWriter {
+	return &zipArchiveWriter{zw: zip.NewWriter(w)}
+}
+
+// WriteFile writes the file with the metadata of the manifest entry encoded
+// as JSON in the comment of the file. If the reader fails, the file is
+// truncated.
+func (a *zipArchiveWriter) WriteFile(path string, metadata map[string]string, _ int64, r io.Reader) error {
+	hdr := &zip.FileHeader{
+		Name:   path,
+		Method: zip.Deflate,
+	}
+	if len(metadata) > 0 {
+		comment, err := json.Marshal(metadata)
+		if err != nil {
+			return err
+		}
+		hdr.Comment = string(comment)
+	}
+	fw, err := a.zw.CreateHeader(hdr)
+	if err != nil {
+		return err
+	}
+	er := &archiveErrReader{r: r}
+	if _, err := io.Copy(fw, er); er.err == nil {
+		return err
+	}
+	return &archiveReadError{err: er.err}
+}
+
+func (a *zipArchiveWriter) Close() error {
+	return a.zw.Close()
+}
+
+// archiveErrReader records the error of the reader, so that it can be told
+// apart from the errors of writing the archive.
+type archiveErrReader struct {
+	r   io.Reader
+	err error
+}
+
+func (r *archiveErrReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		r.err = err
+	}
+	return n, err
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
@@ -0,0 +1,313 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/file/loadsave"
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/manifest"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage"
+	smock "github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func TestBzzArchive(t *testing.T) {
+	var (
+		storerMock      = smock.NewStorer()
+		logger          = logging.New(io.Discard, 0)
+		client, _, _, _ = newTestServer(t, testServerOptions{
+			Storer: storerMock,
+			Tags:   tags.NewTags(statestore.NewStateStore(), logger),
+			Logger: logger,
+			Post:   mockpost.New(mockpost.WithAcceptAll()),
+		})
+	)
+
+	upload := func(t *testing.T, files []f) string {
+		t.Helper()
+
+		var resp api.BzzUploadResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "true"),
+			jsonhttptest.WithRequestBody(tarFiles(t, files)),
+			jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+		return "/bzz/" + resp.Reference.String() + "/"
+	}
+
+	files := []f{
+		{data: []byte("<h1>index</h1>"), name: "index.html"},
+		{data: []byte("robots text"), name: "robots.txt"},
+		{data: bytes.Repeat([]byte("large"), 500000), name: "large.bin", dir: "data"},
+		{data: []byte("image 1"), name: "1.png", dir: "img"},
+		{data: []byte("image 3"), name: "3.png", dir: "img/old"},
+	}
+	root := upload(t, files)
+
+	readTar := func(t *testing.T, data []byte) (names []string, contents map[string][]byte, headers map[string]*tar.Header) {
+		t.Helper()
+
+		contents = make(map[string][]byte)
+		headers = make(map[string]*tar.Header)
+		tr := tar.NewReader(bytes.NewReader(data))
+		for {
+			hdr, err := tr.Next()
+			if errors.Is(err, io.EOF) {
+				return names, contents, headers
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := io.ReadAll(tr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			names = append(names, hdr.Name)
+			contents[hdr.Name] = b
+			headers[hdr.Name] = hdr
+		}
+	}
+
+	t.Run("tar", func(t *testing.T) {
+		var body []byte
+		header := jsonhttptest.Request(t, client, http.MethodGet, root+"?archive=tar", http.StatusOK,
+			jsonhttptest.WithPutResponseBody(&body),
+		)
+		if got := header.Get("Content-Type"); got != api.ContentTypeTar {
+			t.Fatalf("got content type %q, want %q", got, api.ContentTypeTar)
+		}
+
+		names, contents, headers := readTar(t, body)
+		want := []string{"data/large.bin", "img/1.png", "img/old/3.png", "index.html", "robots.txt"}
+		if strings.Join(names, ",") != strings.Join(want, ",") {
+			t.Fatalf("got entries %v, want %v", names, want)
+		}
+		for _, file := range files {
+			name := file.name
+			if file.dir != "" {
+				name = file.dir + "/" + name
+			}
+			if !bytes.Equal(contents[name], file.data) {
+				t.Fatalf("entry %s: content mismatch", name)
+			}
+		}
+		if got := headers["robots.txt"].PAXRecords["SWARM.Content-Type"]; got != "text/plain; charset=utf-8" {
+			t.Fatalf("got content type record %q", got)
+		}
+	})
+
+	t.Run("zip", func(t *testing.T) {
+		var body []byte
+		jsonhttptest.Request(t, client, http.MethodGet, root+"img/?archive=zip", http.StatusOK,
+			jsonhttptest.WithPutResponseBody(&body),
+		)
+
+		zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]string{"1.png": "image 1", "old/3.png": "image 3"}
+		if len(zr.File) != len(want) {
+			t.Fatalf("got %d entries, want %d", len(zr.File), len(want))
+		}
+		for _, zf := range zr.File {
+			rc, err := zf.Open()
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != want[zf.Name] {
+				t.Fatalf("entry %s: got %q, want %q", zf.Name, b, want[zf.Name])
+			}
+			var metadata map[string]string
+			if err := json.Unmarshal([]byte(zf.Comment), &metadata); err != nil {
+				t.Fatal(err)
+			}
+			if metadata["Content-Type"] != "image/png" {
+				t.Fatalf("entry %s: got metadata %v", zf.Name, metadata)
+			}
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		root := upload(t, []f{
+			{data: []byte("kept"), name: "kept.txt"},
+			{data: []byte("missing"), name: "missing.txt"},
+		})
+
+		var listing api.BzzListingResponse
+		jsonhttptest.Request(t, client, http.MethodGet, root+"?list=json", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&listing),
+		)
+		for _, e := range listing.Entries {
+			if e.Name == "missing.txt" {
+				if err := storerMock.Set(context.Background(), storage.ModeSetRemove, *e.Reference); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+
+		var body []byte
+		jsonhttptest.Request(t, client, http.MethodGet, root+"?archive=tar", http.StatusOK,
+			jsonhttptest.WithPutResponseBody(&body),
+		)
+		names, contents, _ := readTar(t, body)
+		if strings.Join(names, ",") != "kept.txt,.swarm-missing" {
+			t.Fatalf("got entries %v", names)
+		}
+		if !strings.HasPrefix(string(contents[".swarm-missing"]), "missing.txt\t") {
+			t.Fatalf("got missing entries %q", contents[".swarm-missing"])
+		}
+	})
+
+	t.Run("interrupted", func(t *testing.T) {
+		large := bytes.Repeat([]byte("large"), 500000)
+		root := upload(t, []f{
+			{data: large, name: "large.bin"},
+			{data: []byte("kept"), name: "kept.txt"},
+		})
+
+		var listing api.BzzListingResponse
+		jsonhttptest.Request(t, client, http.MethodGet, root+"?list=json", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&listing),
+		)
+		for _, e := range listing.Entries {
+			if e.Name != "large.bin" {
+				continue
+			}
+			// the last part of the file can not be retrieved, so the file
+			// fails after it is partly written to the archive
+			ch, err := storerMock.Get(context.Background(), storage.ModeGetRequest, *e.Reference)
+			if err != nil {
+				t.Fatal(err)
+			}
+			last := swarm.NewAddress(ch.Data()[len(ch.Data())-swarm.HashSize:])
+			if err := storerMock.Set(context.Background(), storage.ModeSetRemove, last); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		var body []byte
+		jsonhttptest.Request(t, client, http.MethodGet, root+"?archive=tar", http.StatusOK,
+			jsonhttptest.WithPutResponseBody(&body),
+		)
+		names, contents, _ := readTar(t, body)
+		if strings.Join(names, ",") != "kept.txt,large.bin,.swarm-missing" {
+			t.Fatalf("got entries %v", names)
+		}
+		if len(contents["large.bin"]) != len(large) || bytes.Equal(contents["large.bin"], large) {
+			t.Fatal("got complete content of the interrupted file")
+		}
+		if !strings.HasPrefix(string(contents[".swarm-missing"]), "large.bin\t") {
+			t.Fatalf("got missing entries %q", contents[".swarm-missing"])
+		}
+	})
+
+	t.Run("unsafe paths", func(t *testing.T) {
+		ctx := context.Background()
+		ref, err := builder.FeedPipeline(ctx, builder.NewPipelineBuilder(ctx, storerMock, storage.ModePutUpload, false), strings.NewReader("content"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, err := manifest.NewDefaultManifest(loadsave.New(storerMock, pipelineFactory(storerMock, storage.ModePutUpload, false)), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, p := range []string{"../escaped.txt", "a/../../escaped.txt", "a/./b/../kept.txt"} {
+			if err := m.Add(ctx, p, manifest.NewEntry(ref, nil)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		addr, err := m.Store(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var body []byte
+		jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+addr.String()+"/?archive=zip", http.StatusOK,
+			jsonhttptest.WithPutResponseBody(&body),
+		)
+		zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, zf := range zr.File {
+			names = append(names, zf.Name)
+		}
+		if strings.Join(names, ",") != "a/kept.txt,.swarm-missing" {
+			t.Fatalf("got entries %v", names)
+		}
+	})
+
+	t.Run("invalid metadata keys", func(t *testing.T) {
+		ctx := context.Background()
+		ref, err := builder.FeedPipeline(ctx, builder.NewPipelineBuilder(ctx, storerMock, storage.ModePutUpload, false), strings.NewReader("content"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, err := manifest.NewDefaultManifest(loadsave.New(storerMock, pipelineFactory(storerMock, storage.ModePutUpload, false)), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		metadata := map[string]string{"a=b": "1", "nul\x00": "2", "Valid": "3"}
+		for _, p := range []string{"1.txt", "2.txt"} {
+			if err := m.Add(ctx, p, manifest.NewEntry(ref, metadata)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		addr, err := m.Store(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var body []byte
+		jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+addr.String()+"/?archive=tar", http.StatusOK,
+			jsonhttptest.WithPutResponseBody(&body),
+		)
+		names, _, headers := readTar(t, body)
+		if strings.Join(names, ",") != "1.txt,2.txt" {
+			t.Fatalf("got entries %v", names)
+		}
+		for _, name := range names {
+			records := headers[name].PAXRecords
+			if len(records) != 1 || records["SWARM.Valid"] != "3" {
+				t.Fatalf("entry %s: got records %v", name, records)
+			}
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodGet, root+"nope/?archive=tar", http.StatusNotFound)
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodGet, root+"?archive=rar", http.StatusBadRequest)
+	})
+}
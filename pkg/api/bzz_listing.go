@@ -0,0 +1,226 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/ethersphere/bee/pkg/file/joiner"
+	"github.com/ethersphere/bee/pkg/file/loadsave"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/manifest"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tracing"
+)
+
+const (
+	listingFormatJSON = "json"
+	listingFormatHTML = "html"
+
+	defaultListingLimit = 100
+	maxListingLimit     = 1000
+)
+
+const (
+	listingTypeFile      = "file"
+	listingTypeDirectory = "directory"
+)
+
+type BzzListingEntry struct {
+	Name        string            `json:"name"`
+	Path        string            `json:"path"`
+	Type        string            `json:"type"`
+	Reference   *swarm.Address    `json:"reference,omitempty"`
+	Size        int64             `json:"size,omitempty"`
+	ContentType string            `json:"contentType,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+type BzzListingResponse struct {
+	Path    string            `json:"path"`
+	Entries []BzzListingEntry `json:"entries"`
+	Offset  int               `json:"offset"`
+	Limit   int               `json:"limit"`
+	// More reports whether there are entries after the listed ones.
+	More bool `json:"more"`
+	// Total is the number of the entries in the directory, which is only
+	// counted if it is requested, as all entries have to be walked.
+	Total *int `json:"total,omitempty"`
+}
+
+// errListingPageFull stops the walk of a directory when the listed page is
+// full.
+var errListingPageFull = errors.New("listing page full")
+
+var listingTemplate = template.Must(template.New("listing").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Index of /{{.Path}}</title></head>
+<body>
+<h1>Index of /{{.Path}}</h1>
+<table>
+<tr><th>Name</th><th>Size</th><th>Type</th></tr>
+{{- range .Entries}}
+<tr><td><a href="{{.Name}}{{if eq .Type "directory"}}/{{end}}">{{.Name}}{{if eq .Type "directory"}}/{{end}}</a></td><td>{{if eq .Type "file"}}{{.Size}}{{end}}</td><td>{{.ContentType}}</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+// bzzListingHandler serves a paginated listing of the entries directly
+// contained in a manifest directory, formatted as JSON or HTML.
+func (s *server) bzzListingHandler(w http.ResponseWriter, r *http.Request, address swarm.Address, dir, format string) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger)
+
+	if format != listingFormatJSON && format != listingFormatHTML {
+		jsonhttp.BadRequest(w, "invalid listing format")
+		return
+	}
+
+	offset, limit, err := listingPagination(r)
+	if err != nil {
+		logger.Debugf("bzz listing: pagination: %v", err)
+		logger.Error("bzz listing: pagination")
+		jsonhttp.BadRequest(w, err.Error())
+		return
+	}
+	countTotal := false
+	if v := r.URL.Query().Get("total"); v != "" {
+		if countTotal, err = strconv.ParseBool(v); err != nil {
+			logger.Debugf("bzz listing: total: %v", err)
+			logger.Error("bzz listing: total")
+			jsonhttp.BadRequest(w, "invalid total")
+			return
+		}
+	}
+
+	if dir == "/" {
+		dir = ""
+	}
+
+	var (
+		ctx   = r.Context()
+		page  []manifest.DirEntry
+		more  bool
+		total int
+	)
+	err = manifest.WalkDir(ctx, loadsave.NewReadonly(s.storer), address, dir, func(e manifest.DirEntry) error {
+		switch {
+		case total < offset:
+		case len(page) < limit:
+			page = append(page, e)
+		default:
+			more = true
+			if !countTotal {
+				return errListingPageFull
+			}
+		}
+		total++
+		return nil
+	})
+	if errors.Is(err, errListingPageFull) {
+		err = nil
+	}
+	if err != nil {
+		logger.Debugf("bzz listing: walk %s/%s: %v", address, dir, err)
+		logger.Error("bzz listing: walk")
+		if errors.Is(err, storage.ErrNotFound) {
+			jsonhttp.NotFound(w, nil)
+			return
+		}
+		jsonhttp.InternalServerError(w, nil)
+		return
+	}
+	if total == 0 && dir != "" {
+		jsonhttp.NotFound(w, "path address not found")
+		return
+	}
+
+	resp := BzzListingResponse{
+		Path:    dir,
+		Entries: make([]BzzListingEntry, 0, len(page)),
+		Offset:  offset,
+		Limit:   limit,
+		More:    more,
+	}
+	if countTotal {
+		resp.Total = &total
+	}
+	for _, e := range page {
+		if e.IsDir {
+			resp.Entries = append(resp.Entries, BzzListingEntry{
+				Name: path.Base(e.Path),
+				Path: e.Path,
+				Type: listingTypeDirectory,
+			})
+			continue
+		}
+
+		ref := e.Entry.Reference()
+		entry := BzzListingEntry{
+			Name:        path.Base(e.Path),
+			Path:        e.Path,
+			Type:        listingTypeFile,
+			Reference:   &ref,
+			ContentType: e.Entry.Metadata()[manifest.EntryMetadataContentTypeKey],
+			Metadata:    e.Entry.Metadata(),
+		}
+		// the size is read from the span of the file root chunk
+		newJoiner := joiner.New
+		if entry.Metadata[manifest.EntryMetadataChunkingKey] == manifest.ChunkingCDC {
+			newJoiner = joiner.NewCDC
+		}
+		if _, size, err := newJoiner(ctx, s.storer, ref); err == nil {
+			entry.Size = size
+		} else {
+			logger.Debugf("bzz listing: file size %s: %v", ref, err)
+		}
+		resp.Entries = append(resp.Entries, entry)
+	}
+
+	if format == listingFormatHTML {
+		w.Header().Set(contentTypeHeader, "text/html; charset=utf-8")
+		if err := listingTemplate.Execute(w, resp); err != nil {
+			logger.Debugf("bzz listing: render html: %v", err)
+			logger.Error("bzz listing: render html")
+		}
+		return
+	}
+
+	jsonhttp.OK(w, resp)
+}
+
+// listingPagination parses the offset and limit query parameters.
+func listingPagination(r *http.Request) (offset, limit int, err error) {
+	limit = defaultListingLimit
+	q := r.URL.Query()
+	if v := q.Get("offset"); v != "" {
+		offset, err = strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return 0, 0, errors.New("invalid offset")
+		}
+	}
+	if v := q.Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return 0, 0, errors.New("invalid limit")
+		}
+		if limit > maxListingLimit {
+			limit = maxListingLimit
+		}
+	}
+	return offset, limit, nil
+}
+
+// isDirectoryPath returns true if the bzz path refers to a directory.
+func isDirectoryPath(p string) bool {
+	return p == "" || strings.HasSuffix(p, "/")
+}
@@ -0,0 +1,153 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/logging"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	smock "github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func TestBzzListing(t *testing.T) {
+	var (
+		storerMock      = smock.NewStorer()
+		logger          = logging.New(io.Discard, 0)
+		client, _, _, _ = newTestServer(t, testServerOptions{
+			Storer: storerMock,
+			Tags:   tags.NewTags(statestore.NewStateStore(), logger),
+			Logger: logger,
+			Post:   mockpost.New(mockpost.WithAcceptAll()),
+		})
+	)
+
+	var upload api.BzzUploadResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/bzz", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmIndexDocumentHeader, "index.html"),
+		jsonhttptest.WithRequestBody(tarFiles(t, []f{
+			{data: []byte("<h1>index</h1>"), name: "index.html"},
+			{data: []byte("robots text"), name: "robots.txt"},
+			{data: []byte("image 1"), name: "1.png", dir: "img"},
+			{data: []byte("image 2"), name: "2.png", dir: "img"},
+			{data: []byte("image 3"), name: "3.png", dir: "img/old"},
+		})),
+		jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+		jsonhttptest.WithUnmarshalJSONResponse(&upload),
+	)
+	root := "/bzz/" + upload.Reference.String() + "/"
+
+	t.Run("root", func(t *testing.T) {
+		var resp api.BzzListingResponse
+		jsonhttptest.Request(t, client, http.MethodGet, root+"?list=json", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+
+		if len(resp.Entries) != 3 || resp.More || resp.Total != nil {
+			t.Fatalf("got %d entries, more %v, total %v, want 3 entries", len(resp.Entries), resp.More, resp.Total)
+		}
+		want := []struct{ name, typ string }{
+			{"img", "directory"},
+			{"index.html", "file"},
+			{"robots.txt", "file"},
+		}
+		for i, w := range want {
+			if e := resp.Entries[i]; e.Name != w.name || e.Type != w.typ {
+				t.Fatalf("entry %d: got %s %s, want %s %s", i, e.Name, e.Type, w.name, w.typ)
+			}
+		}
+		if e := resp.Entries[2]; e.Size != int64(len("robots text")) || e.ContentType != "text/plain; charset=utf-8" || e.Reference == nil {
+			t.Fatalf("unexpected file entry %+v", e)
+		}
+	})
+
+	t.Run("subdirectory", func(t *testing.T) {
+		var resp api.BzzListingResponse
+		jsonhttptest.Request(t, client, http.MethodGet, root+"img/?list=json", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+
+		var paths []string
+		for _, e := range resp.Entries {
+			paths = append(paths, e.Path)
+		}
+		if got, want := strings.Join(paths, ","), "img/1.png,img/2.png,img/old/"; got != want {
+			t.Fatalf("got entries %s, want %s", got, want)
+		}
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		var resp api.BzzListingResponse
+		jsonhttptest.Request(t, client, http.MethodGet, root+"img/?list=json&offset=1&limit=1", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+
+		if !resp.More || resp.Total != nil || resp.Offset != 1 || resp.Limit != 1 {
+			t.Fatalf("unexpected pagination %+v", resp)
+		}
+		if len(resp.Entries) != 1 || resp.Entries[0].Path != "img/2.png" {
+			t.Fatalf("unexpected entries %+v", resp.Entries)
+		}
+
+		jsonhttptest.Request(t, client, http.MethodGet, root+"img/?list=json&offset=2&limit=1", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+		if resp.More || len(resp.Entries) != 1 || resp.Entries[0].Path != "img/old/" {
+			t.Fatalf("unexpected last page %+v", resp)
+		}
+	})
+
+	t.Run("total", func(t *testing.T) {
+		var resp api.BzzListingResponse
+		jsonhttptest.Request(t, client, http.MethodGet, root+"img/?list=json&limit=1&total=true", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+
+		if !resp.More || resp.Total == nil || *resp.Total != 3 || len(resp.Entries) != 1 {
+			t.Fatalf("unexpected listing %+v", resp)
+		}
+	})
+
+	t.Run("html", func(t *testing.T) {
+		var body []byte
+		jsonhttptest.Request(t, client, http.MethodGet, root+"img/?list=html", http.StatusOK,
+			jsonhttptest.WithPutResponseBody(&body),
+		)
+
+		for _, s := range []string{`href="1.png"`, `href="2.png"`, `href="old/"`} {
+			if !bytes.Contains(body, []byte(s)) {
+				t.Fatalf("html listing does not contain %s", s)
+			}
+		}
+	})
+
+	t.Run("without list parameter serves index", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodGet, root, http.StatusOK,
+			jsonhttptest.WithExpectedResponse([]byte("<h1>index</h1>")),
+		)
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodGet, root+"css/?list=json", http.StatusNotFound)
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodGet, root+"?list=xml", http.StatusBadRequest)
+		jsonhttptest.Request(t, client, http.MethodGet, root+"?list=json&limit=-1", http.StatusBadRequest)
+		jsonhttptest.Request(t, client, http.MethodGet, root+"?list=json&offset=x", http.StatusBadRequest)
+		jsonhttptest.Request(t, client, http.MethodGet, root+"?list=json&total=x", http.StatusBadRequest)
+	})
+}
@@ -669,3 +669,80 @@ func TestBzzReupload(t *testing.T) {
 		t.Fatalf("got address %s want %s", stewardMock.LastAddress().String(), addr.String())
 	}
 }
+
+func TestBzzContentDefinedChunking(t *testing.T) {
+	var (
+		storerMock      = smock.NewStorer()
+		logger          = logging.New(io.Discard, 0)
+		client, _, _, _ = newTestServer(t, testServerOptions{
+			Storer: storerMock,
+			Tags:   tags.NewTags(statestore.NewStateStore(), logger),
+			Logger: logger,
+			Post:   mockpost.New(mockpost.WithAcceptAll()),
+		})
+		data = make([]byte, 300*1024)
+	)
+	for i := range data {
+		data[i] = byte(i * 7 / 3)
+	}
+
+	t.Run("file", func(t *testing.T) {
+		var resp api.BzzUploadResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz?name=data.bin", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmChunkingHeader, "cdc"),
+			jsonhttptest.WithRequestHeader("Content-Type", "application/octet-stream"),
+			jsonhttptest.WithRequestBody(bytes.NewReader(data)),
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+
+		m, err := manifest.NewDefaultManifestReference(resp.Reference, loadsave.NewReadonly(storerMock))
+		if err != nil {
+			t.Fatal(err)
+		}
+		e, err := m.Lookup(context.Background(), "data.bin")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := e.Metadata()[manifest.EntryMetadataChunkingKey]; got != manifest.ChunkingCDC {
+			t.Fatalf("got chunking %q, want %q", got, manifest.ChunkingCDC)
+		}
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+resp.Reference.String()+"/", http.StatusOK,
+			jsonhttptest.WithExpectedResponse(data),
+		)
+	})
+
+	t.Run("collection", func(t *testing.T) {
+		var resp api.BzzUploadResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmChunkingHeader, "cdc"),
+			jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+			jsonhttptest.WithRequestBody(tarFiles(t, []f{
+				{data: data, name: "data.bin", dir: "backup"},
+			})),
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+resp.Reference.String()+"/backup/data.bin", http.StatusOK,
+			jsonhttptest.WithExpectedResponse(data),
+		)
+	})
+
+	t.Run("bytes", func(t *testing.T) {
+		var resp api.BytesPostResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmChunkingHeader, "cdc"),
+			jsonhttptest.WithRequestBody(bytes.NewReader(data)),
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/bytes/"+resp.Reference.String(), http.StatusOK,
+			jsonhttptest.WithRequestHeader(api.SwarmChunkingHeader, "cdc"),
+			jsonhttptest.WithExpectedResponse(data),
+		)
+	})
+}
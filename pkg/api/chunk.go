@@ -24,7 +24,7 @@ import (
 	"github.com/gorilla/mux"
 )
 
-type chunkAddressResponse struct {
+type ChunkAddressResponse struct {
 	Reference swarm.Address `json:"reference"`
 }
 
@@ -160,7 +160,7 @@ func (s *server) chunkUploadHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Access-Control-Expose-Headers", SwarmTagHeader)
-	jsonhttp.Created(w, chunkAddressResponse{Reference: chunk.Address()})
+	jsonhttp.Created(w, ChunkAddressResponse{Reference: chunk.Address()})
 }
 
 func (s *server) chunkGetHandler(w http.ResponseWriter, r *http.Request) {
@@ -6,6 +6,7 @@ package api
 
 import (
 	"archive/tar"
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -75,6 +76,7 @@ func (s *server) dirUploadHandler(w http.ResponseWriter, r *http.Request, storer
 	reference, err := storeDir(
 		ctx,
 		requestEncrypt(r),
+		requestCDC(r),
 		dReader,
 		s.logger,
 		requestPipelineFn(storer, r),
@@ -90,6 +92,8 @@ func (s *server) dirUploadHandler(w http.ResponseWriter, r *http.Request, storer
 		switch {
 		case errors.Is(err, postage.ErrBucketFull):
 			jsonhttp.PaymentRequired(w, "batch is overissued")
+		case errors.Is(err, errInvalidRedirects):
+			jsonhttp.BadRequest(w, err.Error())
 		default:
 			jsonhttp.InternalServerError(w, errDirectoryStore)
 		}
@@ -123,7 +127,7 @@ func (s *server) dirUploadHandler(w http.ResponseWriter, r *http.Request, storer
 
 	w.Header().Set("Access-Control-Expose-Headers", SwarmTagHeader)
 	w.Header().Set(SwarmTagHeader, fmt.Sprint(tag.Uid))
-	jsonhttp.Created(w, bzzUploadResponse{
+	jsonhttp.Created(w, BzzUploadResponse{
 		Reference: reference,
 	})
 }
@@ -133,6 +137,7 @@ func (s *server) dirUploadHandler(w http.ResponseWriter, r *http.Request, storer
 func storeDir(
 	ctx context.Context,
 	encrypt bool,
+	cdc bool,
 	reader dirReader,
 	log logging.Logger,
 	p pipelineFunc,
@@ -153,7 +158,11 @@ func storeDir(
 		return swarm.ZeroAddress, fmt.Errorf("index document suffix must not include slash character")
 	}
 
-	filesAdded := 0
+	var (
+		filesAdded int
+		links      []*FileInfo
+		redirects  []redirectRule
+	)
 
 	// iterate through the files in the supplied tar
 	for {
@@ -164,6 +173,27 @@ func storeDir(
 			return swarm.ZeroAddress, fmt.Errorf("read tar stream: %w", err)
 		}
 
+		if fileInfo.LinkTarget != "" {
+			// links are added after all files
+			links = append(links, fileInfo)
+			continue
+		}
+
+		if fileInfo.Path == redirectsFilename {
+			data, err := io.ReadAll(io.LimitReader(fileInfo.Reader, maxRedirectsFileSize+1))
+			if err != nil {
+				return swarm.ZeroAddress, fmt.Errorf("read redirects file: %w", err)
+			}
+			if len(data) > maxRedirectsFileSize {
+				return swarm.ZeroAddress, fmt.Errorf("%w: file too large", errInvalidRedirects)
+			}
+			redirects, err = parseRedirects(bytes.NewReader(data))
+			if err != nil {
+				return swarm.ZeroAddress, fmt.Errorf("%w: %v", errInvalidRedirects, err)
+			}
+			fileInfo.Reader = bytes.NewReader(data)
+		}
+
 		if !tagCreated {
 			// only in the case when tag is sent via header (i.e. not created by this request)
 			// for each file
@@ -185,6 +215,9 @@ func storeDir(
 			manifest.EntryMetadataContentTypeKey: fileInfo.ContentType,
 			manifest.EntryMetadataFilenameKey:    fileInfo.Name,
 		}
+		if cdc {
+			fileMtdt[manifest.EntryMetadataChunkingKey] = manifest.ChunkingCDC
+		}
 		// add file entry to dir manifest
 		err = dirManifest.Add(ctx, fileInfo.Path, manifest.NewEntry(fileReference, fileMtdt))
 		if err != nil {
@@ -199,6 +232,22 @@ func storeDir(
 		return swarm.ZeroAddress, fmt.Errorf("no files in tar")
 	}
 
+	for _, l := range links {
+		err = dirManifest.Add(ctx, l.Path, manifest.NewRedirectEntry(l.LinkTarget, 0))
+		if err != nil {
+			return swarm.ZeroAddress, fmt.Errorf("add link to manifest: %w", err)
+		}
+		logger.Tracef("added dir link %v to %v", l.Path, l.LinkTarget)
+	}
+
+	for _, rr := range redirects {
+		err = dirManifest.Add(ctx, rr.from, manifest.NewRedirectEntry(rr.to, rr.status))
+		if err != nil {
+			return swarm.ZeroAddress, fmt.Errorf("add redirect to manifest: %w", err)
+		}
+		logger.Tracef("added dir redirect %v to %v", rr.from, rr.to)
+	}
+
 	// store website information
 	if indexFilename != "" || errorFilename != "" {
 		metadata := map[string]string{}
@@ -246,6 +295,8 @@ type FileInfo struct {
 	ContentType string
 	Size        int64
 	Reader      io.Reader
+	// LinkTarget is set for symbolic links, which have no content.
+	LinkTarget string
 }
 
 type dirReader interface {
@@ -277,6 +328,19 @@ func (t *tarReader) Next() (*FileInfo, error) {
 			// always use Unix path separator
 			filePath = filepath.ToSlash(filePath)
 		}
+		// symbolic and hard links are stored as links to their target
+		if fileHeader.Typeflag == tar.TypeSymlink || fileHeader.Typeflag == tar.TypeLink {
+			linkTarget := fileHeader.Linkname
+			if fileHeader.Typeflag == tar.TypeLink {
+				// hard link targets are relative to the archive root
+				linkTarget = "/" + linkTarget
+			}
+			return &FileInfo{
+				Path:       filePath,
+				Name:       fileName,
+				LinkTarget: filepath.ToSlash(linkTarget),
+			}, nil
+		}
 		// only store regular files
 		if !fileHeader.FileInfo().Mode().IsRegular() {
 			t.logger.Warningf("skipping file upload for %s as it is not a regular file", filePath)
@@ -4,24 +4,14 @@
 
 package api
 
-import "github.com/ethersphere/bee/pkg/swarm"
+import (
+	"time"
 
-type Server = server
-
-type (
-	BytesPostResponse     = bytesPostResponse
-	ChunkAddressResponse  = chunkAddressResponse
-	SocPostResponse       = socPostResponse
-	FeedReferenceResponse = feedReferenceResponse
-	BzzUploadResponse     = bzzUploadResponse
-	TagResponse           = tagResponse
-	TagRequest            = tagRequest
-	ListTagsResponse      = listTagsResponse
-	IsRetrievableResponse = isRetrievableResponse
-	SecurityTokenResponse = securityTokenRsp
-	SecurityTokenRequest  = securityTokenReq
+	"github.com/ethersphere/bee/pkg/swarm"
 )
 
+type Server = server
+
 var (
 	InvalidContentType  = errInvalidContentType
 	InvalidRequest      = errInvalidRequest
@@ -29,8 +19,9 @@ var (
 )
 
 var (
-	ContentTypeTar    = contentTypeTar
-	ContentTypeHeader = contentTypeHeader
+	ContentTypeTar          = contentTypeTar
+	ContentTypeHeader       = contentTypeHeader
+	ContentTypeOffsetStream = contentTypeOffsetStream
 )
 
 var (
@@ -58,3 +49,11 @@ func (s *Server) ResolveNameOrAddress(str string) (swarm.Address, error) {
 func CalculateNumberOfChunks(contentLength int64, isEncrypted bool) int64 {
 	return calculateNumberOfChunks(contentLength, isEncrypted)
 }
+
+func SetPssBufferIdleTimeout(d time.Duration) {
+	pssBufferIdleTimeout = d
+}
+
+func ResetPssBufferIdleTimeout() {
+	pssBufferIdleTimeout = pssBufferIdleTimeoutDefault
+}
@@ -33,7 +33,7 @@ const (
 
 var errInvalidFeedUpdate = errors.New("invalid feed update")
 
-type feedReferenceResponse struct {
+type FeedReferenceResponse struct {
 	Reference swarm.Address `json:"reference"`
 }
 
@@ -121,7 +121,7 @@ func (s *server) feedGetHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set(SwarmFeedIndexNextHeader, hex.EncodeToString(nextBytes))
 	w.Header().Set("Access-Control-Expose-Headers", fmt.Sprintf("%s, %s", SwarmFeedIndexHeader, SwarmFeedIndexNextHeader))
 
-	jsonhttp.OK(w, feedReferenceResponse{Reference: ref})
+	jsonhttp.OK(w, FeedReferenceResponse{Reference: ref})
 }
 
 func (s *server) feedPostHandler(w http.ResponseWriter, r *http.Request) {
@@ -212,7 +212,7 @@ func (s *server) feedPostHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	jsonhttp.Created(w, feedReferenceResponse{Reference: ref})
+	jsonhttp.Created(w, FeedReferenceResponse{Reference: ref})
 }
 
 func parseFeedUpdate(ch swarm.Chunk) (swarm.Address, int64, error) {
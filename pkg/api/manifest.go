@@ -0,0 +1,183 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ethersphere/bee/pkg/encryption"
+	"github.com/ethersphere/bee/pkg/file/loadsave"
+	"github.com/ethersphere/bee/pkg/file/pipeline"
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/manifest"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tracing"
+	"github.com/gorilla/mux"
+)
+
+type ManifestEntryResponse struct {
+	Reference swarm.Address     `json:"reference"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+type ManifestChangeResponse struct {
+	Path            string                 `json:"path"`
+	Old             *ManifestEntryResponse `json:"old,omitempty"`
+	New             *ManifestEntryResponse `json:"new,omitempty"`
+	MetadataChanged []string               `json:"metadataChanged,omitempty"`
+}
+
+type ManifestDiffResponse struct {
+	Added    []ManifestChangeResponse `json:"added"`
+	Removed  []ManifestChangeResponse `json:"removed"`
+	Modified []ManifestChangeResponse `json:"modified"`
+}
+
+// manifestDiffHandler lists the paths that differ between two manifests.
+func (s *server) manifestDiffHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger)
+
+	nameOrHex := mux.Vars(r)["address"]
+	address, err := s.resolveNameOrAddress(nameOrHex)
+	if err != nil {
+		logger.Debugf("manifest diff: parse address %s: %v", nameOrHex, err)
+		logger.Error("manifest diff: parse address")
+		jsonhttp.NotFound(w, nil)
+		return
+	}
+
+	targetNameOrHex := mux.Vars(r)["target"]
+	target, err := s.resolveNameOrAddress(targetNameOrHex)
+	if err != nil {
+		logger.Debugf("manifest diff: parse target address %s: %v", targetNameOrHex, err)
+		logger.Error("manifest diff: parse target address")
+		jsonhttp.NotFound(w, nil)
+		return
+	}
+
+	changes, err := manifest.Diff(r.Context(), loadsave.NewReadonly(s.storer), address, target)
+	if err != nil {
+		logger.Debugf("manifest diff: %s %s: %v", address, target, err)
+		logger.Error("manifest diff: failed")
+		if errors.Is(err, storage.ErrNotFound) {
+			jsonhttp.NotFound(w, nil)
+			return
+		}
+		jsonhttp.InternalServerError(w, nil)
+		return
+	}
+
+	resp := ManifestDiffResponse{
+		Added:    []ManifestChangeResponse{},
+		Removed:  []ManifestChangeResponse{},
+		Modified: []ManifestChangeResponse{},
+	}
+	for _, c := range changes {
+		cr := ManifestChangeResponse{
+			Path:            c.Path,
+			Old:             newManifestEntryResponse(c.Old),
+			New:             newManifestEntryResponse(c.New),
+			MetadataChanged: c.MetadataChanged,
+		}
+		switch c.Type {
+		case manifest.ChangeAdded:
+			resp.Added = append(resp.Added, cr)
+		case manifest.ChangeRemoved:
+			resp.Removed = append(resp.Removed, cr)
+		case manifest.ChangeModified:
+			resp.Modified = append(resp.Modified, cr)
+		}
+	}
+
+	jsonhttp.OK(w, resp)
+}
+
+func newManifestEntryResponse(e manifest.Entry) *ManifestEntryResponse {
+	if e == nil {
+		return nil
+	}
+	return &ManifestEntryResponse{
+		Reference: e.Reference(),
+		Metadata:  e.Metadata(),
+	}
+}
+
+// manifestMergeHandler overlays the entries of one manifest onto another and
+// stores the resulting manifest.
+func (s *server) manifestMergeHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger)
+
+	nameOrHex := mux.Vars(r)["address"]
+	address, err := s.resolveNameOrAddress(nameOrHex)
+	if err != nil {
+		logger.Debugf("manifest merge: parse address %s: %v", nameOrHex, err)
+		logger.Error("manifest merge: parse address")
+		jsonhttp.NotFound(w, nil)
+		return
+	}
+
+	overlayNameOrHex := mux.Vars(r)["overlay"]
+	overlay, err := s.resolveNameOrAddress(overlayNameOrHex)
+	if err != nil {
+		logger.Debugf("manifest merge: parse overlay address %s: %v", overlayNameOrHex, err)
+		logger.Error("manifest merge: parse overlay address")
+		jsonhttp.NotFound(w, nil)
+		return
+	}
+
+	putter, wait, err := s.newStamperPutter(r)
+	if err != nil {
+		logger.Debugf("manifest merge: putter: %v", err)
+		logger.Error("manifest merge: putter")
+		switch {
+		case errors.Is(err, postage.ErrNotFound):
+			jsonhttp.BadRequest(w, "batch not found")
+		case errors.Is(err, postage.ErrNotUsable):
+			jsonhttp.BadRequest(w, "batch not usable yet")
+		default:
+			jsonhttp.BadRequest(w, nil)
+		}
+		return
+	}
+
+	// the merged manifest keeps the encryption of the base manifest
+	ctx := r.Context()
+	mode, encrypt := requestModePut(r), len(address.Bytes()) == encryption.ReferenceSize
+	ls := loadsave.New(putter, func() pipeline.Interface {
+		return builder.NewPipelineBuilder(ctx, putter, mode, encrypt)
+	})
+
+	reference, err := manifest.Merge(ctx, ls, address, overlay)
+	if err != nil {
+		logger.Debugf("manifest merge: %s %s: %v", address, overlay, err)
+		logger.Error("manifest merge: failed")
+		switch {
+		case errors.Is(err, manifest.ErrIncompatibleManifests):
+			jsonhttp.BadRequest(w, "incompatible manifests")
+		case errors.Is(err, storage.ErrNotFound):
+			jsonhttp.NotFound(w, nil)
+		case errors.Is(err, postage.ErrBucketFull):
+			jsonhttp.PaymentRequired(w, "batch is overissued")
+		default:
+			jsonhttp.InternalServerError(w, nil)
+		}
+		return
+	}
+
+	if err = wait(); err != nil {
+		logger.Debugf("manifest merge: sync chunks: %v", err)
+		logger.Error("manifest merge: sync chunks")
+		jsonhttp.InternalServerError(w, nil)
+		return
+	}
+
+	jsonhttp.Created(w, BzzUploadResponse{
+		Reference: reference,
+	})
+}
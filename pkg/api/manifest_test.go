@@ -0,0 +1,117 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/logging"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	smock "github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func TestManifestDiffMerge(t *testing.T) {
+	var (
+		storerMock      = smock.NewStorer()
+		logger          = logging.New(io.Discard, 0)
+		client, _, _, _ = newTestServer(t, testServerOptions{
+			Storer: storerMock,
+			Tags:   tags.NewTags(statestore.NewStateStore(), logger),
+			Logger: logger,
+			Post:   mockpost.New(mockpost.WithAcceptAll()),
+		})
+	)
+
+	upload := func(t *testing.T, files []f) swarm.Address {
+		t.Helper()
+
+		var resp api.BzzUploadResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "true"),
+			jsonhttptest.WithRequestBody(tarFiles(t, files)),
+			jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+		return resp.Reference
+	}
+
+	base := upload(t, []f{
+		{data: []byte("index"), name: "index.html"},
+		{data: []byte("image 1"), name: "1.png", dir: "img"},
+		{data: []byte("image 2"), name: "2.png", dir: "img"},
+	})
+	overlay := upload(t, []f{
+		{data: []byte("new index"), name: "index.html"},
+		{data: []byte("image 1"), name: "1.png", dir: "img"},
+		{data: []byte("about"), name: "about.html"},
+	})
+
+	t.Run("diff", func(t *testing.T) {
+		var resp api.ManifestDiffResponse
+		jsonhttptest.Request(t, client, http.MethodGet, "/manifests/"+base.String()+"/diff/"+overlay.String(), http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+
+		if len(resp.Added) != 1 || resp.Added[0].Path != "about.html" || resp.Added[0].Old != nil {
+			t.Fatalf("unexpected added paths: %+v", resp.Added)
+		}
+		if len(resp.Removed) != 1 || resp.Removed[0].Path != "img/2.png" || resp.Removed[0].New != nil {
+			t.Fatalf("unexpected removed paths: %+v", resp.Removed)
+		}
+		if len(resp.Modified) != 1 || resp.Modified[0].Path != "index.html" {
+			t.Fatalf("unexpected modified paths: %+v", resp.Modified)
+		}
+		if resp.Modified[0].Old.Reference.Equal(resp.Modified[0].New.Reference) {
+			t.Fatal("expected modified entry references to differ")
+		}
+	})
+
+	t.Run("diff same", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodGet, "/manifests/"+base.String()+"/diff/"+base.String(), http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(api.ManifestDiffResponse{
+				Added:    []api.ManifestChangeResponse{},
+				Removed:  []api.ManifestChangeResponse{},
+				Modified: []api.ManifestChangeResponse{},
+			}),
+		)
+	})
+
+	t.Run("diff not found", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodGet, "/manifests/"+base.String()+"/diff/"+swarm.MustParseHexAddress("0000000000000000000000000000000000000000000000000000000000000abc").String(), http.StatusNotFound)
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		var resp api.BzzUploadResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/manifests/"+base.String()+"/merge/"+overlay.String(), http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+
+		for path, want := range map[string]string{
+			"index.html": "new index",
+			"img/1.png":  "image 1",
+			"img/2.png":  "image 2",
+			"about.html": "about",
+		} {
+			jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+resp.Reference.String()+"/"+path, http.StatusOK,
+				jsonhttptest.WithExpectedResponse([]byte(want)),
+			)
+		}
+	})
+
+	t.Run("merge without batch", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodPost, "/manifests/"+base.String()+"/merge/"+overlay.String(), http.StatusBadRequest)
+	})
+}
@@ -15,6 +15,14 @@ import (
 	"github.com/gorilla/mux"
 )
 
+type PinResponse struct {
+	Reference swarm.Address `json:"reference"`
+}
+
+type ListPinsResponse struct {
+	References []swarm.Address `json:"references"`
+}
+
 // pinRootHash pins root hash of given reference. This method is idempotent.
 func (s *server) pinRootHash(w http.ResponseWriter, r *http.Request) {
 	ref, err := swarm.ParseHexAddress(mux.Vars(r)["reference"])
@@ -111,9 +119,7 @@ func (s *server) getPinnedRootHash(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	jsonhttp.OK(w, struct {
-		Reference swarm.Address `json:"reference"`
-	}{
+	jsonhttp.OK(w, PinResponse{
 		Reference: ref,
 	})
 }
@@ -128,9 +134,7 @@ func (s *server) listPinnedRootHashes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	jsonhttp.OK(w, struct {
-		References []swarm.Address `json:"references"`
-	}{
+	jsonhttp.OK(w, ListPinsResponse{
 		References: pinned,
 	})
 }
@@ -55,17 +55,13 @@ func checkPinHandlers(t *testing.T, client *http.Client, rootHash string, create
 	}
 
 	jsonhttptest.Request(t, client, http.MethodGet, pinsReferencePath, http.StatusOK,
-		jsonhttptest.WithExpectedJSONResponse(struct {
-			Reference swarm.Address `json:"reference"`
-		}{
+		jsonhttptest.WithExpectedJSONResponse(api.PinResponse{
 			Reference: swarm.MustParseHexAddress(rootHash),
 		}),
 	)
 
 	jsonhttptest.Request(t, client, http.MethodGet, pinsBasePath, http.StatusOK,
-		jsonhttptest.WithExpectedJSONResponse(struct {
-			References []swarm.Address `json:"references"`
-		}{
+		jsonhttptest.WithExpectedJSONResponse(api.ListPinsResponse{
 			References: []swarm.Address{swarm.MustParseHexAddress(rootHash)},
 		}),
 	)
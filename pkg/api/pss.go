@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -27,14 +28,208 @@ import (
 const (
 	writeDeadline   = 4 * time.Second // write deadline. should be smaller than the shutdown timeout on api close
 	readDeadline    = 4 * time.Second // read deadline. should be smaller than the shutdown timeout on api close
-	targetMaxLength = 3               // max target length in bytes, in order to prevent grieving by excess computation
+	targetMaxLength = pss.MaxTargetLength
 )
 
 func (s *server) pssPostHandler(w http.ResponseWriter, r *http.Request) {
 	topicVar := mux.Vars(r)["topic"]
 	topic := pss.NewTopic(topicVar)
 
-	targetsVar := mux.Vars(r)["targets"]
+	targets, ok := s.pssTargets(w, mux.Vars(r)["targets"])
+	if !ok {
+		return
+	}
+
+	recipientQueryString := r.URL.Query().Get("recipient")
+	recipient, ok := s.pssRecipient(w, topic, recipientQueryString)
+	if !ok {
+		return
+	}
+
+	mailbox, ok := s.pssFlag(w, r, "mailbox", recipientQueryString != "")
+	if !ok {
+		return
+	}
+	ack, ok := s.pssFlag(w, r, "ack", recipientQueryString != "")
+	if !ok {
+		return
+	}
+	if mailbox && ack {
+		s.logger.Debug("pss send: mailbox with ack")
+		s.logger.Error("pss send: mailbox with ack")
+		jsonhttp.BadRequest(w, "mailbox and ack can not be combined")
+		return
+	}
+
+	ctx, cancel, ok := s.pssReplyContext(w, r)
+	if !ok {
+		return
+	}
+	defer cancel()
+
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.logger.Debugf("pss read payload: %v", err)
+		s.logger.Error("pss read payload")
+		jsonhttp.InternalServerError(w, nil)
+		return
+	}
+	stamper, ok := s.pssStamper(w, r)
+	if !ok {
+		return
+	}
+
+	switch {
+	case mailbox:
+		err = s.pss.SendToMailbox(ctx, topic, payload, stamper, recipient, targets)
+	case ack:
+		err = s.pss.SendWithAck(ctx, topic, payload, stamper, recipient, targets)
+	default:
+		err = s.pss.Send(ctx, topic, payload, stamper, recipient, targets)
+	}
+	if err != nil {
+		s.logger.Debugf("pss send payload: %v. topic: %s", err, topicVar)
+		s.logger.Error("pss send payload")
+		s.pssSendError(w, err)
+		return
+	}
+
+	jsonhttp.Created(w, nil)
+}
+
+func (s *server) pssRequestHandler(w http.ResponseWriter, r *http.Request) {
+	topicVar := mux.Vars(r)["topic"]
+	topic := pss.NewTopic(topicVar)
+
+	targets, ok := s.pssTargets(w, r.URL.Query().Get("targets"))
+	if !ok {
+		return
+	}
+
+	// the response is signed by the recipient node
+	recipientQueryString := r.URL.Query().Get("recipient")
+	if recipientQueryString == "" {
+		s.logger.Debug("pss request: no recipient")
+		s.logger.Error("pss request: no recipient")
+		jsonhttp.BadRequest(w, "request requires a recipient")
+		return
+	}
+	recipient, ok := s.pssRecipient(w, topic, recipientQueryString)
+	if !ok {
+		return
+	}
+
+	ctx, cancel, ok := s.pssReplyContext(w, r)
+	if !ok {
+		return
+	}
+	defer cancel()
+
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.logger.Debugf("pss read payload: %v", err)
+		s.logger.Error("pss read payload")
+		jsonhttp.InternalServerError(w, nil)
+		return
+	}
+	stamper, ok := s.pssStamper(w, r)
+	if !ok {
+		return
+	}
+
+	resp, err := s.pss.Request(ctx, topic, payload, stamper, recipient, targets)
+	if err != nil {
+		s.logger.Debugf("pss request: %v. topic: %s", err, topicVar)
+		s.logger.Error("pss request")
+		s.pssSendError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Length", strconv.Itoa(len(resp)))
+	_, _ = w.Write(resp)
+}
+
+const (
+	defaultPssBenchmarkDuration = time.Second
+	maxPssBenchmarkDuration     = 10 * time.Second
+)
+
+type PssEstimateResponse struct {
+	ExpectedAttempts float64 `json:"expectedAttempts"`
+	MiningRate       float64 `json:"miningRate"`
+	// EstimatedTime is the expected time of mining in seconds.
+	EstimatedTime float64 `json:"estimatedTime"`
+}
+
+// pssEstimateHandler returns the expected time of mining a message for the
+// targets, which is the main part of the time to send it.
+func (s *server) pssEstimateHandler(w http.ResponseWriter, r *http.Request) {
+	targets, ok := s.pssTargets(w, mux.Vars(r)["targets"])
+	if !ok {
+		return
+	}
+
+	rate, err := s.pss.MiningRate(r.Context())
+	if err != nil {
+		s.logger.Debugf("pss estimate: mining rate: %v", err)
+		s.logger.Error("pss estimate: mining rate")
+		jsonhttp.InternalServerError(w, nil)
+		return
+	}
+	attempts, err := pss.ExpectedAttempts(targets)
+	if err != nil {
+		s.logger.Debugf("pss estimate: %v", err)
+		s.logger.Error("pss estimate")
+		jsonhttp.BadRequest(w, err.Error())
+		return
+	}
+	d, err := pss.EstimateMiningTime(targets, rate)
+	if err != nil {
+		s.logger.Debugf("pss estimate: %v", err)
+		s.logger.Error("pss estimate")
+		jsonhttp.InternalServerError(w, nil)
+		return
+	}
+
+	jsonhttp.OK(w, PssEstimateResponse{
+		ExpectedAttempts: attempts,
+		MiningRate:       rate,
+		EstimatedTime:    d.Seconds(),
+	})
+}
+
+type PssBenchmarkResponse struct {
+	MiningRate float64 `json:"miningRate"`
+}
+
+// pssBenchmarkHandler measures the mining rate of the node, which is used
+// for the estimates.
+func (s *server) pssBenchmarkHandler(w http.ResponseWriter, r *http.Request) {
+	d := defaultPssBenchmarkDuration
+	if v := r.URL.Query().Get("duration"); v != "" {
+		var err error
+		d, err = time.ParseDuration(v)
+		if err != nil || d <= 0 || d > maxPssBenchmarkDuration {
+			s.logger.Debugf("pss benchmark: duration %q: %v", v, err)
+			s.logger.Error("pss benchmark: duration")
+			jsonhttp.BadRequest(w, fmt.Sprintf("duration must be positive and at most %s", maxPssBenchmarkDuration))
+			return
+		}
+	}
+
+	rate, err := s.pss.BenchmarkMining(r.Context(), d)
+	if err != nil {
+		s.logger.Debugf("pss benchmark: %v", err)
+		s.logger.Error("pss benchmark")
+		jsonhttp.InternalServerError(w, nil)
+		return
+	}
+	jsonhttp.OK(w, PssBenchmarkResponse{MiningRate: rate})
+}
+
+// pssTargets parses the comma separated hex encoded targets.
+func (s *server) pssTargets(w http.ResponseWriter, targetsVar string) (pss.Targets, bool) {
 	var targets pss.Targets
 	tgts := strings.Split(targetsVar, ",")
 
@@ -44,47 +239,88 @@ func (s *server) pssPostHandler(w http.ResponseWriter, r *http.Request) {
 			s.logger.Debugf("pss send: bad target (%s): %v", target, err)
 			s.logger.Errorf("pss send: bad target (%s): %v", target, err)
 			jsonhttp.BadRequest(w, "target is not valid hex string")
-			return
+			return nil, false
 		}
 		if len(target) > targetMaxLength {
 			s.logger.Debugf("pss send: bad target length: %d", len(target))
 			s.logger.Errorf("pss send: bad target length: %d", len(target))
 			jsonhttp.BadRequest(w, fmt.Sprintf("hex string target exceeds max length of %d", targetMaxLength*2))
-			return
+			return nil, false
 		}
 		targets = append(targets, target)
 	}
+	return targets, true
+}
 
-	recipientQueryString := r.URL.Query().Get("recipient")
-	var recipient *ecdsa.PublicKey
+// pssRecipient parses the hex encoded public key of the recipient, or
+// returns the public key for topic-based encryption if there is none.
+func (s *server) pssRecipient(w http.ResponseWriter, topic pss.Topic, recipientQueryString string) (*ecdsa.PublicKey, bool) {
 	if recipientQueryString == "" {
 		// use topic-based encryption
 		privkey := crypto.Secp256k1PrivateKeyFromBytes(topic[:])
-		recipient = &privkey.PublicKey
-	} else {
-		var err error
-		recipient, err = pss.ParseRecipient(recipientQueryString)
-		if err != nil {
-			s.logger.Debugf("pss recipient: %v", err)
-			s.logger.Error("pss recipient")
-			jsonhttp.BadRequest(w, nil)
-			return
-		}
+		return &privkey.PublicKey, true
 	}
+	recipient, err := pss.ParseRecipient(recipientQueryString)
+	if err != nil {
+		s.logger.Debugf("pss recipient: %v", err)
+		s.logger.Error("pss recipient")
+		jsonhttp.BadRequest(w, nil)
+		return nil, false
+	}
+	return recipient, true
+}
 
-	payload, err := io.ReadAll(r.Body)
+// pssFlag parses the boolean query parameter with the name. The mailbox is
+// read and the ack is sent by the recipient node, so these require the
+// recipient.
+func (s *server) pssFlag(w http.ResponseWriter, r *http.Request, name string, hasRecipient bool) (v, ok bool) {
+	q := r.URL.Query().Get(name)
+	if q == "" {
+		return false, true
+	}
+	v, err := strconv.ParseBool(q)
 	if err != nil {
-		s.logger.Debugf("pss read payload: %v", err)
-		s.logger.Error("pss read payload")
-		jsonhttp.InternalServerError(w, nil)
-		return
+		s.logger.Debugf("pss %s: %v", name, err)
+		s.logger.Errorf("pss %s", name)
+		jsonhttp.BadRequest(w, fmt.Sprintf("invalid %s value", name))
+		return false, false
+	}
+	if v && !hasRecipient {
+		s.logger.Debugf("pss %s: no recipient", name)
+		s.logger.Errorf("pss %s: no recipient", name)
+		jsonhttp.BadRequest(w, fmt.Sprintf("%s requires a recipient", name))
+		return false, false
+	}
+	return v, true
+}
+
+// pssReplyContext returns the request context limited by the timeout given
+// in the query, which is the time to wait for a reply.
+func (s *server) pssReplyContext(w http.ResponseWriter, r *http.Request) (context.Context, context.CancelFunc, bool) {
+	v := r.URL.Query().Get("timeout")
+	if v == "" {
+		ctx, cancel := context.WithCancel(r.Context())
+		return ctx, cancel, true
 	}
+	timeout, err := time.ParseDuration(v)
+	if err != nil || timeout <= 0 {
+		s.logger.Debugf("pss timeout: %v", err)
+		s.logger.Error("pss timeout")
+		jsonhttp.BadRequest(w, "invalid timeout value")
+		return nil, nil, false
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	return ctx, cancel, true
+}
+
+// pssStamper returns the stamper for the postage batch of the request.
+func (s *server) pssStamper(w http.ResponseWriter, r *http.Request) (postage.Stamper, bool) {
 	batch, err := requestPostageBatchId(r)
 	if err != nil {
 		s.logger.Debugf("pss: postage batch id: %v", err)
 		s.logger.Error("pss: postage batch id")
 		jsonhttp.BadRequest(w, "invalid postage batch id")
-		return
+		return nil, false
 	}
 	i, err := s.post.GetStampIssuer(batch)
 	if err != nil {
@@ -98,24 +334,57 @@ func (s *server) pssPostHandler(w http.ResponseWriter, r *http.Request) {
 		default:
 			jsonhttp.BadRequest(w, "postage stamp issuer")
 		}
-		return
+		return nil, false
+	}
+	return postage.NewStamper(i, s.signer), true
+}
+
+// pssSendError writes the response for an error of sending a message.
+func (s *server) pssSendError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, postage.ErrBucketFull):
+		jsonhttp.PaymentRequired(w, "batch is overissued")
+	case errors.Is(err, pss.ErrNoReply), errors.Is(err, context.DeadlineExceeded):
+		jsonhttp.GatewayTimeout(w, "no reply from recipient")
+	case errors.Is(err, pss.ErrNoMailbox):
+		jsonhttp.NotImplemented(w, "mailbox not available")
+	case errors.Is(err, pss.ErrNoReplier):
+		jsonhttp.NotImplemented(w, "replies not available")
+	default:
+		jsonhttp.InternalServerError(w, nil)
 	}
-	stamper := postage.NewStamper(i, s.signer)
+}
+
+type PssMessageResponse struct {
+	Index   uint64 `json:"index"`
+	Payload []byte `json:"payload"`
+}
+
+type PssInboxResponse struct {
+	Messages []PssMessageResponse `json:"messages"`
+}
+
+func (s *server) pssInboxHandler(w http.ResponseWriter, r *http.Request) {
+	topicVar := mux.Vars(r)["topic"]
+	topic := pss.NewTopic(topicVar)
 
-	err = s.pss.Send(r.Context(), topic, payload, stamper, recipient, targets)
+	msgs, err := s.pss.Inbox(r.Context(), topic)
 	if err != nil {
-		s.logger.Debugf("pss send payload: %v. topic: %s", err, topicVar)
-		s.logger.Error("pss send payload")
-		switch {
-		case errors.Is(err, postage.ErrBucketFull):
-			jsonhttp.PaymentRequired(w, "batch is overissued")
-		default:
-			jsonhttp.InternalServerError(w, nil)
+		s.logger.Debugf("pss inbox: %v. topic: %s", err, topicVar)
+		s.logger.Error("pss inbox")
+		if errors.Is(err, pss.ErrNoMailbox) {
+			jsonhttp.NotImplemented(w, "mailbox not available")
+			return
 		}
+		jsonhttp.InternalServerError(w, nil)
 		return
 	}
 
-	jsonhttp.Created(w, nil)
+	resp := PssInboxResponse{Messages: make([]PssMessageResponse, 0, len(msgs))}
+	for _, m := range msgs {
+		resp.Messages = append(resp.Messages, PssMessageResponse{Index: m.Index, Payload: m.Payload})
+	}
+	jsonhttp.OK(w, resp)
 }
 
 func (s *server) pssWsHandler(w http.ResponseWriter, r *http.Request) {
@@ -208,3 +477,147 @@ func (s *server) pumpWs(conn *websocket.Conn, t string) {
 		}
 	}
 }
+
+func (s *server) pssServeWsHandler(w http.ResponseWriter, r *http.Request) {
+	upgrader := websocket.Upgrader{
+		ReadBufferSize:  swarm.ChunkSize,
+		WriteBufferSize: swarm.ChunkSize,
+		CheckOrigin:     s.checkOrigin,
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.logger.Debugf("pss serve ws: upgrade: %v", err)
+		s.logger.Error("pss serve ws: cannot upgrade")
+		jsonhttp.InternalServerError(w, nil)
+		return
+	}
+
+	t := mux.Vars(r)["topic"]
+	s.wsWg.Add(1)
+	go s.pumpServeWs(conn, t)
+}
+
+// pssRequest is a request to be answered by a websocket client.
+type pssRequest struct {
+	payload []byte
+	respC   chan []byte
+}
+
+// pumpServeWs answers the requests with the topic with the messages of the
+// websocket client. Every request is written to the client, which replies
+// with the response before it gets the next request.
+func (s *server) pumpServeWs(conn *websocket.Conn, t string) {
+	defer s.wsWg.Done()
+
+	var (
+		reqC   = make(chan pssRequest)
+		readC  = make(chan []byte)
+		gone   = make(chan struct{})
+		done   = make(chan struct{})
+		topic  = pss.NewTopic(t)
+		ticker = time.NewTicker(s.WsPingPeriod)
+		err    error
+	)
+	defer func() {
+		close(done)
+		ticker.Stop()
+		_ = conn.Close()
+	}()
+	cleanup := s.pss.RegisterResponder(topic, func(ctx context.Context, m []byte) ([]byte, error) {
+		req := pssRequest{payload: m, respC: make(chan []byte, 1)}
+		select {
+		case reqC <- req:
+		case <-gone:
+			return nil, errors.New("client gone")
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		select {
+		case b := <-req.respC:
+			return b, nil
+		case <-gone:
+			return nil, errors.New("client gone")
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	})
+
+	defer cleanup()
+
+	conn.SetReadLimit(swarm.ChunkSize)
+	go func() {
+		defer close(gone)
+		for {
+			_, b, err := conn.ReadMessage()
+			if err != nil {
+				s.logger.Debugf("pss serve: client gone: %v", err)
+				return
+			}
+			select {
+			case readC <- b:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	// pending is the request which waits for the response of the client,
+	// no other request is written to the client until it is answered
+	var pending *pssRequest
+	for {
+		in := reqC
+		if pending != nil {
+			in = nil
+		}
+
+		select {
+		case req := <-in:
+			err = conn.SetWriteDeadline(time.Now().Add(writeDeadline))
+			if err != nil {
+				s.logger.Debugf("pss serve set write deadline: %v", err)
+				return
+			}
+			err = conn.WriteMessage(websocket.BinaryMessage, req.payload)
+			if err != nil {
+				s.logger.Debugf("pss serve write to websocket: %v", err)
+				return
+			}
+			pending = &req
+
+		case b := <-readC:
+			if pending == nil {
+				s.logger.Debug("pss serve: response without request")
+				continue
+			}
+			pending.respC <- b
+			pending = nil
+
+		case <-s.quit:
+			// shutdown
+			err = conn.SetWriteDeadline(time.Now().Add(writeDeadline))
+			if err != nil {
+				s.logger.Debugf("pss serve set write deadline: %v", err)
+				return
+			}
+			err = conn.WriteMessage(websocket.CloseMessage, []byte{})
+			if err != nil {
+				s.logger.Debugf("pss serve write close message: %v", err)
+			}
+			return
+		case <-gone:
+			// client gone
+			return
+		case <-ticker.C:
+			err = conn.SetWriteDeadline(time.Now().Add(writeDeadline))
+			if err != nil {
+				s.logger.Debugf("pss serve set write deadline: %v", err)
+				return
+			}
+			if err = conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				// error encountered while pinging client. client probably gone
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,339 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/pss"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// pssBufferSize is the number of recently received messages kept for
+	// a topic.
+	pssBufferSize = 128
+	// pssBufferIdleTimeoutDefault is the time for which messages of a
+	// topic are still received after its last client is gone, so that
+	// clients can resume without missing messages.
+	pssBufferIdleTimeoutDefault = 10 * time.Minute
+	defaultPssPollTimeout       = 30 * time.Second
+	maxPssPollTimeout           = 2 * time.Minute
+)
+
+var pssBufferIdleTimeout = pssBufferIdleTimeoutDefault
+
+const (
+	// pssCursorSeqBits is the number of the low bits of a cursor which
+	// number the messages of a buffer. The bits above them hold the
+	// generation of the buffer, so that cursors of a buffer which was
+	// freed are told apart from the cursors of the buffer of the same topic
+	// created after it. Cursors stay below 2^53, so that they are exact as
+	// numbers of JSON parsers which use floating point numbers.
+	pssCursorSeqBits = 32
+	pssCursorGenBits = 21
+)
+
+// pssBuffer keeps the recently received messages of a topic for clients
+// which are not connected all the time. Every message is numbered by a
+// cursor, which clients pass in order to get the messages after it.
+type pssBuffer struct {
+	mu     sync.Mutex
+	msgs   []pssBufferedMessage
+	gen    uint64        // generation in the high bits of the cursors
+	cursor uint64        // cursor of the last message
+	notify chan struct{} // closed on the next message
+
+	// guarded by the pssBuffersMu of the server
+	clients int
+	idle    *time.Timer // frees the buffer when it stays without clients
+	cleanup func()
+}
+
+type pssBufferedMessage struct {
+	cursor  uint64
+	payload []byte
+}
+
+func newPssBuffer() (*pssBuffer, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return nil, err
+	}
+	gen := binary.BigEndian.Uint64(b[:]) >> (64 - pssCursorGenBits) << pssCursorSeqBits
+	return &pssBuffer{
+		gen:    gen,
+		cursor: gen,
+		notify: make(chan struct{}),
+	}, nil
+}
+
+func (b *pssBuffer) add(payload []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.cursor++
+	b.msgs = append(b.msgs, pssBufferedMessage{cursor: b.cursor, payload: payload})
+	if len(b.msgs) > pssBufferSize {
+		b.msgs = b.msgs[1:]
+	}
+	close(b.notify)
+	b.notify = make(chan struct{})
+}
+
+// since returns the messages after the cursor and the cursor of the last
+// message. Missed reports whether messages after the cursor were already
+// dropped from the buffer, or may have been received by a buffer which was
+// freed since the client got the cursor. The returned channel is closed on
+// the next message.
+func (b *pssBuffer) since(cursor uint64) (msgs []pssBufferedMessage, last uint64, missed bool, notify <-chan struct{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case cursor == 0:
+		cursor = b.gen
+	case cursor&^(1<<pssCursorSeqBits-1) != b.gen || cursor > b.cursor:
+		// the cursor is of another buffer of the topic
+		cursor = b.gen
+		missed = true
+	}
+	for _, m := range b.msgs {
+		if m.cursor > cursor {
+			msgs = append(msgs, m)
+		}
+	}
+	if cursor > b.gen && len(b.msgs) > 0 && b.msgs[0].cursor > cursor+1 {
+		missed = true
+	}
+	return msgs, b.cursor, missed, b.notify
+}
+
+// acquirePssBuffer returns the buffer of the topic, which receives the
+// messages of the topic until it is without clients for
+// pssBufferIdleTimeout.
+func (s *server) acquirePssBuffer(topic pss.Topic) (*pssBuffer, error) {
+	s.pssBuffersMu.Lock()
+	defer s.pssBuffersMu.Unlock()
+
+	if s.pssBuffers == nil {
+		s.pssBuffers = make(map[pss.Topic]*pssBuffer)
+	}
+	b, ok := s.pssBuffers[topic]
+	if !ok {
+		var err error
+		if b, err = newPssBuffer(); err != nil {
+			return nil, err
+		}
+		b.cleanup = s.pss.Register(topic, func(_ context.Context, m []byte) {
+			b.add(m)
+		})
+		s.pssBuffers[topic] = b
+	}
+	if b.idle != nil {
+		b.idle.Stop()
+		b.idle = nil
+	}
+	b.clients++
+	return b, nil
+}
+
+// releasePssBuffer releases the buffer of the topic. When the last client
+// releases it, the handler of the topic is deregistered and the buffer is
+// freed after pssBufferIdleTimeout, unless it is acquired again before.
+func (s *server) releasePssBuffer(topic pss.Topic, b *pssBuffer) {
+	s.pssBuffersMu.Lock()
+	defer s.pssBuffersMu.Unlock()
+
+	b.clients--
+	if b.clients > 0 {
+		return
+	}
+	var idle *time.Timer
+	idle = time.AfterFunc(pssBufferIdleTimeout, func() {
+		s.pssBuffersMu.Lock()
+		defer s.pssBuffersMu.Unlock()
+
+		// the buffer was acquired again or freed on close
+		if b.idle != idle || s.pssBuffers[topic] != b {
+			return
+		}
+		b.cleanup()
+		delete(s.pssBuffers, topic)
+	})
+	b.idle = idle
+}
+
+type PssStreamMessage struct {
+	Cursor  uint64 `json:"cursor"`
+	Payload []byte `json:"payload"`
+}
+
+type PssPollResponse struct {
+	Messages []PssStreamMessage `json:"messages"`
+	// Cursor is the cursor to pass in order to get the next messages.
+	Cursor uint64 `json:"cursor"`
+	// Missed reports whether messages after the passed cursor were
+	// dropped before they were polled.
+	Missed bool `json:"missed"`
+}
+
+// pssPollHandler returns the received messages of the topic after the
+// cursor, waiting for the next message until the timeout if there are
+// none.
+func (s *server) pssPollHandler(w http.ResponseWriter, r *http.Request) {
+	topic := pss.NewTopic(mux.Vars(r)["topic"])
+
+	cursor, ok := s.pssCursor(w, r.URL.Query().Get("cursor"))
+	if !ok {
+		return
+	}
+	timeout := defaultPssPollTimeout
+	if v := r.URL.Query().Get("timeout"); v != "" {
+		var err error
+		timeout, err = time.ParseDuration(v)
+		if err != nil || timeout < 0 || timeout > maxPssPollTimeout {
+			s.logger.Debugf("pss poll: timeout %q: %v", v, err)
+			s.logger.Error("pss poll: timeout")
+			jsonhttp.BadRequest(w, fmt.Sprintf("timeout must be at most %s", maxPssPollTimeout))
+			return
+		}
+	}
+
+	b, err := s.acquirePssBuffer(topic)
+	if err != nil {
+		s.logger.Debugf("pss poll: buffer: %v", err)
+		s.logger.Error("pss poll: buffer")
+		jsonhttp.InternalServerError(w, nil)
+		return
+	}
+	defer s.releasePssBuffer(topic, b)
+
+	msgs, last, missed, notify := b.since(cursor)
+	if len(msgs) == 0 && timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
+		select {
+		case <-notify:
+			msgs, last, missed, _ = b.since(cursor)
+		case <-timer.C:
+		case <-r.Context().Done():
+			return
+		case <-s.quit:
+		}
+	}
+
+	resp := PssPollResponse{
+		Messages: make([]PssStreamMessage, 0, len(msgs)),
+		Cursor:   last,
+		Missed:   missed,
+	}
+	for _, m := range msgs {
+		resp.Messages = append(resp.Messages, PssStreamMessage{Cursor: m.cursor, Payload: m.payload})
+	}
+	jsonhttp.OK(w, resp)
+}
+
+// pssEventsHandler streams the received messages of the topic as server
+// sent events. The event id is the cursor of the message and the data is
+// the base64 encoded payload. A client resumes after the cursor given by
+// the Last-Event-ID header or the cursor query parameter, and gets a
+// missed event if messages after it were dropped.
+func (s *server) pssEventsHandler(w http.ResponseWriter, r *http.Request) {
+	topic := pss.NewTopic(mux.Vars(r)["topic"])
+
+	v := r.Header.Get("Last-Event-ID")
+	if v == "" {
+		v = r.URL.Query().Get("cursor")
+	}
+	cursor, ok := s.pssCursor(w, v)
+	if !ok {
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		s.logger.Error("pss events: streaming not supported")
+		jsonhttp.InternalServerError(w, nil)
+		return
+	}
+
+	b, err := s.acquirePssBuffer(topic)
+	if err != nil {
+		s.logger.Debugf("pss events: buffer: %v", err)
+		s.logger.Error("pss events: buffer")
+		jsonhttp.InternalServerError(w, nil)
+		return
+	}
+	defer s.releasePssBuffer(topic, b)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// disable buffering of proxies like nginx
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(s.WsPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		msgs, last, missed, notify := b.since(cursor)
+		if missed {
+			if _, err := fmt.Fprintf(w, "event: missed\ndata: %d\n\n", cursor); err != nil {
+				s.logger.Debugf("pss events: write: %v", err)
+				return
+			}
+		}
+		for _, m := range msgs {
+			if _, err := fmt.Fprintf(w, "id: %d\ndata: %s\n\n", m.cursor, base64.StdEncoding.EncodeToString(m.payload)); err != nil {
+				s.logger.Debugf("pss events: write: %v", err)
+				return
+			}
+		}
+		cursor = last
+		flusher.Flush()
+
+		select {
+		case <-notify:
+		case <-ticker.C:
+			// keep the connection open through proxies
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				s.logger.Debugf("pss events: write: %v", err)
+				return
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// pssCursor parses the cursor, which is zero if it is empty.
+func (s *server) pssCursor(w http.ResponseWriter, v string) (uint64, bool) {
+	if v == "" {
+		return 0, true
+	}
+	cursor, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		s.logger.Debugf("pss cursor: %v", err)
+		s.logger.Error("pss cursor")
+		jsonhttp.BadRequest(w, "invalid cursor")
+		return 0, false
+	}
+	return cursor, true
+}
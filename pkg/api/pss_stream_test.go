@@ -0,0 +1,308 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/pss"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+)
+
+func newPssStreamTest(t *testing.T) (pss.Interface, *ecdsa.PublicKey, *http.Client) {
+	t.Helper()
+
+	privkey, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := logging.New(io.Discard, 0)
+	p := pss.New(privkey, logger)
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Pss:          p,
+		Storer:       mock.NewStorer(),
+		Logger:       logger,
+		WsPingPeriod: 50 * time.Millisecond,
+	})
+	return p, &privkey.PublicKey, client
+}
+
+// deliverPss delivers the message to the pss instance as if it was
+// received from the network.
+func deliverPss(t *testing.T, p pss.Interface, recipient *ecdsa.PublicKey, msg []byte) {
+	t.Helper()
+
+	chunk, err := pss.Wrap(context.Background(), topic, msg, recipient, targets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.TryUnwrap(chunk)
+}
+
+// pollPss polls the messages of the topic after the cursor without waiting.
+func pollPss(t *testing.T, client *http.Client, cursor uint64) api.PssPollResponse {
+	t.Helper()
+
+	var resp api.PssPollResponse
+	jsonhttptest.Request(t, client, http.MethodGet, fmt.Sprintf("/pss/poll/testtopic?timeout=0s&cursor=%d", cursor), http.StatusOK,
+		jsonhttptest.WithUnmarshalJSONResponse(&resp),
+	)
+	return resp
+}
+
+func TestPssPoll(t *testing.T) {
+	p, recipient, client := newPssStreamTest(t)
+
+	// the cursor before the first message of the buffer
+	base := pollPss(t, client, 0).Cursor
+	jsonhttptest.Request(t, client, http.MethodGet, "/pss/poll/testtopic?timeout=10ms", http.StatusOK,
+		jsonhttptest.WithExpectedJSONResponse(api.PssPollResponse{
+			Messages: []api.PssStreamMessage{},
+			Cursor:   base,
+		}),
+	)
+
+	deliverPss(t, p, recipient, []byte("one"))
+	deliverPss(t, p, recipient, []byte("two"))
+
+	var resp api.PssPollResponse
+	for i := 0; i < 50 && resp.Cursor < base+2; i++ {
+		time.Sleep(10 * time.Millisecond)
+		jsonhttptest.Request(t, client, http.MethodGet, "/pss/poll/testtopic?timeout=0s", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+	}
+	if len(resp.Messages) != 2 || string(resp.Messages[0].Payload) != "one" || string(resp.Messages[1].Payload) != "two" {
+		t.Fatalf("got messages %v", resp.Messages)
+	}
+
+	jsonhttptest.Request(t, client, http.MethodGet, fmt.Sprintf("/pss/poll/testtopic?cursor=%d", base+1), http.StatusOK,
+		jsonhttptest.WithExpectedJSONResponse(api.PssPollResponse{
+			Messages: []api.PssStreamMessage{{Cursor: base + 2, Payload: []byte("two")}},
+			Cursor:   base + 2,
+		}),
+	)
+
+	t.Run("wait", func(t *testing.T) {
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			deliverPss(t, p, recipient, []byte("three"))
+		}()
+		jsonhttptest.Request(t, client, http.MethodGet, fmt.Sprintf("/pss/poll/testtopic?cursor=%d", base+2), http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(api.PssPollResponse{
+				Messages: []api.PssStreamMessage{{Cursor: base + 3, Payload: []byte("three")}},
+				Cursor:   base + 3,
+			}),
+		)
+	})
+
+	t.Run("other buffer", func(t *testing.T) {
+		// the cursor of a buffer which was freed, the messages are replayed
+		resp := pollPss(t, client, base^1<<40+5)
+		if !resp.Missed || len(resp.Messages) != 3 || resp.Cursor != base+3 {
+			t.Fatalf("got response %+v, want the missed messages up to cursor %d", resp, base+3)
+		}
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodGet, "/pss/poll/testtopic?cursor=abc", http.StatusBadRequest,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "invalid cursor",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodGet, "/pss/poll/testtopic?timeout=1h", http.StatusBadRequest,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "timeout must be at most 2m0s",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+}
+
+func TestPssEvents(t *testing.T) {
+	p, recipient, client := newPssStreamTest(t)
+
+	// receive a message before the client connects
+	base := pollPss(t, client, 0).Cursor
+	deliverPss(t, p, recipient, []byte("one"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/pss/events/testtopic", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("got status %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("got content type %q, want %q", ct, "text/event-stream")
+	}
+
+	// pings keep the connection open while there are no messages
+	lines := make(chan string)
+	pinged := make(chan struct{}, 1)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if l := scanner.Text(); strings.HasPrefix(l, ": ping") {
+				select {
+				case pinged <- struct{}{}:
+				default:
+				}
+				scanner.Scan() // the empty line ending the comment
+				continue
+			}
+			lines <- scanner.Text()
+		}
+	}()
+	expect := func(want ...string) {
+		t.Helper()
+		for _, w := range want {
+			select {
+			case l := <-lines:
+				if l != w {
+					t.Fatalf("got line %q, want %q", l, w)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("timeout waiting for line %q", w)
+			}
+		}
+	}
+
+	expect(fmt.Sprintf("id: %d", base+1), "data: "+base64.StdEncoding.EncodeToString([]byte("one")), "")
+	deliverPss(t, p, recipient, []byte("two"))
+	expect(fmt.Sprintf("id: %d", base+2), "data: "+base64.StdEncoding.EncodeToString([]byte("two")), "")
+
+	select {
+	case <-pinged:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no ping received")
+	}
+}
+
+func TestPssEventsResume(t *testing.T) {
+	p, recipient, client := newPssStreamTest(t)
+
+	base := pollPss(t, client, 0).Cursor
+	deliverPss(t, p, recipient, []byte("one"))
+	deliverPss(t, p, recipient, []byte("two"))
+
+	var poll api.PssPollResponse
+	for i := 0; i < 50 && poll.Cursor < base+2; i++ {
+		time.Sleep(10 * time.Millisecond)
+		jsonhttptest.Request(t, client, http.MethodGet, "/pss/poll/testtopic?timeout=0s", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&poll),
+		)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/pss/events/testtopic", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Last-Event-ID", fmt.Sprint(base+1))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	if !scanner.Scan() {
+		t.Fatal(scanner.Err())
+	}
+	if l, want := scanner.Text(), fmt.Sprintf("id: %d", base+2); l != want {
+		t.Fatalf("got line %q, want %q", l, want)
+	}
+}
+
+// registerCountingPss counts the handlers which are registered.
+type registerCountingPss struct {
+	pss.Interface
+	handlers int32
+}
+
+func (p *registerCountingPss) Register(topic pss.Topic, h pss.Handler) func() {
+	atomic.AddInt32(&p.handlers, 1)
+	cleanup := p.Interface.Register(topic, h)
+	return func() {
+		cleanup()
+		atomic.AddInt32(&p.handlers, -1)
+	}
+}
+
+func TestPssBufferIdle(t *testing.T) {
+	api.SetPssBufferIdleTimeout(time.Second)
+	defer api.ResetPssBufferIdleTimeout()
+
+	privkey, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := logging.New(io.Discard, 0)
+	p := &registerCountingPss{Interface: pss.New(privkey, logger)}
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Pss:    p,
+		Storer: mock.NewStorer(),
+		Logger: logger,
+	})
+
+	base := pollPss(t, client, 0).Cursor
+	deliverPss(t, p, &privkey.PublicKey, []byte("one"))
+
+	// the buffer is kept for the clients which resume in time
+	jsonhttptest.Request(t, client, http.MethodGet, fmt.Sprintf("/pss/poll/testtopic?timeout=1s&cursor=%d", base), http.StatusOK,
+		jsonhttptest.WithExpectedJSONResponse(api.PssPollResponse{
+			Messages: []api.PssStreamMessage{{Cursor: base + 1, Payload: []byte("one")}},
+			Cursor:   base + 1,
+		}),
+	)
+	if n := atomic.LoadInt32(&p.handlers); n != 1 {
+		t.Fatalf("got %d registered handlers, want 1", n)
+	}
+
+	// the handler is deregistered once the buffer is idle
+	for i := 0; atomic.LoadInt32(&p.handlers) != 0; i++ {
+		if i == 100 {
+			t.Fatal("handler of the idle buffer is still registered")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	// the message is not received without the buffer and the client is
+	// told that it may have missed messages
+	deliverPss(t, p, &privkey.PublicKey, []byte("two"))
+	if resp := pollPss(t, client, base+1); len(resp.Messages) != 0 || !resp.Missed {
+		t.Fatalf("got response %+v, want missed messages", resp)
+	}
+}
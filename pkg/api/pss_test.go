@@ -28,6 +28,9 @@ import (
 	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
 	"github.com/ethersphere/bee/pkg/pss"
 	"github.com/ethersphere/bee/pkg/pushsync"
+	pushsyncmock "github.com/ethersphere/bee/pkg/pushsync/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/storage/mock"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/gorilla/websocket"
@@ -294,6 +297,245 @@ func TestPssSend(t *testing.T) {
 			t.Fatalf("topic mismatch. want %v got %v", topic, string(receivedTopic[:]))
 		}
 	})
+
+	t.Run("mailbox", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodPost, "/pss/send/testtopic/12?mailbox=true&recipient="+recipient, http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(bytes.NewReader(payload)),
+		)
+		waitDone(t, &mtx, &done)
+		if !bytes.Equal(receivedBytes, payload) {
+			t.Fatalf("payload mismatch. want %v got %v", payload, receivedBytes)
+		}
+	})
+
+	t.Run("ack", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodPost, "/pss/send/testtopic/12?ack=true&timeout=10s&recipient="+recipient, http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(bytes.NewReader(payload)),
+		)
+		waitDone(t, &mtx, &done)
+		if !bytes.Equal(receivedBytes, payload) {
+			t.Fatalf("payload mismatch. want %v got %v", payload, receivedBytes)
+		}
+	})
+
+	t.Run("err - ack without recipient", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodPost, "/pss/send/testtopic/12?ack=true", http.StatusBadRequest,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(bytes.NewReader(payload)),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "ack requires a recipient",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+
+	t.Run("err - mailbox with ack", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodPost, "/pss/send/testtopic/12?ack=true&mailbox=true&recipient="+recipient, http.StatusBadRequest,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(bytes.NewReader(payload)),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "mailbox and ack can not be combined",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+
+	t.Run("err - bad timeout", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodPost, "/pss/send/testtopic/12?ack=true&timeout=soon&recipient="+recipient, http.StatusBadRequest,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(bytes.NewReader(payload)),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "invalid timeout value",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+
+	t.Run("err - mailbox without recipient", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodPost, "/pss/send/testtopic/12?mailbox=true", http.StatusBadRequest,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(bytes.NewReader(payload)),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "mailbox requires a recipient",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+}
+
+// TestPssRequest tests that requests are sent over http and the response is
+// returned.
+func TestPssRequest(t *testing.T) {
+	var (
+		privk, _       = crypto.GenerateSecp256k1Key()
+		publicKeyBytes = (*btcec.PublicKey)(&privk.PublicKey).SerializeCompressed()
+		recipient      = hex.EncodeToString(publicKeyBytes)
+
+		mp              = mockpost.New(mockpost.WithIssuer(postage.NewStampIssuer("", "", batchOk, big.NewInt(3), 11, 10, 1000, true)))
+		client, _, _, _ = newTestServer(t, testServerOptions{
+			Pss: newMockPss(func(context.Context, pss.Targets, swarm.Chunk) error {
+				return nil
+			}),
+			Storer: mock.NewStorer(),
+			Logger: logging.New(io.Discard, 0),
+			Post:   mp,
+		})
+	)
+
+	t.Run("ok", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodPost, "/pss/request/testtopic?targets=12&recipient="+recipient, http.StatusOK,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(bytes.NewReader(payload)),
+			jsonhttptest.WithExpectedResponse(payload),
+		)
+	})
+
+	t.Run("err - without recipient", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodPost, "/pss/request/testtopic?targets=12", http.StatusBadRequest,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(bytes.NewReader(payload)),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "request requires a recipient",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+
+	t.Run("err - bad targets", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodPost, "/pss/request/testtopic?targets=to&recipient="+recipient, http.StatusBadRequest,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(bytes.NewReader(payload)),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "target is not valid hex string",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+}
+
+func TestPssEstimate(t *testing.T) {
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Pss:    newMockPss(nil),
+		Storer: mock.NewStorer(),
+		Logger: logging.New(io.Discard, 0),
+	})
+
+	jsonhttptest.Request(t, client, http.MethodGet, "/pss/estimate/1234,5678", http.StatusOK,
+		jsonhttptest.WithExpectedJSONResponse(api.PssEstimateResponse{
+			ExpectedAttempts: 32768,
+			MiningRate:       1024,
+			EstimatedTime:    32,
+		}),
+	)
+
+	jsonhttptest.Request(t, client, http.MethodGet, "/pss/estimate/12,3456", http.StatusBadRequest,
+		jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+			Message: pss.ErrVarLenTargets.Error(),
+			Code:    http.StatusBadRequest,
+		}),
+	)
+}
+
+func TestPssBenchmark(t *testing.T) {
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Pss:    newMockPss(nil),
+		Storer: mock.NewStorer(),
+		Logger: logging.New(io.Discard, 0),
+	})
+
+	jsonhttptest.Request(t, client, http.MethodPost, "/pss/benchmark?duration=100ms", http.StatusOK,
+		jsonhttptest.WithExpectedJSONResponse(api.PssBenchmarkResponse{
+			MiningRate: 2048,
+		}),
+	)
+
+	jsonhttptest.Request(t, client, http.MethodPost, "/pss/benchmark?duration=1h", http.StatusBadRequest,
+		jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+			Message: "duration must be positive and at most 10s",
+			Code:    http.StatusBadRequest,
+		}),
+	)
+}
+
+// TestPssServe tests that requests are answered by the websocket client.
+func TestPssServe(t *testing.T) {
+	p := newMockPss(nil)
+	_, cl, _, _ := newTestServer(t, testServerOptions{
+		Pss:          p,
+		WsPath:       "/pss/serve/testtopic",
+		Storer:       mock.NewStorer(),
+		Logger:       logging.New(io.Discard, 0),
+		WsPingPeriod: 10 * time.Second,
+	})
+
+	var responder pss.Responder
+	select {
+	case responder = <-p.responderC:
+	case <-time.After(mTimeout):
+		t.Fatal("responder not registered")
+	}
+
+	go func() {
+		_, req, err := cl.ReadMessage()
+		if err != nil {
+			return
+		}
+		_ = cl.WriteMessage(websocket.BinaryMessage, append([]byte("re: "), req...))
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), mTimeout)
+	defer cancel()
+	resp, err := responder(ctx, []byte("ping"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(resp) != "re: ping" {
+		t.Fatalf("got response %q, want %q", resp, "re: ping")
+	}
+}
+
+// TestPssInbox tests that messages stored in the mailbox of the node are
+// returned once by the inbox endpoint.
+func TestPssInbox(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		logger = logging.New(io.Discard, 0)
+		storer = mock.NewStorer()
+
+		privk, _ = crypto.GenerateSecp256k1Key()
+		p        = pss.New(privk, logger)
+		sender   = pss.New(nil, logger)
+	)
+	p.SetMailbox(storer, statestore.NewStateStore())
+	sender.SetMailbox(storer, statestore.NewStateStore())
+	sender.SetPushSyncer(pushsyncmock.New(func(ctx context.Context, chunk swarm.Chunk) (*pushsync.Receipt, error) {
+		_, err := storer.Put(ctx, storage.ModePutSync, chunk)
+		return nil, err
+	}))
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Pss:    p,
+		Storer: storer,
+		Logger: logger,
+	})
+
+	stamper := postage.NewStamper(postage.NewStampIssuer("", "", batchOk, big.NewInt(3), 11, 10, 1000, true), crypto.NewDefaultSigner(privk))
+	if err := sender.SendToMailbox(ctx, topic, payload, stamper, &privk.PublicKey, targets); err != nil {
+		t.Fatal(err)
+	}
+
+	jsonhttptest.Request(t, client, http.MethodGet, "/pss/inbox/testtopic", http.StatusOK,
+		jsonhttptest.WithExpectedJSONResponse(api.PssInboxResponse{
+			Messages: []api.PssMessageResponse{{Index: 0, Payload: payload}},
+		}),
+	)
+	jsonhttptest.Request(t, client, http.MethodGet, "/pss/inbox/testtopic", http.StatusOK,
+		jsonhttptest.WithExpectedJSONResponse(api.PssInboxResponse{
+			Messages: []api.PssMessageResponse{},
+		}),
+	)
 }
 
 // TestPssPingPong tests that the websocket api adheres to the websocket standard
@@ -427,11 +669,12 @@ func newPssTest(t *testing.T, o opts) (pss.Interface, *ecdsa.PublicKey, *websock
 
 type pssSendFn func(context.Context, pss.Targets, swarm.Chunk) error
 type mpss struct {
-	f pssSendFn
+	f          pssSendFn
+	responderC chan pss.Responder
 }
 
 func newMockPss(f pssSendFn) *mpss {
-	return &mpss{f}
+	return &mpss{f: f, responderC: make(chan pss.Responder, 1)}
 }
 
 // Send arbitrary byte slice with the given topic to Targets.
@@ -443,6 +686,51 @@ func (m *mpss) Send(ctx context.Context, topic pss.Topic, payload []byte, _ post
 	return m.f(ctx, targets, chunk)
 }
 
+func (m *mpss) SendToMailbox(ctx context.Context, topic pss.Topic, payload []byte, stamper postage.Stamper, recipient *ecdsa.PublicKey, targets pss.Targets) error {
+	return m.Send(ctx, topic, payload, stamper, recipient, targets)
+}
+
+func (m *mpss) Inbox(_ context.Context, _ pss.Topic) ([]pss.Message, error) {
+	panic("not implemented") // TODO: Implement
+}
+
+func (m *mpss) SetMailbox(_ storage.Getter, _ storage.StateStorer) {
+	panic("not implemented") // TODO: Implement
+}
+
+func (m *mpss) SendWithAck(ctx context.Context, topic pss.Topic, payload []byte, stamper postage.Stamper, recipient *ecdsa.PublicKey, targets pss.Targets) error {
+	return m.Send(ctx, topic, payload, stamper, recipient, targets)
+}
+
+// Request sends the request and responds with the payload.
+func (m *mpss) Request(ctx context.Context, topic pss.Topic, payload []byte, stamper postage.Stamper, recipient *ecdsa.PublicKey, targets pss.Targets) ([]byte, error) {
+	if err := m.Send(ctx, topic, payload, stamper, recipient, targets); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (m *mpss) RegisterResponder(_ pss.Topic, r pss.Responder) func() {
+	m.responderC <- r
+	return func() {}
+}
+
+func (m *mpss) SetReplier(_ swarm.Address, _ func() (postage.Stamper, error)) {
+	panic("not implemented") // TODO: Implement
+}
+
+func (m *mpss) SetMiningWorkers(_ int) {
+	panic("not implemented") // TODO: Implement
+}
+
+func (m *mpss) MiningRate(_ context.Context) (float64, error) {
+	return 1024, nil
+}
+
+func (m *mpss) BenchmarkMining(_ context.Context, _ time.Duration) (float64, error) {
+	return 2048, nil
+}
+
 // Register a Handler for a given Topic.
 func (m *mpss) Register(_ pss.Topic, _ pss.Handler) func() {
 	panic("not implemented") // TODO: Implement
@@ -0,0 +1,179 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/manifest"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tracing"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// redirectsFilename is the name of the file in the root of an uploaded
+	// collection which defines redirect rules.
+	redirectsFilename = "_redirects"
+	// maxRedirectsFileSize limits the size of the redirects file.
+	maxRedirectsFileSize = 64 * 1024
+	// maxSymlinkHops is the maximal number of symbolic links followed
+	// when resolving a path.
+	maxSymlinkHops = 8
+
+	defaultRedirectStatus = http.StatusMovedPermanently
+)
+
+var (
+	errTooManySymlinks  = errors.New("too many levels of symbolic links")
+	errInvalidRedirects = errors.New("invalid redirects file")
+)
+
+// redirectRule is a single line of a redirects file.
+type redirectRule struct {
+	from   string
+	to     string
+	status int
+}
+
+// parseRedirects parses a redirects file. Each non empty line which is not
+// a comment consists of the source path, the target path or URL and an
+// optional redirect status code:
+//
+//	/old-page.html  /new-page.html  301
+//	/docs           https://docs.ethswarm.org
+func parseRedirects(r io.Reader) ([]redirectRule, error) {
+	var rules []redirectRule
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil, fmt.Errorf("redirects line %d: invalid number of fields", line)
+		}
+
+		rule := redirectRule{
+			from:   strings.TrimPrefix(path.Clean("/"+fields[0]), "/"),
+			to:     fields[1],
+			status: defaultRedirectStatus,
+		}
+		if rule.from == "" {
+			return nil, fmt.Errorf("redirects line %d: invalid source path", line)
+		}
+		if len(fields) == 3 {
+			status, err := strconv.Atoi(fields[2])
+			if err != nil || !isRedirectStatus(status) {
+				return nil, fmt.Errorf("redirects line %d: invalid status %q", line, fields[2])
+			}
+			rule.status = status
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, scanner.Err()
+}
+
+func isRedirectStatus(status int) bool {
+	switch status {
+	case http.StatusMovedPermanently,
+		http.StatusFound,
+		http.StatusSeeOther,
+		http.StatusTemporaryRedirect,
+		http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
+
+// isExternalTarget returns true if the redirect target is an absolute URL.
+func isExternalTarget(target string) bool {
+	u, err := url.Parse(target)
+	return err == nil && (u.Scheme != "" || u.Host != "")
+}
+
+// resolveTargetPath resolves an internal redirect target relative to the
+// directory of the entry path. Targets starting with a slash are relative
+// to the manifest root.
+func resolveTargetPath(entryPath, target string) string {
+	if !strings.HasPrefix(target, "/") {
+		target = path.Join(path.Dir("/"+entryPath), target)
+	}
+	p := strings.TrimPrefix(path.Clean(target), "/")
+	if strings.HasSuffix(target, "/") && p != "" {
+		p += "/"
+	}
+	return p
+}
+
+// serveManifestRedirect handles a manifest entry of redirect type. External
+// targets and entries with a status code are answered with an HTTP
+// redirect, symbolic links are followed and the content of their target is
+// served.
+func (s *server) serveManifestRedirect(
+	w http.ResponseWriter,
+	r *http.Request,
+	address swarm.Address,
+	m manifest.Interface,
+	entryPath string,
+	manifestEntry manifest.Entry,
+	etag bool,
+) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger)
+
+	for hops := 0; hops < maxSymlinkHops; hops++ {
+		target, status, ok := manifest.RedirectTarget(manifestEntry)
+		if !ok {
+			s.serveManifestEntry(w, r, address, m, entryPath, manifestEntry, etag)
+			return
+		}
+
+		if isExternalTarget(target) {
+			if status == 0 {
+				status = http.StatusFound
+			}
+			logger.Debugf("bzz download: redirecting %s to %s", entryPath, target)
+			http.Redirect(w, r, target, status)
+			return
+		}
+
+		targetPath := resolveTargetPath(entryPath, target)
+
+		if status != 0 {
+			// keep the part of the request path before the collection path
+			base := strings.TrimSuffix(r.URL.Path, mux.Vars(r)["path"])
+			u := url.URL{Path: base + targetPath}
+			logger.Debugf("bzz download: redirecting %s to %s", entryPath, u.String())
+			http.Redirect(w, r, u.String(), status)
+			return
+		}
+
+		e, err := m.Lookup(r.Context(), targetPath)
+		if err != nil {
+			logger.Debugf("bzz download: symbolic link %s target %s: %v", entryPath, targetPath, err)
+			logger.Error("bzz download: symbolic link target")
+			jsonhttp.NotFound(w, "path address not found")
+			return
+		}
+		entryPath, manifestEntry = targetPath, e
+	}
+
+	logger.Debugf("bzz download: symbolic link %s: %v", entryPath, errTooManySymlinks)
+	logger.Error("bzz download: symbolic link")
+	jsonhttp.NotFound(w, errTooManySymlinks)
+}
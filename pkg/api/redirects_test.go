@@ -0,0 +1,162 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/logging"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	smock "github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func TestBzzRedirects(t *testing.T) {
+	var (
+		storerMock      = smock.NewStorer()
+		logger          = logging.New(io.Discard, 0)
+		client, _, _, _ = newTestServer(t, testServerOptions{
+			Storer: storerMock,
+			Tags:   tags.NewTags(statestore.NewStateStore(), logger),
+			Logger: logger,
+			Post:   mockpost.New(mockpost.WithAcceptAll()),
+
+			PreventRedirect: true,
+		})
+	)
+
+	upload := func(t *testing.T, buf *bytes.Buffer, expectedStatus int) api.BzzUploadResponse {
+		t.Helper()
+
+		var resp api.BzzUploadResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz", expectedStatus,
+			jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "true"),
+			jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+			jsonhttptest.WithRequestBody(buf),
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+		return resp
+	}
+
+	resp := upload(t, tarWithLinks(t, []tarLink{
+		{name: "docs/guide.html", data: []byte("guide")},
+		{name: "docs/latest.html", link: "guide.html", typ: tar.TypeSymlink},
+		{name: "latest", link: "docs/latest.html", typ: tar.TypeSymlink},
+		{name: "copy.html", link: "docs/guide.html", typ: tar.TypeLink},
+		{name: "broken", link: "missing.html", typ: tar.TypeSymlink},
+		{name: "loop-a", link: "loop-b", typ: tar.TypeSymlink},
+		{name: "loop-b", link: "loop-a", typ: tar.TypeSymlink},
+		{name: "_redirects", data: []byte(`
+# moved pages
+/old.html   /docs/guide.html  301
+/tmp.html   docs/guide.html   307
+/external   https://ethswarm.org
+`)},
+	}), http.StatusCreated)
+	root := "/bzz/" + resp.Reference.String() + "/"
+
+	t.Run("symbolic link", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodGet, root+"docs/latest.html", http.StatusOK,
+			jsonhttptest.WithExpectedResponse([]byte("guide")),
+		)
+	})
+
+	t.Run("chained symbolic links", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodGet, root+"latest", http.StatusOK,
+			jsonhttptest.WithExpectedResponse([]byte("guide")),
+		)
+	})
+
+	t.Run("hard link", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodGet, root+"copy.html", http.StatusOK,
+			jsonhttptest.WithExpectedResponse([]byte("guide")),
+		)
+	})
+
+	t.Run("broken symbolic link", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodGet, root+"broken", http.StatusNotFound)
+	})
+
+	t.Run("symbolic link loop", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodGet, root+"loop-a", http.StatusNotFound)
+	})
+
+	t.Run("redirect", func(t *testing.T) {
+		for _, tc := range []struct {
+			path     string
+			status   int
+			location string
+		}{
+			{"old.html", http.StatusMovedPermanently, root + "docs/guide.html"},
+			{"tmp.html", http.StatusTemporaryRedirect, root + "docs/guide.html"},
+			{"external", http.StatusMovedPermanently, "https://ethswarm.org"},
+		} {
+			header := jsonhttptest.Request(t, client, http.MethodGet, root+tc.path, tc.status)
+			if got := header.Get("Location"); got != tc.location {
+				t.Errorf("%s: got location %q, want %q", tc.path, got, tc.location)
+			}
+		}
+	})
+
+	t.Run("redirects file is served", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodGet, root+"_redirects", http.StatusOK)
+	})
+
+	t.Run("invalid redirects file", func(t *testing.T) {
+		upload(t, tarWithLinks(t, []tarLink{
+			{name: "index.html", data: []byte("index")},
+			{name: "_redirects", data: []byte("/a /b 200\n")},
+		}), http.StatusBadRequest)
+	})
+}
+
+type tarLink struct {
+	name string
+	data []byte
+	link string
+	typ  byte
+}
+
+// tarWithLinks creates a tar archive with regular files and links.
+func tarWithLinks(t *testing.T, files []tarLink) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+
+	for _, file := range files {
+		hdr := &tar.Header{
+			Name:     file.name,
+			Mode:     0600,
+			Size:     int64(len(file.data)),
+			Linkname: file.link,
+			Typeflag: file.typ,
+		}
+		if file.typ != 0 {
+			hdr.Mode = 0777
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(file.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return &buf
+}
@@ -0,0 +1,140 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/ethersphere/bee/pkg/encryption"
+	"github.com/ethersphere/bee/pkg/file/joiner"
+	"github.com/ethersphere/bee/pkg/file/loadsave"
+	"github.com/ethersphere/bee/pkg/file/pipeline"
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/manifest"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tracing"
+	"github.com/gorilla/mux"
+)
+
+// bytesReencryptHandler encrypts the data referenced by the address again,
+// with the encryption key from the request or with random keys, and returns
+// the new reference.
+func (s *server) bytesReencryptHandler(w http.ResponseWriter, r *http.Request) {
+	s.reencryptHandler(w, r, "bytes reencrypt", func(ctx context.Context, newPipeline func() pipeline.Interface, address swarm.Address) (swarm.Address, error) {
+		return s.reencrypt(ctx, newPipeline, address, requestCDC(r))
+	}, func(reference swarm.Address) interface{} {
+		return BytesPostResponse{Reference: reference}
+	})
+}
+
+// manifestReencryptHandler encrypts the manifest referenced by the address
+// and all of its entries again, with the encryption key from the request or
+// with random keys, and returns the reference of the new manifest.
+func (s *server) manifestReencryptHandler(w http.ResponseWriter, r *http.Request) {
+	s.reencryptHandler(w, r, "manifest reencrypt", func(ctx context.Context, newPipeline func() pipeline.Interface, address swarm.Address) (swarm.Address, error) {
+		src := loadsave.NewReadonly(s.storer)
+		dst := loadsave.New(s.storer, newPipeline)
+		return manifest.Rewrite(ctx, src, dst, address, true, func(_ string, e manifest.Entry) (swarm.Address, error) {
+			cdc := e.Metadata()[manifest.EntryMetadataChunkingKey] == manifest.ChunkingCDC
+			return s.reencrypt(ctx, newPipeline, e.Reference(), cdc)
+		})
+	}, func(reference swarm.Address) interface{} {
+		return BzzUploadResponse{Reference: reference}
+	})
+}
+
+type reencryptFunc func(ctx context.Context, newPipeline func() pipeline.Interface, address swarm.Address) (swarm.Address, error)
+
+func (s *server) reencryptHandler(w http.ResponseWriter, r *http.Request, name string, reencryptFn reencryptFunc, responseFn func(swarm.Address) interface{}) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger)
+
+	nameOrHex := mux.Vars(r)["address"]
+	address, err := s.resolveNameOrAddress(nameOrHex)
+	if err != nil {
+		logger.Debugf("%s: parse address %s: %v", name, nameOrHex, err)
+		logger.Errorf("%s: parse address", name)
+		jsonhttp.NotFound(w, nil)
+		return
+	}
+
+	key, err := requestEncryptionKey(r)
+	if err != nil {
+		logger.Debugf("%s: encryption key: %v", name, err)
+		logger.Errorf("%s: encryption key", name)
+		jsonhttp.BadRequest(w, err)
+		return
+	}
+
+	putter, wait, err := s.newStamperPutter(r)
+	if err != nil {
+		logger.Debugf("%s: putter: %v", name, err)
+		logger.Errorf("%s: putter", name)
+		switch {
+		case errors.Is(err, postage.ErrNotFound):
+			jsonhttp.BadRequest(w, "batch not found")
+		case errors.Is(err, postage.ErrNotUsable):
+			jsonhttp.BadRequest(w, "batch not usable yet")
+		default:
+			jsonhttp.BadRequest(w, nil)
+		}
+		return
+	}
+
+	// the content is always encrypted, with random keys if no key is given
+	ctx, mode := r.Context(), requestModePut(r)
+	newPipeline := func() pipeline.Interface {
+		if key != nil {
+			return builder.NewEncryptionPipelineBuilder(ctx, putter, mode, encryption.NewConvergentChunkEncrypter(key))
+		}
+		return builder.NewPipelineBuilder(ctx, putter, mode, true)
+	}
+
+	reference, err := reencryptFn(ctx, newPipeline, address)
+	if err != nil {
+		logger.Debugf("%s: %s: %v", name, address, err)
+		logger.Errorf("%s: failed", name)
+		switch {
+		case errors.Is(err, storage.ErrNotFound):
+			jsonhttp.NotFound(w, nil)
+		case errors.Is(err, postage.ErrBucketFull):
+			jsonhttp.PaymentRequired(w, "batch is overissued")
+		default:
+			jsonhttp.InternalServerError(w, nil)
+		}
+		return
+	}
+
+	if err = wait(); err != nil {
+		logger.Debugf("%s: sync chunks: %v", name, err)
+		logger.Errorf("%s: sync chunks", name)
+		jsonhttp.InternalServerError(w, nil)
+		return
+	}
+
+	jsonhttp.Created(w, responseFn(reference))
+}
+
+// reencrypt reads the content referenced by the address and stores it with
+// a pipeline created by newPipeline. The content is split again on the node,
+// which results in the same tree of chunks under the new encryption.
+func (s *server) reencrypt(ctx context.Context, newPipeline func() pipeline.Interface, address swarm.Address, cdc bool) (swarm.Address, error) {
+	newJoiner := joiner.New
+	pipe := newPipeline()
+	if cdc {
+		newJoiner = joiner.NewCDC
+		pipe = builder.NewCDCPipelineBuilder(newPipeline)
+	}
+
+	reader, _, err := newJoiner(ctx, s.storer, address)
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+	return builder.FeedPipeline(ctx, pipe, reader)
+}
@@ -0,0 +1,145 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/encryption"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/logging"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	smock "github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+)
+
+func TestEncryptionKey(t *testing.T) {
+	const (
+		key      = "8abf1502f557f15026716030fb6384792583daf39608a3cd02ff2f47e9bc6e49"
+		otherKey = "0000000000000000000000000000000000000000000000000000000000000001"
+	)
+
+	var (
+		storerMock      = smock.NewStorer()
+		logger          = logging.New(io.Discard, 0)
+		client, _, _, _ = newTestServer(t, testServerOptions{
+			Storer: storerMock,
+			Tags:   tags.NewTags(statestore.NewStateStore(), logger),
+			Logger: logger,
+			Post:   mockpost.New(mockpost.WithAcceptAll()),
+		})
+		data = bytes.Repeat([]byte("swarm "), 2000)
+	)
+
+	uploadBytes := func(t *testing.T, headers ...string) swarm.Address {
+		t.Helper()
+
+		opts := []jsonhttptest.Option{
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestBody(bytes.NewReader(data)),
+		}
+		for i := 0; i < len(headers); i += 2 {
+			opts = append(opts, jsonhttptest.WithRequestHeader(headers[i], headers[i+1]))
+		}
+		var resp api.BytesPostResponse
+		opts = append(opts, jsonhttptest.WithUnmarshalJSONResponse(&resp))
+		jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusCreated, opts...)
+		return resp.Reference
+	}
+
+	t.Run("deterministic upload", func(t *testing.T) {
+		ref1 := uploadBytes(t, api.SwarmEncryptionKeyHeader, key)
+		ref2 := uploadBytes(t, api.SwarmEncryptionKeyHeader, key)
+		ref3 := uploadBytes(t, api.SwarmEncryptionKeyHeader, otherKey)
+
+		if len(ref1.Bytes()) != encryption.ReferenceSize {
+			t.Fatalf("got reference length %d, want %d", len(ref1.Bytes()), encryption.ReferenceSize)
+		}
+		if !ref1.Equal(ref2) {
+			t.Fatalf("got different references %s and %s for the same key", ref1, ref2)
+		}
+		if ref1.Equal(ref3) {
+			t.Fatal("got the same reference for different keys")
+		}
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/bytes/"+ref1.String(), http.StatusOK,
+			jsonhttptest.WithExpectedResponse(data),
+		)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		for _, k := range []string{"xyz", "0102"} {
+			jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusBadRequest,
+				jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+				jsonhttptest.WithRequestHeader(api.SwarmEncryptionKeyHeader, k),
+				jsonhttptest.WithRequestBody(bytes.NewReader(data)),
+			)
+		}
+	})
+
+	t.Run("reencrypt bytes", func(t *testing.T) {
+		ref := uploadBytes(t, api.SwarmEncryptHeader, "true")
+
+		var resp api.BytesPostResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/bytes/"+ref.String()+"/reencrypt", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmEncryptionKeyHeader, otherKey),
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+
+		// the result is the same as a direct upload with the new key
+		if want := uploadBytes(t, api.SwarmEncryptionKeyHeader, otherKey); !resp.Reference.Equal(want) {
+			t.Fatalf("got reference %s, want %s", resp.Reference, want)
+		}
+		jsonhttptest.Request(t, client, http.MethodGet, "/bytes/"+resp.Reference.String(), http.StatusOK,
+			jsonhttptest.WithExpectedResponse(data),
+		)
+	})
+
+	t.Run("reencrypt manifest", func(t *testing.T) {
+		var upload api.BzzUploadResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/bzz", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmCollectionHeader, "true"),
+			jsonhttptest.WithRequestHeader(api.SwarmIndexDocumentHeader, "index.html"),
+			jsonhttptest.WithRequestHeader("Content-Type", api.ContentTypeTar),
+			jsonhttptest.WithRequestBody(tarFiles(t, []f{
+				{data: []byte("<h1>index</h1>"), name: "index.html"},
+				{data: data, name: "data.txt", dir: "files"},
+			})),
+			jsonhttptest.WithUnmarshalJSONResponse(&upload),
+		)
+
+		var resp api.BzzUploadResponse
+		jsonhttptest.Request(t, client, http.MethodPost, "/manifests/"+upload.Reference.String()+"/reencrypt", http.StatusCreated,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.SwarmEncryptionKeyHeader, key),
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+
+		if len(resp.Reference.Bytes()) != encryption.ReferenceSize || resp.Reference.Equal(upload.Reference) {
+			t.Fatalf("unexpected reference %s", resp.Reference)
+		}
+		root := "/bzz/" + resp.Reference.String() + "/"
+		jsonhttptest.Request(t, client, http.MethodGet, root, http.StatusOK,
+			jsonhttptest.WithExpectedResponse([]byte("<h1>index</h1>")),
+		)
+		jsonhttptest.Request(t, client, http.MethodGet, root+"files/data.txt", http.StatusOK,
+			jsonhttptest.WithExpectedResponse(data),
+		)
+	})
+
+	t.Run("reencrypt not found", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodPost, "/bytes/"+swarm.NewAddress(make([]byte, 32)).String()+"/reencrypt", http.StatusNotFound,
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		)
+	})
+}
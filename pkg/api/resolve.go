@@ -0,0 +1,93 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/resolver"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tracing"
+	"github.com/gorilla/mux"
+)
+
+type ResolveStep struct {
+	Resolver string `json:"resolver"`
+	Endpoint string `json:"endpoint,omitempty"`
+	Cached   bool   `json:"cached"`
+	Error    string `json:"error,omitempty"`
+}
+
+type ResolveResponse struct {
+	Name      string        `json:"name"`
+	Reference swarm.Address `json:"reference"`
+	Chain     []ResolveStep `json:"chain"`
+	Cached    bool          `json:"cached"`
+	// CacheAge is the age of the cached result in seconds.
+	CacheAge int64 `json:"cacheAge"`
+}
+
+// resolveHandler resolves a name and returns the reference together with
+// the resolvers which were asked and the age of the cached result. If the
+// name can not be resolved, the response contains the errors of the chain.
+func (s *server) resolveHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger)
+	name := mux.Vars(r)["name"]
+
+	if addr, err := swarm.ParseHexAddress(name); err == nil {
+		jsonhttp.OK(w, ResolveResponse{
+			Name:      name,
+			Reference: addr,
+			Chain:     []ResolveStep{},
+		})
+		return
+	}
+
+	if s.resolver == nil {
+		logger.Debugf("resolve: %s: %v", name, errNoResolver)
+		logger.Error("resolve: no resolver")
+		jsonhttp.NotFound(w, errNoResolver)
+		return
+	}
+
+	var (
+		res resolver.Resolution
+		err error
+	)
+	if inspector, ok := s.resolver.(resolver.Inspector); ok {
+		res, err = inspector.ResolveWithInfo(name)
+	} else {
+		res.Address, err = s.resolver.Resolve(name)
+	}
+
+	resp := ResolveResponse{
+		Name:      name,
+		Reference: res.Address,
+		Chain:     make([]ResolveStep, 0, len(res.Chain)),
+		Cached:    res.Cached,
+		CacheAge:  int64(res.CacheAge.Seconds()),
+	}
+	for _, step := range res.Chain {
+		rs := ResolveStep{
+			Resolver: step.Resolver,
+			Endpoint: step.Endpoint,
+			Cached:   step.Cached,
+		}
+		if step.Err != nil {
+			rs.Error = step.Err.Error()
+		}
+		resp.Chain = append(resp.Chain, rs)
+	}
+
+	if err != nil {
+		logger.Debugf("resolve: %s: %v", name, err)
+		logger.Errorf("resolve: %s: failed", name)
+		jsonhttp.NotFound(w, resp)
+		return
+	}
+
+	jsonhttp.OK(w, resp)
+}
@@ -0,0 +1,77 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/resolver/multiresolver"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestResolve(t *testing.T) {
+	addr := swarm.MustParseHexAddress("aaf2a3c9e8dcfc4c5ba9ccbba46a7dd8f1e4e5b4a4e5b2d93b1c1c4dab7c4e6f")
+
+	path := filepath.Join(t.TempDir(), "names")
+	if err := os.WriteFile(path, []byte("example.eth "+addr.String()+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	mr := multiresolver.NewMultiResolver(
+		multiresolver.WithConnectionConfigs([]multiresolver.ConnectionConfig{
+			{Endpoint: "file://" + path},
+		}),
+		multiresolver.WithCacheTTL(time.Minute, time.Minute),
+	)
+
+	client, _, _, _ := newTestServer(t, testServerOptions{
+		Resolver: mr,
+	})
+
+	t.Run("name", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodGet, "/resolve/example.eth", http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(api.ResolveResponse{
+				Name:      "example.eth",
+				Reference: addr,
+				Chain: []api.ResolveStep{
+					{Resolver: "static", Endpoint: "names"},
+				},
+			}),
+		)
+
+		var resp api.ResolveResponse
+		jsonhttptest.Request(t, client, http.MethodGet, "/resolve/example.eth", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+		if !resp.Cached || !resp.Chain[0].Cached || !resp.Reference.Equal(addr) {
+			t.Fatalf("got uncached response %+v", resp)
+		}
+	})
+
+	t.Run("address", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodGet, "/resolve/"+addr.String(), http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(api.ResolveResponse{
+				Name:      addr.String(),
+				Reference: addr,
+				Chain:     []api.ResolveStep{},
+			}),
+		)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		var resp api.ResolveResponse
+		jsonhttptest.Request(t, client, http.MethodGet, "/resolve/other.eth", http.StatusNotFound,
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+		if len(resp.Chain) != 1 || resp.Chain[0].Error == "" {
+			t.Fatalf("got chain %+v, want a failed step", resp.Chain)
+		}
+	})
+}
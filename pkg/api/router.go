@@ -77,9 +77,45 @@ func (s *server) setupRouting() {
 			web.FinalHandlerFunc(s.bytesGetHandler),
 		),
 	})
+	handle("/bytes/{address}/reencrypt", web.ChainHandlers(
+		s.gatewayModeForbidEndpointHandler,
+		web.FinalHandler(jsonhttp.MethodHandler{
+			"POST": web.ChainHandlers(
+				s.newTracingHandler("bytes-reencrypt"),
+				web.FinalHandlerFunc(s.bytesReencryptHandler),
+			),
+		})),
+	)
+
+	handle("/uploads", jsonhttp.MethodHandler{
+		"POST": web.ChainHandlers(
+			s.newTracingHandler("uploads-create"),
+			web.FinalHandlerFunc(s.uploadCreateHandler),
+		),
+	})
+	handle("/uploads/{id}", jsonhttp.MethodHandler{
+		"GET": web.ChainHandlers(
+			s.newTracingHandler("uploads-get"),
+			web.FinalHandlerFunc(s.uploadGetHandler),
+		),
+		"HEAD": web.ChainHandlers(
+			s.newTracingHandler("uploads-head"),
+			web.FinalHandlerFunc(s.uploadHeadHandler),
+		),
+		"PATCH": web.ChainHandlers(
+			s.contentLengthMetricMiddleware(),
+			s.newTracingHandler("uploads-patch"),
+			web.FinalHandlerFunc(s.uploadPatchHandler),
+		),
+		"DELETE": web.ChainHandlers(
+			s.newTracingHandler("uploads-delete"),
+			web.FinalHandlerFunc(s.uploadDeleteHandler),
+		),
+	})
 
 	handle("/chunks", jsonhttp.MethodHandler{
 		"POST": web.ChainHandlers(
+			s.newTracingHandler("chunks-upload"),
 			jsonhttp.NewMaxBodyBytesHandler(swarm.ChunkWithSpanSize),
 			web.FinalHandlerFunc(s.chunkUploadHandler),
 		),
@@ -91,19 +127,27 @@ func (s *server) setupRouting() {
 	))
 
 	handle("/chunks/{addr}", jsonhttp.MethodHandler{
-		"GET": http.HandlerFunc(s.chunkGetHandler),
+		"GET": web.ChainHandlers(
+			s.newTracingHandler("chunks-download"),
+			web.FinalHandlerFunc(s.chunkGetHandler),
+		),
 	})
 
 	handle("/soc/{owner}/{id}", jsonhttp.MethodHandler{
 		"POST": web.ChainHandlers(
+			s.newTracingHandler("soc-upload"),
 			jsonhttp.NewMaxBodyBytesHandler(swarm.ChunkWithSpanSize),
 			web.FinalHandlerFunc(s.socUploadHandler),
 		),
 	})
 
 	handle("/feeds/{owner}/{topic}", jsonhttp.MethodHandler{
-		"GET": http.HandlerFunc(s.feedGetHandler),
+		"GET": web.ChainHandlers(
+			s.newTracingHandler("feeds-download"),
+			web.FinalHandlerFunc(s.feedGetHandler),
+		),
 		"POST": web.ChainHandlers(
+			s.newTracingHandler("feeds-upload"),
 			jsonhttp.NewMaxBodyBytesHandler(swarm.ChunkWithSpanSize),
 			web.FinalHandlerFunc(s.feedPostHandler),
 		),
@@ -133,6 +177,35 @@ func (s *server) setupRouting() {
 		),
 	})
 
+	handle("/manifests/{address}/diff/{target}", jsonhttp.MethodHandler{
+		"GET": web.ChainHandlers(
+			s.newTracingHandler("manifest-diff"),
+			web.FinalHandlerFunc(s.manifestDiffHandler),
+		),
+	})
+	handle("/manifests/{address}/merge/{overlay}", jsonhttp.MethodHandler{
+		"POST": web.ChainHandlers(
+			s.newTracingHandler("manifest-merge"),
+			web.FinalHandlerFunc(s.manifestMergeHandler),
+		),
+	})
+	handle("/manifests/{address}/reencrypt", web.ChainHandlers(
+		s.gatewayModeForbidEndpointHandler,
+		web.FinalHandler(jsonhttp.MethodHandler{
+			"POST": web.ChainHandlers(
+				s.newTracingHandler("manifest-reencrypt"),
+				web.FinalHandlerFunc(s.manifestReencryptHandler),
+			),
+		})),
+	)
+
+	handle("/resolve/{name}", jsonhttp.MethodHandler{
+		"GET": web.ChainHandlers(
+			s.newTracingHandler("resolve"),
+			web.FinalHandlerFunc(s.resolveHandler),
+		),
+	})
+
 	handle("/pss/send/{topic}/{targets}", web.ChainHandlers(
 		s.gatewayModeForbidEndpointHandler,
 		web.FinalHandler(jsonhttp.MethodHandler{
@@ -143,6 +216,56 @@ func (s *server) setupRouting() {
 		})),
 	)
 
+	handle("/pss/estimate/{targets}", web.ChainHandlers(
+		s.gatewayModeForbidEndpointHandler,
+		web.FinalHandler(jsonhttp.MethodHandler{
+			"GET": http.HandlerFunc(s.pssEstimateHandler),
+		})),
+	)
+
+	handle("/pss/benchmark", web.ChainHandlers(
+		s.gatewayModeForbidEndpointHandler,
+		web.FinalHandler(jsonhttp.MethodHandler{
+			"POST": http.HandlerFunc(s.pssBenchmarkHandler),
+		})),
+	)
+
+	handle("/pss/request/{topic}", web.ChainHandlers(
+		s.gatewayModeForbidEndpointHandler,
+		web.FinalHandler(jsonhttp.MethodHandler{
+			"POST": web.ChainHandlers(
+				jsonhttp.NewMaxBodyBytesHandler(swarm.ChunkSize),
+				web.FinalHandlerFunc(s.pssRequestHandler),
+			),
+		})),
+	)
+
+	handle("/pss/serve/{topic}", web.ChainHandlers(
+		s.gatewayModeForbidEndpointHandler,
+		web.FinalHandlerFunc(s.pssServeWsHandler),
+	))
+
+	handle("/pss/inbox/{topic}", web.ChainHandlers(
+		s.gatewayModeForbidEndpointHandler,
+		web.FinalHandler(jsonhttp.MethodHandler{
+			"GET": http.HandlerFunc(s.pssInboxHandler),
+		})),
+	)
+
+	handle("/pss/poll/{topic}", web.ChainHandlers(
+		s.gatewayModeForbidEndpointHandler,
+		web.FinalHandler(jsonhttp.MethodHandler{
+			"GET": http.HandlerFunc(s.pssPollHandler),
+		})),
+	)
+
+	handle("/pss/events/{topic}", web.ChainHandlers(
+		s.gatewayModeForbidEndpointHandler,
+		web.FinalHandler(jsonhttp.MethodHandler{
+			"GET": http.HandlerFunc(s.pssEventsHandler),
+		})),
+	)
+
 	handle("/pss/subscribe/{topic}", web.ChainHandlers(
 		s.gatewayModeForbidEndpointHandler,
 		web.FinalHandlerFunc(s.pssWsHandler),
@@ -207,8 +330,8 @@ func (s *server) setupRouting() {
 				if o := r.Header.Get("Origin"); o != "" && s.checkOrigin(r) {
 					w.Header().Set("Access-Control-Allow-Credentials", "true")
 					w.Header().Set("Access-Control-Allow-Origin", o)
-					w.Header().Set("Access-Control-Allow-Headers", "User-Agent, Origin, Accept, Authorization, Content-Type, X-Requested-With, Decompressed-Content-Length, Access-Control-Request-Headers, Access-Control-Request-Method, Swarm-Tag, Swarm-Pin, Swarm-Encrypt, Swarm-Index-Document, Swarm-Error-Document, Swarm-Collection, Swarm-Postage-Batch-Id, Gas-Price")
-					w.Header().Set("Access-Control-Allow-Methods", "GET, HEAD, OPTIONS, POST, PUT, DELETE")
+					w.Header().Set("Access-Control-Allow-Headers", "User-Agent, Origin, Accept, Authorization, Content-Type, X-Requested-With, Decompressed-Content-Length, Access-Control-Request-Headers, Access-Control-Request-Method, Swarm-Tag, Swarm-Pin, Swarm-Encrypt, Swarm-Index-Document, Swarm-Error-Document, Swarm-Collection, Swarm-Postage-Batch-Id, Swarm-Chunking, Swarm-Encryption-Key, Gas-Price, Tus-Resumable, Upload-Length, Upload-Offset, Upload-Metadata")
+					w.Header().Set("Access-Control-Allow-Methods", "GET, HEAD, OPTIONS, POST, PUT, PATCH, DELETE")
 					w.Header().Set("Access-Control-Max-Age", "3600")
 				}
 				h.ServeHTTP(w, r)
@@ -238,7 +361,7 @@ func (s *server) gatewayModeForbidHeadersHandler(h http.Handler) http.Handler {
 				jsonhttp.Forbidden(w, "pinning is disabled")
 				return
 			}
-			if strings.ToLower(r.Header.Get(SwarmEncryptHeader)) == "true" {
+			if requestEncrypt(r) {
 				s.logger.Tracef("gateway mode: forbidden encryption %s", r.URL.String())
 				jsonhttp.Forbidden(w, "encryption is disabled")
 				return
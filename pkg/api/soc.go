@@ -21,7 +21,7 @@ import (
 
 var errBadRequestParams = errors.New("owner, id or span is not well formed")
 
-type socPostResponse struct {
+type SocPostResponse struct {
 	Reference swarm.Address `json:"reference"`
 }
 
@@ -180,5 +180,5 @@ func (s *server) socUploadHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	jsonhttp.Created(w, chunkAddressResponse{Reference: sch.Address()})
+	jsonhttp.Created(w, ChunkAddressResponse{Reference: sch.Address()})
 }
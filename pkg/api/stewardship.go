@@ -32,7 +32,7 @@ func (s *server) stewardshipPutHandler(w http.ResponseWriter, r *http.Request) {
 	jsonhttp.OK(w, nil)
 }
 
-type isRetrievableResponse struct {
+type IsRetrievableResponse struct {
 	IsRetrievable bool `json:"isRetrievable"`
 }
 
@@ -53,7 +53,7 @@ func (s *server) stewardshipGetHandler(w http.ResponseWriter, r *http.Request) {
 		jsonhttp.InternalServerError(w, nil)
 		return
 	}
-	jsonhttp.OK(w, isRetrievableResponse{
+	jsonhttp.OK(w, IsRetrievableResponse{
 		IsRetrievable: res,
 	})
 }
@@ -18,11 +18,11 @@ import (
 	"github.com/gorilla/mux"
 )
 
-type tagRequest struct {
+type TagRequest struct {
 	Address swarm.Address `json:"address,omitempty"`
 }
 
-type tagResponse struct {
+type TagResponse struct {
 	Uid       uint32    `json:"uid"`
 	StartedAt time.Time `json:"startedAt"`
 	Total     int64     `json:"total"`
@@ -30,12 +30,12 @@ type tagResponse struct {
 	Synced    int64     `json:"synced"`
 }
 
-type listTagsResponse struct {
-	Tags []tagResponse `json:"tags"`
+type ListTagsResponse struct {
+	Tags []TagResponse `json:"tags"`
 }
 
-func newTagResponse(tag *tags.Tag) tagResponse {
-	return tagResponse{
+func newTagResponse(tag *tags.Tag) TagResponse {
+	return TagResponse{
 		Uid:       tag.Uid,
 		StartedAt: tag.StartedAt,
 		Total:     tag.Total,
@@ -56,7 +56,7 @@ func (s *server) createTagHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tagr := tagRequest{}
+	tagr := TagRequest{}
 	if len(body) > 0 {
 		err = json.Unmarshal(body, &tagr)
 		if err != nil {
@@ -158,7 +158,7 @@ func (s *server) doneSplitHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tagr := tagRequest{}
+	tagr := TagRequest{}
 	if len(body) > 0 {
 		err = json.Unmarshal(body, &tagr)
 		if err != nil {
@@ -224,12 +224,12 @@ func (s *server) listTagsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tags := make([]tagResponse, len(tagList))
+	tags := make([]TagResponse, len(tagList))
 	for i, t := range tagList {
 		tags[i] = newTagResponse(t)
 	}
 
-	jsonhttp.OK(w, listTagsResponse{
+	jsonhttp.OK(w, ListTagsResponse{
 		Tags: tags,
 	})
 }
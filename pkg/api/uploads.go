@@ -0,0 +1,638 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/encryption"
+	"github.com/ethersphere/bee/pkg/file/loadsave"
+	"github.com/ethersphere/bee/pkg/file/pipeline"
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/manifest"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/resumable"
+	"github.com/ethersphere/bee/pkg/sctx"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+	"github.com/ethersphere/bee/pkg/tracing"
+	"github.com/gorilla/mux"
+)
+
+// The headers of the tus resumable upload protocol.
+const (
+	TusResumableHeader   = "Tus-Resumable"
+	TusVersionHeader     = "Tus-Version"
+	UploadLengthHeader   = "Upload-Length"
+	UploadOffsetHeader   = "Upload-Offset"
+	UploadMetadataHeader = "Upload-Metadata"
+	UploadExpiresHeader  = "Upload-Expires"
+
+	tusVersion              = "1.0.0"
+	contentTypeOffsetStream = "application/offset+octet-stream"
+)
+
+// uploadCheckpointInterval is the number of bytes written to the pipeline of
+// a resumable upload after which its state is checkpointed.
+const uploadCheckpointInterval = 64 * 1024 * 1024
+
+// uploadSweepInterval is the interval at which the expired resumable upload
+// sessions are deleted.
+const uploadSweepInterval = time.Hour
+
+var (
+	errUploadLength   = errors.New("invalid upload length")
+	errUploadOffset   = errors.New("invalid upload offset")
+	errUploadMetadata = errors.New("invalid upload metadata")
+)
+
+type UploadResponse struct {
+	ID        string         `json:"id"`
+	Length    int64          `json:"length"`
+	Offset    int64          `json:"offset"`
+	Reference *swarm.Address `json:"reference,omitempty"`
+}
+
+// uploadCreateHandler creates a resumable upload session of the length set in
+// the request, bound to the tag and the postage batch of the request.
+func (s *server) uploadCreateHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger)
+
+	if !s.checkTusResumable(w, r) {
+		return
+	}
+
+	length, err := strconv.ParseInt(r.Header.Get(UploadLengthHeader), 10, 64)
+	if err != nil || length <= 0 {
+		logger.Debugf("upload create: upload length %q: %v", r.Header.Get(UploadLengthHeader), err)
+		logger.Error("upload create: upload length")
+		jsonhttp.BadRequest(w, errUploadLength)
+		return
+	}
+
+	if requestCDC(r) {
+		logger.Debug("upload create: content defined chunking not supported")
+		logger.Error("upload create: content defined chunking not supported")
+		jsonhttp.BadRequest(w, "content defined chunking is not supported for resumable uploads")
+		return
+	}
+
+	key, err := requestEncryptionKey(r)
+	if err != nil {
+		logger.Debugf("upload create: encryption key: %v", err)
+		logger.Error("upload create: encryption key")
+		jsonhttp.BadRequest(w, err)
+		return
+	}
+
+	batch, err := requestPostageBatchId(r)
+	if err != nil {
+		logger.Debugf("upload create: postage batch id: %v", err)
+		logger.Error("upload create: postage batch id")
+		jsonhttp.BadRequest(w, errInvalidPostageBatch)
+		return
+	}
+	if _, err := s.post.GetStampIssuer(batch); err != nil {
+		logger.Debugf("upload create: stamp issuer: %v", err)
+		logger.Error("upload create: stamp issuer")
+		jsonhttp.BadRequest(w, errInvalidPostageBatch)
+		return
+	}
+
+	deferred, err := requestDeferred(r)
+	if err != nil {
+		logger.Debugf("upload create: deferred: %v", err)
+		logger.Error("upload create: deferred")
+		jsonhttp.BadRequest(w, nil)
+		return
+	}
+
+	metadata, err := parseUploadMetadata(r.Header.Get(UploadMetadataHeader))
+	if err != nil {
+		logger.Debugf("upload create: metadata: %v", err)
+		logger.Error("upload create: metadata")
+		jsonhttp.BadRequest(w, errUploadMetadata)
+		return
+	}
+
+	tag, created, err := s.getOrCreateTag(r.Header.Get(SwarmTagHeader))
+	if err != nil {
+		logger.Debugf("upload create: get or create tag: %v", err)
+		logger.Error("upload create: get or create tag")
+		jsonhttp.InternalServerError(w, "cannot get or create tag")
+		return
+	}
+
+	encrypt := requestEncrypt(r)
+	if !created {
+		// only in the case when tag is sent via header (i.e. not created by this request)
+		if err := tag.IncN(tags.TotalChunks, calculateNumberOfChunks(length, encrypt)); err != nil {
+			logger.Debugf("upload create: increment tag: %v", err)
+			logger.Error("upload create: increment tag")
+			jsonhttp.InternalServerError(w, "increment tag")
+			return
+		}
+	}
+
+	session := &resumable.Session{
+		Length:        length,
+		BatchID:       batch,
+		TagUID:        tag.Uid,
+		TagCreated:    created,
+		Encrypt:       encrypt,
+		EncryptionKey: key,
+		Deferred:      deferred,
+		Pin:           strings.ToLower(r.Header.Get(SwarmPinHeader)) == "true",
+		Filename:      metadata["filename"],
+		ContentType:   metadata["filetype"],
+	}
+	if err := s.uploads.Create(session); err != nil {
+		logger.Debugf("upload create: create session: %v", err)
+		logger.Error("upload create: create session")
+		jsonhttp.InternalServerError(w, nil)
+		return
+	}
+
+	w.Header().Set("Location", "/uploads/"+session.ID)
+	w.Header().Set(UploadOffsetHeader, "0")
+	w.Header().Set(UploadExpiresHeader, session.ExpiresAt().Format(http.TimeFormat))
+	w.Header().Set(SwarmTagHeader, fmt.Sprint(tag.Uid))
+	w.Header().Set("Access-Control-Expose-Headers", strings.Join([]string{"Location", UploadOffsetHeader, UploadExpiresHeader, TusResumableHeader, SwarmTagHeader}, ", "))
+	jsonhttp.Created(w, UploadResponse{
+		ID:     session.ID,
+		Length: session.Length,
+	})
+}
+
+// uploadHeadHandler reports the progress of the resumable upload.
+func (s *server) uploadHeadHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger)
+
+	if !s.checkTusResumable(w, r) {
+		return
+	}
+
+	session, err := s.uploads.Get(mux.Vars(r)["id"])
+	if err != nil {
+		logger.Debugf("upload head: get session: %v", err)
+		logger.Error("upload head: get session")
+		if errors.Is(err, resumable.ErrNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	s.setUploadHeaders(w, session)
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+}
+
+// uploadGetHandler returns the progress of the resumable upload and the
+// reference of the uploaded data once it is complete.
+func (s *server) uploadGetHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger)
+
+	session, err := s.uploads.Get(mux.Vars(r)["id"])
+	if err != nil {
+		logger.Debugf("upload get: get session: %v", err)
+		logger.Error("upload get: get session")
+		if errors.Is(err, resumable.ErrNotFound) {
+			jsonhttp.NotFound(w, nil)
+			return
+		}
+		jsonhttp.InternalServerError(w, nil)
+		return
+	}
+
+	resp := UploadResponse{
+		ID:     session.ID,
+		Length: session.Length,
+		Offset: session.Offset,
+	}
+	if session.Done() && !session.Reference.IsZero() {
+		resp.Reference = &session.Reference
+	}
+
+	s.setUploadHeaders(w, session)
+	w.Header().Set("Cache-Control", "no-store")
+	jsonhttp.OK(w, resp)
+}
+
+// uploadPatchHandler writes the body of the request to the pipeline of the
+// resumable upload, continuing from the checkpointed state at the offset of
+// the session. The state is checkpointed periodically and when the request
+// ends, so that an interrupted request can be resumed from the last offset
+// acknowledged by the node. The reference of the data is computed when all
+// of the bytes are written.
+func (s *server) uploadPatchHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger)
+
+	if !s.checkTusResumable(w, r) {
+		return
+	}
+
+	if ct := r.Header.Get(contentTypeHeader); ct != contentTypeOffsetStream {
+		logger.Debugf("upload patch: content type %q", ct)
+		logger.Error("upload patch: content type")
+		jsonhttp.UnsupportedMediaType(w, errInvalidContentType)
+		return
+	}
+
+	offset, err := strconv.ParseInt(r.Header.Get(UploadOffsetHeader), 10, 64)
+	if err != nil || offset < 0 {
+		logger.Debugf("upload patch: upload offset %q: %v", r.Header.Get(UploadOffsetHeader), err)
+		logger.Error("upload patch: upload offset")
+		jsonhttp.BadRequest(w, errUploadOffset)
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	unlock, err := s.uploads.Lock(id)
+	if err != nil {
+		logger.Debugf("upload patch: lock session %s: %v", id, err)
+		logger.Error("upload patch: lock session")
+		jsonhttp.Conflict(w, "upload in progress")
+		return
+	}
+	defer unlock()
+
+	session, err := s.uploads.Get(id)
+	if err != nil {
+		logger.Debugf("upload patch: get session: %v", err)
+		logger.Error("upload patch: get session")
+		if errors.Is(err, resumable.ErrNotFound) {
+			jsonhttp.NotFound(w, nil)
+			return
+		}
+		jsonhttp.InternalServerError(w, nil)
+		return
+	}
+
+	if offset != session.Offset {
+		logger.Debugf("upload patch: offset %d of session %s at %d", offset, id, session.Offset)
+		logger.Error("upload patch: offset mismatch")
+		s.setUploadHeaders(w, session)
+		jsonhttp.Conflict(w, errUploadOffset)
+		return
+	}
+	if session.Done() {
+		s.setUploadHeaders(w, session)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	tag, err := s.tags.Get(session.TagUID)
+	if err != nil {
+		logger.Debugf("upload patch: get tag %d: %v", session.TagUID, err)
+		logger.Error("upload patch: get tag")
+		jsonhttp.InternalServerError(w, "cannot get tag")
+		return
+	}
+
+	putter, wait, err := s.newBatchStamperPutter(session.BatchID, session.Deferred)
+	if err != nil {
+		logger.Debugf("upload patch: get putter: %v", err)
+		logger.Error("upload patch: putter")
+		jsonhttp.BadRequest(w, nil)
+		return
+	}
+
+	ctx := sctx.SetTag(r.Context(), tag)
+	p := newSessionPipeline(ctx, putter, session)
+	if session.Checkpoint != nil {
+		if err := pipeline.Restore(p, session.Checkpoint); err != nil {
+			logger.Debugf("upload patch: restore checkpoint of session %s: %v", id, err)
+			logger.Error("upload patch: restore checkpoint")
+			jsonhttp.InternalServerError(w, nil)
+			return
+		}
+	}
+
+	n, err := s.writeUpload(ctx, p, wait, session, io.LimitReader(r.Body, session.Length-session.Offset))
+	if err != nil {
+		logger.Debugf("upload patch: write session %s at offset %d: %v", id, session.Offset, err)
+		logger.Error("upload patch: write")
+		switch {
+		case errors.Is(err, postage.ErrBucketFull):
+			jsonhttp.PaymentRequired(w, "batch is overissued")
+		default:
+			jsonhttp.InternalServerError(w, nil)
+		}
+		return
+	}
+	logger.Debugf("upload patch: wrote %d bytes of session %s, offset %d of %d", n, id, session.Offset, session.Length)
+
+	if session.Done() {
+		reference, err := s.completeUpload(ctx, p, putter, wait, tag, session)
+		if err != nil {
+			logger.Debugf("upload patch: complete session %s: %v", id, err)
+			logger.Error("upload patch: complete")
+			switch {
+			case errors.Is(err, postage.ErrBucketFull):
+				jsonhttp.PaymentRequired(w, "batch is overissued")
+			default:
+				jsonhttp.InternalServerError(w, nil)
+			}
+			return
+		}
+		session.Checkpoint = nil
+		session.Reference = reference
+		if err := s.uploads.Save(session); err != nil {
+			logger.Debugf("upload patch: save session %s: %v", id, err)
+			logger.Error("upload patch: save session")
+			jsonhttp.InternalServerError(w, nil)
+			return
+		}
+	}
+
+	s.setUploadHeaders(w, session)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// uploadDeleteHandler terminates the resumable upload. The chunks which were
+// already uploaded are not removed.
+func (s *server) uploadDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	logger := tracing.NewLoggerWithTraceID(r.Context(), s.logger)
+
+	if !s.checkTusResumable(w, r) {
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	unlock, err := s.uploads.Lock(id)
+	if err != nil {
+		logger.Debugf("upload delete: lock session %s: %v", id, err)
+		logger.Error("upload delete: lock session")
+		jsonhttp.Conflict(w, "upload in progress")
+		return
+	}
+	defer unlock()
+
+	if _, err := s.uploads.Get(id); err != nil {
+		logger.Debugf("upload delete: get session: %v", err)
+		logger.Error("upload delete: get session")
+		if errors.Is(err, resumable.ErrNotFound) {
+			jsonhttp.NotFound(w, nil)
+			return
+		}
+		jsonhttp.InternalServerError(w, nil)
+		return
+	}
+
+	if err := s.uploads.Delete(id); err != nil {
+		logger.Debugf("upload delete: delete session %s: %v", id, err)
+		logger.Error("upload delete: delete session")
+		jsonhttp.InternalServerError(w, nil)
+		return
+	}
+
+	w.Header().Set(TusResumableHeader, tusVersion)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeUpload writes the data to the pipeline of the session and advances
+// the offset of the session, which is saved with the checkpoint of the
+// pipeline every uploadCheckpointInterval bytes and when the data ends. The
+// chunks are synced before the checkpoint is saved, so that the offset is
+// only acknowledged for the data whose chunks are stored.
+func (s *server) writeUpload(ctx context.Context, p pipeline.Interface, wait func() error, session *resumable.Session, r io.Reader) (int64, error) {
+	var (
+		total   int64
+		pending int64
+		data    = make([]byte, swarm.ChunkSize)
+	)
+
+	checkpoint := func() error {
+		if pending == 0 {
+			return nil
+		}
+		if err := wait(); err != nil {
+			return fmt.Errorf("sync chunks: %w", err)
+		}
+		state, err := pipeline.Checkpoint(p)
+		if err != nil {
+			return fmt.Errorf("checkpoint: %w", err)
+		}
+		session.Offset += pending
+		session.Checkpoint = state
+		pending = 0
+		return s.uploads.Save(session)
+	}
+
+	for {
+		c, rerr := r.Read(data)
+		if c > 0 {
+			if _, err := p.Write(data[:c]); err != nil {
+				return total, err
+			}
+			total += int64(c)
+			pending += int64(c)
+			if pending >= uploadCheckpointInterval {
+				if err := checkpoint(); err != nil {
+					return total, err
+				}
+			}
+		}
+		if rerr != nil {
+			// the data written before the connection dropped is kept
+			if err := checkpoint(); err != nil {
+				return total, err
+			}
+			if errors.Is(rerr, io.EOF) {
+				return total, nil
+			}
+			return total, rerr
+		}
+		select {
+		case <-ctx.Done():
+			if err := checkpoint(); err != nil {
+				return total, err
+			}
+			return total, ctx.Err()
+		default:
+		}
+	}
+}
+
+// completeUpload computes the reference of the data of the session, stores
+// the manifest of the file if the session has a filename and finishes the
+// tag and the pin of the upload.
+func (s *server) completeUpload(ctx context.Context, p pipeline.Interface, putter storage.Storer, wait func() error, tag *tags.Tag, session *resumable.Session) (swarm.Address, error) {
+	sum, err := p.Sum()
+	if err != nil {
+		return swarm.ZeroAddress, fmt.Errorf("sum: %w", err)
+	}
+	reference := swarm.NewAddress(sum)
+
+	if session.Filename != "" {
+		reference, err = s.storeUploadManifest(ctx, putter, tag, session, reference)
+		if err != nil {
+			return swarm.ZeroAddress, fmt.Errorf("store manifest: %w", err)
+		}
+	}
+
+	if err := wait(); err != nil {
+		return swarm.ZeroAddress, fmt.Errorf("sync chunks: %w", err)
+	}
+
+	if session.TagCreated {
+		if _, err := tag.DoneSplit(reference); err != nil {
+			return swarm.ZeroAddress, fmt.Errorf("done split: %w", err)
+		}
+	}
+
+	if session.Pin {
+		if err := s.pinning.CreatePin(ctx, reference, false); err != nil {
+			return swarm.ZeroAddress, fmt.Errorf("create pin: %w", err)
+		}
+	}
+
+	return reference, nil
+}
+
+// storeUploadManifest stores the manifest of the file of the session in the
+// same way as the files uploaded to the bzz endpoint and returns its
+// reference.
+func (s *server) storeUploadManifest(ctx context.Context, putter storage.Storer, tag *tags.Tag, session *resumable.Session, fr swarm.Address) (swarm.Address, error) {
+	l := loadsave.New(putter, func() pipeline.Interface {
+		return newSessionPipeline(ctx, putter, session)
+	})
+
+	m, err := manifest.NewDefaultManifest(l, session.Encrypt)
+	if err != nil {
+		return swarm.ZeroAddress, fmt.Errorf("create manifest: %w", err)
+	}
+
+	rootMetadata := map[string]string{
+		manifest.WebsiteIndexDocumentSuffixKey: session.Filename,
+	}
+	if err := m.Add(ctx, manifest.RootPath, manifest.NewEntry(swarm.ZeroAddress, rootMetadata)); err != nil {
+		return swarm.ZeroAddress, fmt.Errorf("add root metadata: %w", err)
+	}
+
+	contentType := session.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	fileMtdt := map[string]string{
+		manifest.EntryMetadataContentTypeKey: contentType,
+		manifest.EntryMetadataFilenameKey:    session.Filename,
+	}
+	if err := m.Add(ctx, session.Filename, manifest.NewEntry(fr, fileMtdt)); err != nil {
+		return swarm.ZeroAddress, fmt.Errorf("add file: %w", err)
+	}
+
+	var storeSizeFn []manifest.StoreSizeFunc
+	if !session.TagCreated {
+		storeSizeFn = append(storeSizeFn, func(dataSize int64) error {
+			if estimatedTotalChunks := calculateNumberOfChunks(dataSize, session.Encrypt); estimatedTotalChunks > 0 {
+				if err := tag.IncN(tags.TotalChunks, estimatedTotalChunks); err != nil {
+					return fmt.Errorf("increment tag: %w", err)
+				}
+			}
+			return nil
+		})
+	}
+
+	return m.Store(ctx, storeSizeFn...)
+}
+
+// sweepUploads deletes the expired resumable upload sessions when the server
+// starts and then every uploadSweepInterval, until it is closed.
+func (s *server) sweepUploads() {
+	ticker := time.NewTicker(uploadSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		n, err := s.uploads.Sweep(time.Now())
+		if err != nil {
+			s.logger.Debugf("uploads: sweep expired sessions: %v", err)
+		} else if n > 0 {
+			s.logger.Debugf("uploads: deleted %d expired sessions", n)
+		}
+		select {
+		case <-s.quit:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// newSessionPipeline returns a pipeline with the encryption and the pinning
+// settings of the session.
+func newSessionPipeline(ctx context.Context, putter storage.Putter, session *resumable.Session) pipeline.Interface {
+	mode := storage.ModePutUpload
+	if session.Pin {
+		mode = storage.ModePutUploadPin
+	}
+	if session.EncryptionKey != nil {
+		return builder.NewEncryptionPipelineBuilder(ctx, putter, mode, encryption.NewConvergentChunkEncrypter(session.EncryptionKey))
+	}
+	return builder.NewPipelineBuilder(ctx, putter, mode, session.Encrypt)
+}
+
+// setUploadHeaders sets the headers with the progress of the session.
+func (s *server) setUploadHeaders(w http.ResponseWriter, session *resumable.Session) {
+	w.Header().Set(TusResumableHeader, tusVersion)
+	w.Header().Set(UploadOffsetHeader, strconv.FormatInt(session.Offset, 10))
+	w.Header().Set(UploadLengthHeader, strconv.FormatInt(session.Length, 10))
+	w.Header().Set(UploadExpiresHeader, session.ExpiresAt().Format(http.TimeFormat))
+	w.Header().Set(SwarmTagHeader, fmt.Sprint(session.TagUID))
+	exposed := []string{UploadOffsetHeader, UploadLengthHeader, UploadExpiresHeader, TusResumableHeader, SwarmTagHeader}
+	if session.Done() && !session.Reference.IsZero() {
+		w.Header().Set("ETag", fmt.Sprintf("%q", session.Reference))
+		exposed = append(exposed, "ETag")
+	}
+	w.Header().Set("Access-Control-Expose-Headers", strings.Join(exposed, ", "))
+}
+
+// checkTusResumable responds with the error and returns false if the request
+// uses a version of the tus protocol other than the supported one.
+func (s *server) checkTusResumable(w http.ResponseWriter, r *http.Request) bool {
+	if v := r.Header.Get(TusResumableHeader); v != "" && v != tusVersion {
+		w.Header().Set(TusVersionHeader, tusVersion)
+		jsonhttp.PreconditionFailed(w, "unsupported tus version")
+		return false
+	}
+	w.Header().Set(TusResumableHeader, tusVersion)
+	return true
+}
+
+// parseUploadMetadata parses the comma separated key and base64 encoded value
+// pairs of the Upload-Metadata header.
+func parseUploadMetadata(h string) (map[string]string, error) {
+	metadata := make(map[string]string)
+	if strings.TrimSpace(h) == "" {
+		return metadata, nil
+	}
+	for _, pair := range strings.Split(h, ",") {
+		fields := strings.Fields(pair)
+		if len(fields) == 0 || len(fields) > 2 {
+			return nil, fmt.Errorf("pair %q", pair)
+		}
+		var value []byte
+		if len(fields) == 2 {
+			var err error
+			value, err = base64.StdEncoding.DecodeString(fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("value of %s: %w", fields[0], err)
+			}
+		}
+		metadata[fields[0]] = string(value)
+	}
+	return metadata, nil
+}
@@ -0,0 +1,232 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/logging"
+	pinning "github.com/ethersphere/bee/pkg/pinning/mock"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	"github.com/ethersphere/bee/pkg/resumable"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+	"gitlab.com/nolash/go-mockbytes"
+)
+
+// TestUploads tests that the data uploaded in multiple requests of a
+// resumable upload results in the same reference as the data uploaded to the
+// bytes endpoint in a single request.
+func TestUploads(t *testing.T) {
+	const resource = "/uploads"
+
+	var (
+		logger          = logging.New(io.Discard, 0)
+		stateStore      = statestore.NewStateStore()
+		client, _, _, _ = newTestServer(t, testServerOptions{
+			Storer:  mock.NewStorer(),
+			Tags:    tags.NewTags(stateStore, logger),
+			Uploads: resumable.New(stateStore, nil),
+			Pinning: pinning.NewServiceMock(),
+			Logger:  logger,
+			Post:    mockpost.New(mockpost.WithAcceptAll()),
+		})
+	)
+
+	g := mockbytes.New(0, mockbytes.MockTypeStandard).WithModulus(255)
+	content, err := g.SequentialBytes(swarm.ChunkSize*130 + 17)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var want api.BytesPostResponse
+	jsonhttptest.Request(t, client, http.MethodPost, "/bytes", http.StatusCreated,
+		jsonhttptest.WithRequestHeader(api.SwarmDeferredUploadHeader, "true"),
+		jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		jsonhttptest.WithRequestBody(bytes.NewReader(content)),
+		jsonhttptest.WithUnmarshalJSONResponse(&want),
+	)
+
+	create := func(t *testing.T, opts ...jsonhttptest.Option) string {
+		t.Helper()
+
+		var res api.UploadResponse
+		header := jsonhttptest.Request(t, client, http.MethodPost, resource, http.StatusCreated,
+			append([]jsonhttptest.Option{
+				jsonhttptest.WithRequestHeader(api.TusResumableHeader, "1.0.0"),
+				jsonhttptest.WithRequestHeader(api.UploadLengthHeader, strconv.Itoa(len(content))),
+				jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+				jsonhttptest.WithUnmarshalJSONResponse(&res),
+			}, opts...)...,
+		)
+		if got, want := header.Get("Location"), resource+"/"+res.ID; got != want {
+			t.Fatalf("got location %q, want %q", got, want)
+		}
+		if res.Length != int64(len(content)) {
+			t.Fatalf("got length %d, want %d", res.Length, len(content))
+		}
+		return res.ID
+	}
+
+	patch := func(t *testing.T, id string, offset int, data []byte, status int) http.Header {
+		t.Helper()
+
+		return jsonhttptest.Request(t, client, http.MethodPatch, resource+"/"+id, status,
+			jsonhttptest.WithRequestHeader(api.TusResumableHeader, "1.0.0"),
+			jsonhttptest.WithRequestHeader(api.ContentTypeHeader, api.ContentTypeOffsetStream),
+			jsonhttptest.WithRequestHeader(api.UploadOffsetHeader, strconv.Itoa(offset)),
+			jsonhttptest.WithRequestBody(bytes.NewReader(data)),
+		)
+	}
+
+	offset := func(t *testing.T, id string) int {
+		t.Helper()
+
+		header := jsonhttptest.Request(t, client, http.MethodHead, resource+"/"+id, http.StatusOK,
+			jsonhttptest.WithRequestHeader(api.TusResumableHeader, "1.0.0"),
+		)
+		if got := header.Get(api.UploadLengthHeader); got != strconv.Itoa(len(content)) {
+			t.Fatalf("got upload length %q, want %d", got, len(content))
+		}
+		if expires, err := http.ParseTime(header.Get(api.UploadExpiresHeader)); err != nil || time.Until(expires) < resumable.Expiry-time.Minute {
+			t.Fatalf("got upload expiry %q, want in %v", header.Get(api.UploadExpiresHeader), resumable.Expiry)
+		}
+		o, err := strconv.Atoi(header.Get(api.UploadOffsetHeader))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return o
+	}
+
+	t.Run("resume", func(t *testing.T) {
+		id := create(t)
+
+		splits := []int{0, 1000, 40*swarm.ChunkSize + 3, 129 * swarm.ChunkSize, len(content)}
+		for i := 1; i < len(splits); i++ {
+			if got := offset(t, id); got != splits[i-1] {
+				t.Fatalf("got offset %d, want %d", got, splits[i-1])
+			}
+			header := patch(t, id, splits[i-1], content[splits[i-1]:splits[i]], http.StatusNoContent)
+			if got := header.Get(api.UploadOffsetHeader); got != strconv.Itoa(splits[i]) {
+				t.Fatalf("got offset %q, want %d", got, splits[i])
+			}
+		}
+
+		var res api.UploadResponse
+		header := jsonhttptest.Request(t, client, http.MethodGet, resource+"/"+id, http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&res),
+		)
+		if res.Reference == nil || !res.Reference.Equal(want.Reference) {
+			t.Fatalf("got reference %v, want %s", res.Reference, want.Reference)
+		}
+		if got, want := header.Get("ETag"), fmt.Sprintf("%q", want.Reference); got != want {
+			t.Fatalf("got etag %s, want %s", got, want)
+		}
+	})
+
+	t.Run("excess data", func(t *testing.T) {
+		id := create(t)
+
+		patch(t, id, 0, append(content, 1, 2, 3), http.StatusNoContent)
+		if got := offset(t, id); got != len(content) {
+			t.Fatalf("got offset %d, want %d", got, len(content))
+		}
+	})
+
+	t.Run("manifest", func(t *testing.T) {
+		metadata := "filename " + base64.StdEncoding.EncodeToString([]byte("file.bin")) +
+			",filetype " + base64.StdEncoding.EncodeToString([]byte("application/octet-stream"))
+		id := create(t, jsonhttptest.WithRequestHeader(api.UploadMetadataHeader, metadata))
+
+		header := patch(t, id, 0, content, http.StatusNoContent)
+		reference, err := strconv.Unquote(header.Get("ETag"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		jsonhttptest.Request(t, client, http.MethodGet, "/bzz/"+reference, http.StatusOK,
+			jsonhttptest.WithExpectedResponse(content),
+		)
+	})
+
+	t.Run("offset conflict", func(t *testing.T) {
+		id := create(t)
+
+		patch(t, id, 0, content[:100], http.StatusNoContent)
+		jsonhttptest.Request(t, client, http.MethodPatch, resource+"/"+id, http.StatusConflict,
+			jsonhttptest.WithRequestHeader(api.ContentTypeHeader, api.ContentTypeOffsetStream),
+			jsonhttptest.WithRequestHeader(api.UploadOffsetHeader, "0"),
+			jsonhttptest.WithRequestBody(bytes.NewReader(content)),
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "invalid upload offset",
+				Code:    http.StatusConflict,
+			}),
+		)
+		if got := offset(t, id); got != 100 {
+			t.Fatalf("got offset %d, want %d", got, 100)
+		}
+	})
+
+	t.Run("invalid content type", func(t *testing.T) {
+		id := create(t)
+
+		jsonhttptest.Request(t, client, http.MethodPatch, resource+"/"+id, http.StatusUnsupportedMediaType,
+			jsonhttptest.WithRequestHeader(api.ContentTypeHeader, "application/octet-stream"),
+			jsonhttptest.WithRequestHeader(api.UploadOffsetHeader, "0"),
+			jsonhttptest.WithRequestBody(bytes.NewReader(content)),
+		)
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		header := jsonhttptest.Request(t, client, http.MethodPost, resource, http.StatusPreconditionFailed,
+			jsonhttptest.WithRequestHeader(api.TusResumableHeader, "0.2.2"),
+			jsonhttptest.WithRequestHeader(api.UploadLengthHeader, "10"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		)
+		if got := header.Get(api.TusVersionHeader); got != "1.0.0" {
+			t.Fatalf("got tus version %q, want %q", got, "1.0.0")
+		}
+	})
+
+	t.Run("invalid length", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodPost, resource, http.StatusBadRequest,
+			jsonhttptest.WithRequestHeader(api.UploadLengthHeader, "0"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+		)
+	})
+
+	t.Run("invalid metadata", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodPost, resource, http.StatusBadRequest,
+			jsonhttptest.WithRequestHeader(api.UploadLengthHeader, "10"),
+			jsonhttptest.WithRequestHeader(api.SwarmPostageBatchIdHeader, batchOkStr),
+			jsonhttptest.WithRequestHeader(api.UploadMetadataHeader, "filename !!!"),
+		)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		id := create(t)
+
+		jsonhttptest.Request(t, client, http.MethodDelete, resource+"/"+id, http.StatusNoContent)
+		jsonhttptest.Request(t, client, http.MethodHead, resource+"/"+id, http.StatusNotFound)
+		patch(t, id, 0, content, http.StatusNotFound)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		jsonhttptest.Request(t, client, http.MethodGet, resource+"/"+strings.Repeat("0", 32), http.StatusNotFound)
+	})
+}
@@ -234,6 +234,9 @@ func applyPolicies(e *casbin.Enforcer) error {
 	_, err := e.AddPolicies([][]string{
 		{"consumer", "/bytes/*", "GET"},
 		{"creator", "/bytes", "POST"},
+		{"creator", "/bytes/*/reencrypt", "POST"},
+		{"creator", "/uploads", "POST"},
+		{"creator", "/uploads/*", "(GET)|(HEAD)|(PATCH)|(DELETE)"},
 		{"consumer", "/chunks/*", "GET"},
 		{"creator", "/chunks", "POST"},
 		{"consumer", "/bzz/*", "GET"},
@@ -241,6 +244,9 @@ func applyPolicies(e *casbin.Enforcer) error {
 		{"creator", "/bzz", "POST"},
 		{"creator", "/bzz?*", "POST"},
 		{"consumer", "/bzz/*/*", "GET"},
+		{"consumer", "/manifests/*/diff/*", "GET"},
+		{"creator", "/manifests/*/merge/*", "POST"},
+		{"creator", "/manifests/*/reencrypt", "POST"},
 		{"creator", "/tags", "GET"},
 		{"creator", "/tags?*", "GET"},
 		{"creator", "/tags", "POST"},
@@ -249,6 +255,14 @@ func applyPolicies(e *casbin.Enforcer) error {
 		{"maintainer", "/pins", "GET"},
 		{"creator", "/pss/send/*", "POST"},
 		{"consumer", "/pss/subscribe/*", "GET"},
+		{"consumer", "/pss/inbox/*", "GET"},
+		{"consumer", "/pss/poll/*", "GET"},
+		{"consumer", "/pss/events/*", "GET"},
+		{"creator", "/pss/request/*", "POST"},
+		{"consumer", "/pss/serve/*", "GET"},
+		{"creator", "/pss/estimate/*", "GET"},
+		{"maintainer", "/pss/benchmark", "POST"},
+		{"maintainer", "/pss/benchmark?*", "POST"},
 		{"creator", "/soc/*/*", "POST"},
 		{"creator", "/feeds/*/*", "POST"},
 		{"consumer", "/feeds/*/*", "GET"},
@@ -265,6 +279,12 @@ func applyPolicies(e *casbin.Enforcer) error {
 		{"maintainer", "/pingpong/*", "POST"},
 		{"maintainer", "/topology", "GET"},
 		{"maintainer", "/welcome-message", "(GET)|(POST)"},
+		{"maintainer", "/loggers", "GET"},
+		{"maintainer", "/loggers/*", "PUT"},
+		{"maintainer", "/config", "GET"},
+		{"maintainer", "/config/reload", "POST"},
+		{"maintainer", "/events", "GET"},
+		{"maintainer", "/events?*", "GET"},
 		{"maintainer", "/balances", "GET"},
 		{"maintainer", "/balances/*", "GET"},
 		{"maintainer", "/chequebook/cashout/*", "GET"},
@@ -291,6 +311,7 @@ func applyPolicies(e *casbin.Enforcer) error {
 		{"consumer", "/chunks/stream", "GET"},
 		{"creator", "/stewardship/*", "GET"},
 		{"consumer", "/stewardship/*", "PUT"},
+		{"consumer", "/resolve/*", "GET"},
 	})
 
 	if err != nil {
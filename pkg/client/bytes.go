@@ -0,0 +1,144 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// UploadOptions are the options of the uploads.
+type UploadOptions struct {
+	// BatchID is the ID of the postage batch which stamps the chunks.
+	BatchID []byte
+	// Tag is the UID of the tag which tracks the upload. A new tag is
+	// created if it is zero.
+	Tag uint32
+	// Pin pins the uploaded content locally.
+	Pin bool
+	// Encrypt encrypts the uploaded content.
+	Encrypt bool
+	// Direct uploads the chunks to the network before the response is
+	// returned, instead of syncing them from the local store.
+	Direct bool
+	// IndexDocument and ErrorDocument are the paths of the documents served
+	// for the collection and for the paths not found in it. They are only
+	// sent with the collections.
+	IndexDocument string
+	ErrorDocument string
+}
+
+func (o UploadOptions) setHeaders(h http.Header) {
+	h.Set(api.SwarmPostageBatchIdHeader, hex.EncodeToString(o.BatchID))
+	if o.Tag != 0 {
+		h.Set(api.SwarmTagHeader, strconv.FormatUint(uint64(o.Tag), 10))
+	}
+	if o.Pin {
+		h.Set(api.SwarmPinHeader, "true")
+	}
+	if o.Encrypt {
+		h.Set(api.SwarmEncryptHeader, "true")
+	}
+	if o.Direct {
+		h.Set(api.SwarmDeferredUploadHeader, "false")
+	}
+}
+
+// UploadResult is the result of an upload.
+type UploadResult struct {
+	Reference swarm.Address
+	// TagUID is the UID of the tag which tracked the upload.
+	TagUID uint32
+}
+
+// upload sends the upload request, decodes the response into v and returns
+// the UID of the tag which tracked the upload.
+func (c *Client) upload(r *http.Request, o UploadOptions, v interface{}) (uint32, error) {
+	o.setHeaders(r.Header)
+	resp, err := c.sendJSON(r, v)
+	if err != nil {
+		return 0, err
+	}
+	h := resp.Header.Get(api.SwarmTagHeader)
+	if h == "" {
+		return o.Tag, nil
+	}
+	uid, err := strconv.ParseUint(h, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("parse tag uid: %w", err)
+	}
+	return uint32(uid), nil
+}
+
+// UploadBytes uploads the data read from the reader and returns its
+// reference.
+func (c *Client) UploadBytes(ctx context.Context, data io.Reader, o UploadOptions) (UploadResult, error) {
+	r, err := c.newRequest(ctx, http.MethodPost, "/bytes", nil, data)
+	if err != nil {
+		return UploadResult{}, err
+	}
+	r.Header.Set("Content-Type", "application/octet-stream")
+
+	var resp api.BytesPostResponse
+	tag, err := c.upload(r, o, &resp)
+	if err != nil {
+		return UploadResult{}, err
+	}
+	return UploadResult{Reference: resp.Reference, TagUID: tag}, nil
+}
+
+// DownloadBytes returns the reader of the data with the reference, which must
+// be closed.
+func (c *Client) DownloadBytes(ctx context.Context, reference swarm.Address) (io.ReadCloser, error) {
+	r, err := c.newRequest(ctx, http.MethodGet, "/bytes/"+reference.String(), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.send(r)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// UploadChunk uploads the data of a content addressed chunk, which is the span
+// followed by the payload.
+func (c *Client) UploadChunk(ctx context.Context, data []byte, o UploadOptions) (UploadResult, error) {
+	r, err := c.newRequest(ctx, http.MethodPost, "/chunks", nil, bytes.NewReader(data))
+	if err != nil {
+		return UploadResult{}, err
+	}
+	r.Header.Set("Content-Type", "application/octet-stream")
+
+	var resp api.ChunkAddressResponse
+	tag, err := c.upload(r, o, &resp)
+	if err != nil {
+		return UploadResult{}, err
+	}
+	return UploadResult{Reference: resp.Reference, TagUID: tag}, nil
+}
+
+// DownloadChunk returns the data of the chunk with the address.
+func (c *Client) DownloadChunk(ctx context.Context, address swarm.Address) ([]byte, error) {
+	r, err := c.newRequest(ctx, http.MethodGet, "/chunks/"+address.String(), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.send(r)
+	if err != nil {
+		return nil, err
+	}
+	defer drain(resp.Body)
+
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,95 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/client"
+	testingc "github.com/ethersphere/bee/pkg/storage/testing"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestBytes(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		c    = newTestClient(t, testServerOptions{})
+		data = make([]byte, 3*swarm.ChunkSize+17)
+	)
+	copy(data, "streamed data")
+
+	// the data is streamed through a pipe to make sure the client does not
+	// need to know its length in advance
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := pw.Write(data)
+		_ = pw.CloseWithError(err)
+	}()
+
+	res, err := c.UploadBytes(ctx, pr, client.UploadOptions{BatchID: batchID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.TagUID == 0 {
+		t.Fatal("upload is not tracked by a tag")
+	}
+
+	tag, err := c.GetTag(ctx, res.TagUID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag.Total != 5 {
+		t.Fatalf("got %d chunks, want 5", tag.Total)
+	}
+
+	rc, err := c.DownloadBytes(ctx, res.Reference)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	got, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatal("downloaded data does not match")
+	}
+
+	_, err = c.DownloadBytes(ctx, swarm.MustParseHexAddress("ca1e9f3938cc1425c6061b96ad9eb93e134dfe8734ad490164ef20af9d1cf59c"))
+	if !client.IsNotFound(err) {
+		t.Fatalf("got error %v, want not found", err)
+	}
+}
+
+func TestChunk(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		c     = newTestClient(t, testServerOptions{})
+		chunk = testingc.GenerateTestRandomChunk()
+	)
+
+	res, err := c.UploadChunk(ctx, chunk.Data(), client.UploadOptions{BatchID: batchID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Reference.Equal(chunk.Address()) {
+		t.Fatalf("got address %s, want %s", res.Reference, chunk.Address())
+	}
+
+	got, err := c.DownloadChunk(ctx, chunk.Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, chunk.Data()) {
+		t.Fatal("downloaded chunk does not match")
+	}
+
+	if _, err := c.UploadChunk(ctx, chunk.Data(), client.UploadOptions{}); err == nil {
+		t.Fatal("got no error without a postage batch")
+	}
+}
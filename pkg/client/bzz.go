@@ -0,0 +1,104 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"context"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// File is a file downloaded from a manifest.
+type File struct {
+	io.ReadCloser
+	// Name is the file name of the manifest entry, if it has one.
+	Name        string
+	ContentType string
+	// Size is the size of the data, or -1 if it is not known.
+	Size int64
+}
+
+// UploadFile uploads the data read from the reader as a single file with the
+// name and the content type and returns the reference of its manifest.
+func (c *Client) UploadFile(ctx context.Context, name, contentType string, data io.Reader, o UploadOptions) (UploadResult, error) {
+	var query url.Values
+	if name != "" {
+		query = url.Values{"name": {name}}
+	}
+	r, err := c.newRequest(ctx, http.MethodPost, "/bzz", query, data)
+	if err != nil {
+		return UploadResult{}, err
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	r.Header.Set("Content-Type", contentType)
+
+	var resp api.BzzUploadResponse
+	tag, err := c.upload(r, o, &resp)
+	if err != nil {
+		return UploadResult{}, err
+	}
+	return UploadResult{Reference: resp.Reference, TagUID: tag}, nil
+}
+
+// UploadCollection uploads the files of the tar archive read from the reader
+// as a collection and returns the reference of its manifest.
+func (c *Client) UploadCollection(ctx context.Context, tar io.Reader, o UploadOptions) (UploadResult, error) {
+	r, err := c.newRequest(ctx, http.MethodPost, "/bzz", nil, tar)
+	if err != nil {
+		return UploadResult{}, err
+	}
+	r.Header.Set("Content-Type", "application/x-tar")
+	r.Header.Set(api.SwarmCollectionHeader, "true")
+	if o.IndexDocument != "" {
+		r.Header.Set(api.SwarmIndexDocumentHeader, o.IndexDocument)
+	}
+	if o.ErrorDocument != "" {
+		r.Header.Set(api.SwarmErrorDocumentHeader, o.ErrorDocument)
+	}
+
+	var resp api.BzzUploadResponse
+	tag, err := c.upload(r, o, &resp)
+	if err != nil {
+		return UploadResult{}, err
+	}
+	return UploadResult{Reference: resp.Reference, TagUID: tag}, nil
+}
+
+// DownloadFile returns the file at the path in the manifest with the
+// reference. The index document of the collection is returned for the empty
+// path. The file must be closed.
+func (c *Client) DownloadFile(ctx context.Context, reference swarm.Address, path string) (*File, error) {
+	r, err := c.newRequest(ctx, http.MethodGet, "/bzz/"+reference.String()+"/"+strings.TrimPrefix(path, "/"), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.send(r)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &File{
+		ReadCloser:  resp.Body,
+		ContentType: resp.Header.Get("Content-Type"),
+		Size:        resp.ContentLength,
+	}
+	// the transport drops the content length of the compressed responses
+	if n, err := strconv.ParseInt(resp.Header.Get("Decompressed-Content-Length"), 10, 64); err == nil {
+		f.Size = n
+	}
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		f.Name = params["filename"]
+	}
+	return f, nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/client"
+)
+
+func TestFile(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		c    = newTestClient(t, testServerOptions{})
+		data = "file content"
+	)
+
+	res, err := c.UploadFile(ctx, "file.txt", "text/plain; charset=utf-8", strings.NewReader(data), client.UploadOptions{BatchID: batchID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := c.DownloadFile(ctx, res.Reference, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if f.Name != "file.txt" {
+		t.Errorf("got name %q", f.Name)
+	}
+	if f.ContentType != "text/plain; charset=utf-8" {
+		t.Errorf("got content type %q", f.ContentType)
+	}
+	if f.Size != int64(len(data)) {
+		t.Errorf("got size %d, want %d", f.Size, len(data))
+	}
+	got, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != data {
+		t.Fatalf("got data %q, want %q", got, data)
+	}
+}
+
+func TestCollection(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		c     = newTestClient(t, testServerOptions{})
+		files = map[string]string{
+			"index.html":     "<h1>index</h1>",
+			"img/logo.svg":   "<svg></svg>",
+			"docs/readme.md": "# readme",
+		}
+	)
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, data := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(data))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := c.UploadCollection(ctx, &buf, client.UploadOptions{BatchID: batchID, IndexDocument: "index.html"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for path, want := range map[string]string{
+		"":               files["index.html"],
+		"img/logo.svg":   files["img/logo.svg"],
+		"docs/readme.md": files["docs/readme.md"],
+	} {
+		f, err := c.DownloadFile(ctx, res.Reference, path)
+		if err != nil {
+			t.Fatalf("%q: %v", path, err)
+		}
+		got, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("%q: got data %q, want %q", path, got, want)
+		}
+	}
+
+	if _, err := c.DownloadFile(ctx, res.Reference, "missing"); !client.IsNotFound(err) {
+		t.Fatalf("got error %v, want not found", err)
+	}
+}
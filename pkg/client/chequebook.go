@@ -0,0 +1,105 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/url"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/debugapi"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// ChequebookAddress returns the address of the chequebook contract of the
+// node.
+func (c *DebugClient) ChequebookAddress(ctx context.Context) (common.Address, error) {
+	var resp debugapi.ChequebookAddressResponse
+	if _, err := c.requestJSON(ctx, http.MethodGet, "/chequebook/address", nil, nil, &resp); err != nil {
+		return common.Address{}, err
+	}
+	return common.HexToAddress(resp.Address), nil
+}
+
+// ChequebookBalance returns the total and the available balance of the
+// chequebook.
+func (c *DebugClient) ChequebookBalance(ctx context.Context) (debugapi.ChequebookBalanceResponse, error) {
+	var resp debugapi.ChequebookBalanceResponse
+	_, err := c.requestJSON(ctx, http.MethodGet, "/chequebook/balance", nil, nil, &resp)
+	return resp, err
+}
+
+// ChequebookDeposit deposits the amount to the chequebook and returns the
+// hash of the transaction.
+func (c *DebugClient) ChequebookDeposit(ctx context.Context, amount, gasPrice *big.Int) (common.Hash, error) {
+	return c.chequebookTx(ctx, "/chequebook/deposit", amount, gasPrice)
+}
+
+// ChequebookWithdraw withdraws the amount from the chequebook and returns the
+// hash of the transaction.
+func (c *DebugClient) ChequebookWithdraw(ctx context.Context, amount, gasPrice *big.Int) (common.Hash, error) {
+	return c.chequebookTx(ctx, "/chequebook/withdraw", amount, gasPrice)
+}
+
+func (c *DebugClient) chequebookTx(ctx context.Context, path string, amount, gasPrice *big.Int) (common.Hash, error) {
+	r, err := c.newRequest(ctx, http.MethodPost, path, url.Values{"amount": {amount.String()}}, nil)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if gasPrice != nil {
+		r.Header.Set(debugapi.GasPriceHeader, gasPrice.String())
+	}
+
+	var resp debugapi.ChequebookTxResponse
+	if _, err := c.sendJSON(r, &resp); err != nil {
+		return common.Hash{}, err
+	}
+	return resp.TransactionHash, nil
+}
+
+// LastCheques returns the last cheques sent to and received from the peers.
+func (c *DebugClient) LastCheques(ctx context.Context) ([]debugapi.ChequebookLastChequesPeerResponse, error) {
+	var resp debugapi.ChequebookLastChequesResponse
+	if _, err := c.requestJSON(ctx, http.MethodGet, "/chequebook/cheque", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.LastCheques, nil
+}
+
+// LastChequesPeer returns the last cheques sent to and received from the
+// peer.
+func (c *DebugClient) LastChequesPeer(ctx context.Context, peer swarm.Address) (debugapi.ChequebookLastChequesPeerResponse, error) {
+	var resp debugapi.ChequebookLastChequesPeerResponse
+	_, err := c.requestJSON(ctx, http.MethodGet, "/chequebook/cheque/"+peer.String(), nil, nil, &resp)
+	return resp, err
+}
+
+// Cashout cashes the last cheque received from the peer and returns the hash
+// of the transaction.
+func (c *DebugClient) Cashout(ctx context.Context, peer swarm.Address, gasPrice *big.Int) (common.Hash, error) {
+	r, err := c.newRequest(ctx, http.MethodPost, "/chequebook/cashout/"+peer.String(), nil, nil)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if gasPrice != nil {
+		r.Header.Set(debugapi.GasPriceHeader, gasPrice.String())
+	}
+
+	var resp debugapi.SwapCashoutResponse
+	if _, err := c.sendJSON(r, &resp); err != nil {
+		return common.Hash{}, err
+	}
+	return common.HexToHash(resp.TransactionHash), nil
+}
+
+// CashoutStatus returns the status of the last cashout of the cheques
+// received from the peer.
+func (c *DebugClient) CashoutStatus(ctx context.Context, peer swarm.Address) (debugapi.SwapCashoutStatusResponse, error) {
+	var resp debugapi.SwapCashoutStatusResponse
+	_, err := c.requestJSON(ctx, http.MethodGet, "/chequebook/cashout/"+peer.String(), nil, nil, &resp)
+	return resp, err
+}
@@ -0,0 +1,145 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/sctx"
+	"github.com/ethersphere/bee/pkg/settlement/swap/chequebook"
+	chequebookmock "github.com/ethersphere/bee/pkg/settlement/swap/chequebook/mock"
+	swapmock "github.com/ethersphere/bee/pkg/settlement/swap/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestChequebook(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		address = common.HexToAddress("0xfffff")
+		txHash  = common.HexToHash("0xffff")
+		peer    = swarm.MustParseHexAddress("ca1e9f3938cc1425c6061b96ad9eb93e134dfe8734ad490164ef20af9d1cf59c")
+		cheque  = &chequebook.SignedCheque{
+			Cheque: chequebook.Cheque{
+				Chequebook:       common.HexToAddress("0xeeee"),
+				Beneficiary:      common.HexToAddress("0xdddd"),
+				CumulativePayout: big.NewInt(42),
+			},
+		}
+
+		deposited, withdrawn, gasPrice *big.Int
+	)
+
+	c := newTestDebugClient(t, debugServerOptions{
+		ChequebookOpts: []chequebookmock.Option{
+			chequebookmock.WithChequebookAddressFunc(func() common.Address { return address }),
+			chequebookmock.WithChequebookBalanceFunc(func(context.Context) (*big.Int, error) { return big.NewInt(100), nil }),
+			chequebookmock.WithChequebookAvailableBalanceFunc(func(context.Context) (*big.Int, error) { return big.NewInt(60), nil }),
+			chequebookmock.WithChequebookDepositFunc(func(ctx context.Context, amount *big.Int) (common.Hash, error) {
+				deposited, gasPrice = amount, sctx.GetGasPrice(ctx)
+				return txHash, nil
+			}),
+			chequebookmock.WithChequebookWithdrawFunc(func(ctx context.Context, amount *big.Int) (common.Hash, error) {
+				withdrawn, gasPrice = amount, sctx.GetGasPrice(ctx)
+				return txHash, nil
+			}),
+		},
+		SwapOpts: []swapmock.Option{
+			swapmock.WithLastReceivedChequeFunc(func(swarm.Address) (*chequebook.SignedCheque, error) { return cheque, nil }),
+			swapmock.WithLastSentChequeFunc(func(swarm.Address) (*chequebook.SignedCheque, error) { return nil, chequebook.ErrNoCheque }),
+			swapmock.WithLastReceivedChequesFunc(func() (map[string]*chequebook.SignedCheque, error) {
+				return map[string]*chequebook.SignedCheque{peer.String(): cheque}, nil
+			}),
+			swapmock.WithLastSentChequesFunc(func() (map[string]*chequebook.SignedCheque, error) { return nil, nil }),
+			swapmock.WithCashChequeFunc(func(context.Context, swarm.Address) (common.Hash, error) { return txHash, nil }),
+			swapmock.WithCashoutStatusFunc(func(context.Context, swarm.Address) (*chequebook.CashoutStatus, error) {
+				return &chequebook.CashoutStatus{UncashedAmount: big.NewInt(42)}, nil
+			}),
+		},
+	})
+
+	t.Run("address", func(t *testing.T) {
+		got, err := c.ChequebookAddress(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != address {
+			t.Fatalf("got address %s, want %s", got, address)
+		}
+	})
+
+	t.Run("balance", func(t *testing.T) {
+		got, err := c.ChequebookBalance(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.TotalBalance.Cmp(big.NewInt(100)) != 0 || got.AvailableBalance.Cmp(big.NewInt(60)) != 0 {
+			t.Fatalf("got total balance %s and available balance %s", got.TotalBalance, got.AvailableBalance)
+		}
+	})
+
+	t.Run("deposit", func(t *testing.T) {
+		got, err := c.ChequebookDeposit(ctx, big.NewInt(10), big.NewInt(5))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != txHash || deposited.Cmp(big.NewInt(10)) != 0 || gasPrice.Cmp(big.NewInt(5)) != 0 {
+			t.Fatalf("got hash %s, amount %s and gas price %s", got, deposited, gasPrice)
+		}
+	})
+
+	t.Run("withdraw", func(t *testing.T) {
+		got, err := c.ChequebookWithdraw(ctx, big.NewInt(20), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != txHash || withdrawn.Cmp(big.NewInt(20)) != 0 || gasPrice != nil {
+			t.Fatalf("got hash %s, amount %s and gas price %s", got, withdrawn, gasPrice)
+		}
+	})
+
+	t.Run("last cheques", func(t *testing.T) {
+		got, err := c.LastCheques(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 || got[0].Peer != peer.String() || got[0].LastReceived == nil || got[0].LastSent != nil {
+			t.Fatalf("got cheques %+v", got)
+		}
+		if got[0].LastReceived.Payout.Cmp(big.NewInt(42)) != 0 {
+			t.Fatalf("got payout %s, want 42", got[0].LastReceived.Payout)
+		}
+	})
+
+	t.Run("last cheques of peer", func(t *testing.T) {
+		got, err := c.LastChequesPeer(ctx, peer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Peer != peer.String() || got.LastReceived == nil || got.LastSent != nil {
+			t.Fatalf("got cheques %+v", got)
+		}
+	})
+
+	t.Run("cashout", func(t *testing.T) {
+		got, err := c.Cashout(ctx, peer, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != txHash {
+			t.Fatalf("got hash %s, want %s", got, txHash)
+		}
+
+		status, err := c.CashoutStatus(ctx, peer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !status.Peer.Equal(peer) || status.Result != nil || status.UncashedAmount.Cmp(big.NewInt(42)) != 0 {
+			t.Fatalf("got cashout status %+v", status)
+		}
+	})
+}
@@ -0,0 +1,190 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package client provides the clients of the Bee HTTP API and the Bee Debug
+// HTTP API. The requests and the responses are the types of the api and the
+// debugapi packages which serve them, so the clients and the servers can not
+// diverge.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+)
+
+// Options are the options of the clients.
+type Options struct {
+	// HTTPClient sends the requests, http.DefaultClient if nil.
+	HTTPClient *http.Client
+	// Token is the security token sent with the requests to the restricted
+	// APIs.
+	Token string
+}
+
+// Client is a client of the Bee HTTP API.
+type Client struct {
+	service
+}
+
+// New returns a new client of the Bee HTTP API served at the base URL.
+func New(baseURL string, o *Options) (*Client, error) {
+	s, err := newService(baseURL, o)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{service: s}, nil
+}
+
+// DebugClient is a client of the Bee Debug HTTP API.
+type DebugClient struct {
+	service
+}
+
+// NewDebug returns a new client of the Bee Debug HTTP API served at the base
+// URL.
+func NewDebug(baseURL string, o *Options) (*DebugClient, error) {
+	s, err := newService(baseURL, o)
+	if err != nil {
+		return nil, err
+	}
+	return &DebugClient{service: s}, nil
+}
+
+// Error is the error returned for the responses with an unsuccessful status
+// code.
+type Error struct {
+	Code    int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("response status %d: %s", e.Code, e.Message)
+}
+
+// IsNotFound reports whether the error is the response for a resource which
+// was not found.
+func IsNotFound(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.Code == http.StatusNotFound
+}
+
+// service sends the requests of the clients.
+type service struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	token      string
+}
+
+func newService(baseURL string, o *Options) (service, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return service{}, fmt.Errorf("parse base url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return service{}, fmt.Errorf("unsupported base url scheme %q", u.Scheme)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+
+	if o == nil {
+		o = new(Options)
+	}
+	httpClient := o.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return service{baseURL: u, httpClient: httpClient, token: o.Token}, nil
+}
+
+// url returns the URL of the path, which is not escaped, with the query.
+func (s *service) url(path string, query url.Values) *url.URL {
+	u := *s.baseURL
+	u.Path += path
+	u.RawQuery = query.Encode()
+	return &u
+}
+
+func (s *service) newRequest(ctx context.Context, method, path string, query url.Values, body io.Reader) (*http.Request, error) {
+	r, err := http.NewRequestWithContext(ctx, method, s.url(path, query).String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if s.token != "" {
+		r.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	return r, nil
+}
+
+// send sends the request and returns the response if its status code is
+// successful. The body of the response must be closed.
+func (s *service) send(r *http.Request) (*http.Response, error) {
+	resp, err := s.httpClient.Do(r)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return resp, nil
+	}
+	defer drain(resp.Body)
+
+	e := &Error{Code: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+	var sr jsonhttp.StatusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&sr); err == nil && sr.Message != "" {
+		e.Message = sr.Message
+	}
+	return nil, e
+}
+
+// sendJSON sends the request and decodes the JSON body of the response into
+// v, unless v is nil. The returned response has the body closed.
+func (s *service) sendJSON(r *http.Request, v interface{}) (*http.Response, error) {
+	resp, err := s.send(r)
+	if err != nil {
+		return nil, err
+	}
+	defer drain(resp.Body)
+
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			return nil, fmt.Errorf("decode response: %w", err)
+		}
+	}
+	return resp, nil
+}
+
+// requestJSON sends the request with the JSON encoding of body, unless it is
+// nil, and decodes the JSON body of the response into v, unless it is nil.
+func (s *service) requestJSON(ctx context.Context, method, path string, query url.Values, body, v interface{}) (*http.Response, error) {
+	var data io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		data = bytes.NewReader(b)
+	}
+	r, err := s.newRequest(ctx, method, path, query, data)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		r.Header.Set("Content-Type", jsonhttp.DefaultContentTypeHeader)
+	}
+	return s.sendJSON(r, v)
+}
+
+// drain reads the rest of the body so that the connection can be reused and
+// closes it.
+func drain(body io.ReadCloser) {
+	_, _ = io.Copy(io.Discard, body)
+	_ = body.Close()
+}
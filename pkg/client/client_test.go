@@ -0,0 +1,199 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client_test
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	accountingmock "github.com/ethersphere/bee/pkg/accounting/mock"
+	"github.com/ethersphere/bee/pkg/api"
+	mockauth "github.com/ethersphere/bee/pkg/auth/mock"
+	"github.com/ethersphere/bee/pkg/client"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/debugapi"
+	"github.com/ethersphere/bee/pkg/feeds/factory"
+	"github.com/ethersphere/bee/pkg/logging"
+	p2pmock "github.com/ethersphere/bee/pkg/p2p/mock"
+	pinningmock "github.com/ethersphere/bee/pkg/pinning/mock"
+	"github.com/ethersphere/bee/pkg/postage"
+	batchstoremock "github.com/ethersphere/bee/pkg/postage/batchstore/mock"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	"github.com/ethersphere/bee/pkg/postage/postagecontract"
+	contractmock "github.com/ethersphere/bee/pkg/postage/postagecontract/mock"
+	"github.com/ethersphere/bee/pkg/pss"
+	resolvermock "github.com/ethersphere/bee/pkg/resolver/mock"
+	"github.com/ethersphere/bee/pkg/resumable"
+	chequebookmock "github.com/ethersphere/bee/pkg/settlement/swap/chequebook/mock"
+	erc20mock "github.com/ethersphere/bee/pkg/settlement/swap/erc20/mock"
+	swapmock "github.com/ethersphere/bee/pkg/settlement/swap/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	stewardmock "github.com/ethersphere/bee/pkg/steward/mock"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tags"
+	"github.com/ethersphere/bee/pkg/topology/lightnode"
+	topologymock "github.com/ethersphere/bee/pkg/topology/mock"
+	"github.com/ethersphere/bee/pkg/transaction/backendmock"
+	transactionmock "github.com/ethersphere/bee/pkg/transaction/mock"
+	"github.com/ethersphere/bee/pkg/traversal"
+)
+
+var batchID = make([]byte, 32)
+
+func init() {
+	_, _ = rand.Read(batchID)
+}
+
+type testServerOptions struct {
+	Storer storage.Storer
+	Pss    pss.Interface
+}
+
+// newTestClient returns a client of an API server with the storer and the pss
+// in the options, and with the mocks of the other dependencies.
+func newTestClient(t *testing.T, o testServerOptions) *client.Client {
+	t.Helper()
+
+	pk, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := logging.New(io.Discard, 0)
+	if o.Storer == nil {
+		o.Storer = mock.NewStorer()
+	}
+	s, _ := api.New(
+		tags.NewTags(statestore.NewStateStore(), logger),
+		resumable.New(statestore.NewStateStore(), nil),
+		o.Storer,
+		resolvermock.NewResolver(),
+		o.Pss,
+		traversal.New(o.Storer),
+		pinningmock.NewServiceMock(),
+		factory.New(o.Storer),
+		mockpost.New(mockpost.WithAcceptAll()),
+		nil,
+		&stewardmock.Steward{},
+		crypto.NewDefaultSigner(pk),
+		&mockauth.Auth{},
+		logger,
+		nil,
+		api.Options{WsPingPeriod: 60 * time.Second},
+	)
+	ts := httptest.NewServer(s)
+	t.Cleanup(func() {
+		_ = s.Close()
+		ts.Close()
+	})
+
+	c, err := client.New(ts.URL, &client.Options{HTTPClient: ts.Client()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+type debugServerOptions struct {
+	P2P             *p2pmock.Service
+	Post            postage.Service
+	BatchStore      postage.Storer
+	PostageContract postagecontract.Interface
+	ChequebookOpts  []chequebookmock.Option
+	SwapOpts        []swapmock.Option
+}
+
+// newTestDebugClient returns a client of a Debug API server with the
+// dependencies in the options, or with the mocks of the ones which are not
+// set.
+func newTestDebugClient(t *testing.T, o debugServerOptions) *client.DebugClient {
+	t.Helper()
+
+	pk, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := logging.New(io.Discard, 0)
+	if o.P2P == nil {
+		o.P2P = p2pmock.New()
+	}
+	if o.Post == nil {
+		o.Post = mockpost.New()
+	}
+	if o.BatchStore == nil {
+		o.BatchStore = batchstoremock.New()
+	}
+	if o.PostageContract == nil {
+		o.PostageContract = contractmock.New()
+	}
+	overlay := swarm.MustParseHexAddress("ca1e9f3938cc1425c6061b96ad9eb93e134dfe8734ad490164ef20af9d1cf59c")
+	s := debugapi.New(pk.PublicKey, pk.PublicKey, common.Address{}, logger, nil, nil, nil, big.NewInt(2), transactionmock.New(), backendmock.New(), false, nil, false, debugapi.FullMode, 1)
+	s.Configure(overlay, o.P2P, nil, topologymock.NewTopologyDriver(), lightnode.NewContainer(overlay), mock.NewStorer(), tags.NewTags(statestore.NewStateStore(), logger), accountingmock.NewAccounting(), swapmock.New(), true, true, swapmock.New(o.SwapOpts...), chequebookmock.NewChequebook(o.ChequebookOpts...), o.BatchStore, o.Post, o.PostageContract, nil, erc20mock.New())
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+
+	c, err := client.NewDebug(ts.URL, &client.Options{HTTPClient: ts.Client()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestNew(t *testing.T) {
+	for _, u := range []string{"localhost:1633", "ftp://localhost", "http://[::1"} {
+		if _, err := client.New(u, nil); err == nil {
+			t.Errorf("%s: got no error", u)
+		}
+	}
+	if _, err := client.New("http://localhost:1633/", nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestError(t *testing.T) {
+	c := newTestClient(t, testServerOptions{})
+
+	_, err := c.GetTag(context.Background(), 1)
+	var e *client.Error
+	if !errors.As(err, &e) {
+		t.Fatalf("got error %v, want %T", err, e)
+	}
+	if e.Code != http.StatusNotFound || e.Message != "tag not present" {
+		t.Fatalf("got error %+v", e)
+	}
+	if !client.IsNotFound(err) {
+		t.Fatal("error is not not found")
+	}
+}
+
+func TestToken(t *testing.T) {
+	var got string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"isRetrievable":true}`))
+	}))
+	defer ts.Close()
+
+	c, err := client.New(ts.URL+"/", &client.Options{Token: "token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.IsRetrievable(context.Background(), swarm.ZeroAddress); err != nil {
+		t.Fatal(err)
+	}
+	if got != "Bearer token" {
+		t.Fatalf("got authorization %q", got)
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/feeds/sequence"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// FeedUpdate is an update of a sequence feed.
+type FeedUpdate struct {
+	// Reference is the reference in the payload of the update.
+	Reference swarm.Address
+	// Index is the index of the update and NextIndex is the index of the
+	// next update of the feed.
+	Index     uint64
+	NextIndex uint64
+}
+
+// UploadSOC uploads the single owner chunk with the ID and the signature of
+// the owner, which wraps the data of a content addressed chunk, the span
+// followed by the payload.
+func (c *Client) UploadSOC(ctx context.Context, owner common.Address, id, signature, data []byte, o UploadOptions) (UploadResult, error) {
+	query := url.Values{"sig": {hex.EncodeToString(signature)}}
+	path := "/soc/" + hex.EncodeToString(owner.Bytes()) + "/" + hex.EncodeToString(id)
+	r, err := c.newRequest(ctx, http.MethodPost, path, query, bytes.NewReader(data))
+	if err != nil {
+		return UploadResult{}, err
+	}
+	r.Header.Set("Content-Type", "application/octet-stream")
+
+	var resp api.SocPostResponse
+	tag, err := c.upload(r, o, &resp)
+	if err != nil {
+		return UploadResult{}, err
+	}
+	return UploadResult{Reference: resp.Reference, TagUID: tag}, nil
+}
+
+// CreateFeedManifest uploads the manifest of the sequence feed of the owner
+// with the topic and returns its reference. The manifest resolves to the
+// content referenced by the latest update of the feed.
+func (c *Client) CreateFeedManifest(ctx context.Context, owner common.Address, topic []byte, o UploadOptions) (swarm.Address, error) {
+	r, err := c.newRequest(ctx, http.MethodPost, feedPath(owner, topic), nil, nil)
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+
+	var resp api.FeedReferenceResponse
+	if _, err := c.upload(r, o, &resp); err != nil {
+		return swarm.ZeroAddress, err
+	}
+	return resp.Reference, nil
+}
+
+// FindFeedUpdate returns the latest update of the sequence feed of the owner
+// with the topic.
+func (c *Client) FindFeedUpdate(ctx context.Context, owner common.Address, topic []byte) (FeedUpdate, error) {
+	query := url.Values{"at": {strconv.FormatInt(time.Now().Unix(), 10)}}
+
+	var resp api.FeedReferenceResponse
+	r, err := c.requestJSON(ctx, http.MethodGet, feedPath(owner, topic), query, nil, &resp)
+	if err != nil {
+		return FeedUpdate{}, err
+	}
+
+	u := FeedUpdate{Reference: resp.Reference}
+	if u.Index, err = feedIndex(r.Header.Get(api.SwarmFeedIndexHeader)); err != nil {
+		return FeedUpdate{}, fmt.Errorf("feed index: %w", err)
+	}
+	if u.NextIndex, err = feedIndex(r.Header.Get(api.SwarmFeedIndexNextHeader)); err != nil {
+		return FeedUpdate{}, fmt.Errorf("next feed index: %w", err)
+	}
+	return u, nil
+}
+
+// UpdateFeed uploads the update with the index of the sequence feed of the
+// signer with the topic, which references the content with the reference.
+// The index of the first update of a feed is zero, the index of the next
+// updates is returned by FindFeedUpdate.
+func (c *Client) UpdateFeed(ctx context.Context, signer crypto.Signer, topic []byte, index uint64, reference swarm.Address, o UploadOptions) error {
+	owner, err := signer.EthereumAddress()
+	if err != nil {
+		return err
+	}
+	p := &socPutter{client: c, owner: owner, o: o}
+	u, err := sequence.NewUpdaterAt(p, signer, topic, index)
+	if err != nil {
+		return err
+	}
+	return u.Update(ctx, time.Now().Unix(), reference.Bytes())
+}
+
+// socPutter uploads the signed single owner chunks of an owner.
+type socPutter struct {
+	client *Client
+	owner  common.Address
+	o      UploadOptions
+}
+
+func (p *socPutter) Put(ctx context.Context, _ storage.ModePut, chs ...swarm.Chunk) ([]bool, error) {
+	for _, ch := range chs {
+		data := ch.Data()
+		if len(data) < swarm.SocMinChunkSize {
+			return nil, fmt.Errorf("short single owner chunk %s", ch.Address())
+		}
+		id := data[:swarm.HashSize]
+		signature := data[swarm.HashSize : swarm.HashSize+swarm.SocSignatureSize]
+		if _, err := p.client.UploadSOC(ctx, p.owner, id, signature, data[swarm.HashSize+swarm.SocSignatureSize:], p.o); err != nil {
+			return nil, err
+		}
+	}
+	return make([]bool, len(chs)), nil
+}
+
+func feedPath(owner common.Address, topic []byte) string {
+	return "/feeds/" + hex.EncodeToString(owner.Bytes()) + "/" + hex.EncodeToString(topic)
+}
+
+// feedIndex decodes the hex encoded index of a sequence feed.
+func feedIndex(h string) (uint64, error) {
+	b, err := hex.DecodeString(h)
+	if err != nil {
+		return 0, err
+	}
+	if len(b) != 8 {
+		return 0, fmt.Errorf("invalid length %d", len(b))
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/client"
+	"github.com/ethersphere/bee/pkg/crypto"
+)
+
+func TestFeed(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		c     = newTestClient(t, testServerOptions{})
+		o     = client.UploadOptions{BatchID: batchID}
+		topic = []byte("topic")
+	)
+
+	pk, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := crypto.NewDefaultSigner(pk)
+	owner, err := signer.EthereumAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.FindFeedUpdate(ctx, owner, topic); !client.IsNotFound(err) {
+		t.Fatalf("got error %v, want not found", err)
+	}
+
+	manifest, err := c.CreateFeedManifest(ctx, owner, topic, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, data := range []string{"first version", "second version"} {
+		res, err := c.UploadFile(ctx, "file.txt", "text/plain", strings.NewReader(data), o)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.UpdateFeed(ctx, signer, topic, uint64(i), res.Reference, o); err != nil {
+			t.Fatal(err)
+		}
+
+		u, err := c.FindFeedUpdate(ctx, owner, topic)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !u.Reference.Equal(res.Reference) {
+			t.Fatalf("got reference %s, want %s", u.Reference, res.Reference)
+		}
+		if u.Index != uint64(i) || u.NextIndex != uint64(i+1) {
+			t.Fatalf("got index %d and next index %d, want %d and %d", u.Index, u.NextIndex, i, i+1)
+		}
+
+		f, err := c.DownloadFile(ctx, manifest, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != data {
+			t.Fatalf("got data %q from the feed manifest, want %q", got, data)
+		}
+	}
+}
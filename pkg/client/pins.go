@@ -0,0 +1,47 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// Pin pins the content with the reference locally.
+func (c *Client) Pin(ctx context.Context, reference swarm.Address) error {
+	_, err := c.requestJSON(ctx, http.MethodPost, "/pins/"+reference.String(), nil, nil, nil)
+	return err
+}
+
+// Unpin removes the pin of the content with the reference.
+func (c *Client) Unpin(ctx context.Context, reference swarm.Address) error {
+	_, err := c.requestJSON(ctx, http.MethodDelete, "/pins/"+reference.String(), nil, nil, nil)
+	return err
+}
+
+// IsPinned reports whether the content with the reference is pinned.
+func (c *Client) IsPinned(ctx context.Context, reference swarm.Address) (bool, error) {
+	var resp api.PinResponse
+	_, err := c.requestJSON(ctx, http.MethodGet, "/pins/"+reference.String(), nil, nil, &resp)
+	if IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Pins returns the references of the pinned content.
+func (c *Client) Pins(ctx context.Context) ([]swarm.Address, error) {
+	var resp api.ListPinsResponse
+	if _, err := c.requestJSON(ctx, http.MethodGet, "/pins", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.References, nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/client"
+)
+
+func TestPins(t *testing.T) {
+	var (
+		ctx = context.Background()
+		c   = newTestClient(t, testServerOptions{})
+	)
+
+	pinned, err := c.UploadBytes(ctx, strings.NewReader("pinned on upload"), client.UploadOptions{BatchID: batchID, Pin: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := c.UploadBytes(ctx, strings.NewReader("pinned later"), client.UploadOptions{BatchID: batchID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ok, err := c.IsPinned(ctx, other.Reference); err != nil || ok {
+		t.Fatalf("got pinned %t and error %v, want not pinned", ok, err)
+	}
+	if err := c.Pin(ctx, other.Reference); err != nil {
+		t.Fatal(err)
+	}
+
+	refs, err := c.Pins(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(refs) != 2 {
+		t.Fatalf("got %d pins, want 2", len(refs))
+	}
+	for _, ref := range refs {
+		if !ref.Equal(pinned.Reference) && !ref.Equal(other.Reference) {
+			t.Fatalf("got unexpected pin %s", ref)
+		}
+	}
+
+	if err := c.Unpin(ctx, pinned.Reference); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := c.IsPinned(ctx, pinned.Reference); err != nil || ok {
+		t.Fatalf("got pinned %t and error %v, want not pinned", ok, err)
+	}
+	if ok, err := c.IsPinned(ctx, other.Reference); err != nil || !ok {
+		t.Fatalf("got pinned %t and error %v, want pinned", ok, err)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/pss"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/gorilla/websocket"
+)
+
+// SendPSS sends the message with the topic to the recipient, which is in the
+// neighbourhood of one of the targets, the prefixes of the overlay addresses.
+// The message is encrypted with the public key of the recipient, or with a
+// key derived from the topic if the recipient is nil. The trojan chunk of the
+// message is stamped by the postage batch with the batch ID.
+func (c *Client) SendPSS(ctx context.Context, topic string, targets pss.Targets, recipient *ecdsa.PublicKey, data, batchID []byte) error {
+	t := make([]string, 0, len(targets))
+	for _, target := range targets {
+		t = append(t, hex.EncodeToString(target))
+	}
+	var query url.Values
+	if recipient != nil {
+		query = url.Values{"recipient": {hex.EncodeToString(crypto.EncodeSecp256k1PublicKey(recipient))}}
+	}
+
+	r, err := c.newRequest(ctx, http.MethodPost, "/pss/send/"+topic+"/"+strings.Join(t, ","), query, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	r.Header.Set(api.SwarmPostageBatchIdHeader, hex.EncodeToString(batchID))
+	_, err = c.sendJSON(r, nil)
+	return err
+}
+
+// PSSSubscription receives the messages with a topic from a websocket.
+type PSSSubscription struct {
+	conn *websocket.Conn
+}
+
+// SubscribePSS subscribes to the messages with the topic received by the
+// node. The websocket is not opened by the HTTP client of the options.
+func (c *Client) SubscribePSS(ctx context.Context, topic string) (*PSSSubscription, error) {
+	u := c.url("/pss/subscribe/"+topic, nil)
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	default:
+		u.Scheme = "ws"
+	}
+
+	header := make(http.Header)
+	if c.token != "" {
+		header.Set("Authorization", "Bearer "+c.token)
+	}
+	conn, resp, err := websocket.DefaultDialer.DialContext(ctx, u.String(), header)
+	if err != nil {
+		if resp != nil {
+			return nil, &Error{Code: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+		}
+		return nil, err
+	}
+	conn.SetReadLimit(swarm.ChunkSize)
+	return &PSSSubscription{conn: conn}, nil
+}
+
+// Receive waits for the next message of the subscription. It returns an error
+// when the subscription is closed.
+func (s *PSSSubscription) Receive() ([]byte, error) {
+	for {
+		typ, data, err := s.conn.ReadMessage()
+		if err != nil {
+			return nil, err
+		}
+		if typ == websocket.BinaryMessage {
+			return data, nil
+		}
+	}
+}
+
+// Close closes the subscription.
+func (s *PSSSubscription) Close() error {
+	_ = s.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	return s.conn.Close()
+}
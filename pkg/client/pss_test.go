@@ -0,0 +1,57 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/pss"
+	"github.com/ethersphere/bee/pkg/pushsync"
+	pushsyncmock "github.com/ethersphere/bee/pkg/pushsync/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestPSS(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	key, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := pss.New(key, logging.New(io.Discard, 0))
+	t.Cleanup(func() { _ = p.Close() })
+	// the trojan chunks sent by the node are delivered back to it
+	p.SetPushSyncer(pushsyncmock.New(func(_ context.Context, ch swarm.Chunk) (*pushsync.Receipt, error) {
+		p.TryUnwrap(ch)
+		return &pushsync.Receipt{Address: ch.Address()}, nil
+	}))
+
+	c := newTestClient(t, testServerOptions{Pss: p})
+
+	sub, err := c.SubscribePSS(ctx, "topic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	msg := []byte("message")
+	if err := c.SendPSS(ctx, "topic", pss.Targets{{1}}, &key.PublicKey, msg, batchID); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := sub.Receive()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(msg) {
+		t.Fatalf("got message %q, want %q", got, msg)
+	}
+}
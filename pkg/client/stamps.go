@@ -0,0 +1,81 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/ethersphere/bee/pkg/debugapi"
+)
+
+// StampOptions are the options of the creation of postage batches.
+type StampOptions struct {
+	Label string
+	// Immutable makes the batch stop stamping when it is full, instead of
+	// overwriting the oldest stamps.
+	Immutable bool
+	// GasPrice is the gas price of the transaction, estimated if nil.
+	GasPrice *big.Int
+}
+
+// Stamps returns the postage batches of the node.
+func (c *DebugClient) Stamps(ctx context.Context) ([]debugapi.PostageStampResponse, error) {
+	var resp debugapi.PostageStampsResponse
+	if _, err := c.requestJSON(ctx, http.MethodGet, "/stamps", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Stamps, nil
+}
+
+// Stamp returns the postage batch of the node with the batch ID.
+func (c *DebugClient) Stamp(ctx context.Context, batchID []byte) (debugapi.PostageStampResponse, error) {
+	var resp debugapi.PostageStampResponse
+	_, err := c.requestJSON(ctx, http.MethodGet, "/stamps/"+hex.EncodeToString(batchID), nil, nil, &resp)
+	return resp, err
+}
+
+// CreateStamp buys a new postage batch with the depth and the amount per
+// chunk and returns its batch ID.
+func (c *DebugClient) CreateStamp(ctx context.Context, amount *big.Int, depth uint8, o StampOptions) ([]byte, error) {
+	var query url.Values
+	if o.Label != "" {
+		query = url.Values{"label": {o.Label}}
+	}
+	r, err := c.newRequest(ctx, http.MethodPost, "/stamps/"+amount.String()+"/"+strconv.Itoa(int(depth)), query, nil)
+	if err != nil {
+		return nil, err
+	}
+	if o.Immutable {
+		r.Header.Set(debugapi.ImmutableHeader, "true")
+	}
+	if o.GasPrice != nil {
+		r.Header.Set(debugapi.GasPriceHeader, o.GasPrice.String())
+	}
+
+	var resp debugapi.PostageCreateResponse
+	if _, err := c.sendJSON(r, &resp); err != nil {
+		return nil, err
+	}
+	return resp.BatchID, nil
+}
+
+// TopUpStamp adds the amount per chunk to the postage batch with the batch ID.
+func (c *DebugClient) TopUpStamp(ctx context.Context, batchID []byte, amount *big.Int) error {
+	path := "/stamps/topup/" + hex.EncodeToString(batchID) + "/" + amount.String()
+	_, err := c.requestJSON(ctx, http.MethodPatch, path, nil, nil, nil)
+	return err
+}
+
+// DiluteStamp increases the depth of the postage batch with the batch ID.
+func (c *DebugClient) DiluteStamp(ctx context.Context, batchID []byte, depth uint8) error {
+	path := "/stamps/dilute/" + hex.EncodeToString(batchID) + "/" + strconv.Itoa(int(depth))
+	_, err := c.requestJSON(ctx, http.MethodPatch, path, nil, nil, nil)
+	return err
+}
@@ -0,0 +1,114 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/client"
+	"github.com/ethersphere/bee/pkg/postage"
+	batchstoremock "github.com/ethersphere/bee/pkg/postage/batchstore/mock"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	contractmock "github.com/ethersphere/bee/pkg/postage/postagecontract/mock"
+	postagetesting "github.com/ethersphere/bee/pkg/postage/testing"
+)
+
+func TestStamps(t *testing.T) {
+	var (
+		ctx = context.Background()
+		b   = postagetesting.MustNewBatch()
+		si  = postage.NewStampIssuer("label", "", b.ID, big.NewInt(3), 11, 10, 1000, true)
+		cs  = &postage.ChainState{Block: 10, TotalAmount: big.NewInt(5), CurrentPrice: big.NewInt(2)}
+
+		created, toppedUp, diluted []byte
+		createdDepth, dilutedDepth uint8
+		createdAmount, topUpAmount *big.Int
+		createdLabel               string
+		createdImmutable           bool
+	)
+	b.Value = big.NewInt(20)
+
+	c := newTestDebugClient(t, debugServerOptions{
+		Post:       mockpost.New(mockpost.WithIssuer(si)),
+		BatchStore: batchstoremock.New(batchstoremock.WithChainState(cs), batchstoremock.WithBatch(b)),
+		PostageContract: contractmock.New(
+			contractmock.WithCreateBatchFunc(func(_ context.Context, amount *big.Int, depth uint8, immutable bool, label string) ([]byte, error) {
+				created = postagetesting.MustNewID()
+				createdAmount, createdDepth, createdImmutable, createdLabel = amount, depth, immutable, label
+				return created, nil
+			}),
+			contractmock.WithTopUpBatchFunc(func(_ context.Context, id []byte, amount *big.Int) error {
+				toppedUp, topUpAmount = id, amount
+				return nil
+			}),
+			contractmock.WithDiluteBatchFunc(func(_ context.Context, id []byte, depth uint8) error {
+				diluted, dilutedDepth = id, depth
+				return nil
+			}),
+		),
+	})
+
+	t.Run("list", func(t *testing.T) {
+		stamps, err := c.Stamps(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(stamps) != 1 {
+			t.Fatalf("got %d stamps, want 1", len(stamps))
+		}
+		s := stamps[0]
+		if !bytes.Equal(s.BatchID, b.ID) || s.Label != "label" || s.Depth != 11 || s.Amount.Cmp(big.NewInt(3)) != 0 || !s.Exists {
+			t.Fatalf("got stamp %+v", s)
+		}
+	})
+
+	t.Run("get", func(t *testing.T) {
+		s, err := c.Stamp(ctx, b.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(s.BatchID, b.ID) || s.BatchTTL != 15 {
+			t.Fatalf("got stamp %+v", s)
+		}
+
+		if _, err := c.Stamp(ctx, postagetesting.MustNewID()); err == nil {
+			t.Fatal("got no error for an unknown batch")
+		}
+	})
+
+	t.Run("create", func(t *testing.T) {
+		id, err := c.CreateStamp(ctx, big.NewInt(1000), 20, client.StampOptions{Label: "new", Immutable: true, GasPrice: big.NewInt(10)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(id, created) {
+			t.Fatalf("got batch ID %x, want %x", id, created)
+		}
+		if createdAmount.Cmp(big.NewInt(1000)) != 0 || createdDepth != 20 || !createdImmutable || createdLabel != "new" {
+			t.Fatalf("got amount %s, depth %d, immutable %t and label %q", createdAmount, createdDepth, createdImmutable, createdLabel)
+		}
+	})
+
+	t.Run("top up", func(t *testing.T) {
+		if err := c.TopUpStamp(ctx, b.ID, big.NewInt(100)); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(toppedUp, b.ID) || topUpAmount.Cmp(big.NewInt(100)) != 0 {
+			t.Fatalf("got batch ID %x and amount %s", toppedUp, topUpAmount)
+		}
+	})
+
+	t.Run("dilute", func(t *testing.T) {
+		if err := c.DiluteStamp(ctx, b.ID, 12); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(diluted, b.ID) || dilutedDepth != 12 {
+			t.Fatalf("got batch ID %x and depth %d", diluted, dilutedDepth)
+		}
+	})
+}
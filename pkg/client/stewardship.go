@@ -0,0 +1,30 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// Reupload uploads again the locally pinned content with the reference to
+// the network.
+func (c *Client) Reupload(ctx context.Context, reference swarm.Address) error {
+	_, err := c.requestJSON(ctx, http.MethodPut, "/stewardship/"+reference.String(), nil, nil, nil)
+	return err
+}
+
+// IsRetrievable reports whether all the chunks of the content with the
+// reference can be retrieved from the network.
+func (c *Client) IsRetrievable(ctx context.Context, reference swarm.Address) (bool, error) {
+	var resp api.IsRetrievableResponse
+	if _, err := c.requestJSON(ctx, http.MethodGet, "/stewardship/"+reference.String(), nil, nil, &resp); err != nil {
+		return false, err
+	}
+	return resp.IsRetrievable, nil
+}
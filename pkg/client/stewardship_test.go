@@ -0,0 +1,37 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/client"
+)
+
+func TestStewardship(t *testing.T) {
+	var (
+		ctx = context.Background()
+		c   = newTestClient(t, testServerOptions{})
+	)
+
+	res, err := c.UploadBytes(ctx, strings.NewReader("stewarded data"), client.UploadOptions{BatchID: batchID, Pin: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the steward mock only reports the last reuploaded content as
+	// retrievable
+	if ok, err := c.IsRetrievable(ctx, res.Reference); err != nil || ok {
+		t.Fatalf("got retrievable %t and error %v, want not retrievable", ok, err)
+	}
+	if err := c.Reupload(ctx, res.Reference); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := c.IsRetrievable(ctx, res.Reference); err != nil || !ok {
+		t.Fatalf("got retrievable %t and error %v, want retrievable", ok, err)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// CreateTag creates a new tag, which can be given to the uploads to track
+// their progress.
+func (c *Client) CreateTag(ctx context.Context) (api.TagResponse, error) {
+	var resp api.TagResponse
+	_, err := c.requestJSON(ctx, http.MethodPost, "/tags", nil, nil, &resp)
+	return resp, err
+}
+
+// GetTag returns the tag with the UID.
+func (c *Client) GetTag(ctx context.Context, uid uint32) (api.TagResponse, error) {
+	var resp api.TagResponse
+	_, err := c.requestJSON(ctx, http.MethodGet, tagPath(uid), nil, nil, &resp)
+	return resp, err
+}
+
+// ListTags returns at most limit tags, skipping the first offset tags.
+func (c *Client) ListTags(ctx context.Context, offset, limit int) ([]api.TagResponse, error) {
+	query := url.Values{
+		"offset": {strconv.Itoa(offset)},
+		"limit":  {strconv.Itoa(limit)},
+	}
+	var resp api.ListTagsResponse
+	if _, err := c.requestJSON(ctx, http.MethodGet, "/tags", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Tags, nil
+}
+
+// DeleteTag deletes the tag with the UID.
+func (c *Client) DeleteTag(ctx context.Context, uid uint32) error {
+	_, err := c.requestJSON(ctx, http.MethodDelete, tagPath(uid), nil, nil, nil)
+	return err
+}
+
+// DoneSplit records that the data with the reference, tracked by the tag with
+// the UID, is completely split into chunks, which sets the total number of the
+// chunks of the tag.
+func (c *Client) DoneSplit(ctx context.Context, uid uint32, reference swarm.Address) error {
+	_, err := c.requestJSON(ctx, http.MethodPatch, tagPath(uid), nil, api.TagRequest{Address: reference}, nil)
+	return err
+}
+
+func tagPath(uid uint32) string {
+	return "/tags/" + strconv.FormatUint(uint64(uid), 10)
+}
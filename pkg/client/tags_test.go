@@ -0,0 +1,59 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/client"
+)
+
+func TestTags(t *testing.T) {
+	var (
+		ctx = context.Background()
+		c   = newTestClient(t, testServerOptions{})
+	)
+
+	tag, err := c.CreateTag(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := c.UploadBytes(ctx, strings.NewReader("tagged data"), client.UploadOptions{BatchID: batchID, Tag: tag.Uid})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.TagUID != tag.Uid {
+		t.Fatalf("got tag %d, want %d", res.TagUID, tag.Uid)
+	}
+	if err := c.DoneSplit(ctx, tag.Uid, res.Reference); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := c.GetTag(ctx, tag.Uid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Total != 1 || got.Processed != 1 {
+		t.Fatalf("got tag %+v", got)
+	}
+
+	list, err := c.ListTags(ctx, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Uid != tag.Uid {
+		t.Fatalf("got tags %+v", list)
+	}
+
+	if err := c.DeleteTag(ctx, tag.Uid); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetTag(ctx, tag.Uid); !client.IsNotFound(err) {
+		t.Fatalf("got error %v, want not found", err)
+	}
+}
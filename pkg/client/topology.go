@@ -0,0 +1,31 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ethersphere/bee/pkg/debugapi"
+	"github.com/ethersphere/bee/pkg/topology"
+)
+
+// Topology returns the snapshot of the Kademlia topology of the node.
+func (c *DebugClient) Topology(ctx context.Context) (*topology.KadParams, error) {
+	var resp topology.KadParams
+	if _, err := c.requestJSON(ctx, http.MethodGet, "/topology", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Peers returns the peers connected to the node.
+func (c *DebugClient) Peers(ctx context.Context) ([]debugapi.Peer, error) {
+	var resp debugapi.PeersResponse
+	if _, err := c.requestJSON(ctx, http.MethodGet, "/peers", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Peers, nil
+}
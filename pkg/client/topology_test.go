@@ -0,0 +1,37 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/p2p"
+	p2pmock "github.com/ethersphere/bee/pkg/p2p/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestTopology(t *testing.T) {
+	ctx := context.Background()
+	peer := swarm.MustParseHexAddress("ca1e9f3938cc1425c6061b96ad9eb93e134dfe8734ad490164ef20af9d1cf59c")
+
+	c := newTestDebugClient(t, debugServerOptions{
+		P2P: p2pmock.New(p2pmock.WithPeersFunc(func() []p2p.Peer {
+			return []p2p.Peer{{Address: peer, FullNode: true}}
+		})),
+	})
+
+	if _, err := c.Topology(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	peers, err := c.Peers(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(peers) != 1 || !peers[0].Address.Equal(peer) || !peers[0].FullNode {
+		t.Fatalf("got peers %+v", peers)
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package crypto
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// NonceSize is the size of the nonce an overlay address is derived from.
+const NonceSize = 32
+
+// maxNeighbourhoodBits limits the neighbourhood depth to a prefix which can
+// be mined in a reasonable time.
+const maxNeighbourhoodBits = 32
+
+// ErrInvalidNeighbourhood denotes a malformed target neighbourhood.
+var ErrInvalidNeighbourhood = errors.New("invalid neighbourhood")
+
+// Neighbourhood is a prefix of overlay addresses.
+type Neighbourhood struct {
+	prefix []byte
+	bits   int
+}
+
+// ParseNeighbourhood parses a neighbourhood given as a string of bits, most
+// significant bit first, for example "0110".
+func ParseNeighbourhood(s string) (Neighbourhood, error) {
+	if len(s) == 0 || len(s) > maxNeighbourhoodBits {
+		return Neighbourhood{}, fmt.Errorf("%q: length must be between 1 and %d bits: %w", s, maxNeighbourhoodBits, ErrInvalidNeighbourhood)
+	}
+	n := Neighbourhood{
+		prefix: make([]byte, (len(s)+7)/8),
+		bits:   len(s),
+	}
+	for i, c := range s {
+		switch c {
+		case '0':
+		case '1':
+			n.prefix[i/8] |= 0x80 >> (i % 8)
+		default:
+			return Neighbourhood{}, fmt.Errorf("%q: %w", s, ErrInvalidNeighbourhood)
+		}
+	}
+	return n, nil
+}
+
+// Contains reports whether the address is in the neighbourhood.
+func (n Neighbourhood) Contains(addr swarm.Address) bool {
+	b := addr.Bytes()
+	if len(b)*8 < n.bits {
+		return false
+	}
+	for i := 0; i < n.bits; i++ {
+		mask := byte(0x80 >> (i % 8))
+		if b[i/8]&mask != n.prefix[i/8]&mask {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the neighbourhood as a string of bits.
+func (n Neighbourhood) String() string {
+	s := make([]byte, n.bits)
+	for i := range s {
+		s[i] = '0'
+		if n.prefix[i/8]&(0x80>>(i%8)) != 0 {
+			s[i] = '1'
+		}
+	}
+	return string(s)
+}
+
+// MineOverlayNonce searches for a nonce such that the overlay address
+// derived from the public key, the network ID and the nonce falls within the
+// neighbourhood. Each bit of the neighbourhood doubles the expected time of
+// mining. Mining stops with the context error when the context is done.
+func MineOverlayNonce(ctx context.Context, p ecdsa.PublicKey, networkID uint64, n Neighbourhood) (nonce []byte, overlay swarm.Address, err error) {
+	ethAddr, err := NewEthereumAddress(p)
+	if err != nil {
+		return nil, swarm.ZeroAddress, err
+	}
+
+	nonce = make([]byte, NonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, swarm.ZeroAddress, err
+	}
+
+	for i := 0; ; i++ {
+		if i%4096 == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, swarm.ZeroAddress, err
+			}
+		}
+
+		overlay = NewOverlayFromEthereumAddress(ethAddr, networkID, nonce)
+		if n.Contains(overlay) {
+			return nonce, overlay, nil
+		}
+		incrementNonce(nonce)
+	}
+}
+
+// incrementNonce increments the nonce as a big endian number.
+func incrementNonce(nonce []byte) {
+	for i := len(nonce) - 1; i >= 0; i-- {
+		nonce[i]++
+		if nonce[i] != 0 {
+			return
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package crypto_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestParseNeighbourhood(t *testing.T) {
+	for _, tc := range []struct {
+		in      string
+		overlay string
+		want    bool
+	}{
+		{in: "0", overlay: "7f00000000000000000000000000000000000000000000000000000000000000", want: true},
+		{in: "1", overlay: "7f00000000000000000000000000000000000000000000000000000000000000", want: false},
+		{in: "0111111101", overlay: "7f40000000000000000000000000000000000000000000000000000000000000", want: true},
+		{in: "0111111101", overlay: "7f00000000000000000000000000000000000000000000000000000000000000", want: false},
+	} {
+		n, err := crypto.ParseNeighbourhood(tc.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := n.String(); got != tc.in {
+			t.Fatalf("got %s, want %s", got, tc.in)
+		}
+		if got := n.Contains(swarm.MustParseHexAddress(tc.overlay)); got != tc.want {
+			t.Fatalf("%s contains %s: got %v, want %v", tc.in, tc.overlay, got, tc.want)
+		}
+	}
+
+	for _, in := range []string{"", "012", "000000000000000000000000000000000"} {
+		if _, err := crypto.ParseNeighbourhood(in); !errors.Is(err, crypto.ErrInvalidNeighbourhood) {
+			t.Fatalf("%q: got error %v, want %v", in, err, crypto.ErrInvalidNeighbourhood)
+		}
+	}
+}
+
+func TestMineOverlayNonce(t *testing.T) {
+	key, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := crypto.ParseNeighbourhood("10110")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nonce, overlay, err := crypto.MineOverlayNonce(context.Background(), key.PublicKey, 1, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !n.Contains(overlay) {
+		t.Fatalf("overlay %s not in neighbourhood %s", overlay, n)
+	}
+	want, err := crypto.NewOverlayAddress(key.PublicKey, 1, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !want.Equal(overlay) {
+		t.Fatalf("got overlay %s, want %s", overlay, want)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := crypto.MineOverlayNonce(ctx, key.PublicKey, 1, n); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+}
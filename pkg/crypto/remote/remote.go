@@ -0,0 +1,272 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package remote provides a crypto.Signer which delegates signing to a
+// remote signing service over JSON-RPC, so that keys can be kept in a
+// separate, possibly HSM-backed, service.
+//
+// The service must implement the following methods, where all byte values
+// are hex encoded with a 0x prefix:
+//
+//	signer_publicKey()                        -> public key
+//	signer_signData(data)                     -> eip191 signature of data
+//	signer_signTransaction(tx, chainID)       -> signed binary transaction
+//	signer_signTypedData(typedData)           -> eip712 signature
+//
+// Signatures are in the [R || S || V] format with V being 27 or 28.
+package remote
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/crypto/eip712"
+)
+
+// DefaultTimeout is the default timeout of a single signing request.
+const DefaultTimeout = 30 * time.Second
+
+// Allowlist entries.
+const (
+	// AllowData allows signing of arbitrary data.
+	AllowData = "data"
+	// AllowTx allows signing of all transactions. Transactions to a single
+	// address are allowed with "tx:<address>".
+	AllowTx = "tx"
+	// AllowTypedData allows signing of all typed data. Typed data of a
+	// single domain is allowed with "typed-data:<domain name>".
+	AllowTypedData = "typed-data"
+)
+
+var (
+	// ErrNotAllowed denotes that signing is not allowed by the allowlist.
+	ErrNotAllowed = errors.New("signing not allowed")
+	// ErrInvalidAllowlist denotes an unknown allowlist entry.
+	ErrInvalidAllowlist = errors.New("invalid allowlist entry")
+	// ErrInvalidSignature denotes that the remote signer returned a
+	// signature which was not made by its key.
+	ErrInvalidSignature = errors.New("invalid signature from remote signer")
+)
+
+// Client is the interface for rpc.Client.
+type Client interface {
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+}
+
+type remoteSigner struct {
+	client    Client
+	timeout   time.Duration
+	allowlist *allowlist
+	pubKey    *ecdsa.PublicKey
+	address   common.Address
+}
+
+// Option is a function that applies an option to the signer.
+type Option func(*remoteSigner)
+
+// WithTimeout sets the timeout of a single signing request.
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *remoteSigner) {
+		s.timeout = timeout
+	}
+}
+
+// NewSigner returns a signer which uses the remote signing service behind
+// the client. The public key is requested from the service on creation.
+// An empty allowlist allows signing of everything.
+func NewSigner(client Client, allow []string, opts ...Option) (crypto.Signer, error) {
+	al, err := parseAllowlist(allow)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &remoteSigner{
+		client:    client,
+		timeout:   DefaultTimeout,
+		allowlist: al,
+	}
+	for _, o := range opts {
+		o(s)
+	}
+
+	var key hexutil.Bytes
+	if err := s.call(&key, "signer_publicKey"); err != nil {
+		return nil, fmt.Errorf("public key: %w", err)
+	}
+	pubKey, err := btcec.ParsePubKey(key, btcec.S256())
+	if err != nil {
+		return nil, fmt.Errorf("public key: %w", err)
+	}
+	s.pubKey = (*ecdsa.PublicKey)(pubKey)
+
+	address, err := crypto.NewEthereumAddress(*s.pubKey)
+	if err != nil {
+		return nil, err
+	}
+	s.address = common.BytesToAddress(address)
+
+	return s, nil
+}
+
+// PublicKey returns the public key of the remote signer.
+func (s *remoteSigner) PublicKey() (*ecdsa.PublicKey, error) {
+	return s.pubKey, nil
+}
+
+// EthereumAddress returns the ethereum address of the remote signer.
+func (s *remoteSigner) EthereumAddress() (common.Address, error) {
+	return s.address, nil
+}
+
+// Sign signs data with ethereum prefix (eip191 type 0x45).
+func (s *remoteSigner) Sign(data []byte) ([]byte, error) {
+	if !s.allowlist.data {
+		return nil, fmt.Errorf("data: %w", ErrNotAllowed)
+	}
+
+	var sig hexutil.Bytes
+	if err := s.call(&sig, "signer_signData", hexutil.Bytes(data)); err != nil {
+		return nil, err
+	}
+
+	pubKey, err := crypto.Recover(sig, data)
+	if err != nil || !s.pubKey.Equal(pubKey) {
+		return nil, ErrInvalidSignature
+	}
+	return sig, nil
+}
+
+// SignTx signs an ethereum transaction.
+func (s *remoteSigner) SignTx(transaction *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if !s.allowlist.allowTx(transaction.To()) {
+		return nil, fmt.Errorf("transaction to %v: %w", transaction.To(), ErrNotAllowed)
+	}
+
+	raw, err := transaction.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var signed hexutil.Bytes
+	if err := s.call(&signed, "signer_signTransaction", hexutil.Bytes(raw), (*hexutil.Big)(chainID)); err != nil {
+		return nil, err
+	}
+
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(signed); err != nil {
+		return nil, fmt.Errorf("decode transaction: %w", err)
+	}
+
+	// Ensure that the remote signer signed the transaction we asked for.
+	txSigner := types.NewEIP155Signer(chainID)
+	if txSigner.Hash(tx) != txSigner.Hash(transaction) {
+		return nil, fmt.Errorf("transaction changed: %w", ErrInvalidSignature)
+	}
+	if sender, err := types.Sender(txSigner, tx); err != nil || sender != s.address {
+		return nil, ErrInvalidSignature
+	}
+
+	return tx, nil
+}
+
+// SignTypedData signs data according to eip712.
+func (s *remoteSigner) SignTypedData(typedData *eip712.TypedData) ([]byte, error) {
+	if !s.allowlist.allowTypedData(typedData.Domain.Name) {
+		return nil, fmt.Errorf("typed data of domain %q: %w", typedData.Domain.Name, ErrNotAllowed)
+	}
+
+	var sig hexutil.Bytes
+	if err := s.call(&sig, "signer_signTypedData", typedData); err != nil {
+		return nil, err
+	}
+
+	pubKey, err := crypto.RecoverEIP712(sig, typedData)
+	if err != nil || !s.pubKey.Equal(pubKey) {
+		return nil, ErrInvalidSignature
+	}
+	return sig, nil
+}
+
+func (s *remoteSigner) call(result interface{}, method string, args ...interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	return s.client.CallContext(ctx, result, method, args...)
+}
+
+// allowlist is the parsed list of what may be signed.
+type allowlist struct {
+	data        bool
+	tx          bool
+	txTo        []common.Address
+	typedData   bool
+	typedDomain []string
+}
+
+func parseAllowlist(entries []string) (*allowlist, error) {
+	if len(entries) == 0 {
+		return &allowlist{data: true, tx: true, typedData: true}, nil
+	}
+
+	al := new(allowlist)
+	for _, e := range entries {
+		kind, value := e, ""
+		if i := strings.Index(e, ":"); i >= 0 {
+			kind, value = e[:i], e[i+1:]
+		}
+
+		switch {
+		case kind == AllowData && value == "":
+			al.data = true
+		case kind == AllowTx && value == "":
+			al.tx = true
+		case kind == AllowTx && common.IsHexAddress(value):
+			al.txTo = append(al.txTo, common.HexToAddress(value))
+		case kind == AllowTypedData && value == "":
+			al.typedData = true
+		case kind == AllowTypedData:
+			al.typedDomain = append(al.typedDomain, value)
+		default:
+			return nil, fmt.Errorf("%q: %w", e, ErrInvalidAllowlist)
+		}
+	}
+	return al, nil
+}
+
+func (al *allowlist) allowTx(to *common.Address) bool {
+	if al.tx {
+		return true
+	}
+	if to == nil {
+		return false
+	}
+	for _, a := range al.txTo {
+		if a == *to {
+			return true
+		}
+	}
+	return false
+}
+
+func (al *allowlist) allowTypedData(domain string) bool {
+	if al.typedData {
+		return true
+	}
+	for _, d := range al.typedDomain {
+		if d == domain {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,219 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package remote_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/crypto/eip712"
+	"github.com/ethersphere/bee/pkg/crypto/remote"
+)
+
+// signerService is a stand-in for a remote signing service backed by a
+// local key.
+type signerService struct {
+	signer crypto.Signer
+	delay  time.Duration
+	// other signs the data with a different key if set.
+	other crypto.Signer
+}
+
+func (s *signerService) PublicKey() (hexutil.Bytes, error) {
+	key, err := s.signer.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	return crypto.EncodeSecp256k1PublicKey(key), nil
+}
+
+func (s *signerService) SignData(data hexutil.Bytes) (hexutil.Bytes, error) {
+	time.Sleep(s.delay)
+	if s.other != nil {
+		return s.other.Sign(data)
+	}
+	return s.signer.Sign(data)
+}
+
+func (s *signerService) SignTransaction(raw hexutil.Bytes, chainID *hexutil.Big) (hexutil.Bytes, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return nil, err
+	}
+	tx, err := s.signer.SignTx(tx, (*big.Int)(chainID))
+	if err != nil {
+		return nil, err
+	}
+	return tx.MarshalBinary()
+}
+
+func (s *signerService) SignTypedData(typedData eip712.TypedData) (hexutil.Bytes, error) {
+	return s.signer.SignTypedData(&typedData)
+}
+
+func newTestSigner(t *testing.T, svc *signerService, allow []string, opts ...remote.Option) crypto.Signer {
+	t.Helper()
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("signer", svc); err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	t.Cleanup(server.Stop)
+
+	client, err := rpc.DialContext(context.Background(), httpServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Close)
+
+	signer, err := remote.NewSigner(client, allow, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+func newKeySigner(t *testing.T) crypto.Signer {
+	t.Helper()
+
+	key, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return crypto.NewDefaultSigner(key)
+}
+
+var testTypedData = &eip712.TypedData{
+	Domain: eip712.TypedDataDomain{
+		Name:    "test",
+		Version: "1.0",
+	},
+	Types: eip712.Types{
+		"EIP712Domain": {
+			{Name: "name", Type: "string"},
+			{Name: "version", Type: "string"},
+		},
+		"MyType": {
+			{Name: "test", Type: "string"},
+		},
+	},
+	Message: eip712.TypedDataMessage{
+		"test": "abc",
+	},
+	PrimaryType: "MyType",
+}
+
+func TestSigner(t *testing.T) {
+	local := newKeySigner(t)
+	signer := newTestSigner(t, &signerService{signer: local}, nil)
+
+	wantAddress, _ := local.EthereumAddress()
+	if got, _ := signer.EthereumAddress(); got != wantAddress {
+		t.Fatalf("got address %s, want %s", got, wantAddress)
+	}
+
+	t.Run("sign", func(t *testing.T) {
+		data := []byte("hello world")
+		sig, err := signer.Sign(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubKey, err := crypto.Recover(sig, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, _ := signer.PublicKey(); !want.Equal(pubKey) {
+			t.Fatal("recovered wrong public key")
+		}
+	})
+
+	t.Run("sign tx", func(t *testing.T) {
+		chainID := big.NewInt(100)
+		to := common.HexToAddress("0x31415b599f636129AD03c196cef9f8f8b184D5C7")
+		tx := types.NewTransaction(1, to, big.NewInt(10), 21000, big.NewInt(1), []byte{1, 2, 3})
+
+		signed, err := signer.SignTx(tx, chainID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sender, err := types.Sender(types.NewEIP155Signer(chainID), signed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sender != wantAddress {
+			t.Fatalf("got sender %s, want %s", sender, wantAddress)
+		}
+	})
+
+	t.Run("sign typed data", func(t *testing.T) {
+		sig, err := signer.SignTypedData(testTypedData)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := local.SignTypedData(testTypedData)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hexutil.Encode(sig) != hexutil.Encode(want) {
+			t.Fatalf("got signature %x, want %x", sig, want)
+		}
+	})
+}
+
+func TestSignerAllowlist(t *testing.T) {
+	allowed := common.HexToAddress("0x31415b599f636129AD03c196cef9f8f8b184D5C7")
+	signer := newTestSigner(t, &signerService{signer: newKeySigner(t)}, []string{
+		"tx:" + allowed.Hex(),
+		"typed-data:test",
+	})
+
+	if _, err := signer.Sign([]byte("data")); !errors.Is(err, remote.ErrNotAllowed) {
+		t.Fatalf("got error %v, want %v", err, remote.ErrNotAllowed)
+	}
+
+	chainID := big.NewInt(100)
+	if _, err := signer.SignTx(types.NewTransaction(1, allowed, big.NewInt(0), 21000, big.NewInt(1), nil), chainID); err != nil {
+		t.Fatal(err)
+	}
+	other := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	if _, err := signer.SignTx(types.NewTransaction(1, other, big.NewInt(0), 21000, big.NewInt(1), nil), chainID); !errors.Is(err, remote.ErrNotAllowed) {
+		t.Fatalf("got error %v, want %v", err, remote.ErrNotAllowed)
+	}
+
+	if _, err := signer.SignTypedData(testTypedData); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := remote.NewSigner(nil, []string{"everything"}); !errors.Is(err, remote.ErrInvalidAllowlist) {
+		t.Fatalf("got error %v, want %v", err, remote.ErrInvalidAllowlist)
+	}
+}
+
+func TestSignerErrors(t *testing.T) {
+	t.Run("timeout", func(t *testing.T) {
+		signer := newTestSigner(t, &signerService{signer: newKeySigner(t), delay: time.Second}, nil, remote.WithTimeout(50*time.Millisecond))
+		if _, err := signer.Sign([]byte("data")); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("got error %v, want %v", err, context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		signer := newTestSigner(t, &signerService{signer: newKeySigner(t), other: newKeySigner(t)}, nil)
+		if _, err := signer.Sign([]byte("data")); !errors.Is(err, remote.ErrInvalidSignature) {
+			t.Fatalf("got error %v, want %v", err, remote.ErrInvalidSignature)
+		}
+	})
+}
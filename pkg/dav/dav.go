@@ -0,0 +1,409 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package dav provides a WebDAV gateway which exposes mantaray manifests as
+// filesystems. A manifest is mounted either by its reference, read-only, or
+// by the owner and the topic of a sequence feed whose updates reference the
+// versions of the manifest. The feeds owned by the node are writable: every
+// change stores a new version of the manifest, stamped with the configured
+// postage batch, and publishes its reference to the feed.
+//
+// The gateway does not authenticate requests.
+package dav
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/feeds"
+	"github.com/ethersphere/bee/pkg/feeds/sequence"
+	"github.com/ethersphere/bee/pkg/file/loadsave"
+	"github.com/ethersphere/bee/pkg/file/pipeline"
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/manifest"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/soc"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/gorilla/mux"
+	"golang.org/x/net/webdav"
+	"resenje.org/web"
+)
+
+var errInvalidFeedUpdate = errors.New("invalid feed update")
+
+const (
+	// maxHeads limits the number of the cached versions of the owned feeds.
+	maxHeads = 1000
+	// headTTL is the time for which the cached version of an owned feed is
+	// served to the readers without looking up the updates after it.
+	headTTL = time.Minute
+)
+
+// readMethods are the methods which are allowed on the read-only mounts.
+var readMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	"PROPFIND":         true,
+}
+
+// Service is the WebDAV gateway HTTP handler.
+type Service struct {
+	http.Handler
+
+	storer  storage.Storer
+	post    postage.Service
+	signer  crypto.Signer
+	batchID []byte
+	logger  logging.Logger
+
+	// locks is the lock system of the writable mounts, which holds the
+	// locks by their paths prefixed with the mounts.
+	locks webdav.LockSystem
+
+	// updates serializes the changes of the manifests of the owned feeds,
+	// also with the other services of the node which update them.
+	updates *feeds.UpdateLocks
+
+	// heads are the latest known versions of the owned feeds with updates
+	// by their topics.
+	heads   map[string]head
+	headsMu sync.Mutex
+}
+
+// head is the latest known version of an owned feed.
+type head struct {
+	reference swarm.Address
+	next      uint64
+	// checked is the time when the updates after the version were last
+	// looked up.
+	checked time.Time
+}
+
+// New creates a new WebDAV gateway which reads the manifests from storer. The
+// changes of the owned feeds are stamped with the postage batch batchID, the
+// owned feeds are read-only if it is nil. The updates of the owned feeds are
+// serialized with the other services of the node which update them by
+// updates.
+func New(storer storage.Storer, post postage.Service, signer crypto.Signer, updates *feeds.UpdateLocks, batchID []byte, logger logging.Logger) *Service {
+	s := &Service{
+		storer:  storer,
+		post:    post,
+		signer:  signer,
+		batchID: batchID,
+		logger:  logger,
+		locks:   webdav.NewMemLS(),
+		updates: updates,
+		heads:   make(map[string]head),
+	}
+	s.setupRouting()
+	return s
+}
+
+func (s *Service) setupRouting() {
+	router := mux.NewRouter()
+	router.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	})
+
+	router.PathPrefix("/bzz/{address}").HandlerFunc(s.referenceHandler)
+	router.PathPrefix("/feeds/{owner}/{topic}").HandlerFunc(s.feedHandler)
+
+	s.Handler = web.ChainHandlers(
+		web.FinalHandler(router),
+	)
+}
+
+// referenceHandler serves the manifest with the reference read-only.
+func (s *Service) referenceHandler(w http.ResponseWriter, r *http.Request) {
+	address := mux.Vars(r)["address"]
+	ref, err := swarm.ParseHexAddress(address)
+	if err != nil {
+		s.logger.Debugf("dav: parse address %s: %v", address, err)
+		http.Error(w, "bad address", http.StatusBadRequest)
+		return
+	}
+
+	s.serve(w, r, "/bzz/"+address, &fileSystem{s: s, reference: ref})
+}
+
+// feedHandler serves the manifest referenced by the latest update of the
+// feed, which is writable if the feed is owned by the node.
+func (s *Service) feedHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	prefix := "/feeds/" + vars["owner"] + "/" + vars["topic"]
+
+	ownerBytes, err := hex.DecodeString(vars["owner"])
+	if err != nil || len(ownerBytes) != common.AddressLength {
+		s.logger.Debugf("dav: decode owner %s: %v", vars["owner"], err)
+		http.Error(w, "bad owner", http.StatusBadRequest)
+		return
+	}
+	topic, err := hex.DecodeString(vars["topic"])
+	if err != nil || len(topic) != swarm.HashSize {
+		s.logger.Debugf("dav: decode topic %s: %v", vars["topic"], err)
+		http.Error(w, "bad topic", http.StatusBadRequest)
+		return
+	}
+	owner := common.BytesToAddress(ownerBytes)
+
+	node, err := s.signer.EthereumAddress()
+	if err != nil {
+		s.logger.Debugf("dav: owner: %v", err)
+		s.logger.Error("dav: owner")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if owner == node {
+		s.serve(w, r, prefix, &fileSystem{s: s, topic: topic, writable: s.batchID != nil})
+		return
+	}
+
+	ch, err := feeds.Latest(r.Context(), sequence.NewAsyncFinder(s.storer, feeds.New(topic, owner)), 0)
+	if err != nil {
+		s.logger.Debugf("dav: feed %s lookup: %v", prefix, err)
+		s.logger.Errorf("dav: feed %s lookup", prefix)
+		http.Error(w, "feed lookup failed", http.StatusNotFound)
+		return
+	}
+	if ch == nil {
+		http.Error(w, "feed not found", http.StatusNotFound)
+		return
+	}
+	ref, err := parseFeedUpdate(ch)
+	if err != nil {
+		s.logger.Debugf("dav: feed %s update: %v", prefix, err)
+		s.logger.Errorf("dav: feed %s update", prefix)
+		http.Error(w, "invalid feed update", http.StatusNotFound)
+		return
+	}
+
+	s.serve(w, r, prefix, &fileSystem{s: s, reference: ref})
+}
+
+// serve handles the WebDAV request with the filesystem mounted at the
+// prefix.
+func (s *Service) serve(w http.ResponseWriter, r *http.Request, prefix string, fs *fileSystem) {
+	if !fs.writable && !readMethods[r.Method] {
+		http.Error(w, "read-only", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// an interrupted upload must not be stored as a new version of the file
+	fs.body = &bodyReader{r: r.Body}
+	r.Body = fs.body
+
+	h := &webdav.Handler{
+		Prefix:     prefix,
+		FileSystem: fs,
+		LockSystem: s.lockSystem(prefix, fs.writable),
+		Logger: func(r *http.Request, err error) {
+			if err != nil {
+				s.logger.Debugf("dav: %s %s: %v", r.Method, r.URL.Path, err)
+			}
+		},
+	}
+	h.ServeHTTP(w, r)
+}
+
+// lockSystem returns the lock system of the mount with the prefix. Nothing
+// can be locked on the read-only mounts, so they do not hold any state.
+func (s *Service) lockSystem(prefix string, writable bool) webdav.LockSystem {
+	if !writable {
+		return readOnlyLockSystem{}
+	}
+	return &prefixLockSystem{LockSystem: s.locks, prefix: prefix}
+}
+
+// head returns the latest version of the owned feed with the topic. The
+// cached version is only a hint, as the feed can also be updated through the
+// other gateways, so the updates after it are looked up if fresh is true or
+// if it was not checked for headTTL. The updates are looked up without
+// holding any lock.
+func (s *Service) head(ctx context.Context, topic []byte, fresh bool) (head, error) {
+	owner, err := s.signer.EthereumAddress()
+	if err != nil {
+		return head{}, err
+	}
+	feed := feeds.New(topic, owner)
+
+	s.headsMu.Lock()
+	h, ok := s.heads[string(topic)]
+	s.headsMu.Unlock()
+
+	switch {
+	case ok && !fresh && time.Since(h.checked) < headTTL:
+		return h, nil
+	case ok:
+		getter := feeds.NewGetter(s.storer, feed)
+		for {
+			ch, err := getter.Get(ctx, sequence.NewIndex(h.next))
+			if errors.Is(err, storage.ErrNotFound) {
+				break
+			}
+			if err != nil {
+				return head{}, fmt.Errorf("feed lookup: %w", err)
+			}
+			if h.reference, err = parseFeedUpdate(ch); err != nil {
+				return head{}, err
+			}
+			h.next++
+		}
+	default:
+		// the sequential lookup finds the exact index of the next update
+		ch, _, next, err := sequence.NewFinder(s.storer, feed).At(ctx, time.Now().Unix(), 0)
+		if err != nil {
+			return head{}, fmt.Errorf("feed lookup: %w", err)
+		}
+		h = head{reference: swarm.ZeroAddress}
+		if ch == nil {
+			// the feeds without updates are not cached, as they can be
+			// requested with any topic
+			return h, nil
+		}
+		if h.reference, err = parseFeedUpdate(ch); err != nil {
+			return head{}, err
+		}
+		b, err := next.MarshalBinary()
+		if err != nil {
+			return head{}, err
+		}
+		h.next = binary.BigEndian.Uint64(b)
+	}
+	h.checked = time.Now()
+	s.cacheHead(topic, h)
+	return h, nil
+}
+
+// cacheHead caches the version of the owned feed with the topic, unless a
+// later version is cached already, evicting another one if the cache is
+// full.
+func (s *Service) cacheHead(topic []byte, h head) {
+	s.headsMu.Lock()
+	defer s.headsMu.Unlock()
+
+	cached, ok := s.heads[string(topic)]
+	if ok && cached.next > h.next {
+		return
+	}
+	if !ok && len(s.heads) >= maxHeads {
+		for k := range s.heads {
+			delete(s.heads, k)
+			break
+		}
+	}
+	s.heads[string(topic)] = h
+}
+
+// reference returns the reference of the latest version of the manifest of
+// the owned feed with the topic, which is zero if the feed has no updates.
+func (s *Service) reference(ctx context.Context, topic []byte) (swarm.Address, error) {
+	h, err := s.head(ctx, topic, false)
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+	return h.reference, nil
+}
+
+// update applies the change to the manifest of the owned feed with the
+// topic, stores the new version of the manifest and publishes it to the
+// feed. The change is called with the reference of the latest version.
+func (s *Service) update(ctx context.Context, topic []byte, change func(context.Context, manifest.Interface, swarm.Address) error) error {
+	unlock := s.updates.Lock(topic)
+	defer unlock()
+
+	h, err := s.head(ctx, topic, true)
+	if err != nil {
+		return err
+	}
+
+	putter, err := s.newPutter()
+	if err != nil {
+		return err
+	}
+	ls := loadsave.New(putter, pipelineFactory(ctx, putter))
+
+	var m manifest.Interface
+	if h.reference.IsZero() {
+		m, err = manifest.NewDefaultManifest(ls, false)
+	} else {
+		m, err = manifest.NewDefaultManifestReference(h.reference, ls)
+	}
+	if err != nil {
+		return fmt.Errorf("load manifest: %w", err)
+	}
+	if err := change(ctx, m, h.reference); err != nil {
+		return err
+	}
+	ref, err := m.Store(ctx)
+	if err != nil {
+		return fmt.Errorf("store manifest: %w", err)
+	}
+
+	updater, err := sequence.NewUpdaterAt(putter, s.signer, topic, h.next)
+	if err != nil {
+		return err
+	}
+	if err := updater.Update(ctx, time.Now().Unix(), ref.Bytes()); err != nil {
+		return fmt.Errorf("feed update: %w", err)
+	}
+
+	s.cacheHead(topic, head{reference: ref, next: h.next + 1, checked: time.Now()})
+	return nil
+}
+
+// newPutter returns a putter which stamps the chunks with the postage batch
+// of the gateway and stores them to be pushed to the network.
+func (s *Service) newPutter() (*postage.StamperPutter, error) {
+	return postage.NewStamperPutter(s.storer, s.post, s.signer, s.batchID)
+}
+
+// pipelineFactory returns the constructor of the pipelines which split the
+// data of the files and the manifests.
+func pipelineFactory(ctx context.Context, putter storage.Putter) func() pipeline.Interface {
+	return builder.NewPipelineFactory(ctx, putter, storage.ModePutUpload, false)
+}
+
+// parseFeedUpdate returns the reference in the payload of the feed update.
+func parseFeedUpdate(ch swarm.Chunk) (swarm.Address, error) {
+	s, err := soc.FromChunk(ch)
+	if err != nil {
+		return swarm.ZeroAddress, fmt.Errorf("soc unmarshal: %w", err)
+	}
+	// span, timestamp and the reference with the optional decryption key
+	update := s.WrappedChunk().Data()
+	if len(update) != 48 && len(update) != 80 {
+		return swarm.ZeroAddress, errInvalidFeedUpdate
+	}
+	return swarm.NewAddress(update[16:]), nil
+}
+
+// bodyReader records the error of reading the request body.
+type bodyReader struct {
+	r   io.ReadCloser
+	err error
+}
+
+func (b *bodyReader) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	if err != nil && err != io.EOF {
+		b.err = err
+	}
+	return n, err
+}
+
+func (b *bodyReader) Close() error {
+	return b.r.Close()
+}
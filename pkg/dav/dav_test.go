@@ -0,0 +1,346 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dav_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/dav"
+	"github.com/ethersphere/bee/pkg/feeds"
+	"github.com/ethersphere/bee/pkg/feeds/sequence"
+	"github.com/ethersphere/bee/pkg/logging"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+var (
+	batchID = bytes.Repeat([]byte{1}, 32)
+	topic   = bytes.Repeat([]byte{2}, 32)
+)
+
+func newTestService(t *testing.T, storer *mock.MockStorer) (*dav.Service, crypto.Signer) {
+	t.Helper()
+
+	pk, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := crypto.NewDefaultSigner(pk)
+	post := mockpost.New(mockpost.WithAcceptAll())
+	return dav.New(storer, post, signer, feeds.NewUpdateLocks(), batchID, logging.New(io.Discard, 0)), signer
+}
+
+func feedPrefix(t *testing.T, signer crypto.Signer) string {
+	t.Helper()
+
+	owner, err := signer.EthereumAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "/feeds/" + hex.EncodeToString(owner.Bytes()) + "/" + hex.EncodeToString(topic)
+}
+
+func request(t *testing.T, h http.Handler, method, url string, body io.Reader, header http.Header) ([]byte, *http.Response) {
+	t.Helper()
+
+	r := httptest.NewRequest(method, url, body)
+	for k, v := range header {
+		r.Header[k] = v
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	resp := w.Result()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b, resp
+}
+
+func expectStatus(t *testing.T, h http.Handler, method, url string, body io.Reader, header http.Header, want int) []byte {
+	t.Helper()
+
+	b, resp := request(t, h, method, url, body, header)
+	if resp.StatusCode != want {
+		t.Fatalf("%s %s: got status %d, want %d: %s", method, url, resp.StatusCode, want, b)
+	}
+	return b
+}
+
+// list returns the paths of the entries of the directory listed with a
+// PROPFIND request, relative to the prefix.
+func list(t *testing.T, h http.Handler, prefix, dir string) []string {
+	t.Helper()
+
+	b := expectStatus(t, h, "PROPFIND", prefix+dir, nil, http.Header{"Depth": {"1"}}, http.StatusMultiStatus)
+	var ms struct {
+		Responses []struct {
+			Href string `xml:"href"`
+		} `xml:"response"`
+	}
+	if err := xml.Unmarshal(b, &ms); err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, r := range ms.Responses {
+		paths = append(paths, strings.TrimPrefix(r.Href, prefix))
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func expectList(t *testing.T, h http.Handler, prefix, dir, want string) {
+	t.Helper()
+
+	if got := strings.Join(list(t, h, prefix, dir), ","); got != want {
+		t.Fatalf("list %s: got %q, want %q", dir, got, want)
+	}
+}
+
+// latest returns the reference of the latest update of the feed.
+func latest(t *testing.T, storer *mock.MockStorer, signer crypto.Signer) swarm.Address {
+	t.Helper()
+
+	owner, err := signer.EthereumAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch, _, _, err := sequence.NewFinder(storer, feeds.New(topic, owner)).At(context.Background(), time.Now().Unix(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, payload, err := feeds.FromChunk(ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return swarm.NewAddress(payload)
+}
+
+func TestOwnedFeed(t *testing.T) {
+	storer := mock.NewStorer()
+	s, signer := newTestService(t, storer)
+	prefix := feedPrefix(t, signer)
+
+	expectList(t, s, prefix, "/", "/")
+
+	expectStatus(t, s, "MKCOL", prefix+"/docs", nil, nil, http.StatusCreated)
+	expectStatus(t, s, "MKCOL", prefix+"/missing/docs", nil, nil, http.StatusConflict)
+	expectStatus(t, s, http.MethodPut, prefix+"/docs/a.txt", strings.NewReader("hello"), nil, http.StatusCreated)
+	expectStatus(t, s, http.MethodPut, prefix+"/docs/b.txt", strings.NewReader("world"), nil, http.StatusCreated)
+	expectList(t, s, prefix, "/", "/,/docs/")
+	expectList(t, s, prefix, "/docs/", "/docs/,/docs/a.txt,/docs/b.txt")
+
+	t.Run("get", func(t *testing.T) {
+		b, resp := request(t, s, http.MethodGet, prefix+"/docs/a.txt", nil, nil)
+		if resp.StatusCode != http.StatusOK || string(b) != "hello" {
+			t.Fatalf("got status %d and data %q", resp.StatusCode, b)
+		}
+		if got := resp.Header.Get("Content-Type"); got != "text/plain; charset=utf-8" {
+			t.Fatalf("got content type %q", got)
+		}
+	})
+
+	t.Run("move file", func(t *testing.T) {
+		expectStatus(t, s, "MOVE", prefix+"/docs/b.txt", nil, http.Header{"Destination": {"http://example.com" + prefix + "/c.txt"}}, http.StatusCreated)
+		expectStatus(t, s, http.MethodGet, prefix+"/docs/b.txt", nil, nil, http.StatusNotFound)
+		if got := string(expectStatus(t, s, http.MethodGet, prefix+"/c.txt", nil, nil, http.StatusOK)); got != "world" {
+			t.Fatalf("got data %q", got)
+		}
+	})
+
+	t.Run("delete keeps the directory", func(t *testing.T) {
+		expectStatus(t, s, http.MethodDelete, prefix+"/docs/a.txt", nil, nil, http.StatusNoContent)
+		expectList(t, s, prefix, "/docs/", "/docs/")
+	})
+
+	t.Run("move directory", func(t *testing.T) {
+		expectStatus(t, s, http.MethodPut, prefix+"/docs/d.txt", strings.NewReader("data"), nil, http.StatusCreated)
+		expectStatus(t, s, "MOVE", prefix+"/docs", nil, http.Header{"Destination": {"http://example.com" + prefix + "/archive"}}, http.StatusCreated)
+		expectList(t, s, prefix, "/", "/,/archive/,/c.txt")
+		expectList(t, s, prefix, "/archive/", "/archive/,/archive/d.txt")
+	})
+
+	t.Run("delete directory", func(t *testing.T) {
+		expectStatus(t, s, http.MethodDelete, prefix+"/archive", nil, nil, http.StatusNoContent)
+		expectList(t, s, prefix, "/", "/,/c.txt")
+	})
+
+	t.Run("published version", func(t *testing.T) {
+		ref := latest(t, storer, signer)
+		bzz := "/bzz/" + ref.String()
+		expectList(t, s, bzz, "/", "/,/c.txt")
+		if got := string(expectStatus(t, s, http.MethodGet, bzz+"/c.txt", nil, nil, http.StatusOK)); got != "world" {
+			t.Fatalf("got data %q", got)
+		}
+		expectStatus(t, s, http.MethodPut, bzz+"/e.txt", strings.NewReader("data"), nil, http.StatusMethodNotAllowed)
+	})
+}
+
+func TestFeedOfOtherOwner(t *testing.T) {
+	storer := mock.NewStorer()
+	owner, ownerSigner := newTestService(t, storer)
+	prefix := feedPrefix(t, ownerSigner)
+	expectStatus(t, owner, http.MethodPut, prefix+"/a.txt", strings.NewReader("hello"), nil, http.StatusCreated)
+
+	s, _ := newTestService(t, storer)
+	expectList(t, s, prefix, "/", "/,/a.txt")
+	if got := string(expectStatus(t, s, http.MethodGet, prefix+"/a.txt", nil, nil, http.StatusOK)); got != "hello" {
+		t.Fatalf("got data %q", got)
+	}
+	expectStatus(t, s, http.MethodPut, prefix+"/b.txt", strings.NewReader("data"), nil, http.StatusMethodNotAllowed)
+	expectStatus(t, s, http.MethodGet, "/feeds/"+strings.Repeat("00", 20)+"/"+hex.EncodeToString(topic)+"/", nil, nil, http.StatusNotFound)
+}
+
+type errorReader struct{}
+
+func (errorReader) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestInterruptedUpload(t *testing.T) {
+	storer := mock.NewStorer()
+	s, signer := newTestService(t, storer)
+	prefix := feedPrefix(t, signer)
+
+	body := io.MultiReader(strings.NewReader("partial"), errorReader{})
+	_, resp := request(t, s, http.MethodPut, prefix+"/a.txt", body, nil)
+	if resp.StatusCode < http.StatusBadRequest {
+		t.Fatalf("got status %d", resp.StatusCode)
+	}
+	expectStatus(t, s, http.MethodGet, prefix+"/a.txt", nil, nil, http.StatusNotFound)
+}
+
+func TestFeedUpdatedElsewhere(t *testing.T) {
+	storer := mock.NewStorer()
+	s, signer := newTestService(t, storer)
+	prefix := feedPrefix(t, signer)
+	// another writer of the same feed, like the S3 gateway
+	other := dav.New(storer, mockpost.New(mockpost.WithAcceptAll()), signer, feeds.NewUpdateLocks(), batchID, logging.New(io.Discard, 0))
+
+	expectStatus(t, s, http.MethodPut, prefix+"/a.txt", strings.NewReader("a"), nil, http.StatusCreated)
+	expectStatus(t, other, http.MethodPut, prefix+"/b.txt", strings.NewReader("b"), nil, http.StatusCreated)
+	expectStatus(t, s, http.MethodPut, prefix+"/c.txt", strings.NewReader("c"), nil, http.StatusCreated)
+
+	// the last update is based on the version published by the other writer
+	expectList(t, s, prefix, "/", "/,/a.txt,/b.txt,/c.txt")
+	ref := latest(t, storer, signer)
+	expectList(t, s, "/bzz/"+ref.String(), "/", "/,/a.txt,/b.txt,/c.txt")
+}
+
+func TestLocks(t *testing.T) {
+	storer := mock.NewStorer()
+	s, signer := newTestService(t, storer)
+	prefix := feedPrefix(t, signer)
+	owner, err := signer.EthereumAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPrefix := "/feeds/" + hex.EncodeToString(owner.Bytes()) + "/" + hex.EncodeToString(bytes.Repeat([]byte{3}, 32))
+
+	lock := func(url string, want int) string {
+		t.Helper()
+
+		body := `<?xml version="1.0" encoding="utf-8"?><D:lockinfo xmlns:D="DAV:"><D:lockscope><D:exclusive/></D:lockscope><D:locktype><D:write/></D:locktype></D:lockinfo>`
+		_, resp := request(t, s, "LOCK", url, strings.NewReader(body), http.Header{"Timeout": {"Second-60"}})
+		if resp.StatusCode != want {
+			t.Fatalf("lock %s: got status %d, want %d", url, resp.StatusCode, want)
+		}
+		return resp.Header.Get("Lock-Token")
+	}
+
+	token := lock(prefix+"/a.txt", http.StatusCreated)
+	// the same path in another mount is not locked
+	lock(otherPrefix+"/a.txt", http.StatusCreated)
+	lock(prefix+"/a.txt", http.StatusLocked)
+	expectStatus(t, s, http.MethodPut, prefix+"/a.txt", strings.NewReader("a"), nil, http.StatusLocked)
+	expectStatus(t, s, http.MethodPut, prefix+"/a.txt", strings.NewReader("a"), http.Header{"If": {"(" + token + ")"}}, http.StatusCreated)
+	expectStatus(t, s, "UNLOCK", prefix+"/a.txt", nil, http.Header{"Lock-Token": {token}}, http.StatusNoContent)
+	expectStatus(t, s, http.MethodPut, prefix+"/a.txt", strings.NewReader("b"), nil, http.StatusCreated)
+
+	ref := latest(t, storer, signer)
+	expectStatus(t, s, "LOCK", "/bzz/"+ref.String()+"/a.txt", nil, nil, http.StatusMethodNotAllowed)
+}
+
+// blockingStorer blocks the lookups of the chunk with the address until
+// release is closed.
+type blockingStorer struct {
+	*mock.MockStorer
+	address swarm.Address
+	once    sync.Once
+	blocked chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingStorer) Get(ctx context.Context, mode storage.ModeGet, addr swarm.Address) (swarm.Chunk, error) {
+	if addr.Equal(s.address) {
+		s.once.Do(func() { close(s.blocked) })
+		<-s.release
+	}
+	return s.MockStorer.Get(ctx, mode, addr)
+}
+
+// TestFeedLookupConcurrent checks that a slow lookup of an owned feed does
+// not block the requests to the other owned feeds.
+func TestFeedLookupConcurrent(t *testing.T) {
+	pk, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := crypto.NewDefaultSigner(pk)
+	owner, err := signer.EthereumAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	address, err := feeds.New(topic, owner).Update(sequence.NewIndex(0)).Address()
+	if err != nil {
+		t.Fatal(err)
+	}
+	storer := &blockingStorer{
+		MockStorer: mock.NewStorer(),
+		address:    address,
+		blocked:    make(chan struct{}),
+		release:    make(chan struct{}),
+	}
+	s := dav.New(storer, mockpost.New(mockpost.WithAcceptAll()), signer, feeds.NewUpdateLocks(), batchID, logging.New(io.Discard, 0))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		request(t, s, "PROPFIND", feedPrefix(t, signer)+"/", nil, http.Header{"Depth": {"1"}})
+	}()
+	defer func() {
+		close(storer.release)
+		<-done
+	}()
+	<-storer.blocked
+
+	otherPrefix := "/feeds/" + hex.EncodeToString(owner.Bytes()) + "/" + hex.EncodeToString(bytes.Repeat([]byte{3}, 32))
+	put := make(chan struct{})
+	go func() {
+		defer close(put)
+		request(t, s, http.MethodPut, otherPrefix+"/a.txt", strings.NewReader("a"), nil)
+	}()
+	select {
+	case <-put:
+	case <-time.After(5 * time.Second):
+		t.Fatal("request blocked by the lookup of another feed")
+	}
+	expectList(t, s, otherPrefix, "/", "/,/a.txt")
+}
@@ -0,0 +1,533 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dav
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/file"
+	"github.com/ethersphere/bee/pkg/file/joiner"
+	"github.com/ethersphere/bee/pkg/file/loadsave"
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/manifest"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"golang.org/x/net/webdav"
+)
+
+const (
+	// metadataLastModifiedKey is the metadata key of the manifest entries
+	// with the time of the upload of the files.
+	metadataLastModifiedKey = "Dav-Last-Modified"
+
+	defaultContentType = "application/octet-stream"
+)
+
+// emptyAddress is the reference of the entries which keep the empty
+// directories in the manifests.
+var emptyAddress = swarm.NewAddress(make([]byte, swarm.HashSize))
+
+func isEmpty(ref swarm.Address) bool {
+	return ref.IsZero() || ref.Equal(emptyAddress)
+}
+
+var _ webdav.FileSystem = (*fileSystem)(nil)
+
+// fileSystem is the filesystem of a manifest. The paths of the files are the
+// paths of the manifest entries, the directories are the prefixes of the
+// paths which end with a path separator.
+type fileSystem struct {
+	s *Service
+	// reference is the reference of the manifest of the mounts which are not
+	// owned feeds.
+	reference swarm.Address
+	// topic is the topic of the owned feed.
+	topic []byte
+	// writable is true if the manifest of the owned feed can be changed.
+	writable bool
+	// body is the body of the request.
+	body *bodyReader
+}
+
+// manifestPath returns the path of the manifest entry of the file with the
+// name, which is empty for the root directory.
+func manifestPath(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
+// parentDir returns the path of the directory which contains the entry with
+// the path p, which is empty for the root directory.
+func parentDir(p string) string {
+	if i := strings.LastIndex(strings.TrimSuffix(p, "/"), "/"); i >= 0 {
+		return p[:i+1]
+	}
+	return ""
+}
+
+// manifestReference returns the reference of the manifest, which is zero if
+// an owned feed has no updates.
+func (fs *fileSystem) manifestReference(ctx context.Context) (swarm.Address, error) {
+	if fs.topic == nil {
+		return fs.reference, nil
+	}
+	return fs.s.reference(ctx, fs.topic)
+}
+
+func (fs *fileSystem) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	return fs.stat(ctx, manifestPath(name))
+}
+
+func (fs *fileSystem) stat(ctx context.Context, p string) (*fileInfo, error) {
+	if p == "" {
+		return &fileInfo{name: "/", dir: true}, nil
+	}
+
+	ref, err := fs.manifestReference(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ref.IsZero() {
+		return nil, os.ErrNotExist
+	}
+	m, err := manifest.NewDefaultManifestReference(ref, loadsave.NewReadonly(fs.s.storer))
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := m.Lookup(ctx, p)
+	switch {
+	case err == nil && !isEmpty(e.Reference()):
+		return fs.fileInfo(ctx, p, e)
+	case err != nil && !errors.Is(err, manifest.ErrNotFound):
+		return nil, err
+	}
+
+	dir, err := m.HasPrefix(ctx, p+"/")
+	if err != nil {
+		return nil, err
+	}
+	if !dir {
+		return nil, os.ErrNotExist
+	}
+	return &fileInfo{name: p, dir: true}, nil
+}
+
+// fileInfo returns the information of the file with the manifest entry.
+func (fs *fileSystem) fileInfo(ctx context.Context, p string, e manifest.Entry) (*fileInfo, error) {
+	_, size, err := joiner.New(ctx, fs.s.storer, e.Reference())
+	if err != nil {
+		return nil, err
+	}
+	modTime, _ := time.Parse(time.RFC3339, e.Metadata()[metadataLastModifiedKey])
+	return &fileInfo{
+		name:        p,
+		size:        size,
+		modTime:     modTime,
+		contentType: e.Metadata()[manifest.EntryMetadataContentTypeKey],
+		reference:   e.Reference(),
+	}, nil
+}
+
+func (fs *fileSystem) OpenFile(ctx context.Context, name string, flag int, _ os.FileMode) (webdav.File, error) {
+	p := manifestPath(name)
+
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_APPEND|os.O_CREATE|os.O_TRUNC) == 0 {
+		info, err := fs.stat(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		if info.dir {
+			return &dirFile{fs: fs, ctx: ctx, info: info}, nil
+		}
+		j, _, err := joiner.New(ctx, fs.s.storer, info.reference)
+		if err != nil {
+			return nil, err
+		}
+		return &readFile{Joiner: j, info: info}, nil
+	}
+
+	// the files are only written as a whole
+	if !fs.writable || p == "" || flag&os.O_APPEND != 0 {
+		return nil, os.ErrPermission
+	}
+	info, err := fs.stat(ctx, p)
+	switch {
+	case err == nil:
+		if info.dir || flag&os.O_TRUNC == 0 {
+			return nil, os.ErrPermission
+		}
+		if flag&(os.O_CREATE|os.O_EXCL) == os.O_CREATE|os.O_EXCL {
+			return nil, os.ErrExist
+		}
+	case errors.Is(err, os.ErrNotExist):
+		if flag&os.O_CREATE == 0 {
+			return nil, err
+		}
+		if err := fs.checkDir(ctx, parentDir(p)); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+	return fs.create(ctx, p)
+}
+
+// checkDir returns os.ErrNotExist if there is no directory with the path.
+func (fs *fileSystem) checkDir(ctx context.Context, dir string) error {
+	info, err := fs.stat(ctx, strings.TrimSuffix(dir, "/"))
+	if err != nil {
+		return err
+	}
+	if !info.dir {
+		return os.ErrNotExist
+	}
+	return nil
+}
+
+func (fs *fileSystem) Mkdir(ctx context.Context, name string, _ os.FileMode) error {
+	p := manifestPath(name)
+	if !fs.writable {
+		return os.ErrPermission
+	}
+	if _, err := fs.stat(ctx, p); err == nil {
+		return os.ErrExist
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := fs.checkDir(ctx, parentDir(p)); err != nil {
+		return err
+	}
+
+	return fs.s.update(ctx, fs.topic, func(ctx context.Context, m manifest.Interface, _ swarm.Address) error {
+		return m.Add(ctx, p+"/", manifest.NewEntry(emptyAddress, nil))
+	})
+}
+
+func (fs *fileSystem) RemoveAll(ctx context.Context, name string) error {
+	p := manifestPath(name)
+	if !fs.writable || p == "" {
+		return os.ErrPermission
+	}
+
+	return fs.s.update(ctx, fs.topic, func(ctx context.Context, m manifest.Interface, ref swarm.Address) error {
+		entries, err := fs.entries(ctx, m, ref, p)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if err := m.Remove(ctx, e.path); err != nil && !errors.Is(err, manifest.ErrNotFound) {
+				return err
+			}
+		}
+		return keepDir(ctx, m, parentDir(p))
+	})
+}
+
+func (fs *fileSystem) Rename(ctx context.Context, oldName, newName string) error {
+	oldPath, newPath := manifestPath(oldName), manifestPath(newName)
+	if !fs.writable || oldPath == "" || newPath == "" {
+		return os.ErrPermission
+	}
+	if strings.HasPrefix(newPath+"/", oldPath+"/") {
+		return os.ErrInvalid
+	}
+	if err := fs.checkDir(ctx, parentDir(newPath)); err != nil {
+		return err
+	}
+
+	return fs.s.update(ctx, fs.topic, func(ctx context.Context, m manifest.Interface, ref swarm.Address) error {
+		entries, err := fs.entries(ctx, m, ref, oldPath)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if err := m.Remove(ctx, e.path); err != nil && !errors.Is(err, manifest.ErrNotFound) {
+				return err
+			}
+		}
+		for _, e := range entries {
+			p := newPath + strings.TrimPrefix(e.path, oldPath)
+			// the metadata of the entry is not changed in place
+			metadata := make(map[string]string, len(e.entry.Metadata()))
+			for k, v := range e.entry.Metadata() {
+				metadata[k] = v
+			}
+			if _, ok := metadata[manifest.EntryMetadataFilenameKey]; ok {
+				metadata[manifest.EntryMetadataFilenameKey] = path.Base(p)
+			}
+			if err := m.Add(ctx, p, manifest.NewEntry(e.entry.Reference(), metadata)); err != nil {
+				return err
+			}
+		}
+		return keepDir(ctx, m, parentDir(oldPath))
+	})
+}
+
+type pathEntry struct {
+	path  string
+	entry manifest.Entry
+}
+
+// entries returns the entry of the file with the path p, or the entries of
+// the files and the empty directories in the directory with the path p and
+// in its subdirectories.
+func (fs *fileSystem) entries(ctx context.Context, m manifest.Interface, ref swarm.Address, p string) ([]pathEntry, error) {
+	e, err := m.Lookup(ctx, p)
+	switch {
+	case err == nil && !isEmpty(e.Reference()):
+		return []pathEntry{{p, e}}, nil
+	case err != nil && !errors.Is(err, manifest.ErrNotFound):
+		return nil, err
+	}
+	if ref.IsZero() {
+		return nil, os.ErrNotExist
+	}
+
+	var (
+		entries []pathEntry
+		ls      = loadsave.NewReadonly(fs.s.storer)
+	)
+	var walk func(dir string) error
+	walk = func(dir string) error {
+		// the entry of the directory keeps it if it is empty
+		if e, err := m.Lookup(ctx, dir); err == nil {
+			entries = append(entries, pathEntry{dir, e})
+		}
+		return manifest.WalkDir(ctx, ls, ref, dir, func(d manifest.DirEntry) error {
+			if d.IsDir {
+				return walk(d.Path)
+			}
+			entries = append(entries, pathEntry{d.Path, d.Entry})
+			return nil
+		})
+	}
+	if err := walk(p + "/"); err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, os.ErrNotExist
+	}
+	return entries, nil
+}
+
+// keepDir adds the entry which keeps the directory with the path dir if it
+// is left empty.
+func keepDir(ctx context.Context, m manifest.Interface, dir string) error {
+	if dir == "" {
+		return nil
+	}
+	has, err := m.HasPrefix(ctx, dir)
+	if err != nil || has {
+		return err
+	}
+	return m.Add(ctx, dir, manifest.NewEntry(emptyAddress, nil))
+}
+
+// create returns the file which stores the data written to it as the file
+// with the path p when it is closed.
+func (fs *fileSystem) create(ctx context.Context, p string) (*writeFile, error) {
+	putter, err := fs.s.newPutter()
+	if err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	f := &writeFile{
+		fs:   fs,
+		ctx:  ctx,
+		path: p,
+		pw:   pw,
+		done: make(chan struct{}),
+	}
+	go func() {
+		defer close(f.done)
+		f.ref, f.err = builder.FeedPipeline(ctx, builder.NewPipelineBuilder(ctx, putter, storage.ModePutUpload, false), pr)
+		// unblock the writes if the pipeline fails
+		pr.CloseWithError(f.err)
+	}()
+	return f, nil
+}
+
+var _ os.FileInfo = (*fileInfo)(nil)
+
+type fileInfo struct {
+	name        string
+	size        int64
+	modTime     time.Time
+	dir         bool
+	contentType string
+	reference   swarm.Address
+}
+
+func (i *fileInfo) Name() string       { return path.Base(i.name) }
+func (i *fileInfo) Size() int64        { return i.size }
+func (i *fileInfo) ModTime() time.Time { return i.modTime }
+func (i *fileInfo) IsDir() bool        { return i.dir }
+func (i *fileInfo) Sys() interface{}   { return nil }
+
+func (i *fileInfo) Mode() os.FileMode {
+	if i.dir {
+		return os.ModeDir | 0755
+	}
+	return 0644
+}
+
+// ContentType returns the content type from the metadata of the manifest
+// entry.
+func (i *fileInfo) ContentType(context.Context) (string, error) {
+	if i.contentType == "" {
+		return "", webdav.ErrNotImplemented
+	}
+	return i.contentType, nil
+}
+
+// ETag returns the reference of the file as the entity tag.
+func (i *fileInfo) ETag(context.Context) (string, error) {
+	if i.reference.IsZero() {
+		return "", webdav.ErrNotImplemented
+	}
+	return fmt.Sprintf("%q", i.reference), nil
+}
+
+// readFile is an open file of the manifest.
+type readFile struct {
+	file.Joiner
+	info *fileInfo
+}
+
+func (f *readFile) Close() error                       { return nil }
+func (f *readFile) Stat() (os.FileInfo, error)         { return f.info, nil }
+func (f *readFile) Readdir(int) ([]os.FileInfo, error) { return nil, os.ErrInvalid }
+func (f *readFile) Write([]byte) (int, error)          { return 0, os.ErrPermission }
+
+// dirFile is an open directory of the manifest.
+type dirFile struct {
+	fs      *fileSystem
+	ctx     context.Context
+	info    *fileInfo
+	entries []os.FileInfo
+	listed  bool
+}
+
+func (f *dirFile) Close() error                   { return nil }
+func (f *dirFile) Stat() (os.FileInfo, error)     { return f.info, nil }
+func (f *dirFile) Read([]byte) (int, error)       { return 0, os.ErrInvalid }
+func (f *dirFile) Seek(int64, int) (int64, error) { return 0, os.ErrInvalid }
+func (f *dirFile) Write([]byte) (int, error)      { return 0, os.ErrPermission }
+
+// Readdir lists the files and the subdirectories of the directory.
+func (f *dirFile) Readdir(count int) ([]os.FileInfo, error) {
+	if !f.listed {
+		if err := f.list(); err != nil {
+			return nil, err
+		}
+		f.listed = true
+	}
+
+	if count <= 0 {
+		entries := f.entries
+		f.entries = nil
+		return entries, nil
+	}
+	if len(f.entries) == 0 {
+		return nil, io.EOF
+	}
+	if count > len(f.entries) {
+		count = len(f.entries)
+	}
+	entries := f.entries[:count]
+	f.entries = f.entries[count:]
+	return entries, nil
+}
+
+func (f *dirFile) list() error {
+	ref, err := f.fs.manifestReference(f.ctx)
+	if err != nil || ref.IsZero() {
+		return err
+	}
+
+	dir := ""
+	if f.info.name != "/" {
+		dir = f.info.name + "/"
+	}
+	return manifest.WalkDir(f.ctx, loadsave.NewReadonly(f.fs.s.storer), ref, dir, func(d manifest.DirEntry) error {
+		if d.IsDir {
+			f.entries = append(f.entries, &fileInfo{name: strings.TrimSuffix(d.Path, "/"), dir: true})
+			return nil
+		}
+		if isEmpty(d.Entry.Reference()) {
+			return nil
+		}
+		info, err := f.fs.fileInfo(f.ctx, d.Path, d.Entry)
+		if err != nil {
+			return err
+		}
+		f.entries = append(f.entries, info)
+		return nil
+	})
+}
+
+// writeFile is a file which is being written. Its data is split while it is
+// written and the file is added to the manifest when it is closed.
+type writeFile struct {
+	fs   *fileSystem
+	ctx  context.Context
+	path string
+	pw   *io.PipeWriter
+	size int64
+
+	done chan struct{}
+	ref  swarm.Address
+	err  error
+}
+
+func (f *writeFile) Write(p []byte) (int, error) {
+	n, err := f.pw.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *writeFile) Stat() (os.FileInfo, error) {
+	return &fileInfo{name: f.path, size: f.size, modTime: time.Now()}, nil
+}
+
+func (f *writeFile) Read([]byte) (int, error)           { return 0, os.ErrInvalid }
+func (f *writeFile) Seek(int64, int) (int64, error)     { return 0, os.ErrInvalid }
+func (f *writeFile) Readdir(int) ([]os.FileInfo, error) { return nil, os.ErrInvalid }
+
+// Close stores the file unless the request body could not be read.
+func (f *writeFile) Close() error {
+	if err := f.fs.body.err; err != nil {
+		f.pw.CloseWithError(err)
+		<-f.done
+		return err
+	}
+	_ = f.pw.Close()
+	<-f.done
+	if f.err != nil {
+		return f.err
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(f.path))
+	if contentType == "" {
+		contentType = defaultContentType
+	}
+	metadata := map[string]string{
+		manifest.EntryMetadataContentTypeKey: contentType,
+		manifest.EntryMetadataFilenameKey:    path.Base(f.path),
+		metadataLastModifiedKey:              time.Now().UTC().Format(time.RFC3339),
+	}
+	return f.fs.s.update(f.ctx, f.fs.topic, func(ctx context.Context, m manifest.Interface, _ swarm.Address) error {
+		return m.Add(ctx, f.path, manifest.NewEntry(f.ref, metadata))
+	})
+}
@@ -0,0 +1,66 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dav
+
+import (
+	"strings"
+	"time"
+
+	"golang.org/x/net/webdav"
+)
+
+// prefixLockSystem locks the paths of a mount in a lock system shared by the
+// mounts, by prefixing them with the prefix of the mount.
+type prefixLockSystem struct {
+	webdav.LockSystem
+	prefix string
+}
+
+func (l *prefixLockSystem) Confirm(now time.Time, name0, name1 string, conditions ...webdav.Condition) (func(), error) {
+	return l.LockSystem.Confirm(now, l.name(name0), l.name(name1), conditions...)
+}
+
+func (l *prefixLockSystem) Create(now time.Time, details webdav.LockDetails) (string, error) {
+	details.Root = l.name(details.Root)
+	return l.LockSystem.Create(now, details)
+}
+
+func (l *prefixLockSystem) Refresh(now time.Time, token string, duration time.Duration) (webdav.LockDetails, error) {
+	details, err := l.LockSystem.Refresh(now, token, duration)
+	if err != nil {
+		return webdav.LockDetails{}, err
+	}
+	details.Root = "/" + strings.TrimPrefix(strings.TrimPrefix(details.Root, l.prefix), "/")
+	return details, nil
+}
+
+// name returns the name of the path in the shared lock system. The empty
+// name, which is not locked, is kept.
+func (l *prefixLockSystem) name(name string) string {
+	if name == "" {
+		return ""
+	}
+	return l.prefix + "/" + strings.TrimPrefix(name, "/")
+}
+
+// readOnlyLockSystem is the lock system of the read-only mounts, on which
+// nothing can be locked.
+type readOnlyLockSystem struct{}
+
+func (readOnlyLockSystem) Confirm(time.Time, string, string, ...webdav.Condition) (func(), error) {
+	return func() {}, nil
+}
+
+func (readOnlyLockSystem) Create(time.Time, webdav.LockDetails) (string, error) {
+	return "", webdav.ErrForbidden
+}
+
+func (readOnlyLockSystem) Refresh(time.Time, string, time.Duration) (webdav.LockDetails, error) {
+	return webdav.LockDetails{}, webdav.ErrNoSuchLock
+}
+
+func (readOnlyLockSystem) Unlock(time.Time, string) error {
+	return webdav.ErrNoSuchLock
+}
@@ -22,13 +22,13 @@ var (
 	errInvalidAddress = "Invalid address"
 )
 
-type balanceResponse struct {
+type BalanceResponse struct {
 	Peer    string         `json:"peer"`
 	Balance *bigint.BigInt `json:"balance"`
 }
 
-type balancesResponse struct {
-	Balances []balanceResponse `json:"balances"`
+type BalancesResponse struct {
+	Balances []BalanceResponse `json:"balances"`
 }
 
 func (s *Service) balancesHandler(w http.ResponseWriter, r *http.Request) {
@@ -40,17 +40,17 @@ func (s *Service) balancesHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	balResponses := make([]balanceResponse, len(balances))
+	balResponses := make([]BalanceResponse, len(balances))
 	i := 0
 	for k := range balances {
-		balResponses[i] = balanceResponse{
+		balResponses[i] = BalanceResponse{
 			Peer:    k,
 			Balance: bigint.Wrap(balances[k]),
 		}
 		i++
 	}
 
-	jsonhttp.OK(w, balancesResponse{Balances: balResponses})
+	jsonhttp.OK(w, BalancesResponse{Balances: balResponses})
 }
 
 func (s *Service) peerBalanceHandler(w http.ResponseWriter, r *http.Request) {
@@ -75,7 +75,7 @@ func (s *Service) peerBalanceHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	jsonhttp.OK(w, balanceResponse{
+	jsonhttp.OK(w, BalanceResponse{
 		Peer:    peer.String(),
 		Balance: bigint.Wrap(balance),
 	})
@@ -90,17 +90,17 @@ func (s *Service) compensatedBalancesHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	balResponses := make([]balanceResponse, len(balances))
+	balResponses := make([]BalanceResponse, len(balances))
 	i := 0
 	for k := range balances {
-		balResponses[i] = balanceResponse{
+		balResponses[i] = BalanceResponse{
 			Peer:    k,
 			Balance: bigint.Wrap(balances[k]),
 		}
 		i++
 	}
 
-	jsonhttp.OK(w, balancesResponse{Balances: balResponses})
+	jsonhttp.OK(w, BalancesResponse{Balances: balResponses})
 }
 
 func (s *Service) compensatedPeerBalanceHandler(w http.ResponseWriter, r *http.Request) {
@@ -125,7 +125,7 @@ func (s *Service) compensatedPeerBalanceHandler(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	jsonhttp.OK(w, balanceResponse{
+	jsonhttp.OK(w, BalanceResponse{
 		Peer:    peer.String(),
 		Balance: bigint.Wrap(balance),
 	})
@@ -36,35 +36,37 @@ const (
 	errNoCheque                    = "no prior cheque"
 	errBadGasPrice                 = "bad gas price"
 	errBadGasLimit                 = "bad gas limit"
+)
 
-	gasPriceHeader  = "Gas-Price"
-	gasLimitHeader  = "Gas-Limit"
-	immutableHeader = "Immutable"
+const (
+	GasPriceHeader  = "Gas-Price"
+	GasLimitHeader  = "Gas-Limit"
+	ImmutableHeader = "Immutable"
 )
 
-type chequebookBalanceResponse struct {
+type ChequebookBalanceResponse struct {
 	TotalBalance     *bigint.BigInt `json:"totalBalance"`
 	AvailableBalance *bigint.BigInt `json:"availableBalance"`
 }
 
-type chequebookAddressResponse struct {
+type ChequebookAddressResponse struct {
 	Address string `json:"chequebookAddress"`
 }
 
-type chequebookLastChequePeerResponse struct {
+type ChequebookLastChequePeerResponse struct {
 	Beneficiary string         `json:"beneficiary"`
 	Chequebook  string         `json:"chequebook"`
 	Payout      *bigint.BigInt `json:"payout"`
 }
 
-type chequebookLastChequesPeerResponse struct {
+type ChequebookLastChequesPeerResponse struct {
 	Peer         string                            `json:"peer"`
-	LastReceived *chequebookLastChequePeerResponse `json:"lastreceived"`
-	LastSent     *chequebookLastChequePeerResponse `json:"lastsent"`
+	LastReceived *ChequebookLastChequePeerResponse `json:"lastreceived"`
+	LastSent     *ChequebookLastChequePeerResponse `json:"lastsent"`
 }
 
-type chequebookLastChequesResponse struct {
-	LastCheques []chequebookLastChequesPeerResponse `json:"lastcheques"`
+type ChequebookLastChequesResponse struct {
+	LastCheques []ChequebookLastChequesPeerResponse `json:"lastcheques"`
 }
 
 func (s *Service) chequebookBalanceHandler(w http.ResponseWriter, r *http.Request) {
@@ -90,12 +92,12 @@ func (s *Service) chequebookBalanceHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	jsonhttp.OK(w, chequebookBalanceResponse{TotalBalance: bigint.Wrap(balance), AvailableBalance: bigint.Wrap(availableBalance)})
+	jsonhttp.OK(w, ChequebookBalanceResponse{TotalBalance: bigint.Wrap(balance), AvailableBalance: bigint.Wrap(availableBalance)})
 }
 
 func (s *Service) chequebookAddressHandler(w http.ResponseWriter, r *http.Request) {
 	address := s.chequebook.Address()
-	jsonhttp.OK(w, chequebookAddressResponse{Address: address.String()})
+	jsonhttp.OK(w, ChequebookAddressResponse{Address: address.String()})
 }
 
 func (s *Service) chequebookLastPeerHandler(w http.ResponseWriter, r *http.Request) {
@@ -108,7 +110,7 @@ func (s *Service) chequebookLastPeerHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	var lastSentResponse *chequebookLastChequePeerResponse
+	var lastSentResponse *ChequebookLastChequePeerResponse
 	lastSent, err := s.swap.LastSentCheque(peer)
 	if errors.Is(err, postagecontract.ErrChainDisabled) {
 		s.logger.Debugf("debug api: chequebook cheque peer: %v", err)
@@ -123,14 +125,14 @@ func (s *Service) chequebookLastPeerHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 	if err == nil {
-		lastSentResponse = &chequebookLastChequePeerResponse{
+		lastSentResponse = &ChequebookLastChequePeerResponse{
 			Beneficiary: lastSent.Cheque.Beneficiary.String(),
 			Chequebook:  lastSent.Cheque.Chequebook.String(),
 			Payout:      bigint.Wrap(lastSent.Cheque.CumulativePayout),
 		}
 	}
 
-	var lastReceivedResponse *chequebookLastChequePeerResponse
+	var lastReceivedResponse *ChequebookLastChequePeerResponse
 	lastReceived, err := s.swap.LastReceivedCheque(peer)
 	if err != nil && err != chequebook.ErrNoCheque {
 		s.logger.Debugf("debug api: chequebook cheque peer: get peer %s last cheque: %v", peer.String(), err)
@@ -139,14 +141,14 @@ func (s *Service) chequebookLastPeerHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 	if err == nil {
-		lastReceivedResponse = &chequebookLastChequePeerResponse{
+		lastReceivedResponse = &ChequebookLastChequePeerResponse{
 			Beneficiary: lastReceived.Cheque.Beneficiary.String(),
 			Chequebook:  lastReceived.Cheque.Chequebook.String(),
 			Payout:      bigint.Wrap(lastReceived.Cheque.CumulativePayout),
 		}
 	}
 
-	jsonhttp.OK(w, chequebookLastChequesPeerResponse{
+	jsonhttp.OK(w, ChequebookLastChequesPeerResponse{
 		Peer:         addr,
 		LastReceived: lastReceivedResponse,
 		LastSent:     lastSentResponse,
@@ -178,11 +180,11 @@ func (s *Service) chequebookAllLastHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	lcr := make(map[string]chequebookLastChequesPeerResponse)
+	lcr := make(map[string]ChequebookLastChequesPeerResponse)
 	for i, j := range lastchequessent {
-		lcr[i] = chequebookLastChequesPeerResponse{
+		lcr[i] = ChequebookLastChequesPeerResponse{
 			Peer: i,
-			LastSent: &chequebookLastChequePeerResponse{
+			LastSent: &ChequebookLastChequePeerResponse{
 				Beneficiary: j.Cheque.Beneficiary.String(),
 				Chequebook:  j.Cheque.Chequebook.String(),
 				Payout:      bigint.Wrap(j.Cheque.CumulativePayout),
@@ -193,17 +195,17 @@ func (s *Service) chequebookAllLastHandler(w http.ResponseWriter, r *http.Reques
 	for i, j := range lastchequesreceived {
 		if _, ok := lcr[i]; ok {
 			t := lcr[i]
-			t.LastReceived = &chequebookLastChequePeerResponse{
+			t.LastReceived = &ChequebookLastChequePeerResponse{
 				Beneficiary: j.Cheque.Beneficiary.String(),
 				Chequebook:  j.Cheque.Chequebook.String(),
 				Payout:      bigint.Wrap(j.Cheque.CumulativePayout),
 			}
 			lcr[i] = t
 		} else {
-			lcr[i] = chequebookLastChequesPeerResponse{
+			lcr[i] = ChequebookLastChequesPeerResponse{
 				Peer:     i,
 				LastSent: nil,
-				LastReceived: &chequebookLastChequePeerResponse{
+				LastReceived: &ChequebookLastChequePeerResponse{
 					Beneficiary: j.Cheque.Beneficiary.String(),
 					Chequebook:  j.Cheque.Chequebook.String(),
 					Payout:      bigint.Wrap(j.Cheque.CumulativePayout),
@@ -212,17 +214,17 @@ func (s *Service) chequebookAllLastHandler(w http.ResponseWriter, r *http.Reques
 		}
 	}
 
-	lcresponses := make([]chequebookLastChequesPeerResponse, len(lcr))
+	lcresponses := make([]ChequebookLastChequesPeerResponse, len(lcr))
 	i := 0
 	for k := range lcr {
 		lcresponses[i] = lcr[k]
 		i++
 	}
 
-	jsonhttp.OK(w, chequebookLastChequesResponse{LastCheques: lcresponses})
+	jsonhttp.OK(w, ChequebookLastChequesResponse{LastCheques: lcresponses})
 }
 
-type swapCashoutResponse struct {
+type SwapCashoutResponse struct {
 	TransactionHash string `json:"transactionHash"`
 }
 
@@ -237,7 +239,7 @@ func (s *Service) swapCashoutHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := r.Context()
-	if price, ok := r.Header[gasPriceHeader]; ok {
+	if price, ok := r.Header[GasPriceHeader]; ok {
 		p, ok := big.NewInt(0).SetString(price[0], 10)
 		if !ok {
 			s.logger.Error("debug api: cashout peer: bad gas price")
@@ -247,7 +249,7 @@ func (s *Service) swapCashoutHandler(w http.ResponseWriter, r *http.Request) {
 		ctx = sctx.SetGasPrice(ctx, p)
 	}
 
-	if limit, ok := r.Header[gasLimitHeader]; ok {
+	if limit, ok := r.Header[GasLimitHeader]; ok {
 		l, err := strconv.ParseUint(limit[0], 10, 64)
 		if err != nil {
 			s.logger.Debugf("debug api: cashout peer: bad gas limit: %v", err)
@@ -280,20 +282,20 @@ func (s *Service) swapCashoutHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	jsonhttp.OK(w, swapCashoutResponse{TransactionHash: txHash.String()})
+	jsonhttp.OK(w, SwapCashoutResponse{TransactionHash: txHash.String()})
 }
 
-type swapCashoutStatusResult struct {
+type SwapCashoutStatusResult struct {
 	Recipient  common.Address `json:"recipient"`
 	LastPayout *bigint.BigInt `json:"lastPayout"`
 	Bounced    bool           `json:"bounced"`
 }
 
-type swapCashoutStatusResponse struct {
+type SwapCashoutStatusResponse struct {
 	Peer            swarm.Address                     `json:"peer"`
-	Cheque          *chequebookLastChequePeerResponse `json:"lastCashedCheque"`
+	Cheque          *ChequebookLastChequePeerResponse `json:"lastCashedCheque"`
 	TransactionHash *common.Hash                      `json:"transactionHash"`
-	Result          *swapCashoutStatusResult          `json:"result"`
+	Result          *SwapCashoutStatusResult          `json:"result"`
 	UncashedAmount  *bigint.BigInt                    `json:"uncashedAmount"`
 }
 
@@ -333,18 +335,18 @@ func (s *Service) swapCashoutStatusHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	var result *swapCashoutStatusResult
+	var result *SwapCashoutStatusResult
 	var txHash *common.Hash
-	var chequeResponse *chequebookLastChequePeerResponse
+	var chequeResponse *ChequebookLastChequePeerResponse
 	if status.Last != nil {
 		if status.Last.Result != nil {
-			result = &swapCashoutStatusResult{
+			result = &SwapCashoutStatusResult{
 				Recipient:  status.Last.Result.Recipient,
 				LastPayout: bigint.Wrap(status.Last.Result.TotalPayout),
 				Bounced:    status.Last.Result.Bounced,
 			}
 		}
-		chequeResponse = &chequebookLastChequePeerResponse{
+		chequeResponse = &ChequebookLastChequePeerResponse{
 			Chequebook:  status.Last.Cheque.Chequebook.String(),
 			Payout:      bigint.Wrap(status.Last.Cheque.CumulativePayout),
 			Beneficiary: status.Last.Cheque.Beneficiary.String(),
@@ -352,7 +354,7 @@ func (s *Service) swapCashoutStatusHandler(w http.ResponseWriter, r *http.Reques
 		txHash = &status.Last.TxHash
 	}
 
-	jsonhttp.OK(w, swapCashoutStatusResponse{
+	jsonhttp.OK(w, SwapCashoutStatusResponse{
 		Peer:            peer,
 		TransactionHash: txHash,
 		Cheque:          chequeResponse,
@@ -361,7 +363,7 @@ func (s *Service) swapCashoutStatusHandler(w http.ResponseWriter, r *http.Reques
 	})
 }
 
-type chequebookTxResponse struct {
+type ChequebookTxResponse struct {
 	TransactionHash common.Hash `json:"transactionHash"`
 }
 
@@ -381,7 +383,7 @@ func (s *Service) chequebookWithdrawHandler(w http.ResponseWriter, r *http.Reque
 	}
 
 	ctx := r.Context()
-	if price, ok := r.Header[gasPriceHeader]; ok {
+	if price, ok := r.Header[GasPriceHeader]; ok {
 		p, ok := big.NewInt(0).SetString(price[0], 10)
 		if !ok {
 			s.logger.Error("debug api: withdraw: bad gas price")
@@ -405,7 +407,7 @@ func (s *Service) chequebookWithdrawHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	jsonhttp.OK(w, chequebookTxResponse{TransactionHash: txHash})
+	jsonhttp.OK(w, ChequebookTxResponse{TransactionHash: txHash})
 }
 
 func (s *Service) chequebookDepositHandler(w http.ResponseWriter, r *http.Request) {
@@ -424,7 +426,7 @@ func (s *Service) chequebookDepositHandler(w http.ResponseWriter, r *http.Reques
 	}
 
 	ctx := r.Context()
-	if price, ok := r.Header[gasPriceHeader]; ok {
+	if price, ok := r.Header[GasPriceHeader]; ok {
 		p, ok := big.NewInt(0).SetString(price[0], 10)
 		if !ok {
 			s.logger.Error("debug api: deposit: bad gas price")
@@ -448,5 +450,5 @@ func (s *Service) chequebookDepositHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	jsonhttp.OK(w, chequebookTxResponse{TransactionHash: txHash})
+	jsonhttp.OK(w, ChequebookTxResponse{TransactionHash: txHash})
 }
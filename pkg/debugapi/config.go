@@ -0,0 +1,108 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package debugapi
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+)
+
+// ErrInvalidConfig is returned by the ConfigReloader if the changed options
+// are not valid, in which case none of them is applied.
+var ErrInvalidConfig = errors.New("invalid config")
+
+// ConfigReloadResult lists the options which changed since the node started
+// or the config was last reloaded.
+type ConfigReloadResult struct {
+	// Applied are the options which were applied to the running node.
+	Applied []string `json:"applied"`
+	// RestartRequired are the options which are applied only when the node
+	// is restarted.
+	RestartRequired []string `json:"restartRequired"`
+}
+
+// ConfigReloader reloads the config of the node and applies the options
+// which can be changed while the node runs.
+type ConfigReloader interface {
+	ReloadConfig() (*ConfigReloadResult, error)
+}
+
+// Sources of the values of the config options.
+const (
+	ConfigSourceDefault = "default"
+	ConfigSourceFile    = "file"
+	ConfigSourceEnv     = "env"
+	ConfigSourceFlag    = "flag"
+)
+
+// ConfigOption is an option of the node with the value in effect.
+type ConfigOption struct {
+	Name  string      `json:"name"`
+	Value interface{} `json:"value"`
+	// Source is where the value comes from, one of the ConfigSource
+	// constants.
+	Source string `json:"source"`
+	// RestartRequired is true if changes of the option are applied only
+	// when the node is restarted.
+	RestartRequired bool `json:"restartRequired"`
+}
+
+// ConfigInspector returns the options of the node with the values in effect
+// and the secrets redacted.
+type ConfigInspector interface {
+	ConfigOptions() []ConfigOption
+}
+
+// SetConfigInspector sets the ConfigInspector used by the /config endpoint.
+// It must be called before the Debug API is served.
+func (s *Service) SetConfigInspector(i ConfigInspector) {
+	s.configInspector = i
+}
+
+type ConfigResponse struct {
+	Options []ConfigOption `json:"options"`
+}
+
+func (s *Service) configHandler(w http.ResponseWriter, r *http.Request) {
+	if s.configInspector == nil {
+		jsonhttp.NotImplemented(w, "config not available")
+		return
+	}
+
+	options := s.configInspector.ConfigOptions()
+	if options == nil {
+		options = []ConfigOption{}
+	}
+	jsonhttp.OK(w, ConfigResponse{Options: options})
+}
+
+// SetConfigReloader sets the ConfigReloader used by the /config/reload
+// endpoint. It must be called before the Debug API is served.
+func (s *Service) SetConfigReloader(r ConfigReloader) {
+	s.configReloader = r
+}
+
+func (s *Service) configReloadHandler(w http.ResponseWriter, r *http.Request) {
+	if s.configReloader == nil {
+		jsonhttp.NotImplemented(w, "config reload not available")
+		return
+	}
+
+	res, err := s.configReloader.ReloadConfig()
+	if err != nil {
+		if errors.Is(err, ErrInvalidConfig) {
+			s.logger.Debugf("debugapi: config reload: %v", err)
+			jsonhttp.BadRequest(w, err.Error())
+			return
+		}
+		s.logger.Debugf("debugapi: config reload: %v", err)
+		s.logger.Error("debugapi: config reload")
+		jsonhttp.InternalServerError(w, "config reload failed")
+		return
+	}
+	jsonhttp.OK(w, res)
+}
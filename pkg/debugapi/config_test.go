@@ -0,0 +1,119 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package debugapi_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/debugapi"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+)
+
+type configInspectorFunc func() []debugapi.ConfigOption
+
+func (f configInspectorFunc) ConfigOptions() []debugapi.ConfigOption {
+	return f()
+}
+
+type configReloaderFunc func() (*debugapi.ConfigReloadResult, error)
+
+func (f configReloaderFunc) ReloadConfig() (*debugapi.ConfigReloadResult, error) {
+	return f()
+}
+
+func TestConfig(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		options := []debugapi.ConfigOption{
+			{Name: "cache-capacity", Value: float64(2000), Source: debugapi.ConfigSourceFile},
+			{Name: "p2p-addr", Value: ":1634", Source: debugapi.ConfigSourceDefault, RestartRequired: true},
+			{Name: "password", Value: "[redacted]", Source: debugapi.ConfigSourceEnv, RestartRequired: true},
+		}
+		srv := newTestServer(t, testServerOptions{
+			ConfigInspector: configInspectorFunc(func() []debugapi.ConfigOption {
+				return options
+			}),
+		})
+
+		jsonhttptest.Request(t, srv.Client, http.MethodGet, "/config", http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(debugapi.ConfigResponse{
+				Options: options,
+			}),
+		)
+	})
+
+	t.Run("not available", func(t *testing.T) {
+		srv := newTestServer(t, testServerOptions{})
+
+		jsonhttptest.Request(t, srv.Client, http.MethodGet, "/config", http.StatusNotImplemented,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "config not available",
+				Code:    http.StatusNotImplemented,
+			}),
+		)
+	})
+}
+
+func TestConfigReload(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		want := debugapi.ConfigReloadResult{
+			Applied:         []string{"cache-capacity", "payment-threshold"},
+			RestartRequired: []string{"p2p-addr"},
+		}
+		srv := newTestServer(t, testServerOptions{
+			ConfigReloader: configReloaderFunc(func() (*debugapi.ConfigReloadResult, error) {
+				return &want, nil
+			}),
+		})
+
+		jsonhttptest.Request(t, srv.Client, http.MethodPost, "/config/reload", http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(want),
+		)
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		srv := newTestServer(t, testServerOptions{
+			ConfigReloader: configReloaderFunc(func() (*debugapi.ConfigReloadResult, error) {
+				return nil, fmt.Errorf("payment-threshold: %w", debugapi.ErrInvalidConfig)
+			}),
+		})
+
+		jsonhttptest.Request(t, srv.Client, http.MethodPost, "/config/reload", http.StatusBadRequest,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "payment-threshold: invalid config",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		srv := newTestServer(t, testServerOptions{
+			ConfigReloader: configReloaderFunc(func() (*debugapi.ConfigReloadResult, error) {
+				return nil, errors.New("read config file")
+			}),
+		})
+
+		jsonhttptest.Request(t, srv.Client, http.MethodPost, "/config/reload", http.StatusInternalServerError,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "config reload failed",
+				Code:    http.StatusInternalServerError,
+			}),
+		)
+	})
+
+	t.Run("not available", func(t *testing.T) {
+		srv := newTestServer(t, testServerOptions{})
+
+		jsonhttptest.Request(t, srv.Client, http.MethodPost, "/config/reload", http.StatusNotImplemented,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "config reload not available",
+				Code:    http.StatusNotImplemented,
+			}),
+		)
+	})
+}
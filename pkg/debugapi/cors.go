@@ -12,7 +12,7 @@ import (
 // corsHandler sets CORS headers to HTTP response if allowed origins are configured.
 func (s *Service) corsHandler(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if o := r.Header.Get("Origin"); o != "" && checkOrigin(r, s.corsAllowedOrigins) {
+		if o := r.Header.Get("Origin"); o != "" && s.checkOrigin(r) {
 			w.Header().Set("Access-Control-Allow-Credentials", "true")
 			w.Header().Set("Access-Control-Allow-Origin", o)
 			w.Header().Set("Access-Control-Allow-Headers", "User-Agent, Origin, Accept, Authorization, Content-Type, X-Requested-With, Access-Control-Request-Headers, Access-Control-Request-Method, Gas-Price, Gas-Limit")
@@ -23,8 +23,17 @@ func (s *Service) corsHandler(h http.Handler) http.Handler {
 	})
 }
 
+// SetCORSAllowedOrigins replaces the origins which are allowed to make
+// cross-origin requests.
+func (s *Service) SetCORSAllowedOrigins(origins []string) {
+	s.corsAllowedOriginsMu.Lock()
+	defer s.corsAllowedOriginsMu.Unlock()
+
+	s.corsAllowedOrigins = origins
+}
+
 // checkOrigin returns true if the origin header is not set or is equal to the request host.
-func checkOrigin(r *http.Request, allowed []string) bool {
+func (s *Service) checkOrigin(r *http.Request) bool {
 	origin := r.Header["Origin"]
 	if len(origin) == 0 {
 		return true
@@ -33,8 +42,14 @@ func checkOrigin(r *http.Request, allowed []string) bool {
 	if r.TLS != nil {
 		scheme = "https"
 	}
-	hosts := append(allowed, scheme+"://"+r.Host)
-	for _, v := range hosts {
+	if equalASCIIFold(origin[0], scheme+"://"+r.Host) {
+		return true
+	}
+
+	s.corsAllowedOriginsMu.RLock()
+	defer s.corsAllowedOriginsMu.RUnlock()
+
+	for _, v := range s.corsAllowedOrigins {
 		if equalASCIIFold(origin[0], v) || v == "*" {
 			return true
 		}
@@ -16,6 +16,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/ethersphere/bee/pkg/accounting"
+	"github.com/ethersphere/bee/pkg/events"
 	"github.com/ethersphere/bee/pkg/logging"
 	"github.com/ethersphere/bee/pkg/p2p"
 	"github.com/ethersphere/bee/pkg/pingpong"
@@ -56,6 +57,7 @@ type Service struct {
 	topologyDriver     topology.Driver
 	storer             storage.Storer
 	tracer             *tracing.Tracer
+	events             *events.Bus
 	tags               *tags.Tags
 	accounting         accounting.Interface
 	pseudosettle       settlement.Interface
@@ -79,6 +81,14 @@ type Service struct {
 	erc20Service       erc20.Service
 	chainID            int64
 
+	readinessChecks   []readinessCheck
+	readinessChecksMu sync.Mutex
+
+	corsAllowedOriginsMu sync.RWMutex
+
+	configInspector ConfigInspector
+	configReloader  ConfigReloader
+
 	// handler is changed in the Configure method
 	handler   http.Handler
 	handlerMu sync.RWMutex
@@ -93,7 +103,7 @@ type Service struct {
 // to expose /addresses, /health endpoints, Go metrics and pprof. It is useful to expose
 // these endpoints before all dependencies are configured and injected to have
 // access to basic debugging tools and /health endpoint.
-func New(publicKey, pssPublicKey ecdsa.PublicKey, ethereumAddress common.Address, logger logging.Logger, tracer *tracing.Tracer, corsAllowedOrigins []string, blockTime *big.Int, transaction transaction.Service, chainBackend transaction.Backend, restrict bool, auth authenticator, gatewayMode bool, beeMode BeeNodeMode, chainID int64) *Service {
+func New(publicKey, pssPublicKey ecdsa.PublicKey, ethereumAddress common.Address, logger logging.Logger, tracer *tracing.Tracer, eventBus *events.Bus, corsAllowedOrigins []string, blockTime *big.Int, transaction transaction.Service, chainBackend transaction.Backend, restrict bool, auth authenticator, gatewayMode bool, beeMode BeeNodeMode, chainID int64) *Service {
 	s := new(Service)
 	s.auth = auth
 	s.restricted = restrict
@@ -102,6 +112,7 @@ func New(publicKey, pssPublicKey ecdsa.PublicKey, ethereumAddress common.Address
 	s.ethereumAddress = ethereumAddress
 	s.logger = logger
 	s.tracer = tracer
+	s.events = eventBus
 	s.corsAllowedOrigins = corsAllowedOrigins
 	s.blockTime = blockTime
 	s.metricsRegistry = newMetricsRegistry()
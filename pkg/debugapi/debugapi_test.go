@@ -13,6 +13,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"sort"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -21,6 +22,7 @@ import (
 	"github.com/ethersphere/bee/pkg/api"
 	"github.com/ethersphere/bee/pkg/crypto"
 	"github.com/ethersphere/bee/pkg/debugapi"
+	"github.com/ethersphere/bee/pkg/events"
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
 	"github.com/ethersphere/bee/pkg/logging"
@@ -81,6 +83,11 @@ type testServerOptions struct {
 	Traverser          traversal.Traverser
 	Erc20Opts          []erc20mock.Option
 	ChainID            int64
+	Logger             logging.Logger
+	ReadinessChecks    map[string]debugapi.ReadinessCheck
+	EventBus           *events.Bus
+	ConfigInspector    debugapi.ConfigInspector
+	ConfigReloader     debugapi.ConfigReloader
 }
 
 type testServer struct {
@@ -98,8 +105,25 @@ func newTestServer(t *testing.T, o testServerOptions) *testServer {
 	backend := backendmock.New(o.BackendOpts...)
 	erc20 := erc20mock.New(o.Erc20Opts...)
 	ln := lightnode.NewContainer(o.Overlay)
-	s := debugapi.New(o.PublicKey, o.PSSPublicKey, o.EthereumAddress, logging.New(io.Discard, 0), nil, o.CORSAllowedOrigins, big.NewInt(2), transaction, backend, false, nil, false, debugapi.FullMode, o.ChainID)
+	if o.Logger == nil {
+		o.Logger = logging.New(io.Discard, 0)
+	}
+	s := debugapi.New(o.PublicKey, o.PSSPublicKey, o.EthereumAddress, o.Logger, nil, o.EventBus, o.CORSAllowedOrigins, big.NewInt(2), transaction, backend, false, nil, false, debugapi.FullMode, o.ChainID)
+	if o.ConfigInspector != nil {
+		s.SetConfigInspector(o.ConfigInspector)
+	}
+	if o.ConfigReloader != nil {
+		s.SetConfigReloader(o.ConfigReloader)
+	}
 	s.Configure(o.Overlay, o.P2P, o.Pingpong, topologyDriver, ln, o.Storer, o.Tags, acc, settlement, true, true, swapserv, chequebook, o.BatchStore, o.Post, o.PostageContract, o.Traverser, erc20)
+	names := make([]string, 0, len(o.ReadinessChecks))
+	for name := range o.ReadinessChecks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s.AddReadinessCheck(name, o.ReadinessChecks[name])
+	}
 	ts := httptest.NewServer(s)
 	t.Cleanup(ts.Close)
 
@@ -167,7 +191,7 @@ func TestServer_Configure(t *testing.T) {
 	transaction := transactionmock.New(o.TransactionOpts...)
 	gatewayMode := false
 	beeMode := debugapi.FullMode
-	s := debugapi.New(o.PublicKey, o.PSSPublicKey, o.EthereumAddress, logging.New(io.Discard, 0), nil, nil, big.NewInt(2), transaction, nil, false, nil, gatewayMode, beeMode, 1)
+	s := debugapi.New(o.PublicKey, o.PSSPublicKey, o.EthereumAddress, logging.New(io.Discard, 0), nil, nil, nil, big.NewInt(2), transaction, nil, false, nil, gatewayMode, beeMode, 1)
 	ts := httptest.NewServer(s)
 	t.Cleanup(ts.Close)
 
@@ -202,11 +226,12 @@ func TestServer_Configure(t *testing.T) {
 
 	testBasicRouter(t, client)
 	jsonhttptest.Request(t, client, http.MethodGet, "/readiness", http.StatusOK,
-		jsonhttptest.WithExpectedJSONResponse(debugapi.StatusResponse{
+		jsonhttptest.WithExpectedJSONResponse(debugapi.ReadinessResponse{
 			Status:          "ok",
 			Version:         bee.Version,
 			APIVersion:      api.Version,
 			DebugAPIVersion: debugapi.Version,
+			Checks:          []debugapi.ReadinessCheckResponse{},
 		}),
 	)
 	jsonhttptest.Request(t, client, http.MethodGet, "/addresses", http.StatusOK,
@@ -0,0 +1,91 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package debugapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/events"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+)
+
+// eventsPingPeriod is the period of the comments which keep the event
+// stream open through proxies.
+const eventsPingPeriod = 30 * time.Second
+
+// eventsHandler streams the events of the node as server-sent events. The
+// events can be filtered by one or more type query parameters.
+func (s *Service) eventsHandler(w http.ResponseWriter, r *http.Request) {
+	types := make([]events.Type, 0, len(r.URL.Query()["type"]))
+	for _, v := range r.URL.Query()["type"] {
+		t := events.Type(v)
+		if !isEventType(t) {
+			s.logger.Debugf("debugapi: events: unknown event type %q", v)
+			jsonhttp.BadRequest(w, "unknown event type")
+			return
+		}
+		types = append(types, t)
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		s.logger.Error("debugapi: events: streaming not supported")
+		jsonhttp.InternalServerError(w, nil)
+		return
+	}
+
+	c, unsubscribe := s.events.Subscribe(types...)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// disable buffering of proxies like nginx
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(eventsPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case e, ok := <-c:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(e)
+			if err != nil {
+				s.logger.Debugf("debugapi: events: marshal %s event: %v", e.Type, err)
+				s.logger.Error("debugapi: events: marshal event")
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data); err != nil {
+				s.logger.Debugf("debugapi: events: write: %v", err)
+				return
+			}
+			flusher.Flush()
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				s.logger.Debugf("debugapi: events: write: %v", err)
+				return
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func isEventType(t events.Type) bool {
+	for _, v := range events.Types {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package debugapi_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/events"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+)
+
+func TestEvents(t *testing.T) {
+	bus := events.New()
+	testServer := newTestServer(t, testServerOptions{
+		EventBus: bus,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/events?type=gc&type=radius", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := testServer.Client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("got status %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("got content type %q, want %q", ct, "text/event-stream")
+	}
+
+	bus.Publish(events.TypeDepth, events.DepthData{Depth: 2})
+	bus.Publish(events.TypeGarbageCollection, events.GarbageCollectionData{Collected: 5})
+
+	scanner := bufio.NewScanner(resp.Body)
+	scan := func() string {
+		t.Helper()
+		if !scanner.Scan() {
+			t.Fatalf("stream ended: %v", scanner.Err())
+		}
+		return scanner.Text()
+	}
+
+	if l := scan(); l != "event: gc" {
+		t.Fatalf("got line %q, want %q", l, "event: gc")
+	}
+	l := scan()
+	if !strings.HasPrefix(l, "data: ") {
+		t.Fatalf("got line %q, want data", l)
+	}
+	var e struct {
+		Type events.Type                  `json:"type"`
+		Data events.GarbageCollectionData `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(l, "data: ")), &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Type != events.TypeGarbageCollection || e.Data.Collected != 5 {
+		t.Fatalf("got event %+v", e)
+	}
+	if l := scan(); l != "" {
+		t.Fatalf("got line %q, want empty line", l)
+	}
+
+	// closing the bus ends the stream
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadAll(resp.Body); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEvents_unknownType(t *testing.T) {
+	testServer := newTestServer(t, testServerOptions{
+		EventBus: events.New(),
+	})
+
+	jsonhttptest.Request(t, testServer.Client, http.MethodGet, "/events?type=unknown", http.StatusBadRequest,
+		jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+			Message: "unknown event type",
+			Code:    http.StatusBadRequest,
+		}),
+	)
+}
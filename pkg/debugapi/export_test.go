@@ -4,43 +4,6 @@
 
 package debugapi
 
-type (
-	StatusResponse                    = statusResponse
-	NodeResponse                      = nodeResponse
-	PingpongResponse                  = pingpongResponse
-	PeerConnectResponse               = peerConnectResponse
-	PeersResponse                     = peersResponse
-	AddressesResponse                 = addressesResponse
-	WelcomeMessageRequest             = welcomeMessageRequest
-	WelcomeMessageResponse            = welcomeMessageResponse
-	BalancesResponse                  = balancesResponse
-	BalanceResponse                   = balanceResponse
-	SettlementResponse                = settlementResponse
-	SettlementsResponse               = settlementsResponse
-	ChequebookBalanceResponse         = chequebookBalanceResponse
-	ChequebookAddressResponse         = chequebookAddressResponse
-	ChequebookLastChequePeerResponse  = chequebookLastChequePeerResponse
-	ChequebookLastChequesResponse     = chequebookLastChequesResponse
-	ChequebookLastChequesPeerResponse = chequebookLastChequesPeerResponse
-	ChequebookTxResponse              = chequebookTxResponse
-	SwapCashoutResponse               = swapCashoutResponse
-	SwapCashoutStatusResponse         = swapCashoutStatusResponse
-	SwapCashoutStatusResult           = swapCashoutStatusResult
-	TransactionInfo                   = transactionInfo
-	TransactionPendingList            = transactionPendingList
-	TransactionHashResponse           = transactionHashResponse
-	TagResponse                       = tagResponse
-	ReserveStateResponse              = reserveStateResponse
-	ChainStateResponse                = chainStateResponse
-	PostageCreateResponse             = postageCreateResponse
-	PostageStampResponse              = postageStampResponse
-	PostageStampsResponse             = postageStampsResponse
-	PostageBatchResponse              = postageBatchResponse
-	PostageStampBucketsResponse       = postageStampBucketsResponse
-	BucketData                        = bucketData
-	WalletResponse                    = walletResponse
-)
-
 var (
 	ErrCantBalance           = errCantBalance
 	ErrCantBalances          = errCantBalances
@@ -0,0 +1,61 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package debugapi
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+type LoggerResponse struct {
+	Name  string `json:"name"`
+	Level string `json:"level"`
+}
+
+type LoggersResponse struct {
+	Loggers []LoggerResponse `json:"loggers"`
+}
+
+func (s *Service) loggersHandler(w http.ResponseWriter, r *http.Request) {
+	levels := s.logger.NamedLevels()
+
+	resp := LoggersResponse{Loggers: make([]LoggerResponse, 0, len(levels))}
+	for name, level := range levels {
+		resp.Loggers = append(resp.Loggers, LoggerResponse{Name: name, Level: level.String()})
+	}
+	sort.Slice(resp.Loggers, func(i, j int) bool {
+		return resp.Loggers[i].Name < resp.Loggers[j].Name
+	})
+	jsonhttp.OK(w, resp)
+}
+
+func (s *Service) setLoggerLevelHandler(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	level, err := logrus.ParseLevel(mux.Vars(r)["level"])
+	if err != nil {
+		s.logger.Debugf("debugapi: loggers: parse level: %v", err)
+		jsonhttp.BadRequest(w, "invalid level")
+		return
+	}
+
+	if err := s.logger.SetNamedLevel(name, level); err != nil {
+		if errors.Is(err, logging.ErrUnknownLogger) {
+			jsonhttp.NotFound(w, "logger not found")
+			return
+		}
+		s.logger.Debugf("debugapi: loggers: set level of %s: %v", name, err)
+		s.logger.Error("debugapi: loggers: set level")
+		jsonhttp.InternalServerError(w, err)
+		return
+	}
+	s.logger.Infof("debugapi: level of logger %s set to %s", name, level)
+	jsonhttp.OK(w, nil)
+}
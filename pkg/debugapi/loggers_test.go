@@ -0,0 +1,69 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package debugapi_test
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/debugapi"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+	"github.com/ethersphere/bee/pkg/jsonhttp/jsonhttptest"
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/sirupsen/logrus"
+)
+
+func TestLoggers(t *testing.T) {
+	logger := logging.New(io.Discard, logrus.InfoLevel)
+	logger.Named("pushsync")
+	logger.Named("kademlia").Named("manage")
+
+	srv := newTestServer(t, testServerOptions{
+		Logger: logger,
+	})
+
+	jsonhttptest.Request(t, srv.Client, http.MethodGet, "/loggers", http.StatusOK,
+		jsonhttptest.WithExpectedJSONResponse(debugapi.LoggersResponse{
+			Loggers: []debugapi.LoggerResponse{
+				{Name: "kademlia", Level: "info"},
+				{Name: "kademlia/manage", Level: "info"},
+				{Name: "pushsync", Level: "info"},
+				{Name: "root", Level: "info"},
+			},
+		}),
+	)
+
+	jsonhttptest.Request(t, srv.Client, http.MethodPut, "/loggers/kademlia/manage/debug", http.StatusOK,
+		jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+			Message: http.StatusText(http.StatusOK),
+			Code:    http.StatusOK,
+		}),
+	)
+	if level := logger.NamedLevels()["kademlia/manage"]; level != logrus.DebugLevel {
+		t.Fatalf("got level %s, want %s", level, logrus.DebugLevel)
+	}
+	if level := logger.NamedLevels()["pushsync"]; level != logrus.InfoLevel {
+		t.Fatalf("got level %s, want %s", level, logrus.InfoLevel)
+	}
+
+	t.Run("unknown logger", func(t *testing.T) {
+		jsonhttptest.Request(t, srv.Client, http.MethodPut, "/loggers/pullsync/debug", http.StatusNotFound,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "logger not found",
+				Code:    http.StatusNotFound,
+			}),
+		)
+	})
+
+	t.Run("invalid level", func(t *testing.T) {
+		jsonhttptest.Request(t, srv.Client, http.MethodPut, "/loggers/pushsync/loud", http.StatusBadRequest,
+			jsonhttptest.WithExpectedJSONResponse(jsonhttp.StatusResponse{
+				Message: "invalid level",
+				Code:    http.StatusBadRequest,
+			}),
+		)
+	})
+}
@@ -19,7 +19,7 @@ const (
 	UltraLightMode
 )
 
-type nodeResponse struct {
+type NodeResponse struct {
 	BeeMode           string `json:"beeMode"`
 	GatewayMode       bool   `json:"gatewayMode"`
 	ChequebookEnabled bool   `json:"chequebookEnabled"`
@@ -42,7 +42,7 @@ func (b BeeNodeMode) String() string {
 
 // nodeGetHandler gives back information about the Bee node configuration.
 func (s *Service) nodeGetHandler(w http.ResponseWriter, r *http.Request) {
-	jsonhttp.OK(w, nodeResponse{
+	jsonhttp.OK(w, NodeResponse{
 		BeeMode:           s.beeMode.String(),
 		GatewayMode:       s.gatewayMode,
 		ChequebookEnabled: s.chequebookEnabled,
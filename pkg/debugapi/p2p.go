@@ -15,7 +15,7 @@ import (
 	"github.com/multiformats/go-multiaddr"
 )
 
-type addressesResponse struct {
+type AddressesResponse struct {
 	Overlay      *swarm.Address        `json:"overlay"`
 	Underlay     []multiaddr.Multiaddr `json:"underlay"`
 	Ethereum     common.Address        `json:"ethereum"`
@@ -37,7 +37,7 @@ func (s *Service) addressesHandler(w http.ResponseWriter, r *http.Request) {
 		}
 		underlay = u
 	}
-	jsonhttp.OK(w, addressesResponse{
+	jsonhttp.OK(w, AddressesResponse{
 		Overlay:      s.overlay,
 		Underlay:     underlay,
 		Ethereum:     s.ethereumAddress,
@@ -15,7 +15,7 @@ import (
 	"github.com/multiformats/go-multiaddr"
 )
 
-type peerConnectResponse struct {
+type PeerConnectResponse struct {
 	Address string `json:"address"`
 }
 
@@ -43,7 +43,7 @@ func (s *Service) peerConnectHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	jsonhttp.OK(w, peerConnectResponse{
+	jsonhttp.OK(w, PeerConnectResponse{
 		Address: bzzAddr.Overlay.String(),
 	})
 }
@@ -77,12 +77,12 @@ type Peer struct {
 	FullNode bool          `json:"fullNode"`
 }
 
-type peersResponse struct {
+type PeersResponse struct {
 	Peers []Peer `json:"peers"`
 }
 
 func (s *Service) peersHandler(w http.ResponseWriter, r *http.Request) {
-	jsonhttp.OK(w, peersResponse{
+	jsonhttp.OK(w, PeersResponse{
 		Peers: mapPeers(s.p2p.Peers()),
 	})
 }
@@ -95,7 +95,7 @@ func (s *Service) blocklistedPeersHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	jsonhttp.OK(w, peersResponse{
+	jsonhttp.OK(w, PeersResponse{
 		Peers: mapPeers(peers),
 	})
 }
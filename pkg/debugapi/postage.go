@@ -37,16 +37,29 @@ func (s *Service) postageAccessHandler(h http.Handler) http.Handler {
 	})
 }
 
-// hexByte takes care that a byte slice gets correctly
-// marshaled by the json serializer.
-type hexByte []byte
+// HexByte is a byte slice which is marshaled by the json serializer as a
+// hex encoded string, like the batch IDs of the postage responses.
+type HexByte []byte
 
-func (b hexByte) MarshalJSON() ([]byte, error) {
+func (b HexByte) MarshalJSON() ([]byte, error) {
 	return json.Marshal(hex.EncodeToString(b))
 }
 
-type postageCreateResponse struct {
-	BatchID hexByte `json:"batchID"`
+func (b *HexByte) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	v, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	*b = v
+	return nil
+}
+
+type PostageCreateResponse struct {
+	BatchID HexByte `json:"batchID"`
 }
 
 func (s *Service) postageCreateHandler(w http.ResponseWriter, r *http.Request) {
@@ -71,7 +84,7 @@ func (s *Service) postageCreateHandler(w http.ResponseWriter, r *http.Request) {
 	label := r.URL.Query().Get("label")
 
 	ctx := r.Context()
-	if price, ok := r.Header[gasPriceHeader]; ok {
+	if price, ok := r.Header[GasPriceHeader]; ok {
 		p, ok := big.NewInt(0).SetString(price[0], 10)
 		if !ok {
 			s.logger.Error("create batch: bad gas price")
@@ -82,7 +95,7 @@ func (s *Service) postageCreateHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var immutable bool
-	if val, ok := r.Header[immutableHeader]; ok {
+	if val, ok := r.Header[ImmutableHeader]; ok {
 		immutable, _ = strconv.ParseBool(val[0])
 	}
 
@@ -112,13 +125,13 @@ func (s *Service) postageCreateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	jsonhttp.Created(w, &postageCreateResponse{
+	jsonhttp.Created(w, &PostageCreateResponse{
 		BatchID: batchID,
 	})
 }
 
-type postageStampResponse struct {
-	BatchID       hexByte        `json:"batchID"`
+type PostageStampResponse struct {
+	BatchID       HexByte        `json:"batchID"`
 	Utilization   uint32         `json:"utilization"`
 	Usable        bool           `json:"usable"`
 	Label         string         `json:"label"`
@@ -131,15 +144,15 @@ type postageStampResponse struct {
 	BatchTTL      int64          `json:"batchTTL"`
 }
 
-type postageStampsResponse struct {
-	Stamps []postageStampResponse `json:"stamps"`
+type PostageStampsResponse struct {
+	Stamps []PostageStampResponse `json:"stamps"`
 }
 
-type postageBatchResponse struct {
-	BatchID       hexByte        `json:"batchID"`
+type PostageBatchResponse struct {
+	BatchID       HexByte        `json:"batchID"`
 	Value         *bigint.BigInt `json:"value"`
 	Start         uint64         `json:"start"`
-	Owner         hexByte        `json:"owner"`
+	Owner         HexByte        `json:"owner"`
 	Depth         uint8          `json:"depth"`
 	BucketDepth   uint8          `json:"bucketDepth"`
 	Immutable     bool           `json:"immutable"`
@@ -147,21 +160,21 @@ type postageBatchResponse struct {
 	BatchTTL      int64          `json:"batchTTL"`
 }
 
-type postageStampBucketsResponse struct {
+type PostageStampBucketsResponse struct {
 	Depth            uint8        `json:"depth"`
 	BucketDepth      uint8        `json:"bucketDepth"`
 	BucketUpperBound uint32       `json:"bucketUpperBound"`
-	Buckets          []bucketData `json:"buckets"`
+	Buckets          []BucketData `json:"buckets"`
 }
 
-type bucketData struct {
+type BucketData struct {
 	BucketID   uint32 `json:"bucketID"`
 	Collisions uint32 `json:"collisions"`
 }
 
 func (s *Service) postageGetStampsHandler(w http.ResponseWriter, r *http.Request) {
-	resp := postageStampsResponse{}
-	resp.Stamps = make([]postageStampResponse, 0, len(s.post.StampIssuers()))
+	resp := PostageStampsResponse{}
+	resp.Stamps = make([]PostageStampResponse, 0, len(s.post.StampIssuers()))
 	for _, v := range s.post.StampIssuers() {
 		exists, err := s.batchStore.Exists(v.ID())
 		if err != nil {
@@ -177,7 +190,7 @@ func (s *Service) postageGetStampsHandler(w http.ResponseWriter, r *http.Request
 			jsonhttp.InternalServerError(w, "unable to estimate batch expiration")
 			return
 		}
-		resp.Stamps = append(resp.Stamps, postageStampResponse{
+		resp.Stamps = append(resp.Stamps, PostageStampResponse{
 			BatchID:       v.ID(),
 			Utilization:   v.Utilization(),
 			Usable:        exists && s.post.IssuerUsable(v),
@@ -196,14 +209,14 @@ func (s *Service) postageGetStampsHandler(w http.ResponseWriter, r *http.Request
 }
 
 func (s *Service) postageGetAllStampsHandler(w http.ResponseWriter, _ *http.Request) {
-	batches := make([]postageBatchResponse, 0)
+	batches := make([]PostageBatchResponse, 0)
 	err := s.batchStore.Iterate(func(b *postage.Batch) (bool, error) {
 		batchTTL, err := s.estimateBatchTTL(b)
 		if err != nil {
 			return false, fmt.Errorf("estimate batch ttl: %w", err)
 		}
 
-		batches = append(batches, postageBatchResponse{
+		batches = append(batches, PostageBatchResponse{
 			BatchID:       b.ID,
 			Value:         bigint.Wrap(b.Value),
 			Start:         b.Start,
@@ -224,7 +237,7 @@ func (s *Service) postageGetAllStampsHandler(w http.ResponseWriter, _ *http.Requ
 	}
 
 	batchesRes := struct {
-		Batches []postageBatchResponse `json:"batches"`
+		Batches []PostageBatchResponse `json:"batches"`
 	}{
 		Batches: batches,
 	}
@@ -256,15 +269,15 @@ func (s *Service) postageGetStampBucketsHandler(w http.ResponseWriter, r *http.R
 	}
 
 	b := issuer.Buckets()
-	resp := postageStampBucketsResponse{
+	resp := PostageStampBucketsResponse{
 		Depth:            issuer.Depth(),
 		BucketDepth:      issuer.BucketDepth(),
 		BucketUpperBound: issuer.BucketUpperBound(),
-		Buckets:          make([]bucketData, len(b)),
+		Buckets:          make([]BucketData, len(b)),
 	}
 
 	for i, v := range b {
-		resp.Buckets[i] = bucketData{BucketID: uint32(i), Collisions: v}
+		resp.Buckets[i] = BucketData{BucketID: uint32(i), Collisions: v}
 	}
 
 	jsonhttp.OK(w, resp)
@@ -308,7 +321,7 @@ func (s *Service) postageGetStampHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	resp := postageStampResponse{
+	resp := PostageStampResponse{
 		BatchID:  id,
 		Exists:   exists,
 		BatchTTL: batchTTL,
@@ -328,13 +341,13 @@ func (s *Service) postageGetStampHandler(w http.ResponseWriter, r *http.Request)
 	jsonhttp.OK(w, &resp)
 }
 
-type reserveStateResponse struct {
+type ReserveStateResponse struct {
 	Radius        uint8 `json:"radius"`
 	StorageRadius uint8 `json:"storageRadius"`
 	Commitment    int64 `json:"commitment"`
 }
 
-type chainStateResponse struct {
+type ChainStateResponse struct {
 	ChainTip     uint64         `json:"chainTip"`     // The current highest block number from the chain backend.
 	Block        uint64         `json:"block"`        // The block number of the last postage event.
 	TotalAmount  *bigint.BigInt `json:"totalAmount"`  // Cumulative amount paid per stamp.
@@ -356,7 +369,7 @@ func (s *Service) reserveStateHandler(w http.ResponseWriter, _ *http.Request) {
 		return
 	}
 
-	jsonhttp.OK(w, reserveStateResponse{
+	jsonhttp.OK(w, ReserveStateResponse{
 		Radius:        state.Radius,
 		StorageRadius: state.StorageRadius,
 		Commitment:    commitment,
@@ -375,7 +388,7 @@ func (s *Service) chainStateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	jsonhttp.OK(w, chainStateResponse{
+	jsonhttp.OK(w, ChainStateResponse{
 		ChainTip:     chainBlock,
 		Block:        state.Block,
 		TotalAmount:  bigint.Wrap(state.TotalAmount),
@@ -440,7 +453,7 @@ func (s *Service) postageTopUpHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := r.Context()
-	if price, ok := r.Header[gasPriceHeader]; ok {
+	if price, ok := r.Header[GasPriceHeader]; ok {
 		p, ok := big.NewInt(0).SetString(price[0], 10)
 		if !ok {
 			s.logger.Error("topup batch: bad gas price")
@@ -464,7 +477,7 @@ func (s *Service) postageTopUpHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	jsonhttp.Accepted(w, &postageCreateResponse{
+	jsonhttp.Accepted(w, &PostageCreateResponse{
 		BatchID: id,
 	})
 }
@@ -494,7 +507,7 @@ func (s *Service) postageDiluteHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := r.Context()
-	if price, ok := r.Header[gasPriceHeader]; ok {
+	if price, ok := r.Header[GasPriceHeader]; ok {
 		p, ok := big.NewInt(0).SetString(price[0], 10)
 		if !ok {
 			s.logger.Error("dilute batch: bad gas price")
@@ -518,7 +531,7 @@ func (s *Service) postageDiluteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	jsonhttp.Accepted(w, &postageCreateResponse{
+	jsonhttp.Accepted(w, &PostageCreateResponse{
 		BatchID: id,
 	})
 }
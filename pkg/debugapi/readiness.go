@@ -0,0 +1,112 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package debugapi
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee"
+	"github.com/ethersphere/bee/pkg/api"
+	"github.com/ethersphere/bee/pkg/jsonhttp"
+)
+
+// readinessCheckTimeout limits the time of every readiness check.
+const readinessCheckTimeout = 5 * time.Second
+
+const (
+	readinessStatusOK   = "ok"
+	readinessStatusFail = "fail"
+)
+
+// ReadinessCheck returns an error if the component that it checks is not
+// ready for the node to serve requests.
+type ReadinessCheck func(ctx context.Context) error
+
+type readinessCheck struct {
+	name  string
+	check ReadinessCheck
+}
+
+// AddReadinessCheck registers a check that is evaluated on every request to
+// the /readiness endpoint. The node is ready only if all checks pass.
+func (s *Service) AddReadinessCheck(name string, check ReadinessCheck) {
+	s.readinessChecksMu.Lock()
+	defer s.readinessChecksMu.Unlock()
+
+	s.readinessChecks = append(s.readinessChecks, readinessCheck{name: name, check: check})
+}
+
+type ReadinessCheckResponse struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+type ReadinessResponse struct {
+	Status          string                   `json:"status"`
+	Version         string                   `json:"version"`
+	APIVersion      string                   `json:"apiVersion"`
+	DebugAPIVersion string                   `json:"debugApiVersion"`
+	Checks          []ReadinessCheckResponse `json:"checks"`
+}
+
+// readinessHandler evaluates all registered readiness checks concurrently
+// and responds with the status of each of them. The response status code is
+// 503 if any of the checks fails.
+func (s *Service) readinessHandler(w http.ResponseWriter, r *http.Request) {
+	s.readinessChecksMu.Lock()
+	checks := make([]readinessCheck, len(s.readinessChecks))
+	copy(checks, s.readinessChecks)
+	s.readinessChecksMu.Unlock()
+
+	resp := ReadinessResponse{
+		Status:          readinessStatusOK,
+		Version:         bee.Version,
+		APIVersion:      api.Version,
+		DebugAPIVersion: Version,
+		Checks:          make([]ReadinessCheckResponse, len(checks)),
+	}
+
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c readinessCheck) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(r.Context(), readinessCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := c.check(ctx)
+			resp.Checks[i] = ReadinessCheckResponse{
+				Name:     c.name,
+				Status:   readinessStatusOK,
+				Duration: time.Since(start).String(),
+			}
+			if err != nil {
+				resp.Checks[i].Status = readinessStatusFail
+				resp.Checks[i].Error = err.Error()
+			}
+		}(i, c)
+	}
+	wg.Wait()
+
+	for _, c := range resp.Checks {
+		if c.Status != readinessStatusOK {
+			s.logger.Debugf("debugapi: readiness: check %s: %s", c.Name, c.Error)
+			resp.Status = readinessStatusFail
+		}
+	}
+
+	if resp.Status != readinessStatusOK {
+		jsonhttp.ServiceUnavailable(w, resp)
+		return
+	}
+	jsonhttp.OK(w, resp)
+}
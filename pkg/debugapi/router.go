@@ -71,6 +71,20 @@ func (s *Service) newBasicRouter() *mux.Router {
 		"GET": http.HandlerFunc(s.addressesHandler),
 	})
 
+	handle("/loggers", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.loggersHandler),
+	})
+	handle("/loggers/{name:.+}/{level}", jsonhttp.MethodHandler{
+		"PUT": http.HandlerFunc(s.setLoggerLevelHandler),
+	})
+
+	handle("/config", jsonhttp.MethodHandler{
+		"GET": http.HandlerFunc(s.configHandler),
+	})
+	handle("/config/reload", jsonhttp.MethodHandler{
+		"POST": http.HandlerFunc(s.configReloadHandler),
+	})
+
 	if s.transaction != nil {
 		handle("/transactions", jsonhttp.MethodHandler{
 			"GET": http.HandlerFunc(s.transactionListHandler),
@@ -93,7 +107,7 @@ func (s *Service) newRouter() *mux.Router {
 
 	router.Handle("/readiness", web.ChainHandlers(
 		httpaccess.SetAccessLogLevelHandler(0), // suppress access log messages
-		web.FinalHandlerFunc(statusHandler),
+		web.FinalHandlerFunc(s.readinessHandler),
 	))
 
 	var handle = func(path string, handler http.Handler) {
@@ -107,6 +121,12 @@ func (s *Service) newRouter() *mux.Router {
 		"GET": http.HandlerFunc(s.peersHandler),
 	})
 
+	if s.events != nil {
+		handle("/events", jsonhttp.MethodHandler{
+			"GET": http.HandlerFunc(s.eventsHandler),
+		})
+	}
+
 	handle("/pingpong/{peer-id}", jsonhttp.MethodHandler{
 		"POST": http.HandlerFunc(s.pingpongHandler),
 	})
@@ -22,16 +22,16 @@ var (
 	errCantSettlementsPeer = "can not get settlements for peer"
 )
 
-type settlementResponse struct {
+type SettlementResponse struct {
 	Peer               string         `json:"peer"`
 	SettlementReceived *bigint.BigInt `json:"received"`
 	SettlementSent     *bigint.BigInt `json:"sent"`
 }
 
-type settlementsResponse struct {
+type SettlementsResponse struct {
 	TotalSettlementReceived *bigint.BigInt       `json:"totalReceived"`
 	TotalSettlementSent     *bigint.BigInt       `json:"totalSent"`
-	Settlements             []settlementResponse `json:"settlements"`
+	Settlements             []SettlementResponse `json:"settlements"`
 }
 
 func (s *Service) settlementsHandler(w http.ResponseWriter, r *http.Request) {
@@ -60,10 +60,10 @@ func (s *Service) settlementsHandler(w http.ResponseWriter, r *http.Request) {
 	totalReceived := big.NewInt(0)
 	totalSent := big.NewInt(0)
 
-	settlementResponses := make(map[string]settlementResponse)
+	settlementResponses := make(map[string]SettlementResponse)
 
 	for a, b := range settlementsSent {
-		settlementResponses[a] = settlementResponse{
+		settlementResponses[a] = SettlementResponse{
 			Peer:               a,
 			SettlementSent:     bigint.Wrap(b),
 			SettlementReceived: bigint.Wrap(big.NewInt(0)),
@@ -77,7 +77,7 @@ func (s *Service) settlementsHandler(w http.ResponseWriter, r *http.Request) {
 			t.SettlementReceived = bigint.Wrap(b)
 			settlementResponses[a] = t
 		} else {
-			settlementResponses[a] = settlementResponse{
+			settlementResponses[a] = SettlementResponse{
 				Peer:               a,
 				SettlementSent:     bigint.Wrap(big.NewInt(0)),
 				SettlementReceived: bigint.Wrap(b),
@@ -86,14 +86,14 @@ func (s *Service) settlementsHandler(w http.ResponseWriter, r *http.Request) {
 		totalReceived.Add(b, totalReceived)
 	}
 
-	settlementResponsesArray := make([]settlementResponse, len(settlementResponses))
+	settlementResponsesArray := make([]SettlementResponse, len(settlementResponses))
 	i := 0
 	for k := range settlementResponses {
 		settlementResponsesArray[i] = settlementResponses[k]
 		i++
 	}
 
-	jsonhttp.OK(w, settlementsResponse{TotalSettlementReceived: bigint.Wrap(totalReceived), TotalSettlementSent: bigint.Wrap(totalSent), Settlements: settlementResponsesArray})
+	jsonhttp.OK(w, SettlementsResponse{TotalSettlementReceived: bigint.Wrap(totalReceived), TotalSettlementSent: bigint.Wrap(totalSent), Settlements: settlementResponsesArray})
 }
 
 func (s *Service) peerSettlementsHandler(w http.ResponseWriter, r *http.Request) {
@@ -151,7 +151,7 @@ func (s *Service) peerSettlementsHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	jsonhttp.OK(w, settlementResponse{
+	jsonhttp.OK(w, SettlementResponse{
 		Peer:               peer.String(),
 		SettlementReceived: bigint.Wrap(received),
 		SettlementSent:     bigint.Wrap(sent),
@@ -178,10 +178,10 @@ func (s *Service) settlementsHandlerPseudosettle(w http.ResponseWriter, r *http.
 	totalReceived := big.NewInt(0)
 	totalSent := big.NewInt(0)
 
-	settlementResponses := make(map[string]settlementResponse)
+	settlementResponses := make(map[string]SettlementResponse)
 
 	for a, b := range settlementsSent {
-		settlementResponses[a] = settlementResponse{
+		settlementResponses[a] = SettlementResponse{
 			Peer:               a,
 			SettlementSent:     bigint.Wrap(b),
 			SettlementReceived: bigint.Wrap(big.NewInt(0)),
@@ -195,7 +195,7 @@ func (s *Service) settlementsHandlerPseudosettle(w http.ResponseWriter, r *http.
 			t.SettlementReceived = bigint.Wrap(b)
 			settlementResponses[a] = t
 		} else {
-			settlementResponses[a] = settlementResponse{
+			settlementResponses[a] = SettlementResponse{
 				Peer:               a,
 				SettlementSent:     bigint.Wrap(big.NewInt(0)),
 				SettlementReceived: bigint.Wrap(b),
@@ -204,12 +204,12 @@ func (s *Service) settlementsHandlerPseudosettle(w http.ResponseWriter, r *http.
 		totalReceived.Add(b, totalReceived)
 	}
 
-	settlementResponsesArray := make([]settlementResponse, len(settlementResponses))
+	settlementResponsesArray := make([]SettlementResponse, len(settlementResponses))
 	i := 0
 	for k := range settlementResponses {
 		settlementResponsesArray[i] = settlementResponses[k]
 		i++
 	}
 
-	jsonhttp.OK(w, settlementsResponse{TotalSettlementReceived: bigint.Wrap(totalReceived), TotalSettlementSent: bigint.Wrap(totalSent), Settlements: settlementResponsesArray})
+	jsonhttp.OK(w, SettlementsResponse{TotalSettlementReceived: bigint.Wrap(totalReceived), TotalSettlementSent: bigint.Wrap(totalSent), Settlements: settlementResponsesArray})
 }
@@ -12,7 +12,7 @@ import (
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 )
 
-type statusResponse struct {
+type StatusResponse struct {
 	Status          string `json:"status"`
 	Version         string `json:"version"`
 	APIVersion      string `json:"apiVersion"`
@@ -20,7 +20,7 @@ type statusResponse struct {
 }
 
 func statusHandler(w http.ResponseWriter, r *http.Request) {
-	jsonhttp.OK(w, statusResponse{
+	jsonhttp.OK(w, StatusResponse{
 		Status:          "ok",
 		Version:         bee.Version,
 		APIVersion:      api.Version,
@@ -5,8 +5,12 @@
 package debugapi_test
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/ethersphere/bee"
 	"github.com/ethersphere/bee/pkg/api"
@@ -28,14 +32,76 @@ func TestHealth(t *testing.T) {
 }
 
 func TestReadiness(t *testing.T) {
-	testServer := newTestServer(t, testServerOptions{})
+	t.Run("no checks", func(t *testing.T) {
+		testServer := newTestServer(t, testServerOptions{})
 
-	jsonhttptest.Request(t, testServer.Client, http.MethodGet, "/readiness", http.StatusOK,
-		jsonhttptest.WithExpectedJSONResponse(debugapi.StatusResponse{
-			Status:          "ok",
-			Version:         bee.Version,
-			APIVersion:      api.Version,
-			DebugAPIVersion: debugapi.Version,
-		}),
-	)
+		jsonhttptest.Request(t, testServer.Client, http.MethodGet, "/readiness", http.StatusOK,
+			jsonhttptest.WithExpectedJSONResponse(debugapi.ReadinessResponse{
+				Status:          "ok",
+				Version:         bee.Version,
+				APIVersion:      api.Version,
+				DebugAPIVersion: debugapi.Version,
+				Checks:          []debugapi.ReadinessCheckResponse{},
+			}),
+		)
+	})
+
+	t.Run("checks", func(t *testing.T) {
+		var chainSynced atomic.Value
+		chainSynced.Store(false)
+
+		testServer := newTestServer(t, testServerOptions{
+			ReadinessChecks: map[string]debugapi.ReadinessCheck{
+				"topology": func(context.Context) error {
+					return nil
+				},
+				"chain": func(context.Context) error {
+					if !chainSynced.Load().(bool) {
+						return errors.New("chain backend not synced")
+					}
+					return nil
+				},
+			},
+		})
+
+		var resp debugapi.ReadinessResponse
+		jsonhttptest.Request(t, testServer.Client, http.MethodGet, "/readiness", http.StatusServiceUnavailable,
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+		checkReadiness(t, resp, "fail", []debugapi.ReadinessCheckResponse{
+			{Name: "chain", Status: "fail", Error: "chain backend not synced"},
+			{Name: "topology", Status: "ok"},
+		})
+
+		chainSynced.Store(true)
+
+		resp = debugapi.ReadinessResponse{}
+		jsonhttptest.Request(t, testServer.Client, http.MethodGet, "/readiness", http.StatusOK,
+			jsonhttptest.WithUnmarshalJSONResponse(&resp),
+		)
+		checkReadiness(t, resp, "ok", []debugapi.ReadinessCheckResponse{
+			{Name: "chain", Status: "ok"},
+			{Name: "topology", Status: "ok"},
+		})
+	})
+}
+
+func checkReadiness(t *testing.T, resp debugapi.ReadinessResponse, status string, checks []debugapi.ReadinessCheckResponse) {
+	t.Helper()
+
+	if resp.Status != status {
+		t.Fatalf("got status %q, want %q", resp.Status, status)
+	}
+	if len(resp.Checks) != len(checks) {
+		t.Fatalf("got %d checks, want %d", len(resp.Checks), len(checks))
+	}
+	for i, c := range resp.Checks {
+		if _, err := time.ParseDuration(c.Duration); err != nil {
+			t.Fatalf("check %s: invalid duration %q: %v", c.Name, c.Duration, err)
+		}
+		c.Duration = ""
+		if c != checks[i] {
+			t.Fatalf("got check %+v, want %+v", c, checks[i])
+		}
+	}
 }
@@ -16,7 +16,7 @@ import (
 	"github.com/gorilla/mux"
 )
 
-type tagResponse struct {
+type TagResponse struct {
 	Total     int64         `json:"total"`
 	Split     int64         `json:"split"`
 	Seen      int64         `json:"seen"`
@@ -28,8 +28,8 @@ type tagResponse struct {
 	StartedAt time.Time     `json:"startedAt"`
 }
 
-func newTagResponse(tag *tags.Tag) tagResponse {
-	return tagResponse{
+func newTagResponse(tag *tags.Tag) TagResponse {
+	return TagResponse{
 		Total:     tag.Total,
 		Split:     tag.Split,
 		Seen:      tag.Seen,
@@ -26,7 +26,7 @@ const (
 	errCantResendTransaction = "can't resend transaction"
 )
 
-type transactionInfo struct {
+type TransactionInfo struct {
 	TransactionHash common.Hash     `json:"transactionHash"`
 	To              *common.Address `json:"to"`
 	Nonce           uint64          `json:"nonce"`
@@ -38,8 +38,8 @@ type transactionInfo struct {
 	Value           *bigint.BigInt  `json:"value"`
 }
 
-type transactionPendingList struct {
-	PendingTransactions []transactionInfo `json:"pendingTransactions"`
+type TransactionPendingList struct {
+	PendingTransactions []TransactionInfo `json:"pendingTransactions"`
 }
 
 func (s *Service) transactionListHandler(w http.ResponseWriter, r *http.Request) {
@@ -51,7 +51,7 @@ func (s *Service) transactionListHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	var transactionInfos []transactionInfo = make([]transactionInfo, 0)
+	var transactionInfos []TransactionInfo = make([]TransactionInfo, 0)
 	for _, txHash := range txHashes {
 		storedTransaction, err := s.transaction.StoredTransaction(txHash)
 		if err != nil {
@@ -61,7 +61,7 @@ func (s *Service) transactionListHandler(w http.ResponseWriter, r *http.Request)
 			return
 		}
 
-		transactionInfos = append(transactionInfos, transactionInfo{
+		transactionInfos = append(transactionInfos, TransactionInfo{
 			TransactionHash: txHash,
 			To:              storedTransaction.To,
 			Nonce:           storedTransaction.Nonce,
@@ -75,7 +75,7 @@ func (s *Service) transactionListHandler(w http.ResponseWriter, r *http.Request)
 
 	}
 
-	jsonhttp.OK(w, transactionPendingList{
+	jsonhttp.OK(w, TransactionPendingList{
 		PendingTransactions: transactionInfos,
 	})
 }
@@ -96,7 +96,7 @@ func (s *Service) transactionDetailHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	jsonhttp.OK(w, transactionInfo{
+	jsonhttp.OK(w, TransactionInfo{
 		TransactionHash: txHash,
 		To:              storedTransaction.To,
 		Nonce:           storedTransaction.Nonce,
@@ -109,7 +109,7 @@ func (s *Service) transactionDetailHandler(w http.ResponseWriter, r *http.Reques
 	})
 }
 
-type transactionHashResponse struct {
+type TransactionHashResponse struct {
 	TransactionHash common.Hash `json:"transactionHash"`
 }
 
@@ -131,7 +131,7 @@ func (s *Service) transactionResendHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	jsonhttp.OK(w, transactionHashResponse{
+	jsonhttp.OK(w, TransactionHashResponse{
 		TransactionHash: txHash,
 	})
 }
@@ -141,7 +141,7 @@ func (s *Service) transactionCancelHandler(w http.ResponseWriter, r *http.Reques
 	txHash := common.HexToHash(hash)
 
 	ctx := r.Context()
-	if price, ok := r.Header[gasPriceHeader]; ok {
+	if price, ok := r.Header[GasPriceHeader]; ok {
 		p, ok := big.NewInt(0).SetString(price[0], 10)
 		if !ok {
 			s.logger.Error("debug api: transactions: cancel: bad gas price")
@@ -165,7 +165,7 @@ func (s *Service) transactionCancelHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	jsonhttp.OK(w, transactionHashResponse{
+	jsonhttp.OK(w, TransactionHashResponse{
 		TransactionHash: txHash,
 	})
 }
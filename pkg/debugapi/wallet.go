@@ -12,7 +12,7 @@ import (
 	"github.com/ethersphere/bee/pkg/jsonhttp"
 )
 
-type walletResponse struct {
+type WalletResponse struct {
 	BZZ             *bigint.BigInt `json:"bzz"`             // the BZZ balance of the wallet associated with the eth address of the node
 	XDai            *bigint.BigInt `json:"xDai"`            // the xDai balance of the wallet associated with the eth address of the node
 	ChainID         int64          `json:"chainID"`         // the id of the block chain
@@ -37,7 +37,7 @@ func (s *Service) walletHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	jsonhttp.OK(w, walletResponse{
+	jsonhttp.OK(w, WalletResponse{
 		BZZ:             bigint.Wrap(bzz),
 		XDai:            bigint.Wrap(xdai),
 		ChainID:         s.chainID,
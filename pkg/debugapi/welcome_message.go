@@ -13,23 +13,23 @@ import (
 
 const welcomeMessageMaxRequestSize = 512
 
-type welcomeMessageRequest struct {
+type WelcomeMessageRequest struct {
 	WelcomeMesssage string `json:"welcomeMessage"`
 }
 
-type welcomeMessageResponse struct {
+type WelcomeMessageResponse struct {
 	WelcomeMesssage string `json:"welcomeMessage"`
 }
 
 func (s *Service) getWelcomeMessageHandler(w http.ResponseWriter, r *http.Request) {
 	val := s.p2p.GetWelcomeMessage()
-	jsonhttp.OK(w, welcomeMessageResponse{
+	jsonhttp.OK(w, WelcomeMessageResponse{
 		WelcomeMesssage: val,
 	})
 }
 
 func (s *Service) setWelcomeMessageHandler(w http.ResponseWriter, r *http.Request) {
-	var data welcomeMessageRequest
+	var data WelcomeMessageRequest
 	err := json.NewDecoder(r.Body).Decode(&data)
 	if err != nil {
 		s.logger.Debugf("debugapi: welcome message: failed to read request: %v", err)
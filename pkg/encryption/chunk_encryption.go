@@ -19,7 +19,32 @@ type chunkEncrypter struct{}
 func NewChunkEncrypter() ChunkEncrypter { return &chunkEncrypter{} }
 
 func (c *chunkEncrypter) EncryptChunk(chunkData []byte) (Key, []byte, []byte, error) {
-	key := GenerateRandomKey(KeyLength)
+	return encryptChunk(GenerateRandomKey(KeyLength), chunkData)
+}
+
+type convergentChunkEncrypter struct {
+	rootKey Key
+}
+
+// NewConvergentChunkEncrypter returns a ChunkEncrypter which derives the key
+// of every chunk from the root key and the chunk data, so that the same
+// content is always encrypted into the same chunks under the same root key.
+func NewConvergentChunkEncrypter(rootKey Key) ChunkEncrypter {
+	return &convergentChunkEncrypter{rootKey: rootKey}
+}
+
+func (c *convergentChunkEncrypter) EncryptChunk(chunkData []byte) (Key, []byte, []byte, error) {
+	h := sha3.NewLegacyKeccak256()
+	_, _ = h.Write(c.rootKey)
+	_, _ = h.Write(chunkData)
+
+	// pad with zeros instead of random bytes to keep the result deterministic
+	padded := make([]byte, swarm.SpanSize+swarm.ChunkSize)
+	copy(padded, chunkData)
+	return encryptChunk(h.Sum(nil), padded)
+}
+
+func encryptChunk(key Key, chunkData []byte) (Key, []byte, []byte, error) {
 	encryptedSpan, err := newSpanEncryption(key).Encrypt(chunkData[:8])
 	if err != nil {
 		return nil, nil, nil, err
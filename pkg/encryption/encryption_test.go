@@ -202,3 +202,35 @@ func RandomBytes(seed, length int) []byte {
 	}
 	return b
 }
+
+func TestConvergentChunkEncrypter(t *testing.T) {
+	data := make([]byte, swarm.SpanSize+100)
+	data[0] = 100
+	copy(data[swarm.SpanSize:], "convergent encryption")
+
+	encrypt := func(key encryption.Key) (encryption.Key, []byte) {
+		t.Helper()
+		k, span, enc, err := encryption.NewConvergentChunkEncrypter(key).EncryptChunk(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return k, append(span, enc...)
+	}
+
+	key1, enc1 := encrypt(testKey)
+	key2, enc2 := encrypt(testKey)
+	if !bytes.Equal(key1, key2) || !bytes.Equal(enc1, enc2) {
+		t.Fatal("expected the same encryption for the same root key")
+	}
+
+	otherKey := make(encryption.Key, encryption.KeyLength)
+	key3, enc3 := encrypt(otherKey)
+	if bytes.Equal(key1, key3) || bytes.Equal(enc1, enc3) {
+		t.Fatal("expected different encryption for different root keys")
+	}
+
+	// the chunk key must not be the root key
+	if bytes.Equal(key1, testKey) {
+		t.Fatal("chunk key equals root key")
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package events provides a bus on which the subsystems of the node publish
+// their state changes to subscribers such as the event stream of the Debug
+// API.
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/bigint"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// Type is the type of an event.
+type Type string
+
+// Types of the events published by the node.
+const (
+	TypeTopology           Type = "topology"
+	TypeDepth              Type = "depth"
+	TypeRadius             Type = "radius"
+	TypeBatchCreated       Type = "batch-created"
+	TypeBatchTopUp         Type = "batch-topup"
+	TypeBatchDepthIncrease Type = "batch-depth-increase"
+	TypeGarbageCollection  Type = "gc"
+	TypeChunkSynced        Type = "chunk-synced"
+	TypeChequeSent         Type = "cheque-sent"
+	TypeChequeReceived     Type = "cheque-received"
+)
+
+// Types holds all event types.
+var Types = []Type{
+	TypeTopology,
+	TypeDepth,
+	TypeRadius,
+	TypeBatchCreated,
+	TypeBatchTopUp,
+	TypeBatchDepthIncrease,
+	TypeGarbageCollection,
+	TypeChunkSynced,
+	TypeChequeSent,
+	TypeChequeReceived,
+}
+
+// TopologyData is the data of the topology events, which are published when
+// the connected peers change.
+type TopologyData struct {
+	Connected  int   `json:"connected"`
+	Population int   `json:"population"`
+	Depth      uint8 `json:"depth"`
+}
+
+// DepthData is the data of the depth events.
+type DepthData struct {
+	Depth uint8 `json:"depth"`
+}
+
+// RadiusData is the data of the radius events.
+type RadiusData struct {
+	Radius uint8 `json:"radius"`
+}
+
+// BatchData is the data of the postage batch events.
+type BatchData struct {
+	BatchID     string         `json:"batchID"`
+	Value       *bigint.BigInt `json:"value"`
+	Depth       uint8          `json:"depth"`
+	BucketDepth uint8          `json:"bucketDepth,omitempty"`
+	Immutable   bool           `json:"immutable,omitempty"`
+}
+
+// GarbageCollectionData is the data of the garbage collection events.
+type GarbageCollectionData struct {
+	Collected uint64 `json:"collected"`
+}
+
+// ChunkData is the data of the chunk events.
+type ChunkData struct {
+	Address swarm.Address `json:"address"`
+}
+
+// ChequeData is the data of the cheque events.
+type ChequeData struct {
+	Peer   swarm.Address  `json:"peer"`
+	Amount *bigint.BigInt `json:"amount"`
+}
+
+// subscriptionBufferSize is the number of events which are kept for a slow
+// subscriber before its events are dropped.
+const subscriptionBufferSize = 128
+
+// Event is a state change of a subsystem of the node.
+type Event struct {
+	Type Type        `json:"type"`
+	Time time.Time   `json:"time"`
+	Data interface{} `json:"data,omitempty"`
+}
+
+// Bus delivers the published events to the subscribers. A nil Bus discards
+// all events, so subsystems do not need to check whether they publish to
+// one.
+type Bus struct {
+	mu     sync.Mutex
+	subs   map[*subscription]struct{}
+	closed bool
+}
+
+type subscription struct {
+	c     chan Event
+	types map[Type]struct{} // all types if empty
+}
+
+// New creates a new Bus.
+func New() *Bus {
+	return &Bus{subs: make(map[*subscription]struct{})}
+}
+
+// Publish delivers the event of the type with the data to the subscribers
+// of the type. It does not block, so the events of a subscriber which does
+// not keep up are dropped.
+func (b *Bus) Publish(t Type, data interface{}) {
+	if b == nil {
+		return
+	}
+
+	e := Event{
+		Type: t,
+		Time: time.Now().UTC(),
+		Data: data,
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for s := range b.subs {
+		if len(s.types) > 0 {
+			if _, ok := s.types[t]; !ok {
+				continue
+			}
+		}
+		select {
+		case s.c <- e:
+		default:
+		}
+	}
+}
+
+// Subscribe returns the channel of the events of the types, or of all types
+// if none are given. The channel is closed when the Bus is closed or the
+// returned function is called, which is safe to be called multiple times.
+func (b *Bus) Subscribe(types ...Type) (c <-chan Event, unsubscribe func()) {
+	s := &subscription{
+		c:     make(chan Event, subscriptionBufferSize),
+		types: make(map[Type]struct{}, len(types)),
+	}
+	for _, t := range types {
+		s.types[t] = struct{}{}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		close(s.c)
+		return s.c, func() {}
+	}
+	b.subs[s] = struct{}{}
+
+	unsubscribe = func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if _, ok := b.subs[s]; ok {
+			delete(b.subs, s)
+			close(s.c)
+		}
+	}
+
+	return s.c, unsubscribe
+}
+
+// Close closes the channels of all subscribers.
+func (b *Bus) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for s := range b.subs {
+		delete(b.subs, s)
+		close(s.c)
+	}
+	b.closed = true
+	return nil
+}
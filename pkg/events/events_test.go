@@ -0,0 +1,92 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package events_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/events"
+)
+
+func TestBus(t *testing.T) {
+	bus := events.New()
+
+	all, unsubscribeAll := bus.Subscribe()
+	defer unsubscribeAll()
+	gc, unsubscribeGC := bus.Subscribe(events.TypeGarbageCollection)
+	defer unsubscribeGC()
+
+	bus.Publish(events.TypeDepth, events.DepthData{Depth: 3})
+	bus.Publish(events.TypeGarbageCollection, events.GarbageCollectionData{Collected: 10})
+
+	for _, want := range []events.Type{events.TypeDepth, events.TypeGarbageCollection} {
+		if e := receive(t, all); e.Type != want {
+			t.Fatalf("got event %s, want %s", e.Type, want)
+		}
+	}
+	e := receive(t, gc)
+	if e.Type != events.TypeGarbageCollection {
+		t.Fatalf("got event %s, want %s", e.Type, events.TypeGarbageCollection)
+	}
+	if d := e.Data.(events.GarbageCollectionData); d.Collected != 10 {
+		t.Fatalf("got %d collected, want %d", d.Collected, 10)
+	}
+	select {
+	case e := <-gc:
+		t.Fatalf("got unexpected event %s", e.Type)
+	default:
+	}
+
+	unsubscribeGC()
+	unsubscribeGC()
+	if _, ok := <-gc; ok {
+		t.Fatal("channel not closed on unsubscribe")
+	}
+
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := <-all; ok {
+		t.Fatal("channel not closed on close")
+	}
+	closed, _ := bus.Subscribe()
+	if _, ok := <-closed; ok {
+		t.Fatal("channel of closed bus not closed")
+	}
+}
+
+func TestBus_slowSubscriber(t *testing.T) {
+	bus := events.New()
+	defer bus.Close()
+
+	c, unsubscribe := bus.Subscribe()
+	defer unsubscribe()
+
+	// publishing must not block on a subscriber which does not receive
+	for i := 0; i < 1000; i++ {
+		bus.Publish(events.TypeChunkSynced, nil)
+	}
+	if len(c) != cap(c) {
+		t.Fatalf("got %d buffered events, want %d", len(c), cap(c))
+	}
+}
+
+func TestBus_nil(t *testing.T) {
+	var bus *events.Bus
+	bus.Publish(events.TypeRadius, events.RadiusData{Radius: 1})
+}
+
+func receive(t *testing.T, c <-chan events.Event) events.Event {
+	t.Helper()
+
+	select {
+	case e := <-c:
+		return e
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for event")
+	}
+	return events.Event{}
+}
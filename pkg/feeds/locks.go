@@ -0,0 +1,51 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package feeds
+
+import "sync"
+
+// UpdateLocks serializes the updates of the feeds of the node by their
+// topics, so that the updates of a feed do not use the same index.
+type UpdateLocks struct {
+	mu    sync.Mutex
+	locks map[string]*updateLock
+}
+
+type updateLock struct {
+	sync.Mutex
+	waiting int
+}
+
+// NewUpdateLocks creates a new UpdateLocks.
+func NewUpdateLocks() *UpdateLocks {
+	return &UpdateLocks{locks: make(map[string]*updateLock)}
+}
+
+// Lock locks the feed with the topic until the returned function is called.
+func (l *UpdateLocks) Lock(topic []byte) (unlock func()) {
+	key := string(topic)
+
+	l.mu.Lock()
+	u, ok := l.locks[key]
+	if !ok {
+		u = new(updateLock)
+		l.locks[key] = u
+	}
+	u.waiting++
+	l.mu.Unlock()
+
+	u.Lock()
+	return func() {
+		u.Unlock()
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		u.waiting--
+		if u.waiting == 0 {
+			delete(l.locks, key)
+		}
+	}
+}
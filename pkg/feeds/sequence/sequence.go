@@ -40,6 +40,11 @@ type index struct {
 	index uint64
 }
 
+// NewIndex returns the index i of a sequence feed.
+func NewIndex(i uint64) feeds.Index {
+	return &index{i}
+}
+
 func (i *index) String() string {
 	return fmt.Sprintf("%d", i.index)
 }
@@ -287,6 +292,16 @@ func NewUpdater(putter storage.Putter, signer crypto.Signer, topic []byte) (feed
 	return &updater{Putter: p}, nil
 }
 
+// NewUpdaterAt constructs a feed updater which continues a feed that has next
+// updates, so that its next update has the index next.
+func NewUpdaterAt(putter storage.Putter, signer crypto.Signer, topic []byte, next uint64) (feeds.Updater, error) {
+	p, err := feeds.NewPutter(putter, signer, topic)
+	if err != nil {
+		return nil, err
+	}
+	return &updater{Putter: p, next: next}, nil
+}
+
 // Update pushes an update to the feed through the chunk stores
 func (u *updater) Update(ctx context.Context, at int64, payload []byte) error {
 	err := u.Put(ctx, &index{u.next}, at, payload)
@@ -0,0 +1,174 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package joiner
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/ethersphere/bee/pkg/file"
+	"github.com/ethersphere/bee/pkg/file/pipeline/cdc"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+type cdcJoiner struct {
+	index    file.Joiner
+	segments []cdc.Segment
+	offsets  []int64 // start offset of every segment
+	span     int64
+	off      int64
+
+	// the joiner of the last read segment, ReadAt is called concurrently
+	mu         sync.Mutex
+	current    file.Joiner
+	currentIdx int
+
+	ctx    context.Context
+	getter storage.Getter
+}
+
+// NewCDC creates a new Joiner for data uploaded with the content defined
+// chunking pipeline. The address is the reference of the segment index.
+func NewCDC(ctx context.Context, getter storage.Getter, address swarm.Address) (file.Joiner, int64, error) {
+	index, _, err := New(ctx, getter, address)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if _, err := file.JoinReadAll(ctx, index, buf); err != nil {
+		return nil, 0, err
+	}
+	segments, err := cdc.UnmarshalIndex(buf.Bytes())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	j := &cdcJoiner{
+		index:      index,
+		segments:   segments,
+		offsets:    make([]int64, len(segments)),
+		currentIdx: -1,
+		ctx:        ctx,
+		getter:     getter,
+	}
+	for i, s := range segments {
+		j.offsets[i] = j.span
+		j.span += s.Span
+	}
+
+	return j, j.span, nil
+}
+
+// Read is called by the consumer to retrieve the joined data.
+func (j *cdcJoiner) Read(b []byte) (n int, err error) {
+	read, err := j.ReadAt(b, j.off)
+	if err != nil && err != io.EOF {
+		return read, err
+	}
+
+	j.off += int64(read)
+	return read, err
+}
+
+func (j *cdcJoiner) ReadAt(b []byte, off int64) (read int, err error) {
+	if off >= j.span {
+		return 0, io.EOF
+	}
+
+	// find the segment which contains the offset
+	idx := sort.Search(len(j.offsets), func(i int) bool { return j.offsets[i] > off }) - 1
+
+	for ; read < len(b) && idx < len(j.segments); idx++ {
+		sj, err := j.segmentJoiner(idx)
+		if err != nil {
+			return read, err
+		}
+		// limit the capacity, the joiner reads as much as fits into the buffer
+		n, err := sj.ReadAt(b[read:len(b):len(b)], off-j.offsets[idx])
+		if err != nil && err != io.EOF {
+			return read, err
+		}
+		read += n
+		off += int64(n)
+		if off < j.offsets[idx]+j.segments[idx].Span {
+			break
+		}
+	}
+
+	return read, nil
+}
+
+func (j *cdcJoiner) segmentJoiner(idx int) (file.Joiner, error) {
+	j.mu.Lock()
+	if idx == j.currentIdx {
+		defer j.mu.Unlock()
+		return j.current, nil
+	}
+	j.mu.Unlock()
+
+	sj, _, err := New(j.ctx, j.getter, j.segments[idx].Reference)
+	if err != nil {
+		return nil, err
+	}
+	if sj.Size() != j.segments[idx].Span {
+		return nil, ErrMalformedTrie
+	}
+
+	j.mu.Lock()
+	j.current, j.currentIdx = sj, idx
+	j.mu.Unlock()
+	return sj, nil
+}
+
+func (j *cdcJoiner) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case 0:
+	case 1:
+		offset += j.off
+	case 2:
+		offset = j.span - offset
+		if offset < 0 {
+			return 0, io.EOF
+		}
+	default:
+		return 0, errWhence
+	}
+
+	if offset < 0 {
+		return 0, errOffset
+	}
+	if offset > j.span {
+		return 0, io.EOF
+	}
+	j.off = offset
+	return offset, nil
+}
+
+// IterateChunkAddresses iterates over the chunks of the index and of all
+// segments.
+func (j *cdcJoiner) IterateChunkAddresses(fn swarm.AddressIterFunc) error {
+	if err := j.index.IterateChunkAddresses(fn); err != nil {
+		return err
+	}
+	for _, s := range j.segments {
+		sj, _, err := New(j.ctx, j.getter, s.Reference)
+		if err != nil {
+			return err
+		}
+		if err := sj.IterateChunkAddresses(fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (j *cdcJoiner) Size() int64 {
+	return j.span
+}
@@ -0,0 +1,178 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package joiner_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/file/joiner"
+	"github.com/ethersphere/bee/pkg/file/pipeline"
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"golang.org/x/sync/errgroup"
+)
+
+// countingPutter counts the chunks which were not stored before.
+type countingPutter struct {
+	storage.Storer
+	stored int
+}
+
+func (p *countingPutter) Put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk) ([]bool, error) {
+	exist, err := p.Storer.Put(ctx, mode, chs...)
+	for _, e := range exist {
+		if !e {
+			p.stored++
+		}
+	}
+	return exist, err
+}
+
+func cdcUpload(t *testing.T, s storage.Putter, data []byte) swarm.Address {
+	t.Helper()
+
+	ctx := context.Background()
+	pipe := builder.NewCDCPipelineBuilder(func() pipeline.Interface {
+		return builder.NewPipelineBuilder(ctx, s, storage.ModePutUpload, false)
+	})
+	addr, err := builder.FeedPipeline(ctx, pipe, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return addr
+}
+
+func TestCDCJoiner(t *testing.T) {
+	ctx := context.Background()
+	store := mock.NewStorer()
+
+	data := make([]byte, 1024*1024+100)
+	rand.New(rand.NewSource(1)).Read(data)
+	addr := cdcUpload(t, store, data)
+
+	j, size, err := joiner.NewCDC(ctx, store, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != int64(len(data)) {
+		t.Fatalf("got size %d, want %d", size, len(data))
+	}
+
+	t.Run("read all", func(t *testing.T) {
+		got, err := io.ReadAll(j)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Fatal("data mismatch")
+		}
+	})
+
+	t.Run("read at", func(t *testing.T) {
+		for _, off := range []int64{0, 1, 4095, 70000, 500000, int64(len(data)) - 10} {
+			b := make([]byte, 200000)
+			n, err := j.ReadAt(b, off)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := data[off:]
+			if len(want) > len(b) {
+				want = want[:len(b)]
+			}
+			if n != len(want) || !bytes.Equal(b[:n], want) {
+				t.Fatalf("offset %d: data mismatch", off)
+			}
+		}
+		if _, err := j.ReadAt(make([]byte, 10), size); err != io.EOF {
+			t.Fatalf("got error %v, want %v", err, io.EOF)
+		}
+	})
+
+	t.Run("concurrent read at", func(t *testing.T) {
+		var g errgroup.Group
+		for i := 0; i < 8; i++ {
+			off := int64(i) * int64(len(data)) / 8
+			g.Go(func() error {
+				b := make([]byte, 100000)
+				n, err := j.ReadAt(b, off)
+				if err != nil {
+					return err
+				}
+				if !bytes.Equal(b[:n], data[off:off+int64(n)]) {
+					return fmt.Errorf("offset %d: data mismatch", off)
+				}
+				return nil
+			})
+		}
+		if err := g.Wait(); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("seek", func(t *testing.T) {
+		if _, err := j.Seek(size-100, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(j)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, data[len(data)-100:]) {
+			t.Fatal("data mismatch")
+		}
+	})
+
+	t.Run("iterate chunk addresses", func(t *testing.T) {
+		count := 0
+		err := j.IterateChunkAddresses(func(swarm.Address) error {
+			count++
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// data chunks, intermediate chunks of segments and the index
+		if min := len(data) / swarm.ChunkSize; count < min {
+			t.Fatalf("got %d chunks, want at least %d", count, min)
+		}
+	})
+
+	t.Run("not an index", func(t *testing.T) {
+		pipe := builder.NewPipelineBuilder(ctx, store, storage.ModePutUpload, false)
+		ref, err := builder.FeedPipeline(ctx, pipe, bytes.NewReader(data[:100]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := joiner.NewCDC(ctx, store, ref); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+// TestCDCDeduplication tests that inserting data at the start of a file
+// only stores a small number of new chunks.
+func TestCDCDeduplication(t *testing.T) {
+	store := &countingPutter{Storer: mock.NewStorer()}
+
+	data := make([]byte, 2*1024*1024)
+	rand.New(rand.NewSource(2)).Read(data)
+
+	cdcUpload(t, store, data)
+	total := store.stored
+
+	store.stored = 0
+	cdcUpload(t, store, append([]byte("inserted"), data...))
+
+	if store.stored*10 > total {
+		t.Fatalf("stored %d new chunks of %d", store.stored, total)
+	}
+}
@@ -18,6 +18,8 @@ import (
 	"github.com/ethersphere/bee/pkg/file"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tracing"
+	"github.com/opentracing/opentracing-go/ext"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -34,10 +36,17 @@ type joiner struct {
 
 // New creates a new Joiner. A Joiner provides Read, Seek and Size functionalities.
 func New(ctx context.Context, getter storage.Getter, address swarm.Address) (file.Joiner, int64, error) {
+	traceSpan, ctx := tracing.StartSpanFromParent(ctx, "joiner-new")
+	if tracing.IsRecording(traceSpan) {
+		traceSpan.SetTag("address", address.String())
+	}
+	defer traceSpan.Finish()
+
 	getter = store.New(getter)
 	// retrieve the root chunk to read the total data length the be retrieved
 	rootChunk, err := getter.Get(ctx, storage.ModeGetRequest, address)
 	if err != nil {
+		ext.LogError(traceSpan, err)
 		return nil, 0, err
 	}
 
@@ -54,3 +54,13 @@ func (w *bmtWriter) ChainWrite(p *pipeline.PipeWriteArgs) error {
 func (w *bmtWriter) Sum() ([]byte, error) {
 	return w.next.Sum()
 }
+
+// Checkpoint returns the state of the next writer, as the writer is stateless.
+func (w *bmtWriter) Checkpoint() ([]byte, error) {
+	return pipeline.Checkpoint(w.next)
+}
+
+// Restore restores the state of the next writer.
+func (w *bmtWriter) Restore(state []byte) error {
+	return pipeline.Restore(w.next, state)
+}
@@ -12,22 +12,50 @@ import (
 	"github.com/ethersphere/bee/pkg/encryption"
 	"github.com/ethersphere/bee/pkg/file/pipeline"
 	"github.com/ethersphere/bee/pkg/file/pipeline/bmt"
+	"github.com/ethersphere/bee/pkg/file/pipeline/cdc"
 	enc "github.com/ethersphere/bee/pkg/file/pipeline/encryption"
 	"github.com/ethersphere/bee/pkg/file/pipeline/feeder"
 	"github.com/ethersphere/bee/pkg/file/pipeline/hashtrie"
 	"github.com/ethersphere/bee/pkg/file/pipeline/store"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tracing"
+	"github.com/opentracing/opentracing-go/ext"
 )
 
 // NewPipelineBuilder returns the appropriate pipeline according to the specified parameters
 func NewPipelineBuilder(ctx context.Context, s storage.Putter, mode storage.ModePut, encrypt bool) pipeline.Interface {
 	if encrypt {
-		return newEncryptionPipeline(ctx, s, mode)
+		return newEncryptionPipeline(ctx, s, mode, encryption.NewChunkEncrypter())
 	}
 	return newPipeline(ctx, s, mode)
 }
 
+// NewPipelineFactory returns the constructor of the pipelines returned by
+// NewPipelineBuilder for the parameters.
+func NewPipelineFactory(ctx context.Context, s storage.Putter, mode storage.ModePut, encrypt bool) func() pipeline.Interface {
+	return func() pipeline.Interface {
+		return NewPipelineBuilder(ctx, s, mode, encrypt)
+	}
+}
+
+// NewEncryptionPipelineBuilder returns an encryption pipeline which encrypts
+// the chunks with the keys returned by the provided encrypter.
+func NewEncryptionPipelineBuilder(ctx context.Context, s storage.Putter, mode storage.ModePut, encrypter encryption.ChunkEncrypter) pipeline.Interface {
+	return newEncryptionPipeline(ctx, s, mode, encrypter)
+}
+
+// NewCDCPipelineBuilder returns a content defined chunking pipeline which cuts
+// the data into variable sized segments, stores each of them with a pipeline
+// returned by newPipeline and returns the reference of the segment index.
+func NewCDCPipelineBuilder(newPipeline func() pipeline.Interface) pipeline.Interface {
+	chunker, err := cdc.NewChunker(cdc.DefaultMinSize, cdc.DefaultAvgSize, cdc.DefaultMaxSize)
+	if err != nil {
+		panic(err) // default sizes are valid
+	}
+	return cdc.NewCDCWriter(chunker, newPipeline)
+}
+
 // newPipeline creates a standard pipeline that only hashes content with BMT to create
 // a merkle-tree of hashes that represent the given arbitrary size byte stream. Partial
 // writes are supported. The pipeline flow is: Data -> Feeder -> BMT -> Storage -> HashTrie.
@@ -52,27 +80,37 @@ func newShortPipelineFunc(ctx context.Context, s storage.Putter, mode storage.Mo
 // writes are supported. The pipeline flow is: Data -> Feeder -> Encryption -> BMT -> Storage -> HashTrie.
 // Note that the encryption writer will mutate the data to contain the encrypted span, but the span field
 // with the unencrypted span is preserved.
-func newEncryptionPipeline(ctx context.Context, s storage.Putter, mode storage.ModePut) pipeline.Interface {
-	tw := hashtrie.NewHashTrieWriter(swarm.ChunkSize, 64, swarm.HashSize+encryption.KeyLength, newShortEncryptionPipelineFunc(ctx, s, mode))
+func newEncryptionPipeline(ctx context.Context, s storage.Putter, mode storage.ModePut, encrypter encryption.ChunkEncrypter) pipeline.Interface {
+	tw := hashtrie.NewHashTrieWriter(swarm.ChunkSize, 64, swarm.HashSize+encryption.KeyLength, newShortEncryptionPipelineFunc(ctx, s, mode, encrypter))
 	lsw := store.NewStoreWriter(ctx, s, mode, tw)
 	b := bmt.NewBmtWriter(lsw)
-	enc := enc.NewEncryptionWriter(encryption.NewChunkEncrypter(), b)
+	enc := enc.NewEncryptionWriter(encrypter, b)
 	return feeder.NewChunkFeederWriter(swarm.ChunkSize, enc)
 }
 
 // newShortEncryptionPipelineFunc returns a constructor function for an ephemeral hashing pipeline
 // needed by the hashTrieWriter.
-func newShortEncryptionPipelineFunc(ctx context.Context, s storage.Putter, mode storage.ModePut) func() pipeline.ChainWriter {
+func newShortEncryptionPipelineFunc(ctx context.Context, s storage.Putter, mode storage.ModePut, encrypter encryption.ChunkEncrypter) func() pipeline.ChainWriter {
 	return func() pipeline.ChainWriter {
 		lsw := store.NewStoreWriter(ctx, s, mode, nil)
 		b := bmt.NewBmtWriter(lsw)
-		return enc.NewEncryptionWriter(encryption.NewChunkEncrypter(), b)
+		return enc.NewEncryptionWriter(encrypter, b)
 	}
 }
 
 // FeedPipeline feeds the pipeline with the given reader until EOF is reached.
 // It returns the cryptographic root hash of the content.
 func FeedPipeline(ctx context.Context, pipeline pipeline.Interface, r io.Reader) (addr swarm.Address, err error) {
+	span, ctx := tracing.StartSpanFromParent(ctx, "splitter-feed-pipeline")
+	defer func() {
+		if err != nil {
+			ext.LogError(span, err)
+		} else if tracing.IsRecording(span) {
+			span.SetTag("address", addr.String())
+		}
+		span.Finish()
+	}()
+
 	data := make([]byte, swarm.ChunkSize)
 	for {
 		c, err := r.Read(data)
@@ -13,6 +13,8 @@ import (
 	"strconv"
 	"testing"
 
+	"github.com/ethersphere/bee/pkg/encryption"
+	"github.com/ethersphere/bee/pkg/file/pipeline"
 	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
 	test "github.com/ethersphere/bee/pkg/file/testing"
 	"github.com/ethersphere/bee/pkg/storage"
@@ -77,6 +79,81 @@ func TestEmpty(t *testing.T) {
 	}
 }
 
+// TestCheckpoint tests that the data written to pipelines restored from the
+// checkpoints of the previous ones results in the same reference as the data
+// written to a single pipeline.
+func TestCheckpoint(t *testing.T) {
+	key := make([]byte, encryption.KeyLength)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name        string
+		newPipeline func(storage.Putter) pipeline.Interface
+	}{
+		{
+			name: "plain",
+			newPipeline: func(s storage.Putter) pipeline.Interface {
+				return builder.NewPipelineBuilder(context.Background(), s, storage.ModePutUpload, false)
+			},
+		},
+		{
+			name: "encrypted",
+			newPipeline: func(s storage.Putter) pipeline.Interface {
+				return builder.NewEncryptionPipelineBuilder(context.Background(), s, storage.ModePutUpload, encryption.NewConvergentChunkEncrypter(key))
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data := make([]byte, 300*swarm.ChunkSize+123)
+			if _, err := rand.Read(data); err != nil {
+				t.Fatal(err)
+			}
+
+			p := tc.newPipeline(mock.NewStorer())
+			if _, err := p.Write(data); err != nil {
+				t.Fatal(err)
+			}
+			want, err := p.Sum()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			m := mock.NewStorer()
+			var state []byte
+			offsets := []int{0, 100, swarm.ChunkSize, 129*swarm.ChunkSize + 7, 200 * swarm.ChunkSize, len(data)}
+			for i := 1; i < len(offsets); i++ {
+				p := tc.newPipeline(m)
+				if state != nil {
+					if err := pipeline.Restore(p, state); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if _, err := p.Write(data[offsets[i-1]:offsets[i]]); err != nil {
+					t.Fatal(err)
+				}
+				state, err = pipeline.Checkpoint(p)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			p = tc.newPipeline(m)
+			if err := pipeline.Restore(p, state); err != nil {
+				t.Fatal(err)
+			}
+			got, err := p.Sum()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Fatalf("got reference %x, want %x", got, want)
+			}
+		})
+	}
+}
+
 func TestAllVectors(t *testing.T) {
 	for i := 1; i <= 20; i++ {
 		data, expect := test.GetVector(t, i)
@@ -0,0 +1,177 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package cdc provides a content defined chunking pipeline. The data is cut
+// into variable sized segments at boundaries found by a rolling hash, so that
+// an insertion or a deletion only changes the segments around it. Each
+// segment is stored as a separate merkle-tree of regular content addressed
+// chunks and the root of the upload is an index which records the span and
+// the reference of every segment.
+package cdc
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ethersphere/bee/pkg/encryption"
+	"github.com/ethersphere/bee/pkg/file/pipeline"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+const (
+	indexMagic   = "swarmcdc"
+	indexVersion = 1
+	// IndexHeaderSize is the size of the header which starts every index.
+	IndexHeaderSize = len(indexMagic) + 2
+)
+
+// ErrInvalidIndex is returned when the data is not a valid segment index.
+var ErrInvalidIndex = errors.New("cdc: invalid index")
+
+// Segment is a single content defined segment of the data.
+type Segment struct {
+	Span      int64
+	Reference swarm.Address
+}
+
+// MarshalIndex encodes the segments into an index. The index starts with a
+// magic string, a version and the reference length, followed by the little
+// endian span and the reference of each segment.
+func MarshalIndex(refLength int, segments []Segment) ([]byte, error) {
+	b := make([]byte, IndexHeaderSize, IndexHeaderSize+len(segments)*(swarm.SpanSize+refLength))
+	copy(b, indexMagic)
+	b[len(indexMagic)] = indexVersion
+	b[len(indexMagic)+1] = byte(refLength)
+
+	span := make([]byte, swarm.SpanSize)
+	for _, s := range segments {
+		if len(s.Reference.Bytes()) != refLength {
+			return nil, fmt.Errorf("cdc: reference length %d mismatches %d", len(s.Reference.Bytes()), refLength)
+		}
+		binary.LittleEndian.PutUint64(span, uint64(s.Span))
+		b = append(b, span...)
+		b = append(b, s.Reference.Bytes()...)
+	}
+	return b, nil
+}
+
+// UnmarshalIndex decodes the segments from an index created by MarshalIndex.
+func UnmarshalIndex(b []byte) ([]Segment, error) {
+	if !IsIndex(b) {
+		return nil, ErrInvalidIndex
+	}
+	refLength := int(b[len(indexMagic)+1])
+	if refLength != swarm.HashSize && refLength != encryption.ReferenceSize {
+		return nil, ErrInvalidIndex
+	}
+
+	b = b[IndexHeaderSize:]
+	recordSize := swarm.SpanSize + refLength
+	if len(b)%recordSize != 0 {
+		return nil, ErrInvalidIndex
+	}
+
+	segments := make([]Segment, 0, len(b)/recordSize)
+	for ; len(b) > 0; b = b[recordSize:] {
+		span := binary.LittleEndian.Uint64(b[:swarm.SpanSize])
+		if span == 0 || span > 1<<62 {
+			return nil, ErrInvalidIndex
+		}
+		ref := make([]byte, refLength)
+		copy(ref, b[swarm.SpanSize:recordSize])
+		segments = append(segments, Segment{Span: int64(span), Reference: swarm.NewAddress(ref)})
+	}
+	return segments, nil
+}
+
+// IsIndex returns true if the data starts with the index header.
+func IsIndex(b []byte) bool {
+	return len(b) >= IndexHeaderSize &&
+		string(b[:len(indexMagic)]) == indexMagic &&
+		b[len(indexMagic)] == indexVersion
+}
+
+type cdcWriter struct {
+	newPipeline func() pipeline.Interface
+	chunker     *Chunker
+
+	segment     pipeline.Interface
+	segmentSpan int64
+	segments    []Segment
+	refLength   int
+}
+
+// NewCDCWriter creates a new content defined chunking writer. The data of
+// every segment is written into a new pipeline created by newPipeline, the
+// same kind of pipeline is used to store the index when Sum is called.
+func NewCDCWriter(chunker *Chunker, newPipeline func() pipeline.Interface) pipeline.Interface {
+	return &cdcWriter{
+		newPipeline: newPipeline,
+		chunker:     chunker,
+	}
+}
+
+// Write writes the data into the pipeline of the current segment, starting
+// a new segment at every boundary found by the chunker.
+func (w *cdcWriter) Write(b []byte) (int, error) {
+	written := 0
+	for len(b) > 0 {
+		if w.segment == nil {
+			w.segment = w.newPipeline()
+		}
+
+		n, boundary := w.chunker.Next(b)
+		if _, err := w.segment.Write(b[:n]); err != nil {
+			return written, err
+		}
+		w.segmentSpan += int64(n)
+		written += n
+		b = b[n:]
+
+		if boundary {
+			if err := w.closeSegment(); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+// Sum closes the last segment, stores the index of all segments and
+// returns its reference.
+func (w *cdcWriter) Sum() ([]byte, error) {
+	if w.segment != nil {
+		if err := w.closeSegment(); err != nil {
+			return nil, err
+		}
+	}
+
+	refLength := w.refLength
+	if refLength == 0 {
+		refLength = swarm.HashSize
+	}
+
+	index := w.newPipeline()
+	data, err := MarshalIndex(refLength, w.segments)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := index.Write(data); err != nil {
+		return nil, err
+	}
+	return index.Sum()
+}
+
+func (w *cdcWriter) closeSegment() error {
+	ref, err := w.segment.Sum()
+	if err != nil {
+		return err
+	}
+	w.refLength = len(ref)
+	w.segments = append(w.segments, Segment{Span: w.segmentSpan, Reference: swarm.NewAddress(ref)})
+	w.segment = nil
+	w.segmentSpan = 0
+	return nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cdc_test
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/file/pipeline/cdc"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func segments(t *testing.T, data []byte, writeSize int) []int {
+	t.Helper()
+
+	c, err := cdc.NewChunker(256, 1024, 4096)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		sizes []int
+		size  int
+	)
+	for len(data) > 0 {
+		w := data
+		if len(w) > writeSize {
+			w = w[:writeSize]
+		}
+		for len(w) > 0 {
+			n, boundary := c.Next(w)
+			size += n
+			w = w[n:]
+			data = data[n:]
+			if boundary {
+				sizes = append(sizes, size)
+				size = 0
+			}
+		}
+	}
+	if size > 0 {
+		sizes = append(sizes, size)
+	}
+	return sizes
+}
+
+func TestChunker(t *testing.T) {
+	data := make([]byte, 256*1024)
+	rand.New(rand.NewSource(1)).Read(data)
+
+	sizes := segments(t, data, len(data))
+
+	t.Run("bounds", func(t *testing.T) {
+		for i, s := range sizes[:len(sizes)-1] {
+			if s < 256 || s > 4096 {
+				t.Fatalf("segment %d size %d out of bounds", i, s)
+			}
+		}
+	})
+
+	t.Run("independent of write sizes", func(t *testing.T) {
+		for _, ws := range []int{1, 100, 4096} {
+			got := segments(t, data, ws)
+			if !equal(got, sizes) {
+				t.Fatalf("write size %d: segments differ", ws)
+			}
+		}
+	})
+
+	t.Run("resynchronize after insertion", func(t *testing.T) {
+		modified := append([]byte{42}, data...)
+		got := segments(t, modified, len(modified))
+
+		// all segments except the first few must be the same
+		if len(got) < 10 || !equal(got[len(got)-len(sizes)+5:], sizes[5:]) {
+			t.Fatal("segments did not resynchronize after insertion")
+		}
+	})
+}
+
+func TestNewChunkerInvalidSizes(t *testing.T) {
+	for _, tc := range [][3]int{
+		{0, 1024, 4096},
+		{2048, 1024, 4096},
+		{256, 1000, 4096},
+		{256, 1024, 512},
+	} {
+		if _, err := cdc.NewChunker(tc[0], tc[1], tc[2]); err == nil {
+			t.Fatalf("sizes %v: expected error", tc)
+		}
+	}
+}
+
+func TestIndex(t *testing.T) {
+	segments := []cdc.Segment{
+		{Span: 10, Reference: swarm.MustParseHexAddress("0000000000000000000000000000000000000000000000000000000000000001")},
+		{Span: 70000, Reference: swarm.MustParseHexAddress("0000000000000000000000000000000000000000000000000000000000000002")},
+	}
+
+	b, err := cdc.MarshalIndex(swarm.HashSize, segments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cdc.IsIndex(b) {
+		t.Fatal("expected index")
+	}
+
+	got, err := cdc.UnmarshalIndex(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(segments) {
+		t.Fatalf("got %d segments, want %d", len(got), len(segments))
+	}
+	for i := range got {
+		if got[i].Span != segments[i].Span || !got[i].Reference.Equal(segments[i].Reference) {
+			t.Fatalf("segment %d: got %v, want %v", i, got[i], segments[i])
+		}
+	}
+
+	if _, err := cdc.MarshalIndex(64, segments); err == nil {
+		t.Fatal("expected reference length error")
+	}
+	for _, invalid := range [][]byte{
+		nil,
+		[]byte("not an index"),
+		b[:len(b)-1],
+		bytes.Replace(b, []byte("swarmcdc\x01\x20"), []byte("swarmcdc\x01\x10"), 1),
+	} {
+		if _, err := cdc.UnmarshalIndex(invalid); !errors.Is(err, cdc.ErrInvalidIndex) {
+			t.Fatalf("got error %v, want %v", err, cdc.ErrInvalidIndex)
+		}
+	}
+}
+
+func equal(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cdc
+
+import (
+	"errors"
+	"math/bits"
+)
+
+const (
+	// DefaultMinSize is the default minimal size of a segment.
+	DefaultMinSize = 16 * 1024
+	// DefaultAvgSize is the default average size of a segment.
+	DefaultAvgSize = 64 * 1024
+	// DefaultMaxSize is the default maximal size of a segment.
+	DefaultMaxSize = 256 * 1024
+)
+
+var errInvalidSizes = errors.New("cdc: invalid segment sizes")
+
+// gear is the table of random values used by the rolling hash. It is
+// generated from a fixed seed, since every node must find the same
+// boundaries for the same content.
+var gear [256]uint64
+
+func init() {
+	seed := uint64(0x5357_4152_4d43_4443)
+	for i := range gear {
+		// splitmix64
+		seed += 0x9e3779b97f4a7c15
+		z := seed
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		gear[i] = z ^ (z >> 31)
+	}
+}
+
+// Chunker finds content defined segment boundaries in a byte stream using
+// a gear based rolling hash. A boundary is placed where the hash matches
+// the mask, but never before the minimal and never after the maximal
+// segment size.
+type Chunker struct {
+	min, max int
+	mask     uint64
+
+	hash uint64
+	size int
+}
+
+// NewChunker creates a new Chunker with the given minimal, average and
+// maximal segment sizes. The average size must be a power of two.
+func NewChunker(min, avg, max int) (*Chunker, error) {
+	if min <= 0 || avg < min || max < avg || bits.OnesCount(uint(avg)) != 1 {
+		return nil, errInvalidSizes
+	}
+	return &Chunker{
+		min:  min,
+		max:  max,
+		mask: uint64(avg-1) << (64 - bits.Len(uint(avg-1))),
+	}, nil
+}
+
+// Next consumes b until the next segment boundary. It returns the number
+// of consumed bytes and whether a boundary was found after them, in which
+// case the state is reset for the next segment.
+func (c *Chunker) Next(b []byte) (n int, boundary bool) {
+	for i, v := range b {
+		c.hash = (c.hash << 1) + gear[v]
+		c.size++
+		if c.size >= c.max || (c.size >= c.min && c.hash&c.mask == 0) {
+			c.hash = 0
+			c.size = 0
+			return i + 1, true
+		}
+	}
+	return len(b), false
+}
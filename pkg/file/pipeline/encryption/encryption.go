@@ -38,3 +38,13 @@ func (e *encryptionWriter) ChainWrite(p *pipeline.PipeWriteArgs) error {
 func (e *encryptionWriter) Sum() ([]byte, error) {
 	return e.next.Sum()
 }
+
+// Checkpoint returns the state of the next writer, as the writer is stateless.
+func (e *encryptionWriter) Checkpoint() ([]byte, error) {
+	return pipeline.Checkpoint(e.next)
+}
+
+// Restore restores the state of the next writer.
+func (e *encryptionWriter) Restore(state []byte) error {
+	return pipeline.Restore(e.next, state)
+}
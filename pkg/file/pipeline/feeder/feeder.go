@@ -6,6 +6,8 @@ package feeder
 
 import (
 	"encoding/binary"
+	"encoding/json"
+	"errors"
 
 	"github.com/ethersphere/bee/pkg/file/pipeline"
 	"github.com/ethersphere/bee/pkg/swarm"
@@ -13,6 +15,8 @@ import (
 
 const span = swarm.SpanSize
 
+var errInvalidCheckpoint = errors.New("feeder: invalid checkpoint")
+
 type chunkFeeder struct {
 	size      int
 	next      pipeline.ChainWriter
@@ -119,3 +123,40 @@ func (f *chunkFeeder) Sum() ([]byte, error) {
 
 	return f.next.Sum()
 }
+
+type feederState struct {
+	Buffer []byte `json:"buffer"`
+	Wrote  int64  `json:"wrote"`
+	Next   []byte `json:"next"`
+}
+
+// Checkpoint returns the state of the feeder, which includes the data
+// buffered until a chunk is full, and the state of the subsequent writers.
+func (f *chunkFeeder) Checkpoint() ([]byte, error) {
+	next, err := pipeline.Checkpoint(f.next)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(feederState{
+		Buffer: f.buffer[:f.bufferIdx],
+		Wrote:  f.wrote,
+		Next:   next,
+	})
+}
+
+// Restore restores the state of the feeder and the subsequent writers.
+func (f *chunkFeeder) Restore(state []byte) error {
+	var s feederState
+	if err := json.Unmarshal(state, &s); err != nil {
+		return err
+	}
+	if len(s.Buffer) >= f.size || s.Wrote < 0 {
+		return errInvalidCheckpoint
+	}
+	if err := pipeline.Restore(f.next, s.Next); err != nil {
+		return err
+	}
+	f.bufferIdx = copy(f.buffer, s.Buffer)
+	f.wrote = s.Wrote
+	return nil
+}
@@ -6,6 +6,7 @@ package hashtrie
 
 import (
 	"encoding/binary"
+	"encoding/json"
 	"errors"
 
 	"github.com/ethersphere/bee/pkg/file/pipeline"
@@ -13,8 +14,9 @@ import (
 )
 
 var (
-	errInconsistentRefs = errors.New("inconsistent references")
-	errTrieFull         = errors.New("trie full")
+	errInconsistentRefs  = errors.New("inconsistent references")
+	errTrieFull          = errors.New("trie full")
+	errInvalidCheckpoint = errors.New("invalid checkpoint")
 )
 
 const maxLevel = 8
@@ -190,3 +192,41 @@ func (h *hashTrieWriter) Sum() ([]byte, error) {
 	data := h.buffer[0:h.cursors[8]]
 	return data[8:], nil
 }
+
+type hashTrieState struct {
+	Cursors []int  `json:"cursors"`
+	Buffer  []byte `json:"buffer"`
+	Full    bool   `json:"full"`
+}
+
+// Checkpoint returns the state of the trie, which are the references of
+// all levels that are not wrapped yet.
+func (h *hashTrieWriter) Checkpoint() ([]byte, error) {
+	// level 1 has the highest cursor, the levels above it are kept
+	// before it in the buffer
+	return json.Marshal(hashTrieState{
+		Cursors: h.cursors,
+		Buffer:  h.buffer[:h.cursors[1]],
+		Full:    h.full,
+	})
+}
+
+// Restore restores the state of the trie.
+func (h *hashTrieWriter) Restore(state []byte) error {
+	var s hashTrieState
+	if err := json.Unmarshal(state, &s); err != nil {
+		return err
+	}
+	if len(s.Cursors) != len(h.cursors) || len(s.Buffer) > len(h.buffer) || s.Cursors[1] != len(s.Buffer) {
+		return errInvalidCheckpoint
+	}
+	for i := 1; i < maxLevel; i++ {
+		if s.Cursors[i+1] < 0 || s.Cursors[i+1] > s.Cursors[i] {
+			return errInvalidCheckpoint
+		}
+	}
+	copy(h.cursors, s.Cursors)
+	copy(h.buffer, s.Buffer)
+	h.full = s.Full
+	return nil
+}
@@ -4,7 +4,14 @@
 
 package pipeline
 
-import "io"
+import (
+	"errors"
+	"io"
+)
+
+// ErrCheckpointUnsupported is returned when a writer of a pipeline does not
+// support checkpoints.
+var ErrCheckpointUnsupported = errors.New("pipeline: checkpoint unsupported")
 
 // ChainWriter is a writer in a pipeline.
 // It is up to the implementer to decide whether a writer
@@ -35,3 +42,39 @@ type PipeWriteArgs struct {
 }
 
 type PipelineFunc func() ChainWriter
+
+// Checkpointer is implemented by the writers whose state can be saved and
+// restored, so that the data can be written to a pipeline in multiple
+// sessions, which may span restarts of the node. Implementers should include
+// the state of the subsequent writer in the checkpoint.
+type Checkpointer interface {
+	// Checkpoint returns the state of the writer.
+	Checkpoint() ([]byte, error)
+	// Restore sets the state of a new writer to the state returned by
+	// Checkpoint.
+	Restore(state []byte) error
+}
+
+// Checkpoint returns the state of the writer, or nil if the writer is nil.
+func Checkpoint(w interface{}) ([]byte, error) {
+	if w == nil {
+		return nil, nil
+	}
+	c, ok := w.(Checkpointer)
+	if !ok {
+		return nil, ErrCheckpointUnsupported
+	}
+	return c.Checkpoint()
+}
+
+// Restore sets the state of the writer to the state returned by Checkpoint.
+func Restore(w interface{}, state []byte) error {
+	if w == nil {
+		return nil
+	}
+	c, ok := w.(Checkpointer)
+	if !ok {
+		return ErrCheckpointUnsupported
+	}
+	return c.Restore(state)
+}
@@ -72,3 +72,13 @@ func (w *storeWriter) ChainWrite(p *pipeline.PipeWriteArgs) error {
 func (w *storeWriter) Sum() ([]byte, error) {
 	return w.next.Sum()
 }
+
+// Checkpoint returns the state of the next writer, as the writer is stateless.
+func (w *storeWriter) Checkpoint() ([]byte, error) {
+	return pipeline.Checkpoint(w.next)
+}
+
+// Restore restores the state of the next writer.
+func (w *storeWriter) Restore(state []byte) error {
+	return pipeline.Restore(w.next, state)
+}
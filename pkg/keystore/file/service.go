@@ -9,10 +9,17 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/keystore"
 )
 
+const keyExtension = ".key"
+
+var _ keystore.Service = (*Service)(nil)
+
 // Service is the file-based keystore.Service implementation.
 //
 // Keys are stored in directory where each private key is stored in a file,
@@ -54,27 +61,102 @@ func (s *Service) Key(name, password string) (pk *ecdsa.PrivateKey, created bool
 			return nil, false, fmt.Errorf("generate secp256k1 key: %w", err)
 		}
 
-		d, err := encryptKey(pk, password)
-		if err != nil {
-			return nil, false, err
-		}
-
-		if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
-			return nil, false, err
-		}
-		if err := os.WriteFile(filename, d, 0600); err != nil {
+		if err := s.writeKey(name, pk, password); err != nil {
 			return nil, false, err
 		}
 		return pk, true, nil
 	}
 
-	pk, err = decryptKey(data, password)
+	pk, err = keystore.DecryptKey(data, password)
 	if err != nil {
 		return nil, false, err
 	}
 	return pk, false, nil
 }
 
+func (s *Service) List() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read keys directory: %w", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), keyExtension) {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(e.Name(), keyExtension))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *Service) ChangePassword(name, password, newPassword string) error {
+	pk, err := s.readKey(name, password)
+	if err != nil {
+		return err
+	}
+	return s.writeKey(name, pk, newPassword)
+}
+
+func (s *Service) Export(name, password string) ([]byte, error) {
+	pk, err := s.readKey(name, password)
+	if err != nil {
+		return nil, err
+	}
+	return keystore.EncryptKey(pk, password)
+}
+
+func (s *Service) Import(name, password string, data []byte) error {
+	exists, err := s.Exists(name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return keystore.ErrKeyExists
+	}
+
+	pk, err := keystore.DecryptKey(data, password)
+	if err != nil {
+		return err
+	}
+	return s.writeKey(name, pk, password)
+}
+
+// readKey decrypts an existing key, unlike Key which creates missing keys.
+func (s *Service) readKey(name, password string) (*ecdsa.PrivateKey, error) {
+	data, err := os.ReadFile(s.keyFilename(name))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("read private key: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, keystore.ErrKeyNotFound
+	}
+	return keystore.DecryptKey(data, password)
+}
+
+// writeKey encrypts the key with the password and replaces the key file
+// atomically, so that a key is never lost by a partial write.
+func (s *Service) writeKey(name string, pk *ecdsa.PrivateKey, password string) error {
+	d, err := keystore.EncryptKey(pk, password)
+	if err != nil {
+		return err
+	}
+
+	filename := s.keyFilename(name)
+	if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+		return err
+	}
+	tmp := filename + ".tmp"
+	if err := os.WriteFile(tmp, d, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
+
 func (s *Service) keyFilename(name string) string {
-	return filepath.Join(s.dir, fmt.Sprintf("%s.key", name))
+	return filepath.Join(s.dir, name+keyExtension)
 }
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-package file
+package keystore
 
 import (
 	"bytes"
@@ -16,14 +16,11 @@ import (
 	"io"
 
 	"github.com/ethersphere/bee/pkg/crypto"
-	"github.com/ethersphere/bee/pkg/keystore"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/scrypt"
 	"golang.org/x/crypto/sha3"
 )
 
-var _ keystore.Service = (*Service)(nil)
-
 const (
 	keyHeaderKDF = "scrypt"
 	keyVersion   = 3
@@ -63,7 +60,9 @@ type kdfParams struct {
 	Salt  string `json:"salt"`
 }
 
-func encryptKey(k *ecdsa.PrivateKey, password string) ([]byte, error) {
+// EncryptKey encrypts the private key with the password and returns it in
+// the Ethereum JSON v3 key file format.
+func EncryptKey(k *ecdsa.PrivateKey, password string) ([]byte, error) {
 	data := crypto.EncodeSecp256k1PrivateKey(k)
 	kc, err := encryptData(data, []byte(password))
 	if err != nil {
@@ -81,7 +80,9 @@ func encryptKey(k *ecdsa.PrivateKey, password string) ([]byte, error) {
 	})
 }
 
-func decryptKey(data []byte, password string) (*ecdsa.PrivateKey, error) {
+// DecryptKey decrypts the private key from data in the Ethereum JSON v3 key
+// file format. ErrInvalidPassword is returned if the password is not valid.
+func DecryptKey(data []byte, password string) (*ecdsa.PrivateKey, error) {
 	var k encryptedKey
 	if err := json.Unmarshal(data, &k); err != nil {
 		return nil, err
@@ -163,7 +164,7 @@ func decryptData(v keyCripto, password string) ([]byte, error) {
 			return nil, err
 		}
 		if !bytes.Equal(calculatedMACEth[:], mac) {
-			return nil, keystore.ErrInvalidPassword
+			return nil, ErrInvalidPassword
 		}
 	}
 
@@ -9,9 +9,17 @@ import (
 	"errors"
 )
 
-// ErrInvalidPassword is returned when the password for decrypting content where
-// private key is stored is not valid.
-var ErrInvalidPassword = errors.New("invalid password")
+var (
+	// ErrInvalidPassword is returned when the password for decrypting content where
+	// private key is stored is not valid.
+	ErrInvalidPassword = errors.New("invalid password")
+	// ErrKeyNotFound is returned when the key with the specified name does not
+	// exist.
+	ErrKeyNotFound = errors.New("key not found")
+	// ErrKeyExists is returned when a key is imported with the name of an
+	// existing key.
+	ErrKeyExists = errors.New("key already exists")
+)
 
 // Service for managing keystore private keys.
 type Service interface {
@@ -22,4 +30,15 @@ type Service interface {
 	Key(name, password string) (k *ecdsa.PrivateKey, created bool, err error)
 	// Exists returns true if the key with specified name exists.
 	Exists(name string) (bool, error)
+	// List returns the sorted names of all keys.
+	List() ([]string, error)
+	// ChangePassword encrypts the key with specified name with a new
+	// password.
+	ChangePassword(name, password, newPassword string) error
+	// Export returns the key with specified name encrypted with the password
+	// in the Ethereum JSON v3 key file format.
+	Export(name, password string) ([]byte, error)
+	// Import stores the key from data in the Ethereum JSON v3 key file format,
+	// encrypted with the password, under the specified name.
+	Import(name, password string, data []byte) error
 }
@@ -7,6 +7,7 @@ package mem
 import (
 	"crypto/ecdsa"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/ethersphere/bee/pkg/crypto"
@@ -64,6 +65,76 @@ func (s *Service) Key(name, password string) (pk *ecdsa.PrivateKey, created bool
 	return k.pk, created, nil
 }
 
+func (s *Service) List() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(s.m))
+	for name := range s.m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *Service) ChangePassword(name, password, newPassword string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k, err := s.key(name, password)
+	if err != nil {
+		return err
+	}
+	s.m[name] = key{
+		pk:       k.pk,
+		password: newPassword,
+	}
+	return nil
+}
+
+func (s *Service) Export(name, password string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k, err := s.key(name, password)
+	if err != nil {
+		return nil, err
+	}
+	return keystore.EncryptKey(k.pk, password)
+}
+
+func (s *Service) Import(name, password string, data []byte) error {
+	pk, err := keystore.DecryptKey(data, password)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.m[name]; ok {
+		return keystore.ErrKeyExists
+	}
+	s.m[name] = key{
+		pk:       pk,
+		password: password,
+	}
+	return nil
+}
+
+// key returns an existing key, unlike Key which creates missing keys. It
+// must be called with the lock held.
+func (s *Service) key(name, password string) (key, error) {
+	k, ok := s.m[name]
+	if !ok {
+		return key{}, keystore.ErrKeyNotFound
+	}
+	if k.password != password {
+		return key{}, keystore.ErrInvalidPassword
+	}
+	return k, nil
+}
+
 type key struct {
 	pk       *ecdsa.PrivateKey
 	password string
@@ -7,6 +7,7 @@ package test
 import (
 	"bytes"
 	"errors"
+	"reflect"
 	"testing"
 
 	"github.com/ethersphere/bee/pkg/keystore"
@@ -82,4 +83,59 @@ func Service(t *testing.T, s keystore.Service) {
 	if !bytes.Equal(k3.D.Bytes(), k4.D.Bytes()) {
 		t.Fatal("two keys are not equal")
 	}
+
+	// list keys
+	names, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(names, []string{"libp2p", "swarm"}) {
+		t.Fatalf("got names %v", names)
+	}
+
+	// change password
+	if err := s.ChangePassword("swarm", "invalid password", "new pass"); !errors.Is(err, keystore.ErrInvalidPassword) {
+		t.Fatal(err)
+	}
+	if err := s.ChangePassword("missing", "pass123456", "new pass"); !errors.Is(err, keystore.ErrKeyNotFound) {
+		t.Fatal(err)
+	}
+	if err := s.ChangePassword("swarm", "pass123456", "new pass"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = s.Key("swarm", "pass123456"); !errors.Is(err, keystore.ErrInvalidPassword) {
+		t.Fatal(err)
+	}
+	k5, created, err := s.Key("swarm", "new pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created || !bytes.Equal(k1.D.Bytes(), k5.D.Bytes()) {
+		t.Fatal("key changed with the password")
+	}
+
+	// export and import
+	if _, err := s.Export("swarm", "pass123456"); !errors.Is(err, keystore.ErrInvalidPassword) {
+		t.Fatal(err)
+	}
+	data, err := s.Export("swarm", "new pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Import("libp2p", "new pass", data); !errors.Is(err, keystore.ErrKeyExists) {
+		t.Fatal(err)
+	}
+	if err := s.Import("imported", "other pass", data); !errors.Is(err, keystore.ErrInvalidPassword) {
+		t.Fatal(err)
+	}
+	if err := s.Import("imported", "new pass", data); err != nil {
+		t.Fatal(err)
+	}
+	k6, created, err := s.Key("imported", "new pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created || !bytes.Equal(k1.D.Bytes(), k6.D.Bytes()) {
+		t.Fatal("imported key is not equal to the exported key")
+	}
 }
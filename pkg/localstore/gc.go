@@ -18,8 +18,10 @@ package localstore
 
 import (
 	"errors"
+	"sync/atomic"
 	"time"
 
+	"github.com/ethersphere/bee/pkg/events"
 	"github.com/ethersphere/bee/pkg/sharky"
 	"github.com/ethersphere/bee/pkg/shed"
 	"github.com/ethersphere/bee/pkg/swarm"
@@ -71,6 +73,9 @@ func (db *DB) collectGarbageWorker() {
 			if err != nil {
 				db.logger.Errorf("localstore: collect garbage: %v", err)
 			}
+			if collectedCount > 0 {
+				db.events.Publish(events.TypeGarbageCollection, events.GarbageCollectionData{Collected: collectedCount})
+			}
 			// check if another gc run is needed
 			if !done {
 				db.triggerGarbageCollection()
@@ -254,11 +259,27 @@ func (db *DB) collectGarbage() (evicted uint64, done bool, err error) {
 // gcTarget retruns the absolute value for garbage collection
 // target value, calculated from db.capacity and gcTargetRatio.
 func (db *DB) gcTarget() (target uint64) {
-	return uint64(float64(db.cacheCapacity) * gcTargetRatio)
+	return uint64(float64(atomic.LoadUint64(&db.cacheCapacity)) * gcTargetRatio)
+}
+
+// SetCacheCapacity sets the number of chunks in the cache above which the
+// garbage collection is triggered, and triggers it if the cache is already
+// above the capacity.
+func (db *DB) SetCacheCapacity(capacity uint64) error {
+	atomic.StoreUint64(&db.cacheCapacity, capacity)
+
+	gcSize, err := db.gcSize.Get()
+	if err != nil && !errors.Is(err, leveldb.ErrNotFound) {
+		return err
+	}
+	if gcSize >= capacity {
+		db.triggerGarbageCollection()
+	}
+	return nil
 }
 
 func (db *DB) reserveEvictionTarget() (target uint64) {
-	targetCache := db.reserveCapacity - uint64(float64(db.cacheCapacity)*reserveCollectionRatio)
+	targetCache := db.reserveCapacity - uint64(float64(atomic.LoadUint64(&db.cacheCapacity))*reserveCollectionRatio)
 	targetCeiling := db.reserveCapacity - uint64(float64(db.reserveCapacity)*maxPurgeablePercentageOfReserve)
 	if targetCeiling > targetCache {
 		return targetCeiling
@@ -315,7 +336,7 @@ func (db *DB) incGCSizeInBatch(batch *leveldb.Batch, change int64) (err error) {
 	db.metrics.GCSize.Set(float64(newSize))
 
 	// trigger garbage collection if we reached the capacity
-	if newSize >= db.cacheCapacity {
+	if newSize >= atomic.LoadUint64(&db.cacheCapacity) {
 		db.triggerGarbageCollection()
 	}
 	return nil
@@ -1161,3 +1161,61 @@ func TestReserveEvictionWorker(t *testing.T) {
 	})
 
 }
+
+// TestDB_SetCacheCapacity tests that lowering the cache capacity below the
+// size of the gc index triggers the garbage collection.
+func TestDB_SetCacheCapacity(t *testing.T) {
+	t.Cleanup(setWithinRadiusFunc(func(_ *DB, _ shed.Item) bool { return false }))
+
+	db := newTestDB(t, &Options{
+		Capacity: 100,
+	})
+
+	chunkCount := 50
+	for i := 0; i < chunkCount; i++ {
+		ch := generateTestRandomChunk()
+		unreserveChunkBatch(t, db, 0, ch)
+
+		_, err := db.Put(context.Background(), storage.ModePutUpload, ch)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = db.Set(context.Background(), storage.ModeSetSync, ch.Address())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	gcSize, err := db.gcSize.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gcSize != uint64(chunkCount) {
+		t.Fatalf("got gc size %d, want %d", gcSize, chunkCount)
+	}
+
+	if err := db.SetCacheCapacity(20); err != nil {
+		t.Fatal(err)
+	}
+
+	target := db.gcTarget()
+	if want := uint64(18); target != want {
+		t.Fatalf("got gc target %d, want %d", target, want)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		gcSize, err = db.gcSize.Get()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gcSize <= target {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("gc size %d not reduced to target %d", gcSize, target)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ethersphere/bee/pkg/events"
 	"github.com/ethersphere/bee/pkg/logging"
 	"github.com/ethersphere/bee/pkg/pinning"
 	"github.com/ethersphere/bee/pkg/postage"
@@ -185,6 +186,9 @@ type DB struct {
 	metrics metrics
 
 	logger logging.Logger
+
+	// events of garbage collection runs are published on the bus
+	events *events.Bus
 }
 
 // Options struct holds optional parameters for configuring DB.
@@ -213,6 +217,8 @@ type Options struct {
 	// MetricsPrefix defines a prefix for metrics names.
 	MetricsPrefix string
 	Tags          *tags.Tags
+	// EventBus receives the events of garbage collection runs.
+	EventBus *events.Bus
 }
 
 type memFS struct {
@@ -306,6 +312,7 @@ func New(path string, baseKey []byte, ss storage.StateStorer, o *Options, logger
 		reserveEvictionWorkerDone: make(chan struct{}),
 		metrics:                   newMetrics(),
 		logger:                    logger,
+		events:                    o.EventBus,
 	}
 	if db.cacheCapacity == 0 {
 		db.cacheCapacity = defaultCacheCapacity
@@ -26,6 +26,8 @@ import (
 	"github.com/ethersphere/bee/pkg/shed"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tracing"
+	"github.com/opentracing/opentracing-go/ext"
 	"github.com/syndtr/goleveldb/leveldb"
 )
 
@@ -38,10 +40,17 @@ func (db *DB) Get(ctx context.Context, mode storage.ModeGet, addr swarm.Address)
 	db.metrics.ModeGet.Inc()
 	defer totalTimeMetric(db.metrics.TotalTimeGet, time.Now())
 
+	span, ctx := tracing.StartSpanFromParent(ctx, "localstore-get")
+	if tracing.IsRecording(span) {
+		span.SetTag("mode", mode.String())
+		span.SetTag("address", addr.String())
+	}
 	defer func() {
 		if err != nil {
 			db.metrics.ModeGetFailure.Inc()
+			ext.LogError(span, err)
 		}
+		span.Finish()
 	}()
 
 	out, err := db.get(ctx, mode, addr)
@@ -27,6 +27,8 @@ import (
 	"github.com/ethersphere/bee/pkg/shed"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tracing"
+	"github.com/opentracing/opentracing-go/ext"
 	"github.com/syndtr/goleveldb/leveldb"
 )
 
@@ -43,9 +45,17 @@ func (db *DB) Put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk)
 	db.metrics.ModePut.Inc()
 	defer totalTimeMetric(db.metrics.TotalTimePut, time.Now())
 
+	span, ctx := tracing.StartSpanFromParent(ctx, "localstore-put")
+	if tracing.IsRecording(span) {
+		span.SetTag("mode", mode.String())
+		span.SetTag("chunks", len(chs))
+	}
+	defer span.Finish()
+
 	exist, err = db.put(ctx, mode, chs...)
 	if err != nil {
 		db.metrics.ModePutFailure.Inc()
+		ext.LogError(span, err)
 	}
 
 	return exist, err
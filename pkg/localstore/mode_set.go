@@ -27,7 +27,9 @@ import (
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
 	"github.com/ethersphere/bee/pkg/tags"
+	"github.com/ethersphere/bee/pkg/tracing"
 	"github.com/hashicorp/go-multierror"
+	"github.com/opentracing/opentracing-go/ext"
 	"github.com/syndtr/goleveldb/leveldb"
 )
 
@@ -38,9 +40,18 @@ import (
 func (db *DB) Set(ctx context.Context, mode storage.ModeSet, addrs ...swarm.Address) (err error) {
 	db.metrics.ModeSet.Inc()
 	defer totalTimeMetric(db.metrics.TotalTimeSet, time.Now())
+
+	span, ctx := tracing.StartSpanFromParent(ctx, "localstore-set")
+	if tracing.IsRecording(span) {
+		span.SetTag("mode", mode.String())
+		span.SetTag("chunks", len(addrs))
+	}
+	defer span.Finish()
+
 	err = db.set(ctx, mode, addrs...)
 	if err != nil {
 		db.metrics.ModeSetFailure.Inc()
+		ext.LogError(span, err)
 	}
 	return err
 }
@@ -7,6 +7,7 @@ package localstore
 import (
 	"errors"
 	"fmt"
+	"sync/atomic"
 
 	"github.com/ethersphere/bee/pkg/shed"
 	"github.com/ethersphere/bee/pkg/swarm"
@@ -114,7 +115,7 @@ func (db *DB) UnreserveBatch(id []byte, radius uint8) (evicted uint64, err error
 	}
 
 	// trigger garbage collection if we reached the capacity
-	if gcSize >= db.cacheCapacity {
+	if gcSize >= atomic.LoadUint64(&db.cacheCapacity) {
 		db.triggerGarbageCollection()
 	}
 
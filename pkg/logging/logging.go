@@ -7,11 +7,29 @@
 package logging
 
 import (
+	"errors"
 	"io"
+	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
 
+// Field names which are kept stable for the tools which parse the logs.
+const (
+	ComponentField = "component"
+	PeerField      = "peer"
+	ChunkField     = "chunk"
+	TraceIDField   = "traceID"
+)
+
+// RootName is the name of the logger returned by New.
+const RootName = "root"
+
+// ErrUnknownLogger is returned when the level of a logger which was not
+// created is set.
+var ErrUnknownLogger = errors.New("unknown logger")
+
 type Logger interface {
 	Tracef(format string, args ...interface{})
 	Trace(args ...interface{})
@@ -27,82 +45,219 @@ type Logger interface {
 	WithFields(fields logrus.Fields) *logrus.Entry
 	WriterLevel(logrus.Level) *io.PipeWriter
 	NewEntry() *logrus.Entry
+	// Named returns the logger of a component, which logs with the name in
+	// the component field and has a level which is set independently of
+	// the other loggers. The names of loggers returned by named loggers are
+	// joined with a slash.
+	Named(name string) Logger
+	// NamedLevels returns the levels of all loggers by their names.
+	NamedLevels() map[string]logrus.Level
+	// SetNamedLevel sets the level of the logger with the name.
+	SetNamedLevel(name string, level logrus.Level) error
+}
+
+type options struct {
+	json             bool
+	sampleFirst      int
+	sampleThereafter int
+}
+
+// Option configures the loggers created by New.
+type Option func(*options)
+
+// WithJSONFormat makes the loggers write the entries as JSON objects.
+func WithJSONFormat() Option {
+	return func(o *options) {
+		o.json = true
+	}
+}
+
+// WithSampling limits the number of debug and trace entries of a logger to
+// the first entries every second and every thereafter entry after them.
+func WithSampling(first, thereafter int) Option {
+	return func(o *options) {
+		o.sampleFirst = first
+		o.sampleThereafter = thereafter
+	}
 }
 
 type logger struct {
 	*logrus.Logger
-	metrics metrics
+	name     string
+	metrics  metrics
+	registry *registry
+}
+
+// registry holds all loggers created from the same root logger.
+type registry struct {
+	mu      sync.Mutex
+	loggers map[string]*logger
+	out     io.Writer
+	opts    options
+}
+
+func New(w io.Writer, level logrus.Level, opts ...Option) Logger {
+	r := &registry{
+		loggers: make(map[string]*logger),
+		// loggers of all components write to the same writer
+		out: &syncWriter{w: w},
+	}
+	for _, o := range opts {
+		o(&r.opts)
+	}
+	l := r.newLogger(RootName, level, newMetrics())
+	r.loggers[RootName] = l
+	return l
 }
 
-func New(w io.Writer, level logrus.Level) Logger {
+func (r *registry) newLogger(name string, level logrus.Level, metrics metrics) *logger {
 	l := logrus.New()
-	l.SetOutput(w)
+	l.SetOutput(r.out)
 	l.SetLevel(level)
-	l.Formatter = &logrus.TextFormatter{
+
+	var f logrus.Formatter = &logrus.TextFormatter{
 		FullTimestamp: true,
 	}
-	metrics := newMetrics()
+	if r.opts.json {
+		f = &logrus.JSONFormatter{
+			TimestampFormat: time.RFC3339Nano,
+		}
+	}
+	l.Formatter = &formatter{
+		Formatter: f,
+		name:      name,
+		sampler:   newSampler(r.opts.sampleFirst, r.opts.sampleThereafter),
+	}
 	l.AddHook(metrics)
 	return &logger{
-		Logger:  l,
-		metrics: metrics,
+		Logger:   l,
+		name:     name,
+		metrics:  metrics,
+		registry: r,
 	}
 }
 
 func (l *logger) NewEntry() *logrus.Entry {
 	return logrus.NewEntry(l.Logger)
 }
-// Copyright 2020 The Swarm Authors. All rights reserved.
-// Use of this source code is governed by a BSD-style
-// license that can be found in the LICENSE file.
 
-// Package logging provides the logger interface abstraction
-// and implementation for Bee. It uses logrus under the hood.
-package logging
+func (l *logger) Named(name string) Logger {
+	if l.name != RootName {
+		name = l.name + "/" + name
+	}
 
-import (
-	"io"
+	r := l.registry
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-	"github.com/sirupsen/logrus"
-)
+	if n, ok := r.loggers[name]; ok {
+		return n
+	}
+	n := r.newLogger(name, r.loggers[RootName].GetLevel(), l.metrics)
+	r.loggers[name] = n
+	return n
+}
 
-type Logger interface {
-	Tracef(format string, args ...interface{})
-	Trace(args ...interface{})
-	Debugf(format string, args ...interface{})
-	Debug(args ...interface{})
-	Infof(format string, args ...interface{})
-	Info(args ...interface{})
-	Warningf(format string, args ...interface{})
-	Warning(args ...interface{})
-	Errorf(format string, args ...interface{})
-	Error(args ...interface{})
-	WithField(key string, value interface{}) *logrus.Entry
-	WithFields(fields logrus.Fields) *logrus.Entry
-	WriterLevel(logrus.Level) *io.PipeWriter
-	NewEntry() *logrus.Entry
+func (l *logger) NamedLevels() map[string]logrus.Level {
+	r := l.registry
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	levels := make(map[string]logrus.Level, len(r.loggers))
+	for name, n := range r.loggers {
+		levels[name] = n.GetLevel()
+	}
+	return levels
 }
 
-type logger struct {
-	*logrus.Logger
-	metrics metrics
+func (l *logger) SetNamedLevel(name string, level logrus.Level) error {
+	r := l.registry
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n, ok := r.loggers[name]
+	if !ok {
+		return ErrUnknownLogger
+	}
+	n.SetLevel(level)
+	return nil
 }
 
-func New(w io.Writer, level logrus.Level) Logger {
-	l := logrus.New()
-	l.SetOutput(w)
-	l.SetLevel(level)
-	l.Formatter = &logrus.TextFormatter{
-		FullTimestamp: true,
+// formatter adds the component field to the entries of named loggers and
+// drops the entries which are not sampled.
+type formatter struct {
+	logrus.Formatter
+	name    string
+	sampler *sampler
+}
+
+func (f *formatter) Format(e *logrus.Entry) ([]byte, error) {
+	if !f.sampler.sample(e.Level) {
+		return nil, nil
 	}
-	metrics := newMetrics()
-	l.AddHook(metrics)
-	return &logger{
-		Logger:  l,
-		metrics: metrics,
+	if f.name != RootName {
+		// the data may be shared by the entries of a logrus.Entry
+		data := make(logrus.Fields, len(e.Data)+1)
+		for k, v := range e.Data {
+			data[k] = v
+		}
+		data[ComponentField] = f.name
+		e.Data = data
 	}
+	return f.Formatter.Format(e)
 }
 
-func (l *logger) NewEntry() *logrus.Entry {
-	return logrus.NewEntry(l.Logger)
+// sampler counts the debug and trace entries every second in order to keep
+// only a sample of them when there are too many.
+type sampler struct {
+	first      uint64
+	thereafter uint64
+
+	mu     sync.Mutex
+	counts map[logrus.Level]uint64
+	reset  time.Time
+}
+
+func newSampler(first, thereafter int) *sampler {
+	if first <= 0 {
+		return nil
+	}
+	if thereafter <= 0 {
+		thereafter = 1
+	}
+	return &sampler{
+		first:      uint64(first),
+		thereafter: uint64(thereafter),
+		counts:     make(map[logrus.Level]uint64),
+	}
+}
+
+func (s *sampler) sample(level logrus.Level) bool {
+	if s == nil || level < logrus.DebugLevel {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now := time.Now(); now.Sub(s.reset) >= time.Second {
+		s.counts = make(map[logrus.Level]uint64)
+		s.reset = now
+	}
+	s.counts[level]++
+	n := s.counts[level]
+	return n <= s.first || (n-s.first)%s.thereafter == 0
+}
+
+// syncWriter serializes the writes of loggers which share a writer.
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (w *syncWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.w.Write(p)
 }
@@ -0,0 +1,86 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package logging_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/sirupsen/logrus"
+)
+
+func TestNamedJSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.New(&buf, logrus.InfoLevel, logging.WithJSONFormat())
+
+	logger.Named("pushsync").WithField(logging.PeerField, "abcd").Info("pushed")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range map[string]string{
+		"msg":                  "pushed",
+		"level":                "info",
+		logging.ComponentField: "pushsync",
+		logging.PeerField:      "abcd",
+	} {
+		if entry[k] != v {
+			t.Errorf("got %s %v, want %q", k, entry[k], v)
+		}
+	}
+}
+
+func TestNamedLevels(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.New(&buf, logrus.InfoLevel)
+	pushsync := logger.Named("pushsync")
+	kademlia := logger.Named("kademlia")
+
+	if pushsync != logger.Named("pushsync") {
+		t.Fatal("got a new logger for the same name")
+	}
+
+	if err := logger.SetNamedLevel("pushsync", logrus.DebugLevel); err != nil {
+		t.Fatal(err)
+	}
+	pushsync.Debug("pushsync debug")
+	kademlia.Debug("kademlia debug")
+	logger.Debug("root debug")
+
+	out := buf.String()
+	if !strings.Contains(out, "pushsync debug") {
+		t.Error("debug message of pushsync not logged")
+	}
+	if strings.Contains(out, "kademlia debug") || strings.Contains(out, "root debug") {
+		t.Errorf("debug messages of other loggers logged: %s", out)
+	}
+
+	if err := logger.SetNamedLevel("pullsync", logrus.DebugLevel); !errors.Is(err, logging.ErrUnknownLogger) {
+		t.Fatalf("got error %v, want %v", err, logging.ErrUnknownLogger)
+	}
+}
+
+func TestSampling(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.New(&buf, logrus.DebugLevel, logging.WithSampling(2, 3))
+
+	for i := 0; i < 8; i++ {
+		logger.Debug("sampled")
+		logger.Info("not sampled")
+	}
+
+	// the first 2 and every third after them
+	if n := strings.Count(buf.String(), "msg=sampled"); n != 4 {
+		t.Fatalf("got %d debug messages, want %d", n, 4)
+	}
+	if n := strings.Count(buf.String(), `msg="not sampled"`); n != 8 {
+		t.Fatalf("got %d info messages, want %d", n, 8)
+	}
+}
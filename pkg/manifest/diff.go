@@ -0,0 +1,126 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package manifest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ethersphere/bee/pkg/file"
+	"github.com/ethersphere/bee/pkg/manifest/mantaray"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// ErrIncompatibleManifests is returned when two manifests can not be merged
+// because they use different reference sizes.
+var ErrIncompatibleManifests = errors.New("manifest: incompatible manifests")
+
+// ChangeType describes how a manifest path differs between two manifests.
+type ChangeType string
+
+const (
+	ChangeAdded    ChangeType = "added"
+	ChangeRemoved  ChangeType = "removed"
+	ChangeModified ChangeType = "modified"
+)
+
+// Change represents a single path that differs between two manifests.
+type Change struct {
+	Path string
+	Type ChangeType
+	// Old is the entry in the first manifest, nil for added paths.
+	Old Entry
+	// New is the entry in the second manifest, nil for removed paths.
+	New Entry
+	// MetadataChanged holds the sorted metadata keys whose values differ
+	// between Old and New.
+	MetadataChanged []string
+}
+
+// Diff returns all paths that were added, removed or modified in the
+// mantaray manifest referenced by b compared to the one referenced by a.
+// Subtrees shared by both manifests are not traversed.
+func Diff(ctx context.Context, ls file.LoadSaver, a, b swarm.Address) ([]Change, error) {
+	var changes []Change
+
+	diffFn := func(path []byte, typ mantaray.DiffType, na, nb *mantaray.Node) error {
+		c := Change{
+			Path: string(path),
+			Type: ChangeType(typ.String()),
+		}
+		if na != nil {
+			c.Old = NewEntry(swarm.NewAddress(na.Entry()), na.Metadata())
+		}
+		if nb != nil {
+			c.New = NewEntry(swarm.NewAddress(nb.Entry()), nb.Metadata())
+		}
+		if c.Old != nil && c.New != nil {
+			c.MetadataChanged = metadataChanged(c.Old.Metadata(), c.New.Metadata())
+		}
+		changes = append(changes, c)
+		return nil
+	}
+
+	err := mantaray.Diff(ctx, mantaray.NewNodeRef(a.Bytes()), mantaray.NewNodeRef(b.Bytes()), ls, diffFn)
+	if err != nil {
+		return nil, fmt.Errorf("manifest diff: %w", err)
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes, nil
+}
+
+// Merge overlays every entry of the mantaray manifest referenced by overlay
+// onto the manifest referenced by base, replacing entries on the same path,
+// and stores the result using the provided LoadSaver.
+func Merge(ctx context.Context, ls file.LoadSaver, base, overlay swarm.Address) (swarm.Address, error) {
+	if len(base.Bytes()) != len(overlay.Bytes()) {
+		return swarm.ZeroAddress, ErrIncompatibleManifests
+	}
+
+	trie := mantaray.NewNodeRef(base.Bytes())
+
+	walker := func(path []byte, node *mantaray.Node, err error) error {
+		if err != nil {
+			return err
+		}
+		if !node.IsValueType() {
+			return nil
+		}
+		return trie.Add(ctx, path, node.Entry(), node.Metadata(), ls)
+	}
+
+	err := mantaray.NewNodeRef(overlay.Bytes()).WalkNode(ctx, []byte{}, ls, walker)
+	if err != nil {
+		return swarm.ZeroAddress, fmt.Errorf("manifest merge: %w", err)
+	}
+
+	if err := trie.Save(ctx, ls); err != nil {
+		return swarm.ZeroAddress, fmt.Errorf("manifest merge save: %w", err)
+	}
+
+	return swarm.NewAddress(trie.Reference()), nil
+}
+
+func metadataChanged(a, b map[string]string) []string {
+	var keys []string
+	for k, v := range a {
+		if w, ok := b[k]; !ok || v != w {
+			keys = append(keys, k)
+		}
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,79 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package manifest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethersphere/bee/pkg/file"
+	"github.com/ethersphere/bee/pkg/manifest/mantaray"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// DirEntry is a single entry directly contained in a manifest directory.
+type DirEntry struct {
+	// Path is the full path of the entry in the manifest. Directory paths
+	// end with a path separator.
+	Path  string
+	IsDir bool
+	// Entry holds the file entry, it is nil for directories.
+	Entry Entry
+}
+
+// WalkDirFunc is the type of the function called for each entry visited by
+// WalkDir.
+type WalkDirFunc func(DirEntry) error
+
+// WalkDir calls walkFn for every file and subdirectory directly contained
+// in the directory dir of the mantaray manifest referenced by reference, in
+// lexicographical order. The dir must be empty for the manifest root or end
+// with a path separator.
+func WalkDir(ctx context.Context, ls file.LoadSaver, reference swarm.Address, dir string, walkFn WalkDirFunc) error {
+	walker := func(path []byte, isDir bool, node *mantaray.Node) error {
+		e := DirEntry{
+			Path:  string(path),
+			IsDir: isDir,
+		}
+		if node != nil {
+			e.Entry = NewEntry(swarm.NewAddress(node.Entry()), node.Metadata())
+		}
+		return walkFn(e)
+	}
+
+	err := mantaray.NewNodeRef(reference.Bytes()).WalkDir(ctx, []byte(dir), ls, walker)
+	if err != nil {
+		return fmt.Errorf("manifest walk dir: %w", err)
+	}
+	return nil
+}
+
+// WalkFileFunc is the type of the function called for each file visited by
+// WalkFiles.
+type WalkFileFunc func(path string, entry Entry) error
+
+// WalkFiles calls walkFn for every file contained in the directory dir of
+// the mantaray manifest referenced by reference and in its subdirectories,
+// in lexicographical order of the paths. The dir must be empty for the
+// manifest root or end with a path separator.
+func WalkFiles(ctx context.Context, ls file.LoadSaver, reference swarm.Address, dir string, walkFn WalkFileFunc) error {
+	// the nodes loaded by the walks of the directories are kept in the root
+	root := mantaray.NewNodeRef(reference.Bytes())
+
+	var walk func(dir []byte) error
+	walk = func(dir []byte) error {
+		return root.WalkDir(ctx, dir, ls, func(path []byte, isDir bool, node *mantaray.Node) error {
+			if isDir {
+				return walk(path)
+			}
+			return walkFn(string(path), NewEntry(swarm.NewAddress(node.Entry()), node.Metadata()))
+		})
+	}
+
+	if err := walk([]byte(dir)); err != nil {
+		return fmt.Errorf("manifest walk files: %w", err)
+	}
+	return nil
+}
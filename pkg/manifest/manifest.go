@@ -9,6 +9,7 @@ package manifest
 import (
 	"context"
 	"errors"
+	"strconv"
 
 	"github.com/ethersphere/bee/pkg/file"
 	"github.com/ethersphere/bee/pkg/swarm"
@@ -22,6 +23,20 @@ const (
 	WebsiteErrorDocumentPathKey   = "website-error-document"
 	EntryMetadataContentTypeKey   = "Content-Type"
 	EntryMetadataFilenameKey      = "Filename"
+
+	// EntryMetadataRedirectKey holds the target of a redirect entry, either
+	// a path in the manifest or an external URL.
+	EntryMetadataRedirectKey = "Redirect"
+	// EntryMetadataRedirectStatusKey holds the HTTP status code used for a
+	// redirect entry. Entries without it are symbolic links.
+	EntryMetadataRedirectStatusKey = "Redirect-Status"
+	// EntryMetadataChunkingKey holds the chunking scheme of the entry
+	// content. Entries without it use fixed size chunking.
+	EntryMetadataChunkingKey = "Chunking"
+
+	// ChunkingCDC is the chunking scheme of content uploaded with content
+	// defined chunking, the entry references the index of its segments.
+	ChunkingCDC = "cdc"
 )
 
 var (
@@ -136,3 +151,29 @@ func (e *manifestEntry) Reference() swarm.Address {
 func (e *manifestEntry) Metadata() map[string]string {
 	return e.metadata
 }
+
+// NewRedirectEntry creates a new manifest entry which redirects to target,
+// a path in the manifest or an external URL. A zero status creates a
+// symbolic link which is resolved by the node instead of the client.
+func NewRedirectEntry(target string, status int) Entry {
+	metadata := map[string]string{
+		EntryMetadataRedirectKey: target,
+	}
+	if status != 0 {
+		metadata[EntryMetadataRedirectStatusKey] = strconv.Itoa(status)
+	}
+	return NewEntry(swarm.ZeroAddress, metadata)
+}
+
+// RedirectTarget returns the target and the status code of a redirect
+// entry. The ok result is false if the entry is not a redirect.
+func RedirectTarget(e Entry) (target string, status int, ok bool) {
+	target, ok = e.Metadata()[EntryMetadataRedirectKey]
+	if !ok {
+		return "", 0, false
+	}
+	if v, has := e.Metadata()[EntryMetadataRedirectStatusKey]; has {
+		status, _ = strconv.Atoi(v)
+	}
+	return target, status, true
+}
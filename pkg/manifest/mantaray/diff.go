@@ -0,0 +1,235 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mantaray
+
+import (
+	"bytes"
+	"context"
+	"sort"
+)
+
+// DiffType describes how a path differs between two node trees.
+type DiffType int
+
+const (
+	// DiffAdded marks a value present only in the second tree.
+	DiffAdded DiffType = iota + 1
+	// DiffRemoved marks a value present only in the first tree.
+	DiffRemoved
+	// DiffModified marks a value present in both trees with a different
+	// entry or metadata.
+	DiffModified
+)
+
+// String returns the lowercase name of the difference type.
+func (t DiffType) String() string {
+	switch t {
+	case DiffAdded:
+		return "added"
+	case DiffRemoved:
+		return "removed"
+	case DiffModified:
+		return "modified"
+	default:
+		return "unknown"
+	}
+}
+
+// DiffFunc is the type of the function called for each value path that
+// differs between the trees visited by Diff. For added paths a is nil and
+// for removed paths b is nil.
+type DiffFunc func(path []byte, typ DiffType, a, b *Node) error
+
+// Diff walks the node trees rooted at a and b in parallel, calling diffFn for
+// every value path that was added, removed or modified in b compared to a.
+// Subtrees that are referenced by the same address in both trees are skipped
+// without being loaded.
+func Diff(ctx context.Context, a, b *Node, l Loader, diffFn DiffFunc) error {
+	return diff(ctx, []byte{}, a, b, l, diffFn)
+}
+
+func diff(ctx context.Context, path []byte, a, b *Node, l Loader, diffFn DiffFunc) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if a.ref != nil && bytes.Equal(a.ref, b.ref) {
+		return nil
+	}
+
+	if a.forks == nil {
+		if err := a.load(ctx, l); err != nil {
+			return err
+		}
+	}
+	if b.forks == nil {
+		if err := b.load(ctx, l); err != nil {
+			return err
+		}
+	}
+
+	if err := diffValues(path, a, b, diffFn); err != nil {
+		return err
+	}
+
+	for _, k := range forkKeys(a, b) {
+		fa, fb := a.forks[k], b.forks[k]
+
+		switch {
+		case fb == nil:
+			err := walkValues(ctx, appendPath(path, fa.prefix), fa.Node, l, func(p []byte, n *Node) error {
+				return diffFn(p, DiffRemoved, n, nil)
+			})
+			if err != nil {
+				return err
+			}
+		case fa == nil:
+			err := walkValues(ctx, appendPath(path, fb.prefix), fb.Node, l, func(p []byte, n *Node) error {
+				return diffFn(p, DiffAdded, nil, n)
+			})
+			if err != nil {
+				return err
+			}
+		case bytes.Equal(fa.prefix, fb.prefix):
+			if err := diff(ctx, appendPath(path, fa.prefix), fa.Node, fb.Node, l, diffFn); err != nil {
+				return err
+			}
+		default:
+			// the trees branch at different positions below this fork,
+			// so the values of both subtrees have to be compared by path
+			if err := diffFlat(ctx, path, fa, fb, l, diffFn); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// diffFlat compares two forks with different prefixes by collecting all of
+// their values.
+func diffFlat(ctx context.Context, path []byte, fa, fb *fork, l Loader, diffFn DiffFunc) error {
+	collect := func(f *fork) (map[string]*Node, error) {
+		values := make(map[string]*Node)
+		err := walkValues(ctx, appendPath(path, f.prefix), f.Node, l, func(p []byte, n *Node) error {
+			values[string(p)] = n
+			return nil
+		})
+		return values, err
+	}
+
+	va, err := collect(fa)
+	if err != nil {
+		return err
+	}
+	vb, err := collect(fb)
+	if err != nil {
+		return err
+	}
+
+	paths := make([]string, 0, len(va)+len(vb))
+	for p := range va {
+		paths = append(paths, p)
+	}
+	for p := range vb {
+		if _, ok := va[p]; !ok {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		na, nb := va[p], vb[p]
+		switch {
+		case nb == nil:
+			err = diffFn([]byte(p), DiffRemoved, na, nil)
+		case na == nil:
+			err = diffFn([]byte(p), DiffAdded, nil, nb)
+		case !valuesEqual(na, nb):
+			err = diffFn([]byte(p), DiffModified, na, nb)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diffValues compares the values held by two nodes on the same path.
+func diffValues(path []byte, a, b *Node, diffFn DiffFunc) error {
+	switch {
+	case a.IsValueType() && b.IsValueType():
+		if !valuesEqual(a, b) {
+			return diffFn(path, DiffModified, a, b)
+		}
+	case a.IsValueType():
+		return diffFn(path, DiffRemoved, a, nil)
+	case b.IsValueType():
+		return diffFn(path, DiffAdded, nil, b)
+	}
+	return nil
+}
+
+// walkValues calls fn for every value node in the tree rooted at n.
+func walkValues(ctx context.Context, path []byte, n *Node, l Loader, fn func([]byte, *Node) error) error {
+	return walkNode(ctx, path, l, n, func(p []byte, node *Node, err error) error {
+		if err != nil {
+			return err
+		}
+		if node.IsValueType() {
+			return fn(p, node)
+		}
+		return nil
+	})
+}
+
+// valuesEqual reports whether two value nodes hold the same entry and
+// metadata. Empty entries are equal to zero-filled ones, as the entry is
+// padded to the reference size when a node is persisted.
+func valuesEqual(a, b *Node) bool {
+	if !bytes.Equal(a.entry, b.entry) && !(isZero(a.entry) && isZero(b.entry)) {
+		return false
+	}
+	if len(a.metadata) != len(b.metadata) {
+		return false
+	}
+	for k, v := range a.metadata {
+		if w, ok := b.metadata[k]; !ok || v != w {
+			return false
+		}
+	}
+	return true
+}
+
+func isZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// forkKeys returns the sorted union of fork keys of both nodes.
+func forkKeys(a, b *Node) []byte {
+	keys := make([]byte, 0, len(a.forks)+len(b.forks))
+	for k := range a.forks {
+		keys = append(keys, k)
+	}
+	for k := range b.forks {
+		if _, ok := a.forks[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+func appendPath(path, prefix []byte) []byte {
+	p := append(path[:0:0], path...)
+	return append(p, prefix...)
+}
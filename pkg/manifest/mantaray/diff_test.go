@@ -0,0 +1,185 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mantaray_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/manifest/mantaray"
+)
+
+func TestDiff(t *testing.T) {
+	type entry struct {
+		path  string
+		value string
+		meta  map[string]string
+	}
+
+	for _, tc := range []struct {
+		name     string
+		a, b     []entry
+		expected map[string]mantaray.DiffType
+	}{
+		{
+			name: "identical",
+			a: []entry{
+				{path: "index.html", value: "index"},
+				{path: "img/1.png", value: "1"},
+			},
+			b: []entry{
+				{path: "index.html", value: "index"},
+				{path: "img/1.png", value: "1"},
+			},
+			expected: map[string]mantaray.DiffType{},
+		},
+		{
+			name: "added removed modified",
+			a: []entry{
+				{path: "index.html", value: "index"},
+				{path: "img/1.png", value: "1"},
+				{path: "img/2.png", value: "2"},
+				{path: "robots.txt", value: "robots"},
+			},
+			b: []entry{
+				{path: "index.html", value: "index2"},
+				{path: "img/1.png", value: "1"},
+				{path: "img/3.png", value: "3"},
+				{path: "robots.txt", value: "robots", meta: map[string]string{"Content-Type": "text/plain"}},
+				{path: "about.html", value: "about"},
+			},
+			expected: map[string]mantaray.DiffType{
+				"index.html": mantaray.DiffModified,
+				"img/2.png":  mantaray.DiffRemoved,
+				"img/3.png":  mantaray.DiffAdded,
+				"robots.txt": mantaray.DiffModified,
+				"about.html": mantaray.DiffAdded,
+			},
+		},
+		{
+			name: "different prefixes",
+			a: []entry{
+				{path: "docs/aaa.html", value: "a"},
+				{path: "docs/aab.html", value: "b"},
+			},
+			b: []entry{
+				{path: "docs/aaa.html", value: "a"},
+				{path: "docs/abc.html", value: "c"},
+			},
+			expected: map[string]mantaray.DiffType{
+				"docs/aab.html": mantaray.DiffRemoved,
+				"docs/abc.html": mantaray.DiffAdded,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			ls := newMockLoadSaver()
+
+			create := func(entries []entry) *mantaray.Node {
+				n := mantaray.New()
+				for _, e := range entries {
+					v := append(make([]byte, 32-len(e.value)), e.value...)
+					if err := n.Add(ctx, []byte(e.path), v, e.meta, ls); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if err := n.Save(ctx, ls); err != nil {
+					t.Fatal(err)
+				}
+				return mantaray.NewNodeRef(n.Reference())
+			}
+
+			a, b := create(tc.a), create(tc.b)
+
+			got := make(map[string]mantaray.DiffType)
+			err := mantaray.Diff(ctx, a, b, ls, func(path []byte, typ mantaray.DiffType, na, nb *mantaray.Node) error {
+				if _, ok := got[string(path)]; ok {
+					t.Fatalf("path %q reported twice", path)
+				}
+				got[string(path)] = typ
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(got) != len(tc.expected) {
+				t.Fatalf("got %d changes %v, want %d", len(got), sortedKeys(got), len(tc.expected))
+			}
+			for p, typ := range tc.expected {
+				if got[p] != typ {
+					t.Fatalf("path %q: got %v, want %v", p, got[p], typ)
+				}
+			}
+		})
+	}
+}
+
+func TestDiffSkipsSharedSubtrees(t *testing.T) {
+	ctx := context.Background()
+	ls := newMockLoadSaver()
+
+	n := mantaray.New()
+	for _, p := range []string{"img/1.png", "img/2.png", "index.html"} {
+		v := append(make([]byte, 32-len(p)), p...)
+		if err := n.Add(ctx, []byte(p), v, nil, ls); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := n.Save(ctx, ls); err != nil {
+		t.Fatal(err)
+	}
+	a := mantaray.NewNodeRef(n.Reference())
+
+	n = mantaray.NewNodeRef(n.Reference())
+	v := make([]byte, 32)
+	v[0] = 1
+	if err := n.Add(ctx, []byte("index.html"), v, nil, ls); err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Save(ctx, ls); err != nil {
+		t.Fatal(err)
+	}
+	b := mantaray.NewNodeRef(n.Reference())
+
+	loader := &countingLoader{Loader: ls}
+	var changed []string
+	err := mantaray.Diff(ctx, a, b, loader, func(path []byte, typ mantaray.DiffType, _, _ *mantaray.Node) error {
+		changed = append(changed, string(path))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changed) != 1 || changed[0] != "index.html" {
+		t.Fatalf("got changes %v, want [index.html]", changed)
+	}
+	// only the roots, the shared "i" prefix nodes and the index.html nodes
+	// are loaded, the unchanged img/ subtree is skipped
+	if loader.count != 6 {
+		t.Fatalf("got %d loads, want 6", loader.count)
+	}
+}
+
+type countingLoader struct {
+	mantaray.Loader
+	count int
+}
+
+func (l *countingLoader) Load(ctx context.Context, ref []byte) ([]byte, error) {
+	l.count++
+	return l.Loader.Load(ctx, ref)
+}
+
+func sortedKeys(m map[string]mantaray.DiffType) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -242,6 +242,9 @@ func (n *Node) UnmarshalBinary(data []byte) error {
 	if bytes.Equal(versionHash, version01HashBytes) {
 
 		refBytesSize := int(data[nodeHeaderSize-1])
+		if n.refBytesSize == 0 {
+			n.refBytesSize = refBytesSize
+		}
 
 		n.entry = append([]byte{}, data[nodeHeaderSize:nodeHeaderSize+refBytesSize]...)
 		offset := nodeHeaderSize + refBytesSize // skip entry
@@ -269,6 +272,9 @@ func (n *Node) UnmarshalBinary(data []byte) error {
 	} else if bytes.Equal(versionHash, version02HashBytes) {
 
 		refBytesSize := int(data[nodeHeaderSize-1])
+		if n.refBytesSize == 0 {
+			n.refBytesSize = refBytesSize
+		}
 
 		n.entry = append([]byte{}, data[nodeHeaderSize:nodeHeaderSize+refBytesSize]...)
 		offset := nodeHeaderSize + refBytesSize // skip entry
@@ -109,7 +109,6 @@ func (n *Node) makeWithMetadata() {
 	n.nodeType = n.nodeType | nodeTypeWithMetadata
 }
 
-//nolint:unused
 func (n *Node) makeNotValue() {
 	n.nodeType = (nodeTypeMask ^ nodeTypeValue) & n.nodeType
 }
@@ -123,7 +122,6 @@ func (n *Node) makeNotWithPathSeparator() {
 	n.nodeType = (nodeTypeMask ^ nodeTypeWithPathSeparator) & n.nodeType
 }
 
-//nolint:unused
 func (n *Node) makeNotWithMetadata() {
 	n.nodeType = (nodeTypeMask ^ nodeTypeWithMetadata) & n.nodeType
 }
@@ -206,6 +204,12 @@ func (n *Node) Add(ctx context.Context, path, entry []byte, metadata map[string]
 		return fmt.Errorf("invalid entry size: %d, expected: %d", len(entry), n.refBytesSize)
 	}
 
+	if n.forks == nil {
+		if err := n.load(ctx, ls); err != nil {
+			return err
+		}
+		n.ref = nil
+	}
 	if len(path) == 0 {
 		n.entry = entry
 		n.makeValue()
@@ -216,12 +220,6 @@ func (n *Node) Add(ctx context.Context, path, entry []byte, metadata map[string]
 		n.ref = nil
 		return nil
 	}
-	if n.forks == nil {
-		if err := n.load(ctx, ls); err != nil {
-			return err
-		}
-		n.ref = nil
-	}
 	f := n.forks[path[0]]
 	if f == nil {
 		nn := New()
@@ -317,10 +315,33 @@ func (n *Node) Remove(ctx context.Context, path []byte, ls LoadSaver) error {
 	rest := path[len(f.prefix):]
 	if len(rest) == 0 {
 		// full path matched
-		delete(n.forks, path[0])
+		if f.Node.forks == nil {
+			if err := f.Node.load(ctx, ls); err != nil {
+				return err
+			}
+		}
+		if len(f.Node.forks) == 0 {
+			delete(n.forks, path[0])
+		} else {
+			// keep the entries whose paths continue the path
+			f.Node.entry = nil
+			f.Node.metadata = nil
+			f.Node.makeNotValue()
+			f.Node.makeNotWithMetadata()
+			f.Node.ref = nil
+		}
+		n.ref = nil
 		return nil
 	}
-	return f.Node.Remove(ctx, rest, ls)
+	if err := f.Node.Remove(ctx, rest, ls); err != nil {
+		return err
+	}
+	if len(f.Node.forks) == 0 && !f.Node.IsValueType() {
+		// prune the fork which no longer leads to any entry
+		delete(n.forks, path[0])
+	}
+	n.ref = nil
+	return nil
 }
 
 func common(a, b []byte) (c []byte) {
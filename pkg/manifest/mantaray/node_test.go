@@ -198,6 +198,10 @@ func TestRemove(t *testing.T) {
 		name     string
 		toAdd    []mantaray.NodeEntry
 		toRemove [][]byte
+		// kept are the paths which are found after the removals
+		kept [][]byte
+		// pruned are the prefixes which are gone after the removals
+		pruned [][]byte
 	}{
 		{
 			name: "simple",
@@ -248,6 +252,61 @@ func TestRemove(t *testing.T) {
 				[]byte("img/2/test1.png"),
 			},
 		},
+		{
+			name: "continued-path-is-kept",
+			toAdd: []mantaray.NodeEntry{
+				{
+					Path: []byte("index.html"),
+				},
+				{
+					Path: []byte("index.html.bak"),
+				},
+			},
+			toRemove: [][]byte{
+				[]byte("index.html"),
+			},
+			kept: [][]byte{
+				[]byte("index.html.bak"),
+			},
+		},
+		{
+			name: "nested-path-is-kept",
+			toAdd: []mantaray.NodeEntry{
+				{
+					Path: []byte("a"),
+				},
+				{
+					Path: []byte("a/b"),
+				},
+			},
+			toRemove: [][]byte{
+				[]byte("a"),
+			},
+			kept: [][]byte{
+				[]byte("a/b"),
+			},
+		},
+		{
+			name: "emptied-prefix-is-pruned",
+			toAdd: []mantaray.NodeEntry{
+				{
+					Path: []byte("index.html"),
+				},
+				{
+					Path: []byte("img/2/test1.png"),
+				},
+				{
+					Path: []byte("img/2/test2.png"),
+				},
+			},
+			toRemove: [][]byte{
+				[]byte("img/2/test1.png"),
+				[]byte("img/2/test2.png"),
+			},
+			pruned: [][]byte{
+				[]byte("img/"),
+			},
+		},
 	} {
 		ctx := context.Background()
 		t.Run(tc.name, func(t *testing.T) {
@@ -289,6 +348,21 @@ func TestRemove(t *testing.T) {
 				}
 			}
 
+			for _, p := range tc.kept {
+				if _, err := n.Lookup(ctx, p, nil); err != nil {
+					t.Fatalf("expected no error for %s, got %v", p, err)
+				}
+			}
+
+			for _, p := range tc.pruned {
+				has, err := n.HasPrefix(ctx, p, nil)
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if has {
+					t.Fatalf("expected prefix %s to be pruned", p)
+				}
+			}
 		})
 	}
 }
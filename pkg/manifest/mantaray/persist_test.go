@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha256"
+	"errors"
 	"sync"
 	"testing"
 
@@ -60,6 +61,47 @@ func TestPersistIdempotence(t *testing.T) {
 	}
 }
 
+func TestPersistRemove(t *testing.T) {
+	ctx := context.Background()
+	ls := newMockLoadSaver()
+
+	n := mantaray.New()
+	paths := [][]byte{
+		[]byte("aaaaaa"),
+		[]byte("aaaaab"),
+		[]byte("abbbb"),
+		[]byte("b"),
+	}
+	for _, p := range paths {
+		var v [32]byte
+		copy(v[:], p)
+		if err := n.Add(ctx, p, v[:], nil, ls); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	if err := n.Save(ctx, ls); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// the removal of a nested path changes the reference of the root
+	if err := n.Remove(ctx, []byte("aaaaab"), ls); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := n.Save(ctx, ls); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	loaded := mantaray.NewNodeRef(n.Reference())
+	if _, err := loaded.Lookup(ctx, []byte("aaaaab"), ls); !errors.Is(err, mantaray.ErrNotFound) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+	for _, p := range [][]byte{[]byte("aaaaaa"), []byte("abbbb"), []byte("b")} {
+		if _, err := loaded.Lookup(ctx, p, ls); err != nil {
+			t.Fatalf("expected no error for %s, got %v", p, err)
+		}
+	}
+}
+
 type addr [32]byte
 type mockLoadSaver struct {
 	mtx   sync.Mutex
@@ -4,7 +4,11 @@
 
 package mantaray
 
-import "context"
+import (
+	"bytes"
+	"context"
+	"sort"
+)
 
 // WalkNodeFunc is the type of the function called for each node visited
 // by WalkNode.
@@ -115,3 +119,82 @@ func (n *Node) Walk(ctx context.Context, root []byte, l Loader, walkFn WalkFunc)
 	}
 	return walk(ctx, root, []byte{}, l, node, walkFn)
 }
+
+// WalkDirFunc is the type of the function called for each entry visited by
+// WalkDir. For files node is the value node of the entry, for directories
+// it is nil.
+type WalkDirFunc func(path []byte, isDir bool, node *Node) error
+
+// walkDir recursively descends the forks that lead to or lie below dir,
+// calling walkFn for the entries directly contained in dir.
+func walkDir(ctx context.Context, path, dir []byte, l Loader, n *Node, lastDir *[]byte, walkFn WalkDirFunc) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if len(path) > len(dir) {
+		rel := path[len(dir):]
+		if rel[0] == PathSeparator {
+			// empty path segment, such as the root metadata path
+			return nil
+		}
+		if i := bytes.IndexByte(rel, PathSeparator); i >= 0 {
+			d := path[:len(dir)+i+1]
+			if bytes.Equal(d, *lastDir) {
+				return nil
+			}
+			*lastDir = append((*lastDir)[:0], d...)
+			return walkFn(append(d[:0:0], d...), true, nil)
+		}
+	}
+
+	if n.forks == nil {
+		if err := n.load(ctx, l); err != nil {
+			return err
+		}
+	}
+
+	if len(path) > len(dir) && n.IsValueType() {
+		if err := walkFn(append(path[:0:0], path...), false, n); err != nil {
+			return err
+		}
+	}
+
+	keys := make([]byte, 0, len(n.forks))
+	for k := range n.forks {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	for _, k := range keys {
+		f := n.forks[k]
+		nextPath := append(path[:0:0], path...)
+		nextPath = append(nextPath, f.prefix...)
+
+		if len(nextPath) <= len(dir) {
+			if !bytes.HasPrefix(dir, nextPath) {
+				continue
+			}
+		} else if !bytes.HasPrefix(nextPath, dir) {
+			continue
+		}
+
+		if err := walkDir(ctx, nextPath, dir, l, f.Node, lastDir, walkFn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WalkDir walks the entries directly contained in the directory dir in
+// lexicographical order, calling walkFn for each file and subdirectory.
+// Subdirectories are reported once, with a trailing path separator, and
+// are not descended into. The dir must be empty or end with a path
+// separator.
+func (n *Node) WalkDir(ctx context.Context, dir []byte, l Loader, walkFn WalkDirFunc) error {
+	var lastDir []byte
+	return walkDir(ctx, []byte{}, dir, l, n, &lastDir, walkFn)
+}
@@ -249,3 +249,80 @@ func TestWalk(t *testing.T) {
 		})
 	}
 }
+
+func TestWalkDir(t *testing.T) {
+	toAdd := [][]byte{
+		[]byte("index.html"),
+		[]byte("img-old.png"),
+		[]byte("img/test/"),
+		[]byte("img/test/oho.png"),
+		[]byte("img/test/old/test.png"),
+		[]byte("img/1.png"),
+		[]byte("img2.png"),
+		[]byte("robots.txt"),
+	}
+
+	for _, tc := range []struct {
+		name     string
+		dir      []byte
+		expected []string
+	}{
+		{
+			name:     "root",
+			dir:      []byte{},
+			expected: []string{"img-old.png", "img/", "img2.png", "index.html", "robots.txt"},
+		},
+		{
+			name:     "subdirectory",
+			dir:      []byte("img/"),
+			expected: []string{"img/1.png", "img/test/"},
+		},
+		{
+			name:     "nested",
+			dir:      []byte("img/test/"),
+			expected: []string{"img/test/oho.png", "img/test/old/"},
+		},
+		{
+			name:     "missing",
+			dir:      []byte("css/"),
+			expected: nil,
+		},
+	} {
+		ctx := context.Background()
+
+		n := mantaray.New()
+		for _, c := range toAdd {
+			e := append(make([]byte, 32-len(c)), c...)
+			if err := n.Add(ctx, c, e, nil, nil); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		}
+		ls := newMockLoadSaver()
+		if err := n.Save(ctx, ls); err != nil {
+			t.Fatal(err)
+		}
+
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			walker := func(path []byte, isDir bool, node *mantaray.Node) error {
+				if isDir != bytes.HasSuffix(path, []byte{mantaray.PathSeparator}) {
+					return fmt.Errorf("path %s: unexpected directory flag %v", path, isDir)
+				}
+				if !isDir && node == nil {
+					return fmt.Errorf("path %s: missing value node", path)
+				}
+				got = append(got, string(path))
+				return nil
+			}
+
+			err := mantaray.NewNodeRef(n.Reference()).WalkDir(ctx, tc.dir, ls, walker)
+			if err != nil {
+				t.Fatalf("no error expected, found: %s", err)
+			}
+
+			if fmt.Sprint(got) != fmt.Sprint(tc.expected) {
+				t.Fatalf("got %v, want %v", got, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package manifest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethersphere/bee/pkg/file"
+	"github.com/ethersphere/bee/pkg/manifest/mantaray"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// RewriteFunc returns the new reference of a manifest entry.
+type RewriteFunc func(path string, entry Entry) (swarm.Address, error)
+
+// Rewrite creates a copy of the mantaray manifest referenced by reference,
+// loaded with src, where the reference of every entry is replaced by the
+// result of rewriteFn. Entries without a reference, like the root metadata
+// and redirects, are copied as they are. The new manifest is stored with dst.
+func Rewrite(ctx context.Context, src, dst file.LoadSaver, reference swarm.Address, encrypted bool, rewriteFn RewriteFunc) (swarm.Address, error) {
+	m, err := NewMantarayManifest(dst, encrypted)
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+
+	walker := func(path []byte, node *mantaray.Node, err error) error {
+		if err != nil {
+			return err
+		}
+		if !node.IsValueType() {
+			return nil
+		}
+
+		ref := swarm.ZeroAddress
+		if !isZeroReference(node.Entry()) {
+			ref, err = rewriteFn(string(path), NewEntry(swarm.NewAddress(node.Entry()), node.Metadata()))
+			if err != nil {
+				return fmt.Errorf("rewrite %q: %w", path, err)
+			}
+		}
+		return m.Add(ctx, string(path), NewEntry(ref, node.Metadata()))
+	}
+
+	err = mantaray.NewNodeRef(reference.Bytes()).WalkNode(ctx, []byte{}, src, walker)
+	if err != nil {
+		return swarm.ZeroAddress, fmt.Errorf("manifest rewrite: %w", err)
+	}
+
+	ref, err := m.Store(ctx)
+	if err != nil {
+		return swarm.ZeroAddress, fmt.Errorf("manifest rewrite save: %w", err)
+	}
+	return ref, nil
+}
+
+// isZeroReference returns true if the entry has no reference. Empty entries
+// are padded with zeros when a manifest is stored.
+func isZeroReference(entry []byte) bool {
+	for _, b := range entry {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
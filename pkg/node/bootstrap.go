@@ -103,6 +103,7 @@ func bootstrapNode(
 		WelcomeMessage: o.WelcomeMessage,
 		FullNode:       false,
 		Transaction:    txHash,
+		OverlayNonce:   o.OverlayNonce,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("p2p service: %w", err)
@@ -20,6 +20,7 @@ import (
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/ethersphere/bee/pkg/config"
 	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/events"
 	"github.com/ethersphere/bee/pkg/logging"
 	"github.com/ethersphere/bee/pkg/p2p/libp2p"
 	"github.com/ethersphere/bee/pkg/postage/postagecontract"
@@ -240,6 +241,7 @@ func InitSwap(
 	priceOracleAddress string,
 	chainID int64,
 	transactionService transaction.Service,
+	eventBus *events.Bus,
 ) (*swap.Service, priceoracle.Service, error) {
 
 	var currentPriceOracleAddress common.Address
@@ -274,6 +276,7 @@ func InitSwap(
 		cashoutService,
 		accounting,
 		cashoutAddress,
+		eventBus,
 	)
 
 	swapProtocol.SetSwap(swapService)
@@ -36,6 +36,7 @@ import (
 	"github.com/ethersphere/bee/pkg/pss"
 	"github.com/ethersphere/bee/pkg/pushsync"
 	mockPushsync "github.com/ethersphere/bee/pkg/pushsync/mock"
+	"github.com/ethersphere/bee/pkg/resumable"
 	"github.com/ethersphere/bee/pkg/settlement/pseudosettle"
 	"github.com/ethersphere/bee/pkg/settlement/swap/chequebook"
 	mockchequebook "github.com/ethersphere/bee/pkg/settlement/swap/chequebook/mock"
@@ -165,7 +166,7 @@ func NewDevBee(logger logging.Logger, o *DevOptions) (b *DevBee, err error) {
 			}),
 		)
 
-		debugAPIService = debugapi.New(mockKey.PublicKey, mockKey.PublicKey, overlayEthAddress, logger, tracer, o.CORSAllowedOrigins, big.NewInt(0), mockTransaction, chainBackend, o.Restricted, authenticator, false, debugapi.DevMode, 1)
+		debugAPIService = debugapi.New(mockKey.PublicKey, mockKey.PublicKey, overlayEthAddress, logger, tracer, nil, o.CORSAllowedOrigins, big.NewInt(0), mockTransaction, chainBackend, o.Restricted, authenticator, false, debugapi.DevMode, 1)
 		debugAPIServer := &http.Server{
 			IdleTimeout:       30 * time.Second,
 			ReadHeaderTimeout: 3 * time.Second,
@@ -209,6 +210,7 @@ func NewDevBee(logger logging.Logger, o *DevOptions) (b *DevBee, err error) {
 
 	pssService := pss.New(mockKey, logger)
 	b.pssCloser = pssService
+	pssService.SetMailbox(storer, stateStore)
 
 	pssService.SetPushSyncer(mockPushsync.New(func(ctx context.Context, chunk swarm.Chunk) (*pushsync.Receipt, error) {
 		pssService.TryUnwrap(chunk)
@@ -294,7 +296,7 @@ func NewDevBee(logger logging.Logger, o *DevOptions) (b *DevBee, err error) {
 
 	feedFactory := factory.New(storer)
 
-	apiService, _ := api.New(tagService, storer, nil, pssService, traversalService, pinningService, feedFactory, post, postageContract, &mock.Steward{}, signer, authenticator, logger, tracer, api.Options{
+	apiService, _ := api.New(tagService, resumable.New(stateStore, crypto.EncodeSecp256k1PrivateKey(mockKey)), storer, nil, pssService, traversalService, pinningService, feedFactory, post, postageContract, &mock.Steward{}, signer, authenticator, logger, tracer, api.Options{
 		CORSAllowedOrigins: o.CORSAllowedOrigins,
 		WsPingPeriod:       60 * time.Second,
 		Restricted:         o.Restricted,
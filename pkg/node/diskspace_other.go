@@ -0,0 +1,13 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !darwin && !dragonfly && !freebsd && !linux && !windows
+// +build !darwin,!dragonfly,!freebsd,!linux,!windows
+
+package node
+
+// freeDiskSpace is not supported on this platform.
+func freeDiskSpace(string) (uint64, error) {
+	return 0, errDiskSpaceUnsupported
+}
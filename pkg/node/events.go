@@ -0,0 +1,51 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package node
+
+import (
+	"context"
+
+	"github.com/ethersphere/bee/pkg/events"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/topology/kademlia"
+)
+
+// publishTopologyEvents publishes the topology event on every change of the
+// connected peers and the depth event when the depth changes, until the
+// context is done.
+func publishTopologyEvents(ctx context.Context, kad *kademlia.Kad, bus *events.Bus) {
+	c, unsubscribe := kad.SubscribePeersChange()
+	defer unsubscribe()
+
+	depth := kad.NeighborhoodDepth()
+	for {
+		select {
+		case <-c:
+			s := kad.Snapshot()
+			bus.Publish(events.TypeTopology, events.TopologyData{
+				Connected:  s.Connected,
+				Population: s.Population,
+				Depth:      s.Depth,
+			})
+			if s.Depth != depth {
+				depth = s.Depth
+				bus.Publish(events.TypeDepth, events.DepthData{Depth: depth})
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// radiusEventSetter publishes the radius event when the radius is set.
+type radiusEventSetter struct {
+	postage.RadiusSetter
+	events *events.Bus
+}
+
+func (r radiusEventSetter) SetRadius(radius uint8) {
+	r.RadiusSetter.SetRadius(radius)
+	r.events.Publish(events.TypeRadius, events.RadiusData{Radius: radius})
+}
@@ -10,6 +10,7 @@ package node
 import (
 	"context"
 	"crypto/ecdsa"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -34,7 +35,10 @@ import (
 	"github.com/ethersphere/bee/pkg/chainsyncer"
 	"github.com/ethersphere/bee/pkg/config"
 	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/dav"
 	"github.com/ethersphere/bee/pkg/debugapi"
+	"github.com/ethersphere/bee/pkg/events"
+	"github.com/ethersphere/bee/pkg/feeds"
 	"github.com/ethersphere/bee/pkg/feeds/factory"
 	"github.com/ethersphere/bee/pkg/hive"
 	"github.com/ethersphere/bee/pkg/localstore"
@@ -59,7 +63,9 @@ import (
 	"github.com/ethersphere/bee/pkg/pusher"
 	"github.com/ethersphere/bee/pkg/pushsync"
 	"github.com/ethersphere/bee/pkg/resolver/multiresolver"
+	"github.com/ethersphere/bee/pkg/resumable"
 	"github.com/ethersphere/bee/pkg/retrieval"
+	"github.com/ethersphere/bee/pkg/s3"
 	"github.com/ethersphere/bee/pkg/settlement/pseudosettle"
 	"github.com/ethersphere/bee/pkg/settlement/swap"
 	"github.com/ethersphere/bee/pkg/settlement/swap/chequebook"
@@ -90,9 +96,12 @@ type Bee struct {
 	apiCloser                io.Closer
 	apiServer                *http.Server
 	debugAPIServer           *http.Server
+	s3Server                 *http.Server
+	davServer                *http.Server
 	resolverCloser           io.Closer
 	errorLogWriter           *io.PipeWriter
 	tracerCloser             io.Closer
+	eventBusCloser           io.Closer
 	tagsCloser               io.Closer
 	stateStoreCloser         io.Closer
 	localstoreCloser         io.Closer
@@ -114,6 +123,16 @@ type Bee struct {
 	chainSyncerCloser        io.Closer
 	shutdownInProgress       bool
 	shutdownMutex            sync.Mutex
+
+	// The following are the subsystems whose options can be changed while
+	// the node runs.
+	p2pPeers        p2p.Service
+	pricing         *pricing.Service
+	accounting      *accounting.Accounting
+	storer          *localstore.DB
+	resolver        *multiresolver.MultiResolver
+	apiService      api.Service
+	debugAPIService *debugapi.Service
 }
 
 type Options struct {
@@ -125,6 +144,10 @@ type Options struct {
 	DBDisableSeeksCompaction   bool
 	APIAddr                    string
 	DebugAPIAddr               string
+	S3Addr                     string
+	S3PostageBatchID           string
+	DAVAddr                    string
+	DAVPostageBatchID          string
 	Addr                       string
 	NATAddr                    string
 	EnableWS                   bool
@@ -139,6 +162,9 @@ type Options struct {
 	PaymentTolerance           int64
 	PaymentEarly               int64
 	ResolverConnectionCfgs     []multiresolver.ConnectionConfig
+	ResolverCacheTTL           time.Duration
+	ResolverNegativeCacheTTL   time.Duration
+	PssMiningWorkers           int
 	RetrievalCaching           bool
 	GatewayMode                bool
 	BootnodeMode               bool
@@ -152,6 +178,7 @@ type Options struct {
 	ChainEnable                bool
 	Transaction                string
 	BlockHash                  string
+	OverlayNonce               []byte
 	PostageContractAddress     string
 	PriceOracleAddress         string
 	BlockTime                  uint64
@@ -167,6 +194,8 @@ type Options struct {
 	TokenEncryptionKey         string
 	AdminPasswordHash          string
 	UsePostageSnapshot         bool
+	ConfigInspector            debugapi.ConfigInspector
+	ConfigReloader             debugapi.ConfigReloader
 }
 
 const (
@@ -207,10 +236,13 @@ func NewBee(addr string, publicKey *ecdsa.PublicKey, signer crypto.Signer, netwo
 		warmupTime = 0
 	}
 
+	eventBus := events.New()
+
 	b = &Bee{
 		p2pCancel:      p2pCancel,
 		errorLogWriter: logger.WriterLevel(logrus.ErrorLevel),
 		tracerCloser:   tracerCloser,
+		eventBusCloser: eventBus,
 	}
 
 	stateStore, err := InitStateStore(logger, o.DataDir)
@@ -274,6 +306,31 @@ func NewBee(addr string, publicKey *ecdsa.PublicKey, signer crypto.Signer, netwo
 		logger.Info("starting with restricted APIs")
 	}
 
+	var s3BatchID []byte
+	if o.S3Addr != "" {
+		// the s3 gateway does not authenticate the requests
+		if o.Restricted {
+			return nil, errors.New("s3 gateway is not supported with restricted apis")
+		}
+		if s3BatchID, err = hex.DecodeString(o.S3PostageBatchID); err != nil || len(s3BatchID) != 32 {
+			return nil, fmt.Errorf("s3 gateway: invalid postage batch id %q", o.S3PostageBatchID)
+		}
+	}
+
+	var davBatchID []byte
+	if o.DAVAddr != "" {
+		// the webdav gateway does not authenticate the requests
+		if o.Restricted {
+			return nil, errors.New("webdav gateway is not supported with restricted apis")
+		}
+		// without a postage batch the gateway is read-only
+		if o.DAVPostageBatchID != "" {
+			if davBatchID, err = hex.DecodeString(o.DAVPostageBatchID); err != nil || len(davBatchID) != 32 {
+				return nil, fmt.Errorf("webdav gateway: invalid postage batch id %q", o.DAVPostageBatchID)
+			}
+		}
+	}
+
 	var debugAPIService *debugapi.Service
 
 	if o.DebugAPIAddr != "" {
@@ -297,7 +354,14 @@ func NewBee(addr string, publicKey *ecdsa.PublicKey, signer crypto.Signer, netwo
 		} else if !o.ChainEnable {
 			beeNodeMode = debugapi.UltraLightMode
 		}
-		debugAPIService = debugapi.New(*publicKey, pssPrivateKey.PublicKey, overlayEthAddress, logger, tracer, o.CORSAllowedOrigins, big.NewInt(int64(o.BlockTime)), transactionService, chainBackend, o.Restricted, authenticator, o.GatewayMode, beeNodeMode, chainID)
+		debugAPIService = debugapi.New(*publicKey, pssPrivateKey.PublicKey, overlayEthAddress, logger, tracer, eventBus, o.CORSAllowedOrigins, big.NewInt(int64(o.BlockTime)), transactionService, chainBackend, o.Restricted, authenticator, o.GatewayMode, beeNodeMode, chainID)
+		b.debugAPIService = debugAPIService
+		if o.ConfigInspector != nil {
+			debugAPIService.SetConfigInspector(o.ConfigInspector)
+		}
+		if o.ConfigReloader != nil {
+			debugAPIService.SetConfigReloader(o.ConfigReloader)
+		}
 
 		debugAPIListener, err := net.Listen("tcp", o.DebugAPIAddr)
 		if err != nil {
@@ -406,12 +470,22 @@ func NewBee(addr string, publicKey *ecdsa.PublicKey, signer crypto.Signer, netwo
 		return nil, fmt.Errorf("invalid transaction hash: %w", err)
 	}
 
-	blockHash, err = GetTxNextBlock(p2pCtx, logger, chainBackend, transactionMonitor, pollingInterval, txHash, o.BlockHash)
-	if err != nil {
-		return nil, fmt.Errorf("invalid block hash: %w", err)
+	// a mined overlay is derived from the nonce instead of the hash of the
+	// block following the transaction
+	if o.OverlayNonce != nil {
+		blockHash = o.OverlayNonce
+		logger.Infof("using the mined overlay nonce %x", blockHash)
+	} else {
+		blockHash, err = GetTxNextBlock(p2pCtx, logger, chainBackend, transactionMonitor, pollingInterval, txHash, o.BlockHash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid block hash: %w", err)
+		}
 	}
 
 	swarmAddress, err := crypto.NewOverlayAddress(*pubKey, networkID, blockHash)
+	if err != nil {
+		return nil, fmt.Errorf("overlay address: %w", err)
+	}
 
 	err = CheckOverlayWithStore(swarmAddress, stateStore)
 	if err != nil {
@@ -421,7 +495,7 @@ func NewBee(addr string, publicKey *ecdsa.PublicKey, signer crypto.Signer, netwo
 	lightNodes := lightnode.NewContainer(swarmAddress)
 
 	senderMatcher := transaction.NewMatcher(chainBackend, types.NewLondonSigner(big.NewInt(chainID)), stateStore, chainEnabled)
-	_, err = senderMatcher.Matches(p2pCtx, txHash, networkID, swarmAddress, true)
+	_, err = senderMatcher.Matches(p2pCtx, txHash, networkID, swarmAddress, o.OverlayNonce, true)
 	if err != nil {
 		return nil, fmt.Errorf("identity transaction verification failed: %w", err)
 	}
@@ -441,17 +515,9 @@ func NewBee(addr string, publicKey *ecdsa.PublicKey, signer crypto.Signer, netwo
 
 	// Perform checks related to payment threshold calculations here to not duplicate
 	// the checks in bootstrap process
-	paymentThreshold, ok := new(big.Int).SetString(o.PaymentThreshold, 10)
-	if !ok {
-		return nil, fmt.Errorf("invalid payment threshold: %s", paymentThreshold)
-	}
-
-	if paymentThreshold.Cmp(big.NewInt(minPaymentThreshold)) < 0 {
-		return nil, fmt.Errorf("payment threshold below minimum generally accepted value, need at least %d", minPaymentThreshold)
-	}
-
-	if paymentThreshold.Cmp(big.NewInt(maxPaymentThreshold)) > 0 {
-		return nil, fmt.Errorf("payment threshold above maximum generally accepted value, needs to be reduced to at most %d", maxPaymentThreshold)
+	paymentThreshold, err := ParsePaymentThreshold(o.PaymentThreshold)
+	if err != nil {
+		return nil, err
 	}
 
 	if o.PaymentTolerance < 0 {
@@ -495,13 +561,14 @@ func NewBee(addr string, publicKey *ecdsa.PublicKey, signer crypto.Signer, netwo
 		}
 	}
 
-	p2ps, err := libp2p.New(p2pCtx, signer, networkID, swarmAddress, addr, addressbook, stateStore, lightNodes, senderMatcher, logger, tracer, libp2p.Options{
+	p2ps, err := libp2p.New(p2pCtx, signer, networkID, swarmAddress, addr, addressbook, stateStore, lightNodes, senderMatcher, logger.Named("libp2p"), tracer, libp2p.Options{
 		PrivateKey:      libp2pPrivateKey,
 		NATAddr:         o.NATAddr,
 		EnableWS:        o.EnableWS,
 		WelcomeMessage:  o.WelcomeMessage,
 		FullNode:        o.FullNodeMode,
 		Transaction:     txHash,
+		OverlayNonce:    o.OverlayNonce,
 		ValidateOverlay: chainEnabled,
 	})
 	if err != nil {
@@ -536,13 +603,15 @@ func NewBee(addr string, publicKey *ecdsa.PublicKey, signer crypto.Signer, netwo
 		BlockCacheCapacity:     o.DBBlockCacheCapacity,
 		WriteBufferSize:        o.DBWriteBufferSize,
 		DisableSeeksCompaction: o.DBDisableSeeksCompaction,
+		EventBus:               eventBus,
 	}
 
-	storer, err := localstore.New(path, swarmAddress.Bytes(), stateStore, lo, logger)
+	storer, err := localstore.New(path, swarmAddress.Bytes(), stateStore, lo, logger.Named("localstore"))
 	if err != nil {
 		return nil, fmt.Errorf("localstore: %w", err)
 	}
 	b.localstoreCloser = storer
+	b.storer = storer
 	unreserveFn = storer.UnreserveBatch
 
 	validStamp := postage.ValidStamp(batchStore)
@@ -577,7 +646,7 @@ func NewBee(addr string, publicKey *ecdsa.PublicKey, signer crypto.Signer, netwo
 	eventListener = listener.New(logger, chainBackend, postageContractAddress, o.BlockTime, &pidKiller{node: b}, postageSyncingStallingTimeout, postageSyncingBackoffTimeout)
 	b.listenerCloser = eventListener
 
-	batchSvc, err = batchservice.New(stateStore, batchStore, logger, eventListener, overlayEthAddress.Bytes(), post, sha3.New256, o.Resync)
+	batchSvc, err = batchservice.New(stateStore, batchStore, logger, eventListener, overlayEthAddress.Bytes(), post, sha3.New256, o.Resync, eventBus)
 	if err != nil {
 		return nil, err
 	}
@@ -612,13 +681,13 @@ func NewBee(addr string, publicKey *ecdsa.PublicKey, signer crypto.Signer, netwo
 	}
 
 	// Construct protocols.
-	pingPong := pingpong.New(p2ps, logger, tracer)
+	pingPong := pingpong.New(p2ps, logger.Named("pingpong"), tracer)
 
 	if err = p2ps.AddProtocol(pingPong.Protocol()); err != nil {
 		return nil, fmt.Errorf("pingpong service: %w", err)
 	}
 
-	hive, err := hive.New(p2ps, addressbook, networkID, o.BootnodeMode, o.AllowPrivateCIDRs, logger)
+	hive, err := hive.New(p2ps, addressbook, networkID, o.BootnodeMode, o.AllowPrivateCIDRs, logger.Named("hive"))
 	if err != nil {
 		return nil, fmt.Errorf("hive: %w", err)
 	}
@@ -635,7 +704,7 @@ func NewBee(addr string, publicKey *ecdsa.PublicKey, signer crypto.Signer, netwo
 		return nil, fmt.Errorf("unable to create metrics storage for kademlia: %w", err)
 	}
 
-	kad, err := kademlia.New(swarmAddress, addressbook, hive, p2ps, pingPong, metricsDB, logger,
+	kad, err := kademlia.New(swarmAddress, addressbook, hive, p2ps, pingPong, metricsDB, logger.Named("kademlia"),
 		kademlia.Options{Bootnodes: bootnodes, BootnodeMode: o.BootnodeMode, StaticNodes: o.StaticNodes})
 	if err != nil {
 		return nil, fmt.Errorf("unable to create kademlia: %w", err)
@@ -644,7 +713,7 @@ func NewBee(addr string, publicKey *ecdsa.PublicKey, signer crypto.Signer, netwo
 	b.topologyHalter = kad
 	hive.SetAddPeersHandler(kad.AddPeers)
 	p2ps.SetPickyNotifier(kad)
-	batchStore.SetRadiusSetter(kad)
+	batchStore.SetRadiusSetter(radiusEventSetter{RadiusSetter: kad, events: eventBus})
 
 	if batchSvc != nil && chainEnabled {
 		syncedChan, err := batchSvc.Start(postageSyncStart, initBatchState)
@@ -665,6 +734,8 @@ func NewBee(addr string, publicKey *ecdsa.PublicKey, signer crypto.Signer, netwo
 	pricer := pricer.NewFixedPricer(swarmAddress, basePrice)
 
 	pricing := pricing.New(p2ps, logger, paymentThreshold, big.NewInt(minPaymentThreshold))
+	b.p2pPeers = p2ps
+	b.pricing = pricing
 
 	if err = p2ps.AddProtocol(pricing.Protocol()); err != nil {
 		return nil, fmt.Errorf("pricing service: %w", err)
@@ -693,6 +764,7 @@ func NewBee(addr string, publicKey *ecdsa.PublicKey, signer crypto.Signer, netwo
 		return nil, fmt.Errorf("accounting: %w", err)
 	}
 	b.accountingCloser = acc
+	b.accounting = acc
 
 	var enforcedRefreshRate *big.Int
 
@@ -724,6 +796,7 @@ func NewBee(addr string, publicKey *ecdsa.PublicKey, signer crypto.Signer, netwo
 			o.PriceOracleAddress,
 			chainID,
 			transactionService,
+			eventBus,
 		)
 		if err != nil {
 			return nil, err
@@ -737,36 +810,48 @@ func NewBee(addr string, publicKey *ecdsa.PublicKey, signer crypto.Signer, netwo
 
 	pricing.SetPaymentThresholdObserver(acc)
 
-	retrieve := retrieval.New(swarmAddress, storer, p2ps, kad, logger, acc, pricer, tracer, o.RetrievalCaching, validStamp)
+	retrieve := retrieval.New(swarmAddress, storer, p2ps, kad, logger.Named("retrieval"), acc, pricer, tracer, o.RetrievalCaching, validStamp)
 	tagService := tags.NewTags(stateStore, logger)
 	b.tagsCloser = tagService
 
-	pssService := pss.New(pssPrivateKey, logger)
+	pssService := pss.New(pssPrivateKey, logger.Named("pss"))
 	b.pssCloser = pssService
+	pssService.SetMiningWorkers(o.PssMiningWorkers)
 
 	var ns storage.Storer = netstore.New(storer, validStamp, retrieve, logger)
 	b.nsCloser = ns
 
+	pssService.SetMailbox(ns, stateStore)
+	// acknowledgements and responses are stamped with the first usable batch
+	pssService.SetReplier(swarmAddress, func() (postage.Stamper, error) {
+		for _, i := range post.StampIssuers() {
+			if post.IssuerUsable(i) {
+				return postage.NewStamper(i, signer), nil
+			}
+		}
+		return nil, errors.New("no usable postage batch")
+	})
+
 	traversalService := traversal.New(ns)
 
 	pinningService := pinning.NewService(storer, stateStore, traversalService)
 
-	pushSyncProtocol := pushsync.New(swarmAddress, blockHash, p2ps, storer, kad, tagService, o.FullNodeMode, pssService.TryUnwrap, validStamp, logger, acc, pricer, signer, tracer, warmupTime)
+	pushSyncProtocol := pushsync.New(swarmAddress, blockHash, p2ps, storer, kad, tagService, o.FullNodeMode, pssService.TryUnwrap, validStamp, logger.Named("pushsync"), acc, pricer, signer, tracer, warmupTime)
 
 	// set the pushSyncer in the PSS
 	pssService.SetPushSyncer(pushSyncProtocol)
 
-	pusherService := pusher.New(networkID, storer, kad, pushSyncProtocol, validStamp, tagService, logger, tracer, warmupTime)
+	pusherService := pusher.New(networkID, storer, kad, pushSyncProtocol, validStamp, tagService, logger.Named("pusher"), tracer, warmupTime, eventBus)
 	b.pusherCloser = pusherService
 
 	pullStorage := pullstorage.New(storer)
 
-	pullSyncProtocol := pullsync.New(p2ps, pullStorage, pssService.TryUnwrap, validStamp, logger)
+	pullSyncProtocol := pullsync.New(p2ps, pullStorage, pssService.TryUnwrap, validStamp, logger.Named("pullsync"), tracer)
 	b.pullSyncCloser = pullSyncProtocol
 
 	var pullerService *puller.Puller
 	if o.FullNodeMode && !o.BootnodeMode {
-		pullerService = puller.New(stateStore, kad, pullSyncProtocol, logger, puller.Options{}, warmupTime)
+		pullerService = puller.New(stateStore, kad, pullSyncProtocol, logger.Named("puller"), puller.Options{}, warmupTime)
 		b.pullerCloser = pullerService
 	}
 
@@ -793,11 +878,15 @@ func NewBee(addr string, publicKey *ecdsa.PublicKey, signer crypto.Signer, netwo
 		return nil, fmt.Errorf("pullsync protocol: %w", err)
 	}
 
+	feedFactory := factory.New(ns)
 	multiResolver := multiresolver.NewMultiResolver(
 		multiresolver.WithConnectionConfigs(o.ResolverConnectionCfgs),
 		multiresolver.WithLogger(o.Logger),
+		multiresolver.WithFeedFactory(feedFactory),
+		multiresolver.WithCacheTTL(o.ResolverCacheTTL, o.ResolverNegativeCacheTTL),
 	)
 	b.resolverCloser = multiResolver
+	b.resolver = multiResolver
 	var chainSyncer *chainsyncer.ChainSyncer
 
 	if o.FullNodeMode {
@@ -819,9 +908,8 @@ func NewBee(addr string, publicKey *ecdsa.PublicKey, signer crypto.Signer, netwo
 	if o.APIAddr != "" {
 		// API server
 		var chunkC <-chan *pusher.Op
-		feedFactory := factory.New(ns)
 		steward := steward.New(storer, traversalService, retrieve, pushSyncProtocol)
-		apiService, chunkC = api.New(tagService, ns, multiResolver, pssService, traversalService, pinningService, feedFactory, post, postageContractService, steward, signer, authenticator, logger, tracer, api.Options{
+		apiService, chunkC = api.New(tagService, resumable.New(stateStore, crypto.EncodeSecp256k1PrivateKey(pssPrivateKey)), ns, multiResolver, pssService, traversalService, pinningService, feedFactory, post, postageContractService, steward, signer, authenticator, logger, tracer, api.Options{
 			CORSAllowedOrigins: o.CORSAllowedOrigins,
 			GatewayMode:        o.GatewayMode,
 			WsPingPeriod:       60 * time.Second,
@@ -851,6 +939,62 @@ func NewBee(addr string, publicKey *ecdsa.PublicKey, signer crypto.Signer, netwo
 
 		b.apiServer = apiServer
 		b.apiCloser = apiService
+		b.apiService = apiService
+	}
+
+	// the gateways update the feeds of the node one at a time
+	feedUpdates := feeds.NewUpdateLocks()
+
+	if o.S3Addr != "" {
+		s3Service := s3.New(ns, post, signer, stateStore, feedUpdates, s3BatchID, logger)
+		s3Listener, err := net.Listen("tcp", o.S3Addr)
+		if err != nil {
+			return nil, fmt.Errorf("s3 listener: %w", err)
+		}
+
+		s3Server := &http.Server{
+			IdleTimeout:       30 * time.Second,
+			ReadHeaderTimeout: 3 * time.Second,
+			Handler:           s3Service,
+			ErrorLog:          log.New(b.errorLogWriter, "", 0),
+		}
+
+		go func() {
+			logger.Infof("s3 gateway address: %s", s3Listener.Addr())
+
+			if err := s3Server.Serve(s3Listener); err != nil && err != http.ErrServerClosed {
+				logger.Debugf("s3 server: %v", err)
+				logger.Error("unable to serve s3 gateway")
+			}
+		}()
+
+		b.s3Server = s3Server
+	}
+
+	if o.DAVAddr != "" {
+		davService := dav.New(ns, post, signer, feedUpdates, davBatchID, logger)
+		davListener, err := net.Listen("tcp", o.DAVAddr)
+		if err != nil {
+			return nil, fmt.Errorf("webdav listener: %w", err)
+		}
+
+		davServer := &http.Server{
+			IdleTimeout:       30 * time.Second,
+			ReadHeaderTimeout: 3 * time.Second,
+			Handler:           davService,
+			ErrorLog:          log.New(b.errorLogWriter, "", 0),
+		}
+
+		go func() {
+			logger.Infof("webdav gateway address: %s", davListener.Addr())
+
+			if err := davServer.Serve(davListener); err != nil && err != http.ErrServerClosed {
+				logger.Debugf("webdav server: %v", err)
+				logger.Error("unable to serve webdav gateway")
+			}
+		}()
+
+		b.davServer = davServer
 	}
 
 	if debugAPIService != nil {
@@ -903,6 +1047,7 @@ func NewBee(addr string, publicKey *ecdsa.PublicKey, signer crypto.Signer, netwo
 		}
 
 		debugAPIService.MustRegisterMetrics(pseudosettleService.Metrics()...)
+		debugAPIService.MustRegisterMetrics(multiResolver.Metrics()...)
 
 		if swapService != nil {
 			debugAPIService.MustRegisterMetrics(swapService.Metrics()...)
@@ -919,11 +1064,26 @@ func NewBee(addr string, publicKey *ecdsa.PublicKey, signer crypto.Signer, netwo
 
 		// inject dependencies and configure full debug api http path routes
 		debugAPIService.Configure(swarmAddress, p2ps, pingPong, kad, lightNodes, storer, tagService, acc, pseudosettleService, o.SwapEnable, o.ChequebookEnable, debugSwapService, chequebookService, batchStore, post, postageContractService, traversalService, erc20Service)
+
+		debugAPIService.AddReadinessCheck("topology", topologyReadinessCheck(kad))
+		if chainEnabled {
+			debugAPIService.AddReadinessCheck("chain", chainReadinessCheck(chainBackend))
+			debugAPIService.AddReadinessCheck("postage-listener", postageListenerReadinessCheck(chainBackend, batchStore))
+		}
+		if path != "" {
+			debugAPIService.AddReadinessCheck("localstore", localstoreReadinessCheck(path))
+		}
+		if o.DataDir != "" {
+			if _, err := freeDiskSpace(o.DataDir); !errors.Is(err, errDiskSpaceUnsupported) {
+				debugAPIService.AddReadinessCheck("disk-space", diskSpaceReadinessCheck(o.DataDir))
+			}
+		}
 	}
 
 	if err := kad.Start(p2pCtx); err != nil {
 		return nil, err
 	}
+	go publishTopologyEvents(p2pCtx, kad, eventBus)
 
 	if err := p2ps.Ready(); err != nil {
 		return nil, err
@@ -967,6 +1127,8 @@ func (b *Bee) Shutdown(ctx context.Context) error {
 	}
 
 	tryClose(b.apiCloser, "api")
+	// end the event streams before the api servers are shut down
+	tryClose(b.eventBusCloser, "event bus")
 
 	var eg errgroup.Group
 	if b.apiServer != nil {
@@ -985,6 +1147,22 @@ func (b *Bee) Shutdown(ctx context.Context) error {
 			return nil
 		})
 	}
+	if b.s3Server != nil {
+		eg.Go(func() error {
+			if err := b.s3Server.Shutdown(ctx); err != nil {
+				return fmt.Errorf("s3 server: %w", err)
+			}
+			return nil
+		})
+	}
+	if b.davServer != nil {
+		eg.Go(func() error {
+			if err := b.davServer.Shutdown(ctx); err != nil {
+				return fmt.Errorf("webdav server: %w", err)
+			}
+			return nil
+		})
+	}
 
 	if err := eg.Wait(); err != nil {
 		mErr = multierror.Append(mErr, err)
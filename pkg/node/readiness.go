@@ -0,0 +1,112 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ethersphere/bee/pkg/debugapi"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/topology"
+	"github.com/ethersphere/bee/pkg/transaction"
+)
+
+const (
+	// minFreeDiskSpace is the free space on the disk of the data directory
+	// below which the node is not ready.
+	minFreeDiskSpace = 1 << 30 // 1 GiB
+
+	// maxPostageListenerLag is the number of blocks by which the postage
+	// listener may be behind the head of the chain. The listener tails the
+	// chain and syncs the blocks in batches, so it is never at the head.
+	maxPostageListenerLag = 50
+)
+
+var errDiskSpaceUnsupported = errors.New("disk space check not supported")
+
+// topologyReadinessCheck checks that the node is connected to peers and has
+// reached its neighborhood depth, or is connected to every known peer in a
+// network too small to have one.
+func topologyReadinessCheck(driver topology.Driver) debugapi.ReadinessCheck {
+	return func(context.Context) error {
+		s := driver.Snapshot()
+		if s.Connected == 0 {
+			return errors.New("no connected peers")
+		}
+		if s.Depth == 0 && s.Connected < s.Population {
+			return fmt.Errorf("neighborhood depth not reached with %d of %d known peers connected", s.Connected, s.Population)
+		}
+		return nil
+	}
+}
+
+// chainReadinessCheck checks that the chain backend is synced.
+func chainReadinessCheck(backend transaction.Backend) debugapi.ReadinessCheck {
+	return func(ctx context.Context) error {
+		synced, blockTime, err := transaction.IsSynced(ctx, backend, maxDelay)
+		if err != nil {
+			return fmt.Errorf("chain backend: %w", err)
+		}
+		if !synced {
+			return fmt.Errorf("chain backend not synced, last block time %s", blockTime)
+		}
+		return nil
+	}
+}
+
+// postageListenerReadinessCheck checks that the postage listener has caught
+// up with the head of the chain.
+func postageListenerReadinessCheck(backend transaction.Backend, batchStore postage.Storer) debugapi.ReadinessCheck {
+	return func(ctx context.Context) error {
+		head, err := backend.BlockNumber(ctx)
+		if err != nil {
+			return fmt.Errorf("chain backend: %w", err)
+		}
+		block := batchStore.GetChainState().Block
+		if head > block+maxPostageListenerLag {
+			return fmt.Errorf("postage listener at block %d is %d blocks behind the chain", block, head-block)
+		}
+		return nil
+	}
+}
+
+// localstoreReadinessCheck checks that a file can be written to the
+// directory of the localstore.
+func localstoreReadinessCheck(dir string) debugapi.ReadinessCheck {
+	return func(context.Context) error {
+		f, err := os.CreateTemp(dir, ".readiness-*")
+		if err != nil {
+			return fmt.Errorf("localstore not writable: %w", err)
+		}
+		defer os.Remove(f.Name())
+
+		if _, err := f.Write([]byte("ok")); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("localstore not writable: %w", err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("localstore not writable: %w", err)
+		}
+		return nil
+	}
+}
+
+// diskSpaceReadinessCheck checks that there is enough free space on the disk
+// of the directory.
+func diskSpaceReadinessCheck(dir string) debugapi.ReadinessCheck {
+	return func(context.Context) error {
+		free, err := freeDiskSpace(dir)
+		if err != nil {
+			return fmt.Errorf("free disk space: %w", err)
+		}
+		if free < minFreeDiskSpace {
+			return fmt.Errorf("free disk space %d bytes below %d bytes", free, minFreeDiskSpace)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethersphere/bee/pkg/p2p"
+	"github.com/ethersphere/bee/pkg/resolver/multiresolver"
+)
+
+var errNotAvailable = errors.New("not available")
+
+// ParsePaymentThreshold parses the payment threshold and checks that it is
+// within the generally accepted values.
+func ParsePaymentThreshold(s string) (*big.Int, error) {
+	paymentThreshold, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid payment threshold: %s", s)
+	}
+
+	if paymentThreshold.Cmp(big.NewInt(minPaymentThreshold)) < 0 {
+		return nil, fmt.Errorf("payment threshold below minimum generally accepted value, need at least %d", minPaymentThreshold)
+	}
+
+	if paymentThreshold.Cmp(big.NewInt(maxPaymentThreshold)) > 0 {
+		return nil, fmt.Errorf("payment threshold above maximum generally accepted value, needs to be reduced to at most %d", maxPaymentThreshold)
+	}
+
+	return paymentThreshold, nil
+}
+
+// SetPaymentThreshold changes the payment threshold of the node and announces
+// it to the connected peers. A lower threshold is announced before it is
+// enforced, so that the peers do not exceed it in the meantime.
+func (b *Bee) SetPaymentThreshold(ctx context.Context, s string) error {
+	if b.accounting == nil || b.pricing == nil {
+		return fmt.Errorf("payment threshold: %w", errNotAvailable)
+	}
+
+	paymentThreshold, err := ParsePaymentThreshold(s)
+	if err != nil {
+		return err
+	}
+
+	if paymentThreshold.Cmp(b.pricing.PaymentThreshold()) < 0 {
+		b.announcePaymentThreshold(ctx, paymentThreshold)
+		b.pricing.SetPaymentThreshold(paymentThreshold)
+		b.accounting.SetPaymentThreshold(paymentThreshold)
+		return nil
+	}
+
+	b.pricing.SetPaymentThreshold(paymentThreshold)
+	b.accounting.SetPaymentThreshold(paymentThreshold)
+	b.announcePaymentThreshold(ctx, paymentThreshold)
+	return nil
+}
+
+// announcePaymentThreshold announces the payment threshold to all connected
+// peers concurrently and waits for the announcements to complete.
+func (b *Bee) announcePaymentThreshold(ctx context.Context, paymentThreshold *big.Int) {
+	var wg sync.WaitGroup
+	for _, p := range b.p2pPeers.Peers() {
+		wg.Add(1)
+		go func(p p2p.Peer) {
+			defer wg.Done()
+			_ = b.pricing.AnnouncePaymentThreshold(ctx, p.Address, paymentThreshold)
+		}(p)
+	}
+	wg.Wait()
+}
+
+// SetCacheCapacity changes the number of chunks in the cache of the
+// localstore, which is garbage collected if it exceeds the new capacity.
+func (b *Bee) SetCacheCapacity(capacity uint64) error {
+	if b.storer == nil {
+		return fmt.Errorf("cache capacity: %w", errNotAvailable)
+	}
+	if capacity == 0 {
+		return errors.New("invalid cache capacity: 0")
+	}
+	return b.storer.SetCacheCapacity(capacity)
+}
+
+// SetResolverConnectionConfigs replaces the name resolvers with the ones
+// connected using the connection configs.
+func (b *Bee) SetResolverConnectionConfigs(cfgs []multiresolver.ConnectionConfig) error {
+	if b.resolver == nil {
+		return fmt.Errorf("resolver: %w", errNotAvailable)
+	}
+	return b.resolver.SetConnectionConfigs(cfgs)
+}
+
+// SetCORSAllowedOrigins changes the origins which are allowed to make
+// cross-origin requests to the API and the Debug API.
+func (b *Bee) SetCORSAllowedOrigins(origins []string) {
+	if b.apiService != nil {
+		b.apiService.SetCORSAllowedOrigins(origins)
+	}
+	if b.debugAPIService != nil {
+		b.debugAPIService.SetCORSAllowedOrigins(origins)
+	}
+}
@@ -65,6 +65,7 @@ type Service struct {
 	overlay               swarm.Address
 	fullNode              bool
 	transaction           []byte
+	nonce                 []byte
 	networkID             uint64
 	validateOverlay       bool
 	welcomeMessage        atomic.Value
@@ -89,7 +90,7 @@ func (i *Info) LightString() string {
 }
 
 // New creates a new handshake Service.
-func New(signer crypto.Signer, advertisableAddresser AdvertisableAddressResolver, isSender p2p.SenderMatcher, overlay swarm.Address, networkID uint64, fullNode bool, transaction []byte, welcomeMessage string, validateOverlay bool, ownPeerID libp2ppeer.ID, logger logging.Logger) (*Service, error) {
+func New(signer crypto.Signer, advertisableAddresser AdvertisableAddressResolver, isSender p2p.SenderMatcher, overlay swarm.Address, networkID uint64, fullNode bool, transaction, nonce []byte, welcomeMessage string, validateOverlay bool, ownPeerID libp2ppeer.ID, logger logging.Logger) (*Service, error) {
 	if len(welcomeMessage) > MaxWelcomeMessageLength {
 		return nil, ErrWelcomeMessageLength
 	}
@@ -102,6 +103,7 @@ func New(signer crypto.Signer, advertisableAddresser AdvertisableAddressResolver
 		fullNode:              fullNode,
 		validateOverlay:       validateOverlay,
 		transaction:           transaction,
+		nonce:                 nonce,
 		senderMatcher:         isSender,
 		libp2pID:              ownPeerID,
 		logger:                logger,
@@ -179,7 +181,7 @@ func (s *Service) Handshake(ctx context.Context, stream p2p.Stream, peerMultiadd
 		return nil, ErrNetworkIDIncompatible
 	}
 
-	blockHash, err := s.senderMatcher.Matches(ctx, resp.Ack.Transaction, s.networkID, overlay, false)
+	blockHash, err := s.senderMatcher.Matches(ctx, resp.Ack.Transaction, s.networkID, overlay, resp.Ack.Nonce, false)
 	if err != nil {
 		return nil, fmt.Errorf("overlay %v verification failed: %w", overlay, err)
 	}
@@ -200,6 +202,7 @@ func (s *Service) Handshake(ctx context.Context, stream p2p.Stream, peerMultiadd
 		NetworkID:      s.networkID,
 		FullNode:       s.fullNode,
 		Transaction:    s.transaction,
+		Nonce:          s.nonce,
 		WelcomeMessage: welcomeMessage,
 	}
 
@@ -276,6 +279,7 @@ func (s *Service) Handle(ctx context.Context, stream p2p.Stream, remoteMultiaddr
 			NetworkID:      s.networkID,
 			FullNode:       s.fullNode,
 			Transaction:    s.transaction,
+			Nonce:          s.nonce,
 			WelcomeMessage: welcomeMessage,
 		},
 	}); err != nil {
@@ -303,7 +307,7 @@ func (s *Service) Handle(ctx context.Context, stream p2p.Stream, remoteMultiaddr
 		}
 	}
 
-	blockHash, err := s.senderMatcher.Matches(ctx, ack.Transaction, s.networkID, overlay, false)
+	blockHash, err := s.senderMatcher.Matches(ctx, ack.Transaction, s.networkID, overlay, ack.Nonce, false)
 	if err != nil {
 		return nil, fmt.Errorf("overlay %v verification failed: %w", overlay, err)
 	}
@@ -72,6 +72,7 @@ type Ack struct {
 	FullNode       bool        `protobuf:"varint,3,opt,name=FullNode,proto3" json:"FullNode,omitempty"`
 	Transaction    []byte      `protobuf:"bytes,4,opt,name=Transaction,proto3" json:"Transaction,omitempty"`
 	WelcomeMessage string      `protobuf:"bytes,99,opt,name=WelcomeMessage,proto3" json:"WelcomeMessage,omitempty"`
+	Nonce          []byte      `protobuf:"bytes,5,opt,name=Nonce,proto3" json:"Nonce,omitempty"`
 }
 
 func (m *Ack) Reset()         { *m = Ack{} }
@@ -142,6 +143,13 @@ func (m *Ack) GetWelcomeMessage() string {
 	return ""
 }
 
+func (m *Ack) GetNonce() []byte {
+	if m != nil {
+		return m.Nonce
+	}
+	return nil
+}
+
 type SynAck struct {
 	Syn *Syn `protobuf:"bytes,1,opt,name=Syn,proto3" json:"Syn,omitempty"`
 	Ack *Ack `protobuf:"bytes,2,opt,name=Ack,proto3" json:"Ack,omitempty"`
@@ -264,28 +272,28 @@ func init() {
 func init() { proto.RegisterFile("handshake.proto", fileDescriptor_a77305914d5d202f) }
 
 var fileDescriptor_a77305914d5d202f = []byte{
-	// 324 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x91, 0xdf, 0x4a, 0xf3, 0x30,
-	0x18, 0xc6, 0x97, 0x75, 0xec, 0xcf, 0xbb, 0xb1, 0xef, 0x23, 0x20, 0x14, 0x19, 0x25, 0xf4, 0x40,
-	0x8a, 0x07, 0x13, 0xf5, 0x0a, 0x36, 0x44, 0x10, 0x74, 0x83, 0x54, 0x11, 0x3c, 0x32, 0x6b, 0xc3,
-	0x36, 0x5a, 0x93, 0x91, 0x74, 0x93, 0xee, 0x2a, 0xbc, 0x1e, 0xaf, 0xc0, 0xc3, 0x1d, 0x7a, 0x28,
-	0xdb, 0x8d, 0x48, 0xe3, 0xd6, 0xce, 0x79, 0xf8, 0xfe, 0x9e, 0x27, 0xc9, 0xf3, 0xbc, 0x81, 0x7f,
-	0x13, 0x26, 0x42, 0x3d, 0x61, 0x11, 0xef, 0xce, 0x94, 0x4c, 0x24, 0x6e, 0xe4, 0xc0, 0x3d, 0x07,
-	0xcb, 0x4f, 0x05, 0x3e, 0x85, 0xff, 0xc3, 0x91, 0xe6, 0x6a, 0xc1, 0xc3, 0x07, 0x11, 0x72, 0x15,
-	0xb3, 0xd4, 0x46, 0x04, 0x79, 0x2d, 0xfa, 0x87, 0xbb, 0xef, 0x08, 0xac, 0x5e, 0x10, 0xe1, 0x33,
-	0xa8, 0xf5, 0xc2, 0x50, 0x71, 0xad, 0x8d, 0xb5, 0x79, 0x71, 0xd4, 0x2d, 0x1e, 0xea, 0x2f, 0x97,
-	0x5b, 0x91, 0xee, 0x5c, 0xb8, 0x03, 0x8d, 0x01, 0x4f, 0x5e, 0xa5, 0x8a, 0x6e, 0xae, 0xec, 0x32,
-	0x41, 0x5e, 0x85, 0x16, 0x00, 0x1f, 0x43, 0xfd, 0x7a, 0x1e, 0xc7, 0x03, 0x19, 0x72, 0xdb, 0x22,
-	0xc8, 0xab, 0xd3, 0x7c, 0xc6, 0x04, 0x9a, 0xf7, 0x8a, 0x09, 0xcd, 0x82, 0x64, 0x2a, 0x85, 0x5d,
-	0x31, 0xc9, 0xf6, 0x11, 0x3e, 0x81, 0xf6, 0x23, 0x8f, 0x03, 0xf9, 0xc2, 0xef, 0xb8, 0xd6, 0x6c,
-	0xcc, 0xed, 0x80, 0x20, 0xaf, 0x41, 0x0f, 0xa8, 0x7b, 0x0b, 0x55, 0x3f, 0x15, 0x59, 0x7c, 0x62,
-	0x9a, 0x6f, 0xa3, 0xb7, 0xf7, 0xa2, 0xfb, 0xa9, 0xa0, 0x66, 0x29, 0xc4, 0xf4, 0x34, 0x49, 0x7f,
-	0x3b, 0x7a, 0x41, 0x44, 0x33, 0xc9, 0x7d, 0x06, 0x28, 0x8a, 0x66, 0x0d, 0x0e, 0x96, 0x97, 0xcf,
-	0x59, 0x77, 0x7f, 0x3a, 0x16, 0x2c, 0x99, 0x2b, 0x6e, 0x6e, 0x6c, 0xd1, 0x02, 0x60, 0x1b, 0x6a,
-	0xc3, 0xc5, 0xcf, 0x41, 0xcb, 0x68, 0xbb, 0xb1, 0xdf, 0xf9, 0x58, 0x3b, 0x68, 0xb5, 0x76, 0xd0,
-	0xd7, 0xda, 0x41, 0x6f, 0x1b, 0xa7, 0xb4, 0xda, 0x38, 0xa5, 0xcf, 0x8d, 0x53, 0x7a, 0x2a, 0xcf,
-	0x46, 0xa3, 0xaa, 0xf9, 0xcf, 0xcb, 0xef, 0x00, 0x00, 0x00, 0xff, 0xff, 0xd1, 0x48, 0x2c, 0x4a,
-	0xe2, 0x01, 0x00, 0x00,
+	// 330 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x91, 0xcf, 0x6a, 0x2a, 0x31,
+	0x18, 0xc5, 0x8d, 0xe3, 0xdf, 0x4f, 0xf1, 0x5e, 0xc2, 0xbd, 0x10, 0x8a, 0x0c, 0xc3, 0x2c, 0xca,
+	0xd0, 0x85, 0xa5, 0xed, 0x13, 0x28, 0xa5, 0x50, 0x68, 0x15, 0x32, 0x2d, 0x85, 0xae, 0x1a, 0x67,
+	0x82, 0xca, 0x4c, 0x13, 0x49, 0x46, 0xcb, 0xf8, 0x14, 0x7d, 0xac, 0x2e, 0x5d, 0x76, 0x59, 0xf4,
+	0x45, 0xca, 0xc4, 0x3f, 0x23, 0xba, 0x3c, 0xe7, 0x3b, 0xf9, 0x92, 0xdf, 0x09, 0xfc, 0x19, 0x33,
+	0x11, 0xea, 0x31, 0x8b, 0x78, 0x67, 0xaa, 0x64, 0x22, 0x71, 0x7d, 0x6f, 0xb8, 0x57, 0x60, 0xf9,
+	0xa9, 0xc0, 0x17, 0xf0, 0x77, 0x30, 0xd4, 0x5c, 0xcd, 0x79, 0xf8, 0x2c, 0x42, 0xae, 0x62, 0x96,
+	0x12, 0xe4, 0x20, 0xaf, 0x49, 0x4f, 0x7c, 0x77, 0x89, 0xc0, 0xea, 0x06, 0x11, 0xbe, 0x84, 0x6a,
+	0x37, 0x0c, 0x15, 0xd7, 0xda, 0x44, 0x1b, 0xd7, 0xff, 0x3b, 0xf9, 0x45, 0xbd, 0xc5, 0x62, 0x3b,
+	0xa4, 0xbb, 0x14, 0x6e, 0x43, 0xbd, 0xcf, 0x93, 0x0f, 0xa9, 0xa2, 0xfb, 0x5b, 0x52, 0x74, 0x90,
+	0x57, 0xa2, 0xb9, 0x81, 0xcf, 0xa0, 0x76, 0x37, 0x8b, 0xe3, 0xbe, 0x0c, 0x39, 0xb1, 0x1c, 0xe4,
+	0xd5, 0xe8, 0x5e, 0x63, 0x07, 0x1a, 0x4f, 0x8a, 0x09, 0xcd, 0x82, 0x64, 0x22, 0x05, 0x29, 0x99,
+	0x97, 0x1d, 0x5a, 0xf8, 0x1c, 0x5a, 0x2f, 0x3c, 0x0e, 0xe4, 0x3b, 0x7f, 0xe4, 0x5a, 0xb3, 0x11,
+	0x27, 0x81, 0x83, 0xbc, 0x3a, 0x3d, 0x72, 0xf1, 0x3f, 0x28, 0xf7, 0xa5, 0x08, 0x38, 0x29, 0x9b,
+	0x1d, 0x1b, 0xe1, 0x3e, 0x40, 0xc5, 0x4f, 0x45, 0x06, 0xe5, 0x98, 0x3e, 0xb6, 0x40, 0xad, 0x03,
+	0x20, 0x3f, 0x15, 0xd4, 0x54, 0xe5, 0x18, 0x7a, 0x52, 0x3c, 0x49, 0x74, 0x83, 0x88, 0x66, 0x23,
+	0xf7, 0x0d, 0x20, 0xc7, 0xcf, 0xb8, 0x8e, 0x2a, 0xdd, 0xeb, 0xac, 0x11, 0x7f, 0x32, 0x12, 0x2c,
+	0x99, 0x29, 0x6e, 0x36, 0x36, 0x69, 0x6e, 0x60, 0x02, 0xd5, 0xc1, 0x7c, 0x73, 0xd0, 0x32, 0xb3,
+	0x9d, 0xec, 0xb5, 0xbf, 0x56, 0x36, 0x5a, 0xae, 0x6c, 0xf4, 0xb3, 0xb2, 0xd1, 0xe7, 0xda, 0x2e,
+	0x2c, 0xd7, 0x76, 0xe1, 0x7b, 0x6d, 0x17, 0x5e, 0x8b, 0xd3, 0xe1, 0xb0, 0x62, 0x7e, 0xf9, 0xe6,
+	0x77, 0x00, 0xbb, 0xdb, 0xca, 0xd8, 0xf8, 0x01, 0x00, 0x00,
 }
 
 func (m *Syn) Marshal() (dAtA []byte, err error) {
@@ -347,6 +355,13 @@ func (m *Ack) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i--
 		dAtA[i] = 0x9a
 	}
+	if len(m.Nonce) > 0 {
+		i -= len(m.Nonce)
+		copy(dAtA[i:], m.Nonce)
+		i = encodeVarintHandshake(dAtA, i, uint64(len(m.Nonce)))
+		i--
+		dAtA[i] = 0x2a
+	}
 	if len(m.Transaction) > 0 {
 		i -= len(m.Transaction)
 		copy(dAtA[i:], m.Transaction)
@@ -519,6 +534,10 @@ func (m *Ack) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovHandshake(uint64(l))
 	}
+	l = len(m.Nonce)
+	if l > 0 {
+		n += 1 + l + sovHandshake(uint64(l))
+	}
 	l = len(m.WelcomeMessage)
 	if l > 0 {
 		n += 2 + l + sovHandshake(uint64(l))
@@ -795,6 +814,40 @@ func (m *Ack) Unmarshal(dAtA []byte) error {
 				m.Transaction = []byte{}
 			}
 			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Nonce", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandshake
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthHandshake
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthHandshake
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Nonce = append(m.Nonce[:0], dAtA[iNdEx:postIndex]...)
+			if m.Nonce == nil {
+				m.Nonce = []byte{}
+			}
+			iNdEx = postIndex
 		case 99:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field WelcomeMessage", wireType)
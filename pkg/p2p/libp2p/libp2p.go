@@ -117,6 +117,7 @@ type Options struct {
 	LightNodeLimit  int
 	WelcomeMessage  string
 	Transaction     []byte
+	OverlayNonce    []byte
 	ValidateOverlay bool
 	hostFactory     func(...libp2p.Option) (host.Host, error)
 }
@@ -245,7 +246,7 @@ func New(ctx context.Context, signer beecrypto.Signer, networkID uint64, overlay
 		advertisableAddresser = natAddrResolver
 	}
 
-	handshakeService, err := handshake.New(signer, advertisableAddresser, swapBackend, overlay, networkID, o.FullNode, o.Transaction, o.WelcomeMessage, o.ValidateOverlay, h.ID(), logger)
+	handshakeService, err := handshake.New(signer, advertisableAddresser, swapBackend, overlay, networkID, o.FullNode, o.Transaction, o.OverlayNonce, o.WelcomeMessage, o.ValidateOverlay, h.ID(), logger)
 	if err != nil {
 		return nil, fmt.Errorf("handshake service: %w", err)
 	}
@@ -94,9 +94,12 @@ type Halter interface {
 	Halt()
 }
 
-// SenderMatcher checks if the provided address matches sender details
+// SenderMatcher checks if the provided address matches sender details.
+// The overlay is derived from the nonce if one is given, otherwise from the
+// hash of the block following the transaction. Matches returns the hash the
+// overlay was derived from.
 type SenderMatcher interface {
-	Matches(ctx context.Context, tx []byte, networkID uint64, senderOverlay swarm.Address, ignoreGreylist bool) ([]byte, error)
+	Matches(ctx context.Context, tx []byte, networkID uint64, senderOverlay swarm.Address, nonce []byte, ignoreGreylist bool) ([]byte, error)
 }
 
 // PickyNotifier can decide whether a peer should be picked
@@ -12,6 +12,8 @@ import (
 	"hash"
 	"math/big"
 
+	"github.com/ethersphere/bee/pkg/bigint"
+	"github.com/ethersphere/bee/pkg/events"
 	"github.com/ethersphere/bee/pkg/logging"
 	"github.com/ethersphere/bee/pkg/postage"
 	"github.com/ethersphere/bee/pkg/storage"
@@ -35,6 +37,7 @@ type batchService struct {
 
 	checksum hash.Hash // checksum hasher
 	resync   bool
+	events   *events.Bus
 }
 
 type Interface interface {
@@ -51,6 +54,7 @@ func New(
 	batchListener postage.BatchEventListener,
 	checksumFunc func() hash.Hash,
 	resync bool,
+	eventBus *events.Bus,
 ) (Interface, error) {
 	if checksumFunc == nil {
 		checksumFunc = sha3.New256
@@ -90,7 +94,7 @@ func New(
 		}
 	}
 
-	return &batchService{stateStore, storer, logger, listener, owner, batchListener, sum, resync}, nil
+	return &batchService{stateStore, storer, logger, listener, owner, batchListener, sum, resync, eventBus}, nil
 }
 
 // Create will create a new batch with the given ID, owner value and depth and
@@ -130,6 +134,13 @@ func (svc *batchService) Create(id, owner []byte, normalisedBalance *big.Int, de
 	}
 
 	svc.logger.Debugf("batch service: created batch id %s, tx %x, checksum %x", hex.EncodeToString(batch.ID), txHash, cs)
+	svc.events.Publish(events.TypeBatchCreated, events.BatchData{
+		BatchID:     hex.EncodeToString(batch.ID),
+		Value:       bigint.Wrap(batch.Value),
+		Depth:       batch.Depth,
+		BucketDepth: batch.BucketDepth,
+		Immutable:   batch.Immutable,
+	})
 	return nil
 }
 
@@ -156,6 +167,11 @@ func (svc *batchService) TopUp(id []byte, normalisedBalance *big.Int, txHash []b
 	}
 
 	svc.logger.Debugf("batch service: topped up batch id %s from %v to %v, tx %x, checksum %x", hex.EncodeToString(b.ID), b.Value, normalisedBalance, txHash, cs)
+	svc.events.Publish(events.TypeBatchTopUp, events.BatchData{
+		BatchID: hex.EncodeToString(b.ID),
+		Value:   bigint.Wrap(normalisedBalance),
+		Depth:   b.Depth,
+	})
 	return nil
 }
 
@@ -181,6 +197,11 @@ func (svc *batchService) UpdateDepth(id []byte, depth uint8, normalisedBalance *
 	}
 
 	svc.logger.Debugf("batch service: updated depth of batch id %s from %d to %d, tx %x, checksum %x", hex.EncodeToString(b.ID), b.Depth, depth, txHash, cs)
+	svc.events.Publish(events.TypeBatchDepthIncrease, events.BatchData{
+		BatchID: hex.EncodeToString(b.ID),
+		Value:   bigint.Wrap(normalisedBalance),
+		Depth:   depth,
+	})
 	return nil
 }
 
@@ -0,0 +1,78 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// StamperPutter is a storer which stamps the chunks it puts. Chunks which
+// are already stored are neither stamped nor put again.
+type StamperPutter struct {
+	storage.Storer
+	stamper Stamper
+}
+
+// NewStamperPutter returns a StamperPutter which stamps the chunks with the
+// postage batch with the ID, signed by the signer, and puts them to the
+// storer.
+func NewStamperPutter(s storage.Storer, post Service, signer crypto.Signer, batchID []byte) (*StamperPutter, error) {
+	i, err := post.GetStampIssuer(batchID)
+	if err != nil {
+		return nil, fmt.Errorf("stamp issuer: %w", err)
+	}
+	return &StamperPutter{Storer: s, stamper: NewStamper(i, signer)}, nil
+}
+
+// Put stamps the chunks which are not yet stored and puts them to the
+// storer. The stamped chunks replace the chunks in the argument slice.
+func (p *StamperPutter) Put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk) (exists []bool, err error) {
+	var (
+		ctp []swarm.Chunk
+		idx []int
+	)
+	exists = make([]bool, len(chs))
+
+	for i, c := range chs {
+		has, err := p.Storer.Has(ctx, c.Address())
+		if err != nil {
+			return nil, err
+		}
+		if has || containsChunk(c.Address(), chs[:i]...) {
+			exists[i] = true
+			continue
+		}
+		stamp, err := p.stamper.Stamp(c.Address())
+		if err != nil {
+			return nil, err
+		}
+		chs[i] = c.WithStamp(stamp)
+		ctp = append(ctp, chs[i])
+		idx = append(idx, i)
+	}
+
+	exists2, err := p.Storer.Put(ctx, mode, ctp...)
+	if err != nil {
+		return nil, err
+	}
+	for i, v := range idx {
+		exists[v] = exists2[i]
+	}
+	return exists, nil
+}
+
+func containsChunk(addr swarm.Address, chs ...swarm.Chunk) bool {
+	for _, c := range chs {
+		if addr.Equal(c.Address()) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postage_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/postage"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	testingc "github.com/ethersphere/bee/pkg/storage/testing"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestStamperPutter(t *testing.T) {
+	privKey, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		ctx    = context.Background()
+		st     = newTestStampIssuer(t, 1000)
+		post   = mockpost.New(mockpost.WithIssuer(st))
+		storer = mock.NewStorer()
+		signer = crypto.NewDefaultSigner(privKey)
+	)
+
+	if _, err := postage.NewStamperPutter(storer, post, signer, make([]byte, 32)); err == nil {
+		t.Fatal("expected error for an unknown batch")
+	}
+
+	p, err := postage.NewStamperPutter(storer, post, signer, st.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stored := testingc.GenerateTestRandomChunk()
+	if _, err := storer.Put(ctx, storage.ModePutUpload, stored); err != nil {
+		t.Fatal(err)
+	}
+	ch := testingc.GenerateTestRandomChunk()
+
+	// the new chunk is put only once, even if it is passed twice
+	exists, err := p.Put(ctx, storage.ModePutUpload, stored, ch, swarm.NewChunk(ch.Address(), ch.Data()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []bool{true, false, true}; len(exists) != len(want) || exists[0] != want[0] || exists[1] != want[1] || exists[2] != want[2] {
+		t.Fatalf("got exists %v, want %v", exists, want)
+	}
+
+	got, err := storer.Get(ctx, storage.ModeGetRequest, ch.Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Stamp() == nil || !bytes.Equal(got.Stamp().BatchID(), st.ID()) {
+		t.Fatal("stored chunk is not stamped with the batch")
+	}
+}
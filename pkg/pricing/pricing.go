@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sync"
 	"time"
 
 	"github.com/ethersphere/bee/pkg/logging"
@@ -44,6 +45,7 @@ type PaymentThresholdObserver interface {
 type Service struct {
 	streamer                 p2p.Streamer
 	logger                   logging.Logger
+	paymentThresholdMu       sync.RWMutex
 	paymentThreshold         *big.Int
 	minPaymentThreshold      *big.Int
 	paymentThresholdObserver PaymentThresholdObserver
@@ -104,7 +106,7 @@ func (s *Service) handler(ctx context.Context, p p2p.Peer, stream p2p.Stream) (e
 }
 
 func (s *Service) init(ctx context.Context, p p2p.Peer) error {
-	err := s.AnnouncePaymentThreshold(ctx, p.Address, s.paymentThreshold)
+	err := s.AnnouncePaymentThreshold(ctx, p.Address, s.PaymentThreshold())
 	if err != nil {
 		s.logger.Warningf("could not send payment threshold announcement to peer %v", p.Address)
 	}
@@ -137,6 +139,23 @@ func (s *Service) AnnouncePaymentThreshold(ctx context.Context, peer swarm.Addre
 	return err
 }
 
+// PaymentThreshold returns the payment threshold announced to the peers.
+func (s *Service) PaymentThreshold() *big.Int {
+	s.paymentThresholdMu.RLock()
+	defer s.paymentThresholdMu.RUnlock()
+
+	return s.paymentThreshold
+}
+
+// SetPaymentThreshold sets the payment threshold announced to the peers that
+// connect afterwards.
+func (s *Service) SetPaymentThreshold(paymentThreshold *big.Int) {
+	s.paymentThresholdMu.Lock()
+	defer s.paymentThresholdMu.Unlock()
+
+	s.paymentThreshold = new(big.Int).Set(paymentThreshold)
+}
+
 // SetPaymentThresholdObserver sets the PaymentThresholdObserver to be used when receiving a new payment threshold
 func (s *Service) SetPaymentThresholdObserver(observer PaymentThresholdObserver) {
 	s.paymentThresholdObserver = observer
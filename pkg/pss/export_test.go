@@ -4,6 +4,50 @@
 
 package pss
 
+import (
+	"crypto/ecdsa"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/storage"
+)
+
 var (
 	Contains = contains
 )
+
+// SetAttemptTimeout sets the time to wait for a reply before sending a
+// message again.
+func SetAttemptTimeout(p Interface, d time.Duration) {
+	p.(*pss).attemptTimeout = d
+}
+
+// SetReplyTargetLength sets the length of the overlay address prefix to
+// which replies are sent.
+func SetReplyTargetLength(p Interface, n int) {
+	p.(*pss).replyTargetLength = n
+}
+
+// SetMaxConcurrentReplies sets the number of replies which are sent at the
+// same time.
+func SetMaxConcurrentReplies(p Interface, n int) {
+	p.(*pss).replySem = make(chan struct{}, n)
+}
+
+// ReliableEnvelope returns the payload of a message which expects an
+// acknowledgement to the target.
+func ReliableEnvelope(sender *ecdsa.PublicKey, target Target) []byte {
+	e := &envelope{kind: kindReliable, id: make([]byte, idSize), sender: sender, target: target}
+	b, _ := e.MarshalBinary()
+	return b
+}
+
+// IsEnvelope reports whether the payload is a valid envelope.
+func IsEnvelope(b []byte) bool {
+	return new(envelope).UnmarshalBinary(b) == nil
+}
+
+// SweepMailboxSeen forgets the delivered messages which were marked as seen
+// before the time.
+func SweepMailboxSeen(p Interface, store storage.StateStorer, before time.Time) error {
+	return p.(*pss).sweepSeen(store, before)
+}
@@ -0,0 +1,340 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pss
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/soc"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+const (
+	mailboxNextKeyPrefix   = "pss_mailbox_next_"
+	mailboxCursorKeyPrefix = "pss_mailbox_cursor_"
+	mailboxSeenKeyPrefix   = "pss_mailbox_seen_"
+
+	// mailboxRetention is the time for which the messages which were
+	// delivered directly are remembered in order not to return them from
+	// the mailbox again. Mailboxes which are read less often may return
+	// such messages.
+	mailboxRetention = 7 * 24 * time.Hour
+	// mailboxSweepInterval is the interval at which the remembered
+	// messages older than mailboxRetention are forgotten.
+	mailboxSweepInterval = time.Hour
+)
+
+// ErrNoMailbox is returned when the mailbox is used before it is set up.
+var ErrNoMailbox = errors.New("mailbox not available")
+
+// Mailbox stores messages for recipients which are offline when a message
+// is sent.
+//
+// The mailbox of a recipient for a topic is a sequence of single owner
+// chunks. Their owner is derived from the public key of the recipient and
+// the topic, so that every sender can write to it and the recipient can
+// find it. Each single owner chunk wraps the trojan chunk of a message,
+// which only the recipient can decrypt.
+type Mailbox interface {
+	// SendToMailbox sends a message like Send does and also stores it in
+	// the mailbox of the recipient.
+	SendToMailbox(context.Context, Topic, []byte, postage.Stamper, *ecdsa.PublicKey, Targets) error
+	// Inbox returns the messages with the topic from the mailbox of the
+	// node which were not returned before or delivered to a handler, and
+	// acknowledges them.
+	Inbox(context.Context, Topic) ([]Message, error)
+}
+
+// Message is a message fetched from a mailbox.
+type Message struct {
+	// Index is the position of the message in the mailbox.
+	Index   uint64
+	Payload []byte
+}
+
+// SetMailbox sets the chunk getter which is used to look up mailbox
+// entries in the network and the state store which keeps track of the
+// acknowledged messages. The mailbox is disabled until it is set.
+func (p *pss) SetMailbox(getter storage.Getter, store storage.StateStorer) {
+	p.mailboxMu.Lock()
+	defer p.mailboxMu.Unlock()
+
+	p.getter = getter
+	p.store = store
+
+	go p.sweepSeenLoop(store)
+}
+
+// SendToMailbox sends a message like Send does and also stores it in the
+// mailbox of the recipient.
+func (p *pss) SendToMailbox(ctx context.Context, topic Topic, payload []byte, stamper postage.Stamper, recipient *ecdsa.PublicKey, targets Targets) error {
+	getter, store := p.mailbox()
+	if getter == nil {
+		return ErrNoMailbox
+	}
+
+	tc, err := p.send(ctx, topic, payload, stamper, recipient, targets)
+	if err != nil {
+		return err
+	}
+
+	signer := mailboxSigner(recipient, topic)
+	owner, err := signer.EthereumAddress()
+	if err != nil {
+		return err
+	}
+	nextKey := mailboxNextKeyPrefix + owner.String()
+
+	// the index is taken only when the entry is pushed
+	unlock := p.lockMailbox(nextKey)
+	defer unlock()
+
+	// the stored index is a hint only as other senders write to the same
+	// mailbox, the first index which is not found is used
+	var index uint64
+	if err := store.Get(nextKey, &index); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return err
+	}
+	for ; ; index++ {
+		addr, err := mailboxAddress(owner.Bytes(), topic, index)
+		if err != nil {
+			return err
+		}
+		if _, err := getter.Get(ctx, storage.ModeGetRequest, addr); err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				break
+			}
+			return fmt.Errorf("get mailbox entry %d: %w", index, err)
+		}
+	}
+
+	ch, err := soc.New(mailboxID(topic, index), tc).Sign(signer)
+	if err != nil {
+		return err
+	}
+	stamp, err := stamper.Stamp(ch.Address())
+	if err != nil {
+		return err
+	}
+	if _, err := p.pusher.PushChunkToClosest(ctx, ch.WithStamp(stamp)); err != nil {
+		return fmt.Errorf("push mailbox chunk: %w", err)
+	}
+	p.metrics.TotalMailboxMessagesStoredCounter.Inc()
+
+	return store.Put(nextKey, index+1)
+}
+
+// mailboxSender is the lock of the senders to a mailbox.
+type mailboxSender struct {
+	sync.Mutex
+	waiting int
+}
+
+// lockMailbox locks the mailbox with the key for a sender until the
+// returned function is called.
+func (p *pss) lockMailbox(key string) (unlock func()) {
+	p.sendersMu.Lock()
+	s, ok := p.senders[key]
+	if !ok {
+		s = new(mailboxSender)
+		p.senders[key] = s
+	}
+	s.waiting++
+	p.sendersMu.Unlock()
+
+	s.Lock()
+	return func() {
+		s.Unlock()
+
+		p.sendersMu.Lock()
+		defer p.sendersMu.Unlock()
+
+		s.waiting--
+		if s.waiting == 0 {
+			delete(p.senders, key)
+		}
+	}
+}
+
+// Inbox returns the messages with the topic from the mailbox of the node
+// which were not returned before or delivered to a handler. The mailbox is
+// read up to the first entry which can not be retrieved and the read
+// entries are acknowledged.
+func (p *pss) Inbox(ctx context.Context, topic Topic) ([]Message, error) {
+	getter, store := p.mailbox()
+	if getter == nil {
+		return nil, ErrNoMailbox
+	}
+
+	owner, err := mailboxSigner(&p.key.PublicKey, topic).EthereumAddress()
+	if err != nil {
+		return nil, err
+	}
+	cursorKey := mailboxCursorKeyPrefix + owner.String()
+
+	var index uint64
+	if err := store.Get(cursorKey, &index); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, err
+	}
+
+	msgs := make([]Message, 0)
+	for ; ; index++ {
+		addr, err := mailboxAddress(owner.Bytes(), topic, index)
+		if err != nil {
+			return nil, err
+		}
+		ch, err := getter.Get(ctx, storage.ModeGetRequest, addr)
+		if err != nil {
+			break
+		}
+
+		s, err := soc.FromChunk(ch)
+		if err != nil {
+			p.logger.Debugf("pss mailbox: invalid entry %d: %v", index, err)
+			continue
+		}
+		tc := s.WrappedChunk()
+
+		// the cursor moves past the entry, so it is not needed to remember
+		// that the message was delivered anymore
+		seen, err := p.seen(store, tc.Address())
+		if err != nil {
+			return nil, err
+		}
+		if seen {
+			if err := store.Delete(mailboxSeenKeyPrefix + tc.Address().String()); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		_, msg, err := Unwrap(ctx, p.key, tc, []Topic{topic})
+		if err != nil {
+			p.logger.Debugf("pss mailbox: unwrap entry %d: %v", index, err)
+			continue
+		}
+		msgs = append(msgs, Message{Index: index, Payload: msg})
+	}
+	p.metrics.TotalMailboxMessagesFetchedCounter.Add(float64(len(msgs)))
+
+	if err := store.Put(cursorKey, index); err != nil {
+		return nil, err
+	}
+	return msgs, nil
+}
+
+func (p *pss) mailbox() (storage.Getter, storage.StateStorer) {
+	p.mailboxMu.Lock()
+	defer p.mailboxMu.Unlock()
+
+	return p.getter, p.store
+}
+
+// readsMailbox reports whether the node reads its mailbox for the topic,
+// in which case the messages with the topic which are delivered directly
+// may also be found in the mailbox.
+func (p *pss) readsMailbox(store storage.StateStorer, topic Topic) (bool, error) {
+	owner, err := mailboxSigner(&p.key.PublicKey, topic).EthereumAddress()
+	if err != nil {
+		return false, err
+	}
+	var index uint64
+	err = store.Get(mailboxCursorKeyPrefix+owner.String(), &index)
+	if errors.Is(err, storage.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// seen reports whether the message in the trojan chunk was delivered to a
+// handler.
+func (p *pss) seen(store storage.StateStorer, addr swarm.Address) (bool, error) {
+	var t int64
+	err := store.Get(mailboxSeenKeyPrefix+addr.String(), &t)
+	if errors.Is(err, storage.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// markSeen remembers that the message in the trojan chunk was delivered to
+// a handler, together with the time of the delivery.
+func (p *pss) markSeen(store storage.StateStorer, addr swarm.Address) error {
+	return store.Put(mailboxSeenKeyPrefix+addr.String(), time.Now().UnixNano())
+}
+
+// sweepSeen forgets the delivered messages which were marked as seen
+// before the time.
+func (p *pss) sweepSeen(store storage.StateStorer, before time.Time) error {
+	var expired []string
+	err := store.Iterate(mailboxSeenKeyPrefix, func(key, value []byte) (stop bool, err error) {
+		var t int64
+		if err := json.Unmarshal(value, &t); err != nil || t < before.UnixNano() {
+			expired = append(expired, string(key))
+		}
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, key := range expired {
+		if err := store.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sweepSeenLoop forgets the delivered messages older than mailboxRetention
+// at mailboxSweepInterval until the service is closed.
+func (p *pss) sweepSeenLoop(store storage.StateStorer) {
+	ticker := time.NewTicker(mailboxSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.sweepSeen(store, time.Now().Add(-mailboxRetention)); err != nil {
+			p.logger.Debugf("pss mailbox: sweep seen messages: %v", err)
+		}
+		select {
+		case <-p.quit:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// mailboxSigner returns the signer of the mailbox of the recipient for the
+// topic, whose key is derived from the public key of the recipient and the
+// topic.
+func mailboxSigner(recipient *ecdsa.PublicKey, topic Topic) crypto.Signer {
+	key := (*btcec.PublicKey)(recipient).SerializeCompressed()
+	h, _ := crypto.LegacyKeccak256(append(key, topic[:]...))
+	return crypto.NewDefaultSigner(crypto.Secp256k1PrivateKeyFromBytes(h))
+}
+
+// mailboxID returns the single owner chunk id of the mailbox entry at the
+// index.
+func mailboxID(topic Topic, index uint64) soc.ID {
+	b := make([]byte, len(topic)+8)
+	copy(b, topic[:])
+	binary.BigEndian.PutUint64(b[len(topic):], index)
+	id, _ := crypto.LegacyKeccak256(b)
+	return id
+}
+
+func mailboxAddress(owner []byte, topic Topic, index uint64) (swarm.Address, error) {
+	return soc.CreateAddress(mailboxID(topic, index), owner)
+}
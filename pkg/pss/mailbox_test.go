@@ -0,0 +1,259 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pss_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/pss"
+	"github.com/ethersphere/bee/pkg/pushsync"
+	pushsyncmock "github.com/ethersphere/bee/pkg/pushsync/mock"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"golang.org/x/sync/errgroup"
+)
+
+// TestMailbox sends messages to the mailbox of a recipient and checks that
+// the recipient fetches every message exactly once.
+func TestMailbox(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.New(io.Discard, 0)
+	storer := mock.NewStorer()
+
+	var pushed []swarm.Chunk
+	pushSyncService := pushsyncmock.New(func(ctx context.Context, chunk swarm.Chunk) (*pushsync.Receipt, error) {
+		pushed = append(pushed, chunk)
+		_, err := storer.Put(ctx, storage.ModePutSync, chunk)
+		return nil, err
+	})
+
+	sender := pss.New(nil, logger)
+	sender.SetPushSyncer(pushSyncService)
+
+	privkey, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recipient := pss.New(privkey, logger)
+	recipient.SetMailbox(storer, statestore.NewStateStore())
+
+	topic := pss.NewTopic("topic")
+	targets := pss.Targets([]pss.Target{pss.Target([]byte{1})})
+
+	if err := sender.SendToMailbox(ctx, topic, []byte("a"), &stamper{}, &privkey.PublicKey, targets); !errors.Is(err, pss.ErrNoMailbox) {
+		t.Fatalf("got error %v, want %v", err, pss.ErrNoMailbox)
+	}
+	sender.SetMailbox(storer, statestore.NewStateStore())
+
+	for _, msg := range []string{"a", "b"} {
+		if err := sender.SendToMailbox(ctx, topic, []byte(msg), &stamper{}, &privkey.PublicKey, targets); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	msgs, err := recipient.Inbox(ctx, topic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("got %d messages, want 2", len(msgs))
+	}
+	for i, want := range []string{"a", "b"} {
+		if msgs[i].Index != uint64(i) || string(msgs[i].Payload) != want {
+			t.Fatalf("got message %d %q, want %d %q", msgs[i].Index, msgs[i].Payload, i, want)
+		}
+	}
+
+	msgs, err = recipient.Inbox(ctx, topic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 0 {
+		t.Fatalf("got %d messages after acknowledgement, want 0", len(msgs))
+	}
+
+	// a message that was delivered directly must not be returned again
+	delivered := make(chan struct{})
+	recipient.Register(topic, func(context.Context, []byte) {
+		close(delivered)
+	})
+	pushed = nil
+	if err := sender.SendToMailbox(ctx, topic, []byte("c"), &stamper{}, &privkey.PublicKey, targets); err != nil {
+		t.Fatal(err)
+	}
+	recipient.TryUnwrap(pushed[0])
+	select {
+	case <-delivered:
+	case <-time.After(time.Second):
+		t.Fatal("message not delivered")
+	}
+
+	msgs, err = recipient.Inbox(ctx, topic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 0 {
+		t.Fatalf("got %d messages after delivery, want 0", len(msgs))
+	}
+}
+
+// TestMailboxSeen checks that only the delivered messages which may be
+// returned from the mailbox are remembered, and only until they expire.
+func TestMailboxSeen(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.New(io.Discard, 0)
+	storer := mock.NewStorer()
+
+	var pushed []swarm.Chunk
+	pushSyncService := pushsyncmock.New(func(ctx context.Context, chunk swarm.Chunk) (*pushsync.Receipt, error) {
+		pushed = append(pushed, chunk)
+		_, err := storer.Put(ctx, storage.ModePutSync, chunk)
+		return nil, err
+	})
+
+	sender := pss.New(nil, logger)
+	sender.SetPushSyncer(pushSyncService)
+	sender.SetMailbox(storer, statestore.NewStateStore())
+
+	privkey, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recipient := pss.New(privkey, logger)
+	store := statestore.NewStateStore()
+	recipient.SetMailbox(storer, store)
+
+	topic := pss.NewTopic("topic")
+	targets := pss.Targets([]pss.Target{pss.Target([]byte{1})})
+
+	delivered := make(chan struct{}, 1)
+	recipient.Register(topic, func(context.Context, []byte) {
+		delivered <- struct{}{}
+	})
+	deliver := func(msg string) {
+		t.Helper()
+		pushed = nil
+		if err := sender.Send(ctx, topic, []byte(msg), &stamper{}, &privkey.PublicKey, targets); err != nil {
+			t.Fatal(err)
+		}
+		recipient.TryUnwrap(pushed[0])
+		select {
+		case <-delivered:
+		case <-time.After(time.Second):
+			t.Fatal("message not delivered")
+		}
+	}
+	countSeen := func() int {
+		t.Helper()
+		n := 0
+		if err := store.Iterate("pss_mailbox_seen_", func(_, _ []byte) (bool, error) {
+			n++
+			return false, nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	// the mailbox of the topic is not read, so delivered messages are not
+	// remembered
+	deliver("a")
+	if n := countSeen(); n != 0 {
+		t.Fatalf("got %d seen messages, want 0", n)
+	}
+
+	if _, err := recipient.Inbox(ctx, topic); err != nil {
+		t.Fatal(err)
+	}
+	deliver("b")
+	if n := countSeen(); n != 1 {
+		t.Fatalf("got %d seen messages, want 1", n)
+	}
+
+	if err := pss.SweepMailboxSeen(recipient, store, time.Now().Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if n := countSeen(); n != 1 {
+		t.Fatalf("got %d seen messages after sweep, want 1", n)
+	}
+	if err := pss.SweepMailboxSeen(recipient, store, time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if n := countSeen(); n != 0 {
+		t.Fatalf("got %d seen messages after expiry, want 0", n)
+	}
+}
+
+// failingGetter fails to get the chunks with an error other than not found.
+type failingGetter struct {
+	storage.Storer
+	err error
+}
+
+func (g *failingGetter) Get(context.Context, storage.ModeGet, swarm.Address) (swarm.Chunk, error) {
+	return nil, g.err
+}
+
+// TestMailboxConcurrentSend checks that messages which are sent to the same
+// mailbox at the same time are stored in different entries.
+func TestMailboxConcurrentSend(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.New(io.Discard, 0)
+	storer := mock.NewStorer()
+
+	pushSyncService := pushsyncmock.New(func(ctx context.Context, chunk swarm.Chunk) (*pushsync.Receipt, error) {
+		_, err := storer.Put(ctx, storage.ModePutSync, chunk)
+		return nil, err
+	})
+
+	sender := pss.New(nil, logger)
+	sender.SetPushSyncer(pushSyncService)
+	sender.SetMailbox(storer, statestore.NewStateStore())
+
+	privkey, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recipient := pss.New(privkey, logger)
+	recipient.SetMailbox(storer, statestore.NewStateStore())
+
+	topic := pss.NewTopic("topic")
+	targets := pss.Targets([]pss.Target{pss.Target([]byte{1})})
+
+	const count = 4
+	var g errgroup.Group
+	for i := 0; i < count; i++ {
+		msg := []byte{byte(i)}
+		g.Go(func() error {
+			return sender.SendToMailbox(ctx, topic, msg, &stamper{}, &privkey.PublicKey, targets)
+		})
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs, err := recipient.Inbox(ctx, topic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != count {
+		t.Fatalf("got %d messages, want %d", len(msgs), count)
+	}
+
+	// an entry which can not be retrieved is not taken for a free one
+	errGet := errors.New("timeout")
+	sender.SetMailbox(&failingGetter{Storer: storer, err: errGet}, statestore.NewStateStore())
+	if err := sender.SendToMailbox(ctx, topic, []byte("a"), &stamper{}, &privkey.PublicKey, targets); !errors.Is(err, errGet) {
+		t.Fatalf("got error %v, want %v", err, errGet)
+	}
+}
@@ -12,6 +12,18 @@ import (
 type metrics struct {
 	TotalMessagesSentCounter prometheus.Counter
 	MessageMiningDuration    prometheus.Gauge
+	MiningRate               prometheus.Gauge
+
+	TotalMailboxMessagesStoredCounter  prometheus.Counter
+	TotalMailboxMessagesFetchedCounter prometheus.Counter
+
+	DeliveryLatency            prometheus.Histogram
+	RequestLatency             prometheus.Histogram
+	TotalRetriesCounter        prometheus.Counter
+	TotalNoReplyCounter        prometheus.Counter
+	TotalAcksSentCounter       prometheus.Counter
+	TotalResponsesSentCounter  prometheus.Counter
+	TotalRepliesDroppedCounter prometheus.Counter
 }
 
 func newMetrics() metrics {
@@ -30,6 +42,68 @@ func newMetrics() metrics {
 			Name:      "mining_duration",
 			Help:      "Time duration to mine a message.",
 		}),
+		MiningRate: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "mining_rate",
+			Help:      "Number of nonces tried per second when mining a message.",
+		}),
+		TotalMailboxMessagesStoredCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "total_mailbox_message_stored",
+			Help:      "Total messages stored in mailboxes.",
+		}),
+		TotalMailboxMessagesFetchedCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "total_mailbox_message_fetched",
+			Help:      "Total messages fetched from the mailbox.",
+		}),
+		DeliveryLatency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "delivery_latency",
+			Help:      "Histogram of time until a message is acknowledged.",
+			Buckets:   []float64{0.5, 1, 2.5, 5, 10, 30, 60, 120},
+		}),
+		RequestLatency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "request_latency",
+			Help:      "Histogram of time until a request is answered.",
+			Buckets:   []float64{0.5, 1, 2.5, 5, 10, 30, 60, 120},
+		}),
+		TotalRetriesCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "total_retries",
+			Help:      "Total messages sent again because of a missing reply.",
+		}),
+		TotalNoReplyCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "total_no_reply",
+			Help:      "Total messages which were not replied to.",
+		}),
+		TotalAcksSentCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "total_acks_sent",
+			Help:      "Total acknowledgements sent.",
+		}),
+		TotalResponsesSentCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "total_responses_sent",
+			Help:      "Total responses to requests sent.",
+		}),
+		TotalRepliesDroppedCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "total_replies_dropped",
+			Help:      "Total messages dropped because too many replies were being sent.",
+		}),
 	}
 }
 
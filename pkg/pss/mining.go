@@ -0,0 +1,155 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pss
+
+import (
+	"context"
+	random "crypto/rand"
+	"errors"
+	"io"
+	"math"
+	"runtime"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+const (
+	// defaultBenchmarkDuration is the duration of the benchmark measuring
+	// the mining rate when no message was mined yet.
+	defaultBenchmarkDuration = time.Second
+	// minRateAttempts is the minimal number of nonces tried when mining a
+	// message for the mining rate to be measured from it, as the overhead
+	// of wrapping distorts the rate of short runs.
+	minRateAttempts = 1 << 12
+	// rateWeight is the weight of a new measurement of the mining rate.
+	rateWeight = 0.3
+)
+
+// Miner mines the nonces of trojan chunks, see Wrap.
+type Miner interface {
+	// SetMiningWorkers sets the number of workers mining in parallel,
+	// all available cores if it is not positive.
+	SetMiningWorkers(int)
+	// MiningRate returns the number of nonces tried per second.
+	MiningRate(context.Context) (float64, error)
+	// BenchmarkMining measures the mining rate during the duration.
+	BenchmarkMining(context.Context, time.Duration) (float64, error)
+}
+
+// ExpectedAttempts returns the expected number of nonces tried until the
+// address of a trojan chunk has one of the targets as its prefix.
+func ExpectedAttempts(targets Targets) (float64, error) {
+	if err := checkTargets(targets); err != nil {
+		return 0, err
+	}
+	distinct := make(map[string]struct{}, len(targets))
+	for _, t := range targets {
+		distinct[string(t)] = struct{}{}
+	}
+	return math.Pow(256, float64(len(targets[0]))) / float64(len(distinct)), nil
+}
+
+// EstimateMiningTime returns the expected time of mining a trojan chunk for
+// the targets at the rate of nonces per second.
+func EstimateMiningTime(targets Targets, rate float64) (time.Duration, error) {
+	attempts, err := ExpectedAttempts(targets)
+	if err != nil {
+		return 0, err
+	}
+	if rate <= 0 {
+		return 0, errors.New("invalid mining rate")
+	}
+	return time.Duration(attempts / rate * float64(time.Second)), nil
+}
+
+// MeasureMiningRate measures the number of nonces the workers try per
+// second during the duration.
+func MeasureMiningRate(ctx context.Context, workers int, d time.Duration) (float64, error) {
+	hint := make([]byte, 8)
+	payload := make([]byte, swarm.ChunkSize-32)
+	if _, err := io.ReadFull(random.Reader, payload); err != nil {
+		return 0, err
+	}
+	h := hasher(hint, payload)
+
+	ctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+
+	start := time.Now()
+	_, attempts, err := mine(ctx, false, workers, func(nonce []byte) (swarm.Chunk, error) {
+		_, err := h(nonce)
+		return nil, err
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		return 0, err
+	}
+	return float64(attempts) / time.Since(start).Seconds(), nil
+}
+
+// SetMiningWorkers sets the number of workers mining in parallel, all
+// available cores if it is not positive.
+func (p *pss) SetMiningWorkers(n int) {
+	p.miningMu.Lock()
+	defer p.miningMu.Unlock()
+
+	p.workers = n
+}
+
+// MiningRate returns the number of nonces tried per second, as measured
+// when mining messages. The rate is measured by a benchmark if no message
+// was mined yet.
+func (p *pss) MiningRate(ctx context.Context) (float64, error) {
+	p.miningMu.Lock()
+	rate := p.rate
+	p.miningMu.Unlock()
+
+	if rate > 0 {
+		return rate, nil
+	}
+	return p.BenchmarkMining(ctx, defaultBenchmarkDuration)
+}
+
+// BenchmarkMining measures the mining rate during the duration.
+func (p *pss) BenchmarkMining(ctx context.Context, d time.Duration) (float64, error) {
+	rate, err := MeasureMiningRate(ctx, p.miningWorkers(), d)
+	if err != nil {
+		return 0, err
+	}
+
+	p.miningMu.Lock()
+	p.rate = rate
+	p.miningMu.Unlock()
+	p.metrics.MiningRate.Set(rate)
+
+	return rate, nil
+}
+
+func (p *pss) miningWorkers() int {
+	p.miningMu.Lock()
+	defer p.miningMu.Unlock()
+
+	if p.workers > 0 {
+		return p.workers
+	}
+	return runtime.NumCPU()
+}
+
+// updateMiningRate updates the mining rate with the number of nonces tried
+// during the duration of mining a message.
+func (p *pss) updateMiningRate(attempts uint64, d time.Duration) {
+	if attempts < minRateAttempts || d <= 0 {
+		return
+	}
+	rate := float64(attempts) / d.Seconds()
+
+	p.miningMu.Lock()
+	if p.rate > 0 {
+		rate = rateWeight*rate + (1-rateWeight)*p.rate
+	}
+	p.rate = rate
+	p.miningMu.Unlock()
+	p.metrics.MiningRate.Set(rate)
+}
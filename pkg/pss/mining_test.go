@@ -7,8 +7,10 @@ package pss_test
 import (
 	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/ethersphere/bee/pkg/crypto"
 	"github.com/ethersphere/bee/pkg/pss"
@@ -60,3 +62,68 @@ func BenchmarkWrap(b *testing.B) {
 	}
 
 }
+
+func TestExpectedAttempts(t *testing.T) {
+	for _, tc := range []struct {
+		targets pss.Targets
+		want    float64
+	}{
+		{newTargets(1, 1), 256},
+		{newTargets(4, 1), 64},
+		{newTargets(1, 2), 65536},
+		{pss.Targets{pss.Target{1, 2}, pss.Target{1, 2}}, 65536},
+		{newTargets(256, 3), 65536},
+	} {
+		got, err := pss.ExpectedAttempts(tc.targets)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.want {
+			t.Errorf("got %v expected attempts for %v, want %v", got, tc.targets, tc.want)
+		}
+	}
+
+	if _, err := pss.ExpectedAttempts(nil); !errors.Is(err, pss.ErrEmptyTargets) {
+		t.Fatalf("got error %v, want %v", err, pss.ErrEmptyTargets)
+	}
+}
+
+func TestEstimateMiningTime(t *testing.T) {
+	d, err := pss.EstimateMiningTime(newTargets(1, 2), 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 64 * time.Second; d != want {
+		t.Fatalf("got %v, want %v", d, want)
+	}
+
+	if _, err := pss.EstimateMiningTime(newTargets(1, 2), 0); err == nil {
+		t.Fatal("expected error for zero rate")
+	}
+}
+
+func TestMeasureMiningRate(t *testing.T) {
+	rate, err := pss.MeasureMiningRate(context.Background(), 2, 50*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate <= 0 {
+		t.Fatalf("got rate %v, want positive", rate)
+	}
+}
+
+// TestWrapCancel tests that mining stops when the context is cancelled.
+func TestWrapCancel(t *testing.T) {
+	key, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// mining a single target of the maximal length takes minutes
+	_, err = pss.Wrap(ctx, pss.NewTopic("topic"), []byte("msg"), &key.PublicKey, newTargets(1, 3))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/ethersphere/bee/pkg/logging"
 	"github.com/ethersphere/bee/pkg/postage"
 	"github.com/ethersphere/bee/pkg/pushsync"
+	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
 )
 
@@ -35,12 +36,17 @@ type Sender interface {
 
 type Interface interface {
 	Sender
+	Mailbox
+	Requester
+	Miner
 	// Register a Handler for a given Topic.
 	Register(Topic, Handler) func()
 	// TryUnwrap tries to unwrap a wrapped trojan message.
 	TryUnwrap(swarm.Chunk)
 
 	SetPushSyncer(pushSyncer pushsync.PushSyncer)
+	SetMailbox(getter storage.Getter, store storage.StateStorer)
+	SetReplier(overlay swarm.Address, stamper func() (postage.Stamper, error))
 	io.Closer
 }
 
@@ -52,6 +58,27 @@ type pss struct {
 	metrics    metrics
 	logger     logging.Logger
 	quit       chan struct{}
+
+	getter    storage.Getter
+	store     storage.StateStorer
+	mailboxMu sync.Mutex
+	// senders serializes the sends to the same mailbox
+	senders   map[string]*mailboxSender
+	sendersMu sync.Mutex
+
+	overlay           swarm.Address
+	replyStamper      func() (postage.Stamper, error)
+	responders        map[Topic][]*Responder
+	pending           map[string]*pendingReply
+	received          map[string]*receivedMessage
+	attemptTimeout    time.Duration
+	replyTargetLength int
+	repliesMu         sync.Mutex
+	replySem          chan struct{}
+
+	workers  int
+	rate     float64
+	miningMu sync.Mutex
 }
 
 // New returns a new pss service.
@@ -62,6 +89,14 @@ func New(key *ecdsa.PrivateKey, logger logging.Logger) Interface {
 		handlers: make(map[Topic][]*Handler),
 		metrics:  newMetrics(),
 		quit:     make(chan struct{}),
+		senders:  make(map[string]*mailboxSender),
+
+		responders:        make(map[Topic][]*Responder),
+		pending:           make(map[string]*pendingReply),
+		received:          make(map[string]*receivedMessage),
+		attemptTimeout:    defaultAttemptTimeout,
+		replyTargetLength: defaultReplyTargetLength,
+		replySem:          make(chan struct{}, maxConcurrentReplies),
 	}
 }
 
@@ -86,18 +121,25 @@ type Handler func(context.Context, []byte)
 // wraps it in a trojan chunk such that one of the targets is a prefix of the chunk address.
 // Uses push-sync to deliver message.
 func (p *pss) Send(ctx context.Context, topic Topic, payload []byte, stamper postage.Stamper, recipient *ecdsa.PublicKey, targets Targets) error {
+	_, err := p.send(ctx, topic, payload, stamper, recipient, targets)
+	return err
+}
+
+// send sends the message and returns the trojan chunk it was wrapped in.
+func (p *pss) send(ctx context.Context, topic Topic, payload []byte, stamper postage.Stamper, recipient *ecdsa.PublicKey, targets Targets) (swarm.Chunk, error) {
 	p.metrics.TotalMessagesSentCounter.Inc()
 
 	tStart := time.Now()
 
-	tc, err := Wrap(ctx, topic, payload, recipient, targets)
+	tc, attempts, err := wrap(ctx, topic, payload, recipient, targets, p.miningWorkers())
 	if err != nil {
-		return err
+		return nil, err
 	}
+	p.updateMiningRate(attempts, time.Since(tStart))
 
 	stamp, err := stamper.Stamp(tc.Address())
 	if err != nil {
-		return err
+		return nil, err
 	}
 	tc = tc.WithStamp(stamp)
 
@@ -105,10 +147,10 @@ func (p *pss) Send(ctx context.Context, topic Topic, payload []byte, stamper pos
 
 	// push the chunk using push sync so that it reaches it destination in network
 	if _, err = p.pusher.PushChunkToClosest(ctx, tc); err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return tc, nil
 }
 
 // Register allows the definition of a Handler func for a specific topic on the pss struct.
@@ -132,12 +174,29 @@ func (p *pss) Register(topic Topic, handler Handler) (cleanup func()) {
 	}
 }
 
+// topics returns the topics with handlers, responders or messages waiting
+// for a reply.
 func (p *pss) topics() []Topic {
 	p.handlersMu.Lock()
-	defer p.handlersMu.Unlock()
-
-	ts := make([]Topic, 0, len(p.handlers))
+	set := make(map[Topic]struct{}, len(p.handlers))
 	for t := range p.handlers {
+		set[t] = struct{}{}
+	}
+	p.handlersMu.Unlock()
+
+	p.repliesMu.Lock()
+	for t, r := range p.responders {
+		if len(r) > 0 {
+			set[t] = struct{}{}
+		}
+	}
+	for _, pr := range p.pending {
+		set[pr.topic] = struct{}{}
+	}
+	p.repliesMu.Unlock()
+
+	ts := make([]Topic, 0, len(set))
+	for t := range set {
 		ts = append(ts, t)
 	}
 
@@ -154,12 +213,35 @@ func (p *pss) TryUnwrap(c swarm.Chunk) {
 	if err != nil {
 		return // cannot unwrap
 	}
+
+	if e := new(envelope); e.UnmarshalBinary(msg) == nil {
+		p.handleEnvelope(topic, c.Address(), e)
+		return
+	}
+	p.deliver(topic, c.Address(), msg)
+}
+
+// deliver calls the handlers of the topic with the message.
+func (p *pss) deliver(topic Topic, addr swarm.Address, msg []byte) {
 	h := p.getHandlers(topic)
 	if h == nil {
 		return // no handler
 	}
 
-	ctx, cancel := context.WithCancel(ctx)
+	// the message is also stored in the mailbox if the sender used it, so
+	// remember that it was delivered in order not to return it from the
+	// inbox, if the node reads it
+	if _, store := p.mailbox(); store != nil {
+		if ok, err := p.readsMailbox(store, topic); err != nil {
+			p.logger.Debugf("pss mailbox: check mailbox: %v", err)
+		} else if ok {
+			if err := p.markSeen(store, addr); err != nil {
+				p.logger.Debugf("pss mailbox: mark message seen: %v", err)
+			}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
 	done := make(chan struct{})
 	var wg sync.WaitGroup
 	go func() {
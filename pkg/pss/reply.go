@@ -0,0 +1,482 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pss
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"errors"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+const (
+	kindReliable byte = iota + 1
+	kindAck
+	kindRequest
+	kindResponse
+)
+
+const (
+	idSize        = 32
+	publicKeySize = 33
+	signatureSize = 65
+	// defaultReplyTargetLength is the length of the overlay address prefix
+	// to which replies are sent, which must be at least the depth of the
+	// neighbourhood of the node.
+	defaultReplyTargetLength = 2
+
+	// maxAttempts is the number of times a message is sent before giving
+	// up on waiting for a reply.
+	maxAttempts           = 3
+	defaultAttemptTimeout = 30 * time.Second
+	// replyTimeout limits the time to answer a received message.
+	replyTimeout = time.Minute
+	// maxConcurrentReplies limits the number of replies mined at the same
+	// time, as mining a reply is expensive and can be requested by anyone.
+	// Messages received while the limit is reached are dropped, and their
+	// senders send them again.
+	maxConcurrentReplies = 16
+	// receivedTTL is the time for which received messages are remembered
+	// in order to detect retries.
+	receivedTTL = 10 * time.Minute
+)
+
+var (
+	// ErrNoReply is returned when the recipient did not reply to any
+	// attempt to send a message.
+	ErrNoReply = errors.New("no reply from recipient")
+	// ErrNoReplier is returned when a reply is expected before the node
+	// is set up to receive replies.
+	ErrNoReplier = errors.New("replies not available")
+
+	errInvalidEnvelope = errors.New("invalid envelope")
+
+	// envelopeMagic prefixes the payload of messages which expect or
+	// carry a reply.
+	envelopeMagic = []byte{'p', 's', 's', 1}
+)
+
+// Requester sends messages which are acknowledged or answered by the
+// recipient. The recipient must be a node as messages are correlated with
+// their replies by the node.
+type Requester interface {
+	// SendWithAck sends a message like Send does and waits until the
+	// recipient acknowledges it, sending it again if it does not.
+	SendWithAck(context.Context, Topic, []byte, postage.Stamper, *ecdsa.PublicKey, Targets) error
+	// Request sends a request to the recipient and returns the response
+	// of its Responder for the topic.
+	Request(context.Context, Topic, []byte, postage.Stamper, *ecdsa.PublicKey, Targets) ([]byte, error)
+	// RegisterResponder registers a Responder for a given Topic.
+	RegisterResponder(Topic, Responder) func()
+}
+
+// Responder returns the response to a request.
+type Responder func(context.Context, []byte) ([]byte, error)
+
+// envelope is the payload of a message which expects or carries a reply.
+// The sender of a message expecting a reply includes its public key and
+// overlay address prefix, while the reply is signed by the recipient.
+type envelope struct {
+	kind      byte
+	id        []byte
+	sender    *ecdsa.PublicKey
+	target    Target
+	signature []byte
+	payload   []byte
+}
+
+func (e *envelope) expectsReply() bool {
+	return e.kind == kindReliable || e.kind == kindRequest
+}
+
+func (e *envelope) MarshalBinary() ([]byte, error) {
+	b := append(append([]byte{}, envelopeMagic...), e.kind)
+	b = append(b, e.id...)
+	if e.expectsReply() {
+		b = append(b, crypto.EncodeSecp256k1PublicKey(e.sender)...)
+		b = append(b, byte(len(e.target)))
+		b = append(b, e.target...)
+	} else {
+		b = append(b, e.signature...)
+	}
+	return append(b, e.payload...), nil
+}
+
+func (e *envelope) UnmarshalBinary(b []byte) error {
+	if len(b) < len(envelopeMagic)+1+idSize || !bytes.Equal(b[:len(envelopeMagic)], envelopeMagic) {
+		return errInvalidEnvelope
+	}
+	b = b[len(envelopeMagic):]
+	e.kind, b = b[0], b[1:]
+	e.id, b = b[:idSize], b[idSize:]
+
+	switch e.kind {
+	case kindReliable, kindRequest:
+		if len(b) < publicKeySize+1 {
+			return errInvalidEnvelope
+		}
+		sender, err := btcec.ParsePubKey(b[:publicKeySize], btcec.S256())
+		if err != nil {
+			return errInvalidEnvelope
+		}
+		e.sender, b = (*ecdsa.PublicKey)(sender), b[publicKeySize:]
+		n := int(b[0])
+		if n == 0 || n > MaxTargetLength || len(b) < 1+n {
+			return errInvalidEnvelope
+		}
+		e.target, b = b[1:1+n], b[1+n:]
+	case kindAck, kindResponse:
+		if len(b) < signatureSize {
+			return errInvalidEnvelope
+		}
+		e.signature, b = b[:signatureSize], b[signatureSize:]
+	default:
+		return errInvalidEnvelope
+	}
+	e.payload = b
+	return nil
+}
+
+// signedData returns the data signed by the recipient of a message in
+// its reply.
+func (e *envelope) signedData() []byte {
+	return append(append([]byte{e.kind}, e.id...), e.payload...)
+}
+
+// pendingReply is a message sent by the node which waits for a reply.
+type pendingReply struct {
+	topic     Topic
+	kind      byte
+	recipient *ecdsa.PublicKey
+	c         chan []byte
+}
+
+// receivedMessage is a message received by the node which expects a reply.
+type receivedMessage struct {
+	at    time.Time
+	reply []byte // signed reply, once it was sent
+}
+
+// SetReplier sets the overlay address of the node, to which replies are
+// sent, and the function returning the stamper for the replies sent by the
+// node. The node neither acknowledges messages nor responds to requests
+// until it is set.
+func (p *pss) SetReplier(overlay swarm.Address, stamper func() (postage.Stamper, error)) {
+	p.repliesMu.Lock()
+	defer p.repliesMu.Unlock()
+
+	p.overlay = overlay
+	p.replyStamper = stamper
+}
+
+// SendWithAck sends a message like Send does and waits until the recipient
+// acknowledges it, sending it again if it does not.
+func (p *pss) SendWithAck(ctx context.Context, topic Topic, payload []byte, stamper postage.Stamper, recipient *ecdsa.PublicKey, targets Targets) error {
+	start := time.Now()
+	if _, err := p.roundTrip(ctx, kindReliable, topic, payload, stamper, recipient, targets); err != nil {
+		return err
+	}
+	p.metrics.DeliveryLatency.Observe(time.Since(start).Seconds())
+	return nil
+}
+
+// Request sends a request to the recipient and returns the response of its
+// Responder for the topic, sending the request again if there is none.
+func (p *pss) Request(ctx context.Context, topic Topic, payload []byte, stamper postage.Stamper, recipient *ecdsa.PublicKey, targets Targets) ([]byte, error) {
+	start := time.Now()
+	resp, err := p.roundTrip(ctx, kindRequest, topic, payload, stamper, recipient, targets)
+	if err != nil {
+		return nil, err
+	}
+	p.metrics.RequestLatency.Observe(time.Since(start).Seconds())
+	return resp, nil
+}
+
+// RegisterResponder registers a Responder for a given Topic. Requests are
+// answered by the first registered Responder for their topic.
+func (p *pss) RegisterResponder(topic Topic, responder Responder) (cleanup func()) {
+	p.repliesMu.Lock()
+	defer p.repliesMu.Unlock()
+
+	p.responders[topic] = append(p.responders[topic], &responder)
+
+	return func() {
+		p.repliesMu.Lock()
+		defer p.repliesMu.Unlock()
+
+		r := p.responders[topic]
+		for i := 0; i < len(r); i++ {
+			if r[i] == &responder {
+				p.responders[topic] = append(r[:i], r[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// roundTrip sends the message as an envelope of the kind and waits for the
+// reply of the recipient.
+func (p *pss) roundTrip(ctx context.Context, kind byte, topic Topic, payload []byte, stamper postage.Stamper, recipient *ecdsa.PublicKey, targets Targets) ([]byte, error) {
+	overlay, _ := p.replier()
+	if overlay.IsZero() {
+		return nil, ErrNoReplier
+	}
+
+	id := make([]byte, idSize)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	e := &envelope{
+		kind:    kind,
+		id:      id,
+		sender:  &p.key.PublicKey,
+		target:  Target(overlay.Bytes()[:p.replyTargetLength]),
+		payload: payload,
+	}
+	msg, err := e.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	// the kind of the reply follows the kind of the message
+	replyC := make(chan []byte, 1)
+	p.repliesMu.Lock()
+	p.pending[string(id)] = &pendingReply{topic: topic, kind: kind + 1, recipient: recipient, c: replyC}
+	p.repliesMu.Unlock()
+	defer func() {
+		p.repliesMu.Lock()
+		delete(p.pending, string(id))
+		p.repliesMu.Unlock()
+	}()
+
+	for i := 0; i < maxAttempts; i++ {
+		if i > 0 {
+			p.metrics.TotalRetriesCounter.Inc()
+		}
+		if err := p.Send(ctx, topic, msg, stamper, recipient, targets); err != nil {
+			return nil, err
+		}
+
+		timer := time.NewTimer(p.attemptTimeout)
+		select {
+		case reply := <-replyC:
+			timer.Stop()
+			return reply, nil
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+	p.metrics.TotalNoReplyCounter.Inc()
+	return nil, ErrNoReply
+}
+
+func (p *pss) replier() (swarm.Address, func() (postage.Stamper, error)) {
+	p.repliesMu.Lock()
+	defer p.repliesMu.Unlock()
+
+	return p.overlay, p.replyStamper
+}
+
+// handleEnvelope handles a received message which expects or carries a
+// reply.
+func (p *pss) handleEnvelope(topic Topic, addr swarm.Address, e *envelope) {
+	switch e.kind {
+	case kindAck, kindResponse:
+		p.resolve(topic, e)
+	case kindReliable:
+		if p.getHandlers(topic) == nil {
+			return // no handler, so the message is not acknowledged
+		}
+		if !p.acquireReply() {
+			return
+		}
+		reply, first := p.receive(e.id)
+		if first {
+			p.deliver(topic, addr, e.payload)
+		}
+		go func() {
+			defer p.releaseReply()
+
+			if !first {
+				p.resend(topic, e, reply)
+				return
+			}
+			p.reply(topic, e, kindAck, nil)
+		}()
+	case kindRequest:
+		responder := p.getResponder(topic)
+		if responder == nil {
+			return // no responder
+		}
+		if !p.acquireReply() {
+			return
+		}
+		reply, first := p.receive(e.id)
+		go func() {
+			defer p.releaseReply()
+
+			if !first {
+				p.resend(topic, e, reply)
+				return
+			}
+
+			ctx, cancel := p.replyContext()
+			defer cancel()
+
+			resp, err := responder(ctx, e.payload)
+			if err != nil {
+				p.logger.Debugf("pss: respond to request: %v", err)
+				return
+			}
+			p.reply(topic, e, kindResponse, resp)
+		}()
+	}
+}
+
+// acquireReply takes one of the slots for sending replies and reports
+// whether there was one free. The slot must be released with releaseReply.
+func (p *pss) acquireReply() bool {
+	select {
+	case p.replySem <- struct{}{}:
+		return true
+	default:
+		p.metrics.TotalRepliesDroppedCounter.Inc()
+		p.logger.Debugf("pss: too many replies, message dropped")
+		return false
+	}
+}
+
+func (p *pss) releaseReply() {
+	<-p.replySem
+}
+
+// resolve passes the reply to the message waiting for it if the reply is
+// signed by the recipient of the message.
+func (p *pss) resolve(topic Topic, e *envelope) {
+	p.repliesMu.Lock()
+	pr, ok := p.pending[string(e.id)]
+	p.repliesMu.Unlock()
+	if !ok || pr.topic != topic || pr.kind != e.kind {
+		return
+	}
+
+	signer, err := crypto.Recover(e.signature, e.signedData())
+	if err != nil || !signer.Equal(pr.recipient) {
+		p.logger.Debugf("pss: reply not signed by recipient")
+		return
+	}
+
+	select {
+	case pr.c <- e.payload:
+	default:
+	}
+}
+
+// receive records the message with the id as received and reports whether
+// it was received for the first time, or returns the reply sent to it
+// before.
+func (p *pss) receive(id []byte) (reply []byte, first bool) {
+	p.repliesMu.Lock()
+	defer p.repliesMu.Unlock()
+
+	now := time.Now()
+	for k, r := range p.received {
+		if now.Sub(r.at) > receivedTTL {
+			delete(p.received, k)
+		}
+	}
+
+	if r, ok := p.received[string(id)]; ok {
+		return r.reply, false
+	}
+	p.received[string(id)] = &receivedMessage{at: now}
+	return nil, true
+}
+
+// reply sends a signed reply of the kind to the sender of the message.
+func (p *pss) reply(topic Topic, e *envelope, kind byte, payload []byte) {
+	r := &envelope{kind: kind, id: e.id, payload: payload}
+	sig, err := crypto.NewDefaultSigner(p.key).Sign(r.signedData())
+	if err != nil {
+		p.logger.Debugf("pss: sign reply: %v", err)
+		return
+	}
+	r.signature = sig
+	msg, err := r.MarshalBinary()
+	if err != nil {
+		p.logger.Debugf("pss: marshal reply: %v", err)
+		return
+	}
+
+	p.repliesMu.Lock()
+	if rm, ok := p.received[string(e.id)]; ok {
+		rm.reply = msg
+	}
+	p.repliesMu.Unlock()
+
+	p.resend(topic, e, msg)
+	if kind == kindAck {
+		p.metrics.TotalAcksSentCounter.Inc()
+	} else {
+		p.metrics.TotalResponsesSentCounter.Inc()
+	}
+}
+
+// resend sends the reply to the sender of the message again, as the sender
+// did not receive it if the message is received again. Nothing is sent if
+// there is no reply yet.
+func (p *pss) resend(topic Topic, e *envelope, reply []byte) {
+	if reply == nil {
+		return
+	}
+	_, stamperFn := p.replier()
+	if stamperFn == nil {
+		return
+	}
+	stamper, err := stamperFn()
+	if err != nil {
+		p.logger.Debugf("pss: reply stamper: %v", err)
+		return
+	}
+
+	ctx, cancel := p.replyContext()
+	defer cancel()
+	if err := p.Send(ctx, topic, reply, stamper, e.sender, Targets{e.target}); err != nil {
+		p.logger.Debugf("pss: send reply: %v", err)
+	}
+}
+
+func (p *pss) getResponder(topic Topic) Responder {
+	p.repliesMu.Lock()
+	defer p.repliesMu.Unlock()
+
+	if r := p.responders[topic]; len(r) > 0 {
+		return *r[0]
+	}
+	return nil
+}
+
+// replyContext returns the context for replying to a message, which is
+// cancelled on shutdown.
+func (p *pss) replyContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(context.Background(), replyTimeout)
+	go func() {
+		select {
+		case <-p.quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
@@ -0,0 +1,180 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pss_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/pss"
+	"github.com/ethersphere/bee/pkg/pushsync"
+	pushsyncmock "github.com/ethersphere/bee/pkg/pushsync/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// newReplyPair returns two pss instances which deliver pushed messages to
+// each other. The drop function is called for every pushed chunk and the
+// chunk is not delivered if it returns true.
+func newReplyPair(t *testing.T, drop func(swarm.Chunk) bool) (a, b pss.Interface, keyB *ecdsa.PublicKey) {
+	t.Helper()
+
+	logger := logging.New(io.Discard, 0)
+	nodes := make([]pss.Interface, 2)
+	keys := make([]*ecdsa.PublicKey, 2)
+	for i := range nodes {
+		privkey, err := crypto.GenerateSecp256k1Key()
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := pss.New(privkey, logger)
+		pss.SetAttemptTimeout(p, 500*time.Millisecond)
+		// short reply targets keep mining the replies fast
+		pss.SetReplyTargetLength(p, 1)
+		t.Cleanup(func() { _ = p.Close() })
+		nodes[i] = p
+		keys[i] = &privkey.PublicKey
+	}
+	for i := range nodes {
+		other := nodes[1-i]
+		nodes[i].SetPushSyncer(pushsyncmock.New(func(ctx context.Context, chunk swarm.Chunk) (*pushsync.Receipt, error) {
+			if drop == nil || !drop(chunk) {
+				go other.TryUnwrap(chunk)
+			}
+			return nil, nil
+		}))
+		overlay := swarm.MustParseHexAddress("0100000000000000000000000000000000000000000000000000000000000000")
+		nodes[i].SetReplier(overlay, func() (postage.Stamper, error) { return &stamper{}, nil })
+	}
+	return nodes[0], nodes[1], keys[1]
+}
+
+func TestSendWithAck(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		dropped bool
+	)
+	// drop the first message in order to make the sender retry
+	a, b, recipient := newReplyPair(t, func(swarm.Chunk) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		d := !dropped
+		dropped = true
+		return d
+	})
+
+	topic := pss.NewTopic("topic")
+	received := make(chan []byte, 2)
+	b.Register(topic, func(_ context.Context, m []byte) {
+		received <- m
+	})
+
+	if err := a.SendWithAck(context.Background(), topic, []byte("data"), &stamper{}, recipient, pss.Targets{pss.Target{1}}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case m := <-received:
+		if string(m) != "data" {
+			t.Fatalf("got message %q, want %q", m, "data")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("message not delivered")
+	}
+}
+
+func TestSendWithAckNoReply(t *testing.T) {
+	a, _, recipient := newReplyPair(t, func(swarm.Chunk) bool { return true })
+
+	err := a.SendWithAck(context.Background(), pss.NewTopic("topic"), []byte("data"), &stamper{}, recipient, pss.Targets{pss.Target{1}})
+	if !errors.Is(err, pss.ErrNoReply) {
+		t.Fatalf("got error %v, want %v", err, pss.ErrNoReply)
+	}
+}
+
+func TestSendWithAckNoHandler(t *testing.T) {
+	a, _, recipient := newReplyPair(t, nil)
+
+	err := a.SendWithAck(context.Background(), pss.NewTopic("topic"), []byte("data"), &stamper{}, recipient, pss.Targets{pss.Target{1}})
+	if !errors.Is(err, pss.ErrNoReply) {
+		t.Fatalf("got error %v, want %v", err, pss.ErrNoReply)
+	}
+}
+
+func TestSendWithAckTooManyReplies(t *testing.T) {
+	a, b, recipient := newReplyPair(t, nil)
+	pss.SetMaxConcurrentReplies(b, 0)
+
+	topic := pss.NewTopic("topic")
+	received := make(chan []byte, 3)
+	b.Register(topic, func(_ context.Context, m []byte) {
+		received <- m
+	})
+
+	err := a.SendWithAck(context.Background(), topic, []byte("data"), &stamper{}, recipient, pss.Targets{pss.Target{1}})
+	if !errors.Is(err, pss.ErrNoReply) {
+		t.Fatalf("got error %v, want %v", err, pss.ErrNoReply)
+	}
+	if len(received) != 0 {
+		t.Fatal("dropped message delivered")
+	}
+}
+
+func TestEnvelopeTargetLength(t *testing.T) {
+	privkey, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		target pss.Target
+		valid  bool
+	}{
+		{target: pss.Target{}, valid: false},
+		{target: make(pss.Target, 1), valid: true},
+		{target: make(pss.Target, pss.MaxTargetLength), valid: true},
+		{target: make(pss.Target, pss.MaxTargetLength+1), valid: false},
+		{target: make(pss.Target, 255), valid: false},
+	} {
+		if got := pss.IsEnvelope(pss.ReliableEnvelope(&privkey.PublicKey, tc.target)); got != tc.valid {
+			t.Errorf("target of length %d: got valid %t, want %t", len(tc.target), got, tc.valid)
+		}
+	}
+}
+
+func TestRequest(t *testing.T) {
+	a, b, recipient := newReplyPair(t, nil)
+
+	topic := pss.NewTopic("topic")
+	b.RegisterResponder(topic, func(_ context.Context, m []byte) ([]byte, error) {
+		return append([]byte("re: "), m...), nil
+	})
+
+	resp, err := a.Request(context.Background(), topic, []byte("ping"), &stamper{}, recipient, pss.Targets{pss.Target{1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(resp) != "re: ping" {
+		t.Fatalf("got response %q, want %q", resp, "re: ping")
+	}
+}
+
+func TestRequestNoReplier(t *testing.T) {
+	privkey, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := pss.New(privkey, logging.New(io.Discard, 0))
+
+	_, err = p.Request(context.Background(), pss.NewTopic("topic"), []byte("ping"), &stamper{}, &privkey.PublicKey, pss.Targets{pss.Target{1}})
+	if !errors.Is(err, pss.ErrNoReplier) {
+		t.Fatalf("got error %v, want %v", err, pss.ErrNoReplier)
+	}
+}
@@ -14,6 +14,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"runtime"
+	"sync/atomic"
 
 	"github.com/btcsuite/btcd/btcec"
 	"github.com/ethersphere/bee/pkg/bmtpool"
@@ -55,6 +57,9 @@ type Targets []Target
 const (
 	// MaxPayloadSize is the maximum allowed payload size for the Message type, in bytes
 	MaxPayloadSize = swarm.ChunkSize - 3*swarm.HashSize
+	// MaxTargetLength is the maximum length of a target in bytes, in order to
+	// prevent grieving by excess computation
+	MaxTargetLength = 3
 )
 
 // Wrap creates a new serialised message with the given topic, payload and recipient public key used
@@ -68,15 +73,24 @@ const (
 // - plaintext length encoding
 // - integrity protection
 // message:
+//
+// Mining uses all available cores.
 func Wrap(ctx context.Context, topic Topic, msg []byte, recipient *ecdsa.PublicKey, targets Targets) (swarm.Chunk, error) {
+	chunk, _, err := wrap(ctx, topic, msg, recipient, targets, runtime.NumCPU())
+	return chunk, err
+}
+
+// wrap wraps the message in a trojan chunk mined by the number of workers
+// and returns the number of nonces tried.
+func wrap(ctx context.Context, topic Topic, msg []byte, recipient *ecdsa.PublicKey, targets Targets, workers int) (swarm.Chunk, uint64, error) {
 	if len(msg) > MaxPayloadSize {
-		return nil, ErrPayloadTooBig
+		return nil, 0, ErrPayloadTooBig
 	}
 
 	// integrity protection and plaintext msg length encoding
 	integrity, err := crypto.LegacyKeccak256(msg)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	binary.BigEndian.PutUint16(integrity[:2], uint16(len(msg)))
 
@@ -85,11 +99,11 @@ func Wrap(ctx context.Context, topic Topic, msg []byte, recipient *ecdsa.PublicK
 	// use el-Gamal with ECDH on an ephemeral key, recipient public key and topic as salt
 	enc, ephpub, err := elgamal.NewEncryptor(recipient, topic[:], 4032, swarm.NewHasher)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	ciphertext, err := enc.Encrypt(plaintext)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	// prepend serialised ephemeral public key to the ciphertext
@@ -101,14 +115,14 @@ func Wrap(ctx context.Context, topic Topic, msg []byte, recipient *ecdsa.PublicK
 	odd := ephpubBytes[0]&0x1 != 0
 
 	if err := checkTargets(targets); err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	targetsLen := len(targets[0])
 
 	// topic hash, the first 8 bytes is used as the span of the chunk
 	hash, err := crypto.LegacyKeccak256(append(enc.Key(), topic[:]...))
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	hint := hash[:8]
 	h := hasher(hint, payload)
@@ -127,7 +141,7 @@ func Wrap(ctx context.Context, topic Topic, msg []byte, recipient *ecdsa.PublicK
 		chunk := swarm.NewChunk(swarm.NewAddress(hash), append(hint, append(nonce, payload...)...))
 		return chunk, nil
 	}
-	return mine(ctx, odd, f)
+	return mine(ctx, odd, workers, f)
 }
 
 // Unwrap takes a chunk, a topic and a private key, and tries to decrypt the payload
@@ -201,10 +215,14 @@ func contains(col Targets, elem []byte) bool {
 }
 
 // mine iteratively enumerates different nonces until the address (BMT hash) of the chunkhas one of the targets as its prefix
-func mine(ctx context.Context, odd bool, f func(nonce []byte) (swarm.Chunk, error)) (swarm.Chunk, error) {
+// the nonces are enumerated by the number of workers in parallel, it returns the number of nonces tried
+func mine(ctx context.Context, odd bool, workers int, f func(nonce []byte) (swarm.Chunk, error)) (swarm.Chunk, uint64, error) {
+	if workers < 1 {
+		workers = 1
+	}
 	initnonce := make([]byte, 32)
 	if _, err := io.ReadFull(random.Reader, initnonce); err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	if odd {
 		initnonce[28] |= 0x01
@@ -214,11 +232,14 @@ func mine(ctx context.Context, odd bool, f func(nonce []byte) (swarm.Chunk, erro
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 	eg, ctx := errgroup.WithContext(ctx)
-	result := make(chan swarm.Chunk, 8)
-	for i := 0; i < 8; i++ {
+	result := make(chan swarm.Chunk, workers+1)
+	var attempts uint64
+	for i := 0; i < workers; i++ {
 		eg.Go(func() error {
 			nonce := make([]byte, 32)
 			copy(nonce, initnonce)
+			var n uint64
+			defer func() { atomic.AddUint64(&attempts, n) }()
 			for {
 				select {
 				case <-ctx.Done():
@@ -228,6 +249,7 @@ func mine(ctx context.Context, odd bool, f func(nonce []byte) (swarm.Chunk, erro
 				if _, err := io.ReadFull(random.Reader, nonce[:4]); err != nil {
 					return err
 				}
+				n++
 				res, err := f(nonce)
 				if err != nil {
 					return err
@@ -246,9 +268,12 @@ func mine(ctx context.Context, odd bool, f func(nonce []byte) (swarm.Chunk, erro
 	}()
 	r := <-result
 	if r == nil {
-		return nil, err
+		return nil, atomic.LoadUint64(&attempts), err
 	}
-	return r, nil
+	// wait for the workers in order to count all attempts
+	cancel()
+	_ = eg.Wait()
+	return r, atomic.LoadUint64(&attempts), nil
 }
 
 // extracts ephemeral public key from the chunk data to use with el-Gamal
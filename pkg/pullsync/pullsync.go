@@ -27,6 +27,9 @@ import (
 	"github.com/ethersphere/bee/pkg/soc"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/ethersphere/bee/pkg/tracing"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
 )
 
 const (
@@ -70,6 +73,7 @@ type Syncer struct {
 	streamer   p2p.Streamer
 	metrics    metrics
 	logger     logging.Logger
+	tracer     *tracing.Tracer
 	storage    pullstorage.Storer
 	quit       chan struct{}
 	wg         sync.WaitGroup
@@ -83,7 +87,7 @@ type Syncer struct {
 	io.Closer
 }
 
-func New(streamer p2p.Streamer, storage pullstorage.Storer, unwrap func(swarm.Chunk), validStamp postage.ValidStampFn, logger logging.Logger) *Syncer {
+func New(streamer p2p.Streamer, storage pullstorage.Storer, unwrap func(swarm.Chunk), validStamp postage.ValidStampFn, logger logging.Logger, tracer *tracing.Tracer) *Syncer {
 	return &Syncer{
 		streamer:   streamer,
 		storage:    storage,
@@ -91,6 +95,7 @@ func New(streamer p2p.Streamer, storage pullstorage.Storer, unwrap func(swarm.Ch
 		unwrap:     unwrap,
 		validStamp: validStamp,
 		logger:     logger,
+		tracer:     tracer,
 		ruidCtx:    make(map[string]map[uint32]func()),
 		wg:         sync.WaitGroup{},
 		quit:       make(chan struct{}),
@@ -123,6 +128,14 @@ func (s *Syncer) Protocol() p2p.ProtocolSpec {
 // If the requested interval is too large, the downstream peer has the liberty to
 // provide less chunks than requested.
 func (s *Syncer) SyncInterval(ctx context.Context, peer swarm.Address, bin uint8, from, to uint64) (topmost uint64, ruid uint32, err error) {
+	span, _, ctx := s.tracer.StartSpanFromContext(ctx, "pullsync-sync-interval", s.logger, opentracing.Tag{Key: "peer", Value: peer.String()}, opentracing.Tag{Key: "bin", Value: bin}, opentracing.Tag{Key: "from", Value: from}, opentracing.Tag{Key: "to", Value: to})
+	defer func() {
+		if err != nil {
+			ext.LogError(span, err)
+		}
+		span.Finish()
+	}()
+
 	var ru pb.Ruid
 	stream, err := s.streamer.NewStream(ctx, peer, nil, protocolName, protocolVersion, streamName)
 	if err != nil {
@@ -291,6 +304,13 @@ func (s *Syncer) handler(ctx context.Context, p p2p.Peer, stream p2p.Stream) (er
 	if logMore {
 		s.logger.Debugf("pullsync: peer %s pulling with ruid %d", p.Address.String(), ru.Ruid)
 	}
+	span, _, ctx := s.tracer.StartSpanFromContext(ctx, "pullsync-handler", s.logger, opentracing.Tag{Key: "peer", Value: p.Address.String()})
+	defer func() {
+		if err != nil {
+			ext.LogError(span, err)
+		}
+		span.Finish()
+	}()
 	ctx, cancel := context.WithCancel(ctx)
 
 	s.ruidMtx.Lock()
@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/events"
 	"github.com/ethersphere/bee/pkg/logging"
 	"github.com/ethersphere/bee/pkg/postage"
 	"github.com/ethersphere/bee/pkg/pushsync"
@@ -52,6 +53,7 @@ type Service struct {
 	attempts          *attempts
 	sem               chan struct{}
 	smugler           chan OpChan
+	events            *events.Bus
 }
 
 var (
@@ -68,7 +70,7 @@ var (
 
 const chunkStoreTimeout = 2 * time.Second
 
-func New(networkID uint64, storer storage.Storer, depther topology.NeighborhoodDepther, pushSyncer pushsync.PushSyncer, validStamp postage.ValidStampFn, tagger *tags.Tags, logger logging.Logger, tracer *tracing.Tracer, warmupTime time.Duration) *Service {
+func New(networkID uint64, storer storage.Storer, depther topology.NeighborhoodDepther, pushSyncer pushsync.PushSyncer, validStamp postage.ValidStampFn, tagger *tags.Tags, logger logging.Logger, tracer *tracing.Tracer, warmupTime time.Duration, eventBus *events.Bus) *Service {
 	p := &Service{
 		networkID:         networkID,
 		storer:            storer,
@@ -84,6 +86,7 @@ func New(networkID uint64, storer storage.Storer, depther topology.NeighborhoodD
 		attempts:          &attempts{attempts: make(map[string]int)},
 		sem:               make(chan struct{}, concurrentPushes),
 		smugler:           make(chan OpChan),
+		events:            eventBus,
 	}
 	go p.chunksWorker(warmupTime, tracer)
 	return p
@@ -258,6 +261,8 @@ func (s *Service) pushChunk(ctx context.Context, ch swarm.Chunk, logger *logrus.
 	if err = s.storer.Set(ctx, storage.ModeSetSync, ch.Address()); err != nil {
 		return fmt.Errorf("pusher: set sync: %w", err)
 	}
+	s.events.Publish(events.TypeChunkSynced, events.ChunkData{Address: ch.Address()})
+
 	if ch.TagID() > 0 {
 		// for individual chunks uploaded using the
 		// /chunks api endpoint the tag will be missing
@@ -29,6 +29,8 @@ import (
 	"github.com/ethersphere/bee/pkg/topology"
 	"github.com/ethersphere/bee/pkg/tracing"
 	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/sirupsen/logrus"
 )
 
 const (
@@ -425,7 +427,10 @@ func (ps *PushSync) pushToClosest(ctx context.Context, ch swarm.Chunk, origin bo
 			if ps.warmedUp() && !errors.Is(result.err, accounting.ErrOverdraft) {
 				ps.skipList.Add(ch.Address(), result.peer, sanctionWait)
 				ps.metrics.TotalSkippedPeers.Inc()
-				logger.Debugf("pushsync: adding to skiplist peer %s", result.peer.String())
+				logger.WithFields(logrus.Fields{
+					logging.PeerField:  result.peer,
+					logging.ChunkField: ch.Address(),
+				}).Debug("pushsync: adding peer to skiplist")
 			}
 
 			if result.err == nil {
@@ -433,7 +438,10 @@ func (ps *PushSync) pushToClosest(ctx context.Context, ch swarm.Chunk, origin bo
 			}
 
 			ps.metrics.TotalFailedSendAttempts.Inc()
-			logger.Debugf("pushsync: could not push to peer %s: %v", result.peer, result.err)
+			logger.WithFields(logrus.Fields{
+				logging.PeerField:  result.peer,
+				logging.ChunkField: ch.Address(),
+			}).Debugf("pushsync: could not push to peer: %v", result.err)
 
 			// pushPeer returned early, do not count as an attempt
 			if !result.pushed {
@@ -472,7 +480,13 @@ func (ps *PushSync) pushPeer(ctx context.Context, resultChan chan<- receiptResul
 		now     = time.Now()
 	)
 
+	span, _, ctx := ps.tracer.StartSpanFromContext(ctx, "pushsync-push-peer", ps.logger, opentracing.Tag{Key: "address", Value: ch.Address().String()}, opentracing.Tag{Key: "peer", Value: peer.String()})
+
 	defer func() {
+		if err != nil {
+			ext.LogError(span, err)
+		}
+		span.Finish()
 		select {
 		case resultChan <- receiptResult{pushTime: now, peer: peer, err: err, pushed: pushed, receipt: &receipt}:
 		case <-doneChan:
@@ -0,0 +1,124 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package dnslink provides a name resolution client which resolves names
+// from DNSLink TXT records, eg. "_dnslink.example.com" with the value
+// "dnslink=/swarm/<reference>".
+package dnslink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/resolver/client"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+const (
+	dnslinkSubdomain = "_dnslink."
+	dnslinkPrefix    = "dnslink=/swarm/"
+	lookupTimeout    = 10 * time.Second
+)
+
+// Address is the swarm bzz address.
+type Address = swarm.Address
+
+// Make sure Client implements the resolver.Client interface.
+var _ client.Interface = (*Client)(nil)
+
+var (
+	// ErrResolveFailed denotes that a name could not be resolved.
+	ErrResolveFailed = errors.New("resolve failed")
+	// ErrNoSwarmRecord denotes that there is no DNSLink TXT record with a
+	// swarm reference for the name.
+	ErrNoSwarmRecord = errors.New("no swarm dnslink record")
+)
+
+// Client is a name resolution client that looks up DNSLink TXT records,
+// either with the system resolver or with the DNS server at the endpoint.
+type Client struct {
+	endpoint  string
+	lookupFn  func(ctx context.Context, name string) ([]string, error)
+	connected bool
+}
+
+// Option is a function that applies an option to a Client.
+type Option func(*Client)
+
+// NewClient will return a new Client. An empty endpoint uses the system
+// resolver, otherwise the endpoint is the host:port of a DNS server.
+func NewClient(endpoint string, opts ...Option) (client.Interface, error) {
+	c := &Client{
+		endpoint:  endpoint,
+		connected: true,
+	}
+
+	for _, o := range opts {
+		o(c)
+	}
+
+	if c.lookupFn == nil {
+		r := net.DefaultResolver
+		if endpoint != "" {
+			if _, _, err := net.SplitHostPort(endpoint); err != nil {
+				endpoint = net.JoinHostPort(endpoint, "53")
+			}
+			r = &net.Resolver{
+				PreferGo: true,
+				Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+					var d net.Dialer
+					return d.DialContext(ctx, network, endpoint)
+				},
+			}
+		}
+		c.lookupFn = r.LookupTXT
+	}
+
+	return c, nil
+}
+
+// IsConnected returns true until the client is closed.
+func (c *Client) IsConnected() bool {
+	return c.connected
+}
+
+// Endpoint returns the DNS server used by the client.
+func (c *Client) Endpoint() string {
+	return c.endpoint
+}
+
+// Resolve implements the resolver.Client interface.
+func (c *Client) Resolve(name string) (Address, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), lookupTimeout)
+	defer cancel()
+
+	records, err := c.lookupFn(ctx, dnslinkSubdomain+strings.TrimSuffix(name, "."))
+	if err != nil {
+		return swarm.ZeroAddress, fmt.Errorf("%v: %w", err, ErrResolveFailed)
+	}
+
+	for _, r := range records {
+		if !strings.HasPrefix(r, dnslinkPrefix) {
+			continue
+		}
+		// The reference may be followed by a path, which is not supported.
+		ref := strings.TrimPrefix(r, dnslinkPrefix)
+		if i := strings.Index(ref, "/"); i >= 0 {
+			ref = ref[:i]
+		}
+		return swarm.ParseHexAddress(ref)
+	}
+
+	return swarm.ZeroAddress, fmt.Errorf("%s: %w", name, ErrNoSwarmRecord)
+}
+
+// Close marks the client as disconnected.
+func (c *Client) Close() error {
+	c.connected = false
+	return nil
+}
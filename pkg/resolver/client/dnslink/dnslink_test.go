@@ -0,0 +1,83 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dnslink_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/resolver/client/dnslink"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestResolve(t *testing.T) {
+	const ref = "aaf2a3c9e8dcfc4c5ba9ccbba46a7dd8f1e4e5b4a4e5b2d93b1c1c4dab7c4e6f"
+	errLookup := errors.New("lookup error")
+
+	testCases := []struct {
+		desc     string
+		name     string
+		records  map[string][]string
+		wantAdr  swarm.Address
+		wantErr  error
+		lookupFn func(context.Context, string) ([]string, error)
+	}{
+		{
+			desc: "lookup error",
+			name: "example.com",
+			lookupFn: func(context.Context, string) ([]string, error) {
+				return nil, errLookup
+			},
+			wantErr: dnslink.ErrResolveFailed,
+		},
+		{
+			desc: "no swarm record",
+			name: "example.com",
+			records: map[string][]string{
+				"_dnslink.example.com": {"dnslink=/ipfs/QmHash", "v=spf1 -all"},
+			},
+			wantErr: dnslink.ErrNoSwarmRecord,
+		},
+		{
+			desc: "swarm record",
+			name: "example.com",
+			records: map[string][]string{
+				"_dnslink.example.com": {"v=spf1 -all", "dnslink=/swarm/" + ref},
+			},
+			wantAdr: swarm.MustParseHexAddress(ref),
+		},
+		{
+			desc: "swarm record with path",
+			name: "example.com.",
+			records: map[string][]string{
+				"_dnslink.example.com": {"dnslink=/swarm/" + ref + "/index.html"},
+			},
+			wantAdr: swarm.MustParseHexAddress(ref),
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			lookupFn := tC.lookupFn
+			if lookupFn == nil {
+				lookupFn = func(_ context.Context, name string) ([]string, error) {
+					return tC.records[name], nil
+				}
+			}
+			cl, err := dnslink.NewClient("", dnslink.WithLookupFunc(lookupFn))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			addr, err := cl.Resolve(tC.name)
+			if !errors.Is(err, tC.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tC.wantErr)
+			}
+			if !addr.Equal(tC.wantAdr) {
+				t.Errorf("got address %s, want %s", addr, tC.wantAdr)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dnslink
+
+import "context"
+
+// WithLookupFunc will set the TXT record lookup function implementation.
+func WithLookupFunc(fn func(ctx context.Context, name string) ([]string, error)) Option {
+	return func(c *Client) {
+		c.lookupFn = fn
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package feed provides a name resolution client which maps names to feeds
+// and resolves them to the reference of the latest feed update.
+package feed
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/encryption"
+	"github.com/ethersphere/bee/pkg/feeds"
+	"github.com/ethersphere/bee/pkg/resolver/client"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+const lookupTimeout = 30 * time.Second
+
+// Address is the swarm bzz address.
+type Address = swarm.Address
+
+// Make sure Client implements the resolver.Client interface.
+var _ client.Interface = (*Client)(nil)
+
+var (
+	// ErrNotFound denotes that the name is not in the mapping file.
+	ErrNotFound = errors.New("name not found")
+	// ErrNoUpdate denotes that the feed has no updates.
+	ErrNoUpdate = errors.New("feed has no updates")
+	// ErrInvalidUpdate denotes that the latest feed update does not contain
+	// a swarm reference.
+	ErrInvalidUpdate = errors.New("invalid feed update")
+)
+
+// Client is a name resolution client that resolves names to the latest
+// update of a sequence feed. The feeds are read from a mapping file, where
+// every line contains a name and the hex encoded owner and topic of a feed.
+type Client struct {
+	path    string
+	factory feeds.Factory
+	names   map[string]*feeds.Feed
+}
+
+// NewClient reads the mapping file at path and returns a new Client which
+// looks up the feeds with the factory.
+func NewClient(path string, factory feeds.Factory) (client.Interface, error) {
+	mapping, err := client.ReadMappingFile(path, 2)
+	if err != nil {
+		return nil, fmt.Errorf("read mapping: %w", err)
+	}
+
+	names := make(map[string]*feeds.Feed, len(mapping))
+	for name, fields := range mapping {
+		owner, err := hex.DecodeString(strings.TrimPrefix(fields[0], "0x"))
+		if err != nil || len(owner) != common.AddressLength {
+			return nil, fmt.Errorf("name %s: invalid owner: %w", name, client.ErrInvalidMapping)
+		}
+		topic, err := hex.DecodeString(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("name %s: invalid topic: %w", name, client.ErrInvalidMapping)
+		}
+		names[name] = feeds.New(topic, common.BytesToAddress(owner))
+	}
+
+	return &Client{
+		path:    path,
+		factory: factory,
+		names:   names,
+	}, nil
+}
+
+// IsConnected always returns true as the mapping is loaded on creation.
+func (c *Client) IsConnected() bool {
+	return true
+}
+
+// Endpoint returns the path of the mapping file.
+func (c *Client) Endpoint() string {
+	return c.path
+}
+
+// Resolve implements the resolver.Client interface.
+func (c *Client) Resolve(name string) (Address, error) {
+	f, ok := c.names[strings.ToLower(name)]
+	if !ok {
+		return swarm.ZeroAddress, fmt.Errorf("%s: %w", name, ErrNotFound)
+	}
+
+	lookup, err := c.factory.NewLookup(feeds.Sequence, f)
+	if err != nil {
+		return swarm.ZeroAddress, fmt.Errorf("new lookup: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), lookupTimeout)
+	defer cancel()
+
+	ch, err := feeds.Latest(ctx, lookup, 0)
+	if err != nil {
+		return swarm.ZeroAddress, fmt.Errorf("lookup: %w", err)
+	}
+	if ch == nil {
+		return swarm.ZeroAddress, fmt.Errorf("%s: %w", name, ErrNoUpdate)
+	}
+
+	_, ref, err := feeds.FromChunk(ch)
+	if err != nil {
+		return swarm.ZeroAddress, fmt.Errorf("%v: %w", err, ErrInvalidUpdate)
+	}
+	if len(ref) != swarm.HashSize && len(ref) != encryption.ReferenceSize {
+		return swarm.ZeroAddress, ErrInvalidUpdate
+	}
+
+	return swarm.NewAddress(ref), nil
+}
+
+// Close is a noop.
+func (c *Client) Close() error {
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package feed_test
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/feeds/factory"
+	"github.com/ethersphere/bee/pkg/feeds/sequence"
+	"github.com/ethersphere/bee/pkg/resolver/client/feed"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm/test"
+)
+
+func TestResolve(t *testing.T) {
+	storer := mock.NewStorer()
+
+	pk, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := crypto.NewDefaultSigner(pk)
+	owner, err := signer.EthereumAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	topic := []byte("website")
+	updater, err := sequence.NewUpdater(storer, signer, topic)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "feeds")
+	mapping := "site.eth " + owner.Hex() + " " + hex.EncodeToString(topic) + "\n" +
+		"empty.eth " + owner.Hex() + " " + hex.EncodeToString([]byte("empty")) + "\n"
+	if err := os.WriteFile(path, []byte(mapping), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cl, err := feed.NewClient(path, factory.New(storer))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		ref := test.RandomAddress()
+		if err := updater.Update(ctx, time.Now().Unix(), ref.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+
+		addr, err := cl.Resolve("site.eth")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !addr.Equal(ref) {
+			t.Fatalf("update %d: got address %s, want %s", i, addr, ref)
+		}
+	}
+
+	if _, err := cl.Resolve("empty.eth"); !errors.Is(err, feed.ErrNoUpdate) {
+		t.Errorf("got error %v, want %v", err, feed.ErrNoUpdate)
+	}
+	if _, err := cl.Resolve("other.eth"); !errors.Is(err, feed.ErrNotFound) {
+		t.Errorf("got error %v, want %v", err, feed.ErrNotFound)
+	}
+}
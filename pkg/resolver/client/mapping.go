@@ -0,0 +1,54 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ErrInvalidMapping denotes that a line of a mapping file is not valid.
+var ErrInvalidMapping = errors.New("invalid mapping")
+
+// ReadMappingFile reads a file where every line maps a name to a number of
+// whitespace separated fields. Empty lines and lines starting with "#" are
+// ignored. Names are case insensitive and are returned in lower case.
+func ReadMappingFile(path string, fields int) (map[string][]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseMapping(f, fields)
+}
+
+// ParseMapping parses the mapping lines from the reader. See ReadMappingFile
+// for the format.
+func ParseMapping(r io.Reader, fields int) (map[string][]string, error) {
+	m := make(map[string][]string)
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.Fields(line)
+		if len(parts) != fields+1 {
+			return nil, fmt.Errorf("line %d: %w", n, ErrInvalidMapping)
+		}
+		m[strings.ToLower(parts[0])] = parts[1:]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
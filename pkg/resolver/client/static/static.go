@@ -0,0 +1,78 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package static provides a name resolution client which resolves names
+// from a local mapping file.
+package static
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethersphere/bee/pkg/resolver/client"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+// Address is the swarm bzz address.
+type Address = swarm.Address
+
+// Make sure Client implements the resolver.Client interface.
+var _ client.Interface = (*Client)(nil)
+
+// ErrNotFound denotes that the name is not in the mapping file.
+var ErrNotFound = errors.New("name not found")
+
+// Client is a name resolution client that resolves names from a mapping
+// file, where every line contains a name and a swarm reference.
+type Client struct {
+	path  string
+	names map[string]Address
+}
+
+// NewClient reads the mapping file at path and returns a new Client.
+func NewClient(path string) (client.Interface, error) {
+	mapping, err := client.ReadMappingFile(path, 1)
+	if err != nil {
+		return nil, fmt.Errorf("read mapping: %w", err)
+	}
+
+	names := make(map[string]Address, len(mapping))
+	for name, fields := range mapping {
+		addr, err := swarm.ParseHexAddress(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("name %s: %w", name, err)
+		}
+		names[name] = addr
+	}
+
+	return &Client{
+		path:  path,
+		names: names,
+	}, nil
+}
+
+// IsConnected always returns true as the mapping is loaded on creation.
+func (c *Client) IsConnected() bool {
+	return true
+}
+
+// Endpoint returns the path of the mapping file.
+func (c *Client) Endpoint() string {
+	return c.path
+}
+
+// Resolve implements the resolver.Client interface.
+func (c *Client) Resolve(name string) (Address, error) {
+	addr, ok := c.names[strings.ToLower(name)]
+	if !ok {
+		return swarm.ZeroAddress, fmt.Errorf("%s: %w", name, ErrNotFound)
+	}
+	return addr, nil
+}
+
+// Close is a noop.
+func (c *Client) Close() error {
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package static_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethersphere/bee/pkg/resolver/client"
+	"github.com/ethersphere/bee/pkg/resolver/client/static"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func writeMapping(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "names")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestResolve(t *testing.T) {
+	const ref = "aaf2a3c9e8dcfc4c5ba9ccbba46a7dd8f1e4e5b4a4e5b2d93b1c1c4dab7c4e6f"
+
+	path := writeMapping(t, "# swarm names\n\nExample.eth "+ref+"\n")
+	cl, err := static.NewClient(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cl.Endpoint(); got != path {
+		t.Errorf("endpoint: got %v, want %v", got, path)
+	}
+
+	addr, err := cl.Resolve("example.ETH")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := swarm.MustParseHexAddress(ref); !addr.Equal(want) {
+		t.Errorf("got address %s, want %s", addr, want)
+	}
+
+	if _, err := cl.Resolve("other.eth"); !errors.Is(err, static.ErrNotFound) {
+		t.Errorf("got error %v, want %v", err, static.ErrNotFound)
+	}
+}
+
+func TestNewClientInvalid(t *testing.T) {
+	for _, content := range []string{
+		"example.eth",
+		"example.eth ref1 ref2",
+		"example.eth nothex",
+	} {
+		if _, err := static.NewClient(writeMapping(t, content)); err == nil {
+			t.Errorf("%q: expected error", content)
+		}
+	}
+
+	_, err := static.NewClient(writeMapping(t, "example.eth"))
+	if !errors.Is(err, client.ErrInvalidMapping) {
+		t.Errorf("got error %v, want %v", err, client.ErrInvalidMapping)
+	}
+}
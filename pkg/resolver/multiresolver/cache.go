@@ -0,0 +1,108 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package multiresolver
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/resolver"
+)
+
+// maxCacheEntries limits the number of cached names per resolver, as names
+// that fail to resolve are cached too.
+const maxCacheEntries = 10000
+
+// Ensure cachedResolver implements Resolver interface.
+var _ resolver.Interface = (*cachedResolver)(nil)
+
+type cacheEntry struct {
+	addr    resolver.Address
+	err     error
+	created time.Time
+	expires time.Time
+}
+
+// cachedResolver wraps a resolver and keeps successful resolutions for the
+// duration of the TTL, and failed resolutions for the negative TTL.
+type cachedResolver struct {
+	resolver.Interface
+	kind        string
+	endpoint    string
+	ttl         time.Duration
+	negativeTTL time.Duration
+	now         func() time.Time
+	mu          sync.Mutex
+	entries     map[string]cacheEntry
+}
+
+func newCachedResolver(r resolver.Interface, ttl, negativeTTL time.Duration) *cachedResolver {
+	return &cachedResolver{
+		Interface:   r,
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+		now:         time.Now,
+		entries:     make(map[string]cacheEntry),
+	}
+}
+
+// Resolve returns the cached result for the name if it has not expired,
+// otherwise it resolves the name with the wrapped resolver.
+func (c *cachedResolver) Resolve(name string) (resolver.Address, error) {
+	addr, _, _, err := c.resolve(name)
+	return addr, err
+}
+
+// resolve is Resolve which also returns whether the result was cached and
+// the age of the cached result.
+func (c *cachedResolver) resolve(name string) (addr resolver.Address, cached bool, age time.Duration, err error) {
+	key := strings.ToLower(name)
+	now := c.now()
+
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	if ok && now.Before(e.expires) {
+		c.mu.Unlock()
+		return e.addr, true, now.Sub(e.created), e.err
+	}
+	delete(c.entries, key)
+	c.mu.Unlock()
+
+	addr, err = c.Interface.Resolve(name)
+
+	ttl := c.ttl
+	if err != nil {
+		ttl = c.negativeTTL
+	}
+	if ttl > 0 {
+		now = c.now()
+		c.mu.Lock()
+		if len(c.entries) >= maxCacheEntries {
+			c.evict(now)
+		}
+		c.entries[key] = cacheEntry{addr: addr, err: err, created: now, expires: now.Add(ttl)}
+		c.mu.Unlock()
+	}
+
+	return addr, false, 0, err
+}
+
+// evict removes the expired entries, or an arbitrary entry if none have
+// expired. It must be called with the lock held.
+func (c *cachedResolver) evict(now time.Time) {
+	for k, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	if len(c.entries) < maxCacheEntries {
+		return
+	}
+	for k := range c.entries {
+		delete(c.entries, k)
+		return
+	}
+}
@@ -5,8 +5,10 @@
 package multiresolver
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -16,12 +18,16 @@ import (
 // https://en.wikipedia.org/wiki/Domain_Name_System#cite_note-rfc1034-1
 const maxTLDLength = 63
 
+// ErrInvalidOption denotes an unknown or malformed connection string option.
+var ErrInvalidOption = errors.New("invalid connection option")
+
 // ConnectionConfig contains the TLD, endpoint and contract address used to
-// establish to a resolver.
+// establish to a resolver, and the TTL of the cached resolutions.
 type ConnectionConfig struct {
 	TLD      string
 	Address  string
 	Endpoint string
+	TTL      time.Duration
 }
 
 // ParseConnectionString will try to parse a connection string used to connect
@@ -40,6 +46,23 @@ func parseConnectionString(cs string) (ConnectionConfig, error) {
 	endpoint := cs
 	var tld string
 	var addr string
+	var ttl time.Duration
+
+	// Split the options, eg. "#ttl=5m", from the end of the string.
+	if i := strings.LastIndex(endpoint, "#"); i >= 0 {
+		for _, o := range strings.Split(endpoint[i+1:], ",") {
+			kv := strings.SplitN(o, "=", 2)
+			if len(kv) != 2 || kv[0] != "ttl" {
+				return ConnectionConfig{}, fmt.Errorf("option %q: %w", o, ErrInvalidOption)
+			}
+			d, err := time.ParseDuration(kv[1])
+			if err != nil || d < 0 {
+				return ConnectionConfig{}, fmt.Errorf("option %q: %w", o, ErrInvalidOption)
+			}
+			ttl = d
+		}
+		endpoint = endpoint[:i]
+	}
 
 	// Split TLD and Endpoint strings.
 	if i := strings.Index(endpoint, ":"); i > 0 {
@@ -64,6 +87,7 @@ func parseConnectionString(cs string) (ConnectionConfig, error) {
 		Endpoint: endpoint,
 		Address:  addr,
 		TLD:      tld,
+		TTL:      ttl,
 	}, nil
 }
 
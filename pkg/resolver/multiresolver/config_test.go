@@ -7,6 +7,7 @@ package multiresolver_test
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/ethersphere/bee/pkg/resolver/multiresolver"
 )
@@ -105,6 +106,40 @@ func TestParseConnectionStrings(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "endpoint with ttl",
+			conStrings: []string{
+				"eth:0x314159265dD8dbb310642f98f50C066173C1259b@https://example.com#ttl=5m",
+				"dnslink://#ttl=30s",
+			},
+			wantCfg: []multiresolver.ConnectionConfig{
+				{
+					TLD:      "eth",
+					Address:  "0x314159265dD8dbb310642f98f50C066173C1259b",
+					Endpoint: "https://example.com",
+					TTL:      5 * time.Minute,
+				},
+				{
+					TLD:      "",
+					Endpoint: "dnslink://",
+					TTL:      30 * time.Second,
+				},
+			},
+		},
+		{
+			desc: "invalid ttl",
+			conStrings: []string{
+				"https://example.com#ttl=forever",
+			},
+			wantErr: multiresolver.ErrInvalidOption,
+		},
+		{
+			desc: "unknown option",
+			conStrings: []string{
+				"https://example.com#cache=true",
+			},
+			wantErr: multiresolver.ErrInvalidOption,
+		},
 		{
 			desc: "mixed with error",
 			conStrings: []string{
@@ -137,6 +172,9 @@ func TestParseConnectionStrings(t *testing.T) {
 				if got.Endpoint != want.Endpoint {
 					t.Errorf("got %q, want %q", got.Endpoint, want.Endpoint)
 				}
+				if got.TTL != want.TTL {
+					t.Errorf("got %v, want %v", got.TTL, want.TTL)
+				}
 			}
 		})
 	}
@@ -4,7 +4,12 @@
 
 package multiresolver
 
-import "github.com/ethersphere/bee/pkg/logging"
+import (
+	"time"
+
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/resolver"
+)
 
 func GetLogger(mr *MultiResolver) logging.Logger {
 	return mr.logger
@@ -13,3 +18,9 @@ func GetLogger(mr *MultiResolver) logging.Logger {
 func GetCfgs(mr *MultiResolver) []ConnectionConfig {
 	return mr.cfgs
 }
+
+func NewCachedResolver(r resolver.Interface, ttl, negativeTTL time.Duration, now func() time.Time) resolver.Interface {
+	c := newCachedResolver(r, ttl, negativeTTL)
+	c.now = now
+	return c
+}
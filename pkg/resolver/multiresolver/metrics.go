@@ -0,0 +1,47 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package multiresolver
+
+import (
+	m "github.com/ethersphere/bee/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	CacheHits       prometheus.CounterVec
+	CacheMisses     prometheus.CounterVec
+	ResolveDuration prometheus.HistogramVec
+}
+
+func newMetrics() metrics {
+	subsystem := "resolver"
+
+	return metrics{
+		CacheHits: *prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "cache_hits",
+			Help:      "Number of name resolutions answered from the cache.",
+		}, []string{"tld"}),
+		CacheMisses: *prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "cache_misses",
+			Help:      "Number of name resolutions which asked a name resolution service.",
+		}, []string{"tld"}),
+		ResolveDuration: *prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: m.Namespace,
+			Subsystem: subsystem,
+			Name:      "resolve_duration",
+			Help:      "Histogram of name resolution durations.",
+			Buckets:   []float64{0.001, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		}, []string{"tld"}),
+	}
+}
+
+// Metrics returns the prometheus collectors of the MultiResolver.
+func (mr *MultiResolver) Metrics() []prometheus.Collector {
+	return m.PrometheusCollectorsFromFields(mr.metrics)
+}
@@ -8,17 +8,28 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"path"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/ethersphere/bee/pkg/feeds"
 	"github.com/ethersphere/bee/pkg/logging"
 	"github.com/ethersphere/bee/pkg/resolver"
+	"github.com/ethersphere/bee/pkg/resolver/client"
+	"github.com/ethersphere/bee/pkg/resolver/client/dnslink"
 	"github.com/ethersphere/bee/pkg/resolver/client/ens"
+	"github.com/ethersphere/bee/pkg/resolver/client/feed"
+	"github.com/ethersphere/bee/pkg/resolver/client/static"
 	"github.com/ethersphere/bee/pkg/resolver/multiresolver/multierror"
 )
 
-// Ensure MultiResolver implements Resolver interface.
-var _ resolver.Interface = (*MultiResolver)(nil)
+// Ensure MultiResolver implements Resolver and Inspector interfaces.
+var (
+	_ resolver.Interface = (*MultiResolver)(nil)
+	_ resolver.Inspector = (*MultiResolver)(nil)
+)
 
 var (
 	// ErrTLDTooLong denotes when a TLD in a name exceeds maximum length.
@@ -34,13 +45,28 @@ var (
 	ErrCloseFailed = errors.New("close failed")
 )
 
+// Endpoint schemes which select a resolver client other than ENS.
+const (
+	dnslinkScheme = "dnslink://"
+	feedScheme    = "feed://"
+	fileScheme    = "file://"
+)
+
 type resolverMap map[string][]resolver.Interface
 
 // MultiResolver performs name resolutions based on the TLD label in the name.
 type MultiResolver struct {
+	mu        sync.RWMutex
 	resolvers resolverMap
-	logger    logging.Logger
-	cfgs      []ConnectionConfig
+	// inflight counts the resolutions which use the resolvers, so that
+	// they are closed only after the resolutions end when they are replaced.
+	inflight    *sync.WaitGroup
+	logger      logging.Logger
+	cfgs        []ConnectionConfig
+	feedFactory feeds.Factory
+	ttl         time.Duration
+	negativeTTL time.Duration
+	metrics     metrics
 	// ForceDefault will force all names to be resolved by the default
 	// resolution chain, regadless of their TLD.
 	ForceDefault bool
@@ -53,6 +79,8 @@ type Option func(*MultiResolver)
 func NewMultiResolver(opts ...Option) *MultiResolver {
 	mr := &MultiResolver{
 		resolvers: make(resolverMap),
+		inflight:  new(sync.WaitGroup),
+		metrics:   newMetrics(),
 	}
 
 	// Apply all options.
@@ -73,10 +101,7 @@ func NewMultiResolver(opts ...Option) *MultiResolver {
 
 	// Attempt to conect to each resolver using the connection string.
 	for _, c := range mr.cfgs {
-
-		// NOTE: if we want to create a specific client based on the TLD
-		// we can do it here.
-		mr.connectENSClient(c.TLD, c.Address, c.Endpoint)
+		mr.connectClient(c)
 	}
 
 	return mr
@@ -96,6 +121,23 @@ func WithLogger(logger logging.Logger) Option {
 	}
 }
 
+// WithCacheTTL will set the default TTL of resolved names and the TTL of
+// names that failed to resolve. The default TTL is used for connection
+// configs without a TTL.
+func WithCacheTTL(ttl, negativeTTL time.Duration) Option {
+	return func(mr *MultiResolver) {
+		mr.ttl = ttl
+		mr.negativeTTL = negativeTTL
+	}
+}
+
+// WithFeedFactory will set the feed factory used by the feed resolvers.
+func WithFeedFactory(factory feeds.Factory) Option {
+	return func(mr *MultiResolver) {
+		mr.feedFactory = factory
+	}
+}
+
 // WithForceDefault will force resolution using the default resolver chain.
 func WithForceDefault() Option {
 	return func(mr *MultiResolver) {
@@ -106,12 +148,18 @@ func WithForceDefault() Option {
 // PushResolver will push a new Resolver to the name resolution chain for the
 // given TLD. An empty TLD will push to the default resolver chain.
 func (mr *MultiResolver) PushResolver(tld string, r resolver.Interface) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
 	mr.resolvers[tld] = append(mr.resolvers[tld], r)
 }
 
 // PopResolver will pop the last reslover from the name resolution chain for the
 // given TLD. An empty TLD will pop from the default resolver chain.
 func (mr *MultiResolver) PopResolver(tld string) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
 	l := len(mr.resolvers[tld])
 	if l == 0 {
 		return fmt.Errorf("tld %s: %w", tld, ErrResolverChainEmpty)
@@ -125,6 +173,9 @@ func (mr *MultiResolver) PopResolver(tld string) error {
 // TLD names should be prepended with a dot (eg ".tld"). An empty TLD will
 // return the number of resolvers in the default resolver chain.
 func (mr *MultiResolver) ChainCount(tld string) int {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
 	return len(mr.resolvers[tld])
 }
 
@@ -132,6 +183,9 @@ func (mr *MultiResolver) ChainCount(tld string) int {
 // TLD names should be prepended with a dot (eg ".tld"). An empty TLD will
 // return all resolvers in the default resolver chain.
 func (mr *MultiResolver) GetChain(tld string) []resolver.Interface {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
 	return mr.resolvers[tld]
 }
 
@@ -142,34 +196,119 @@ func (mr *MultiResolver) GetChain(tld string) []resolver.Interface {
 // returning the result of the first Resolver that succeeds. If all resolvers
 // in the chain return an error, the function will return an ErrResolveFailed.
 func (mr *MultiResolver) Resolve(name string) (addr resolver.Address, err error) {
+	res, err := mr.ResolveWithInfo(name)
+	return res.Address, err
+}
+
+// ResolveWithInfo resolves the name like Resolve and also returns the
+// resolvers which were asked and whether the result was cached.
+func (mr *MultiResolver) ResolveWithInfo(name string) (res resolver.Resolution, err error) {
 	tld := ""
 	if !mr.ForceDefault {
 		tld = getTLD(name)
 	}
+	mr.mu.RLock()
 	chain := mr.resolvers[tld]
 
 	// If no resolver chain is found, switch to the default chain.
 	if len(chain) == 0 {
+		tld = ""
 		chain = mr.resolvers[""]
 	}
+	inflight := mr.inflight
+	inflight.Add(1)
+	mr.mu.RUnlock()
+	defer inflight.Done()
+
+	label := tld
+	if label == "" {
+		label = "default"
+	}
+	start := time.Now()
 
+	res.Cached = len(chain) > 0
 	errs := multierror.New()
-	for _, res := range chain {
-		addr, err = res.Resolve(name)
+	for _, r := range chain {
+		step := resolver.ResolutionStep{
+			Resolver: fmt.Sprintf("%T", r),
+		}
+
+		var (
+			addr resolver.Address
+			age  time.Duration
+		)
+		if cr, ok := r.(*cachedResolver); ok {
+			step.Resolver, step.Endpoint = cr.kind, cr.endpoint
+			addr, step.Cached, age, err = cr.resolve(name)
+		} else {
+			addr, err = r.Resolve(name)
+		}
+		step.Err = err
+		res.Chain = append(res.Chain, step)
+		res.Cached = res.Cached && step.Cached
+
 		if err == nil {
-			return addr, nil
+			res.Address = addr
+			res.CacheAge = age
+			break
 		}
 		errs.Append(err)
 	}
 
-	return addr, errs.ErrorOrNil()
+	if res.Cached {
+		mr.metrics.CacheHits.WithLabelValues(label).Inc()
+	} else {
+		mr.metrics.CacheMisses.WithLabelValues(label).Inc()
+		mr.metrics.ResolveDuration.WithLabelValues(label).Observe(time.Since(start).Seconds())
+	}
+
+	if err != nil {
+		return res, errs.ErrorOrNil()
+	}
+	return res, nil
+}
+
+// SetConnectionConfigs replaces all resolver chains with the resolvers
+// connected using the connection configs. Names which are being resolved
+// while the chains are replaced are resolved with the old resolvers, which
+// are closed when these resolutions end. It returns after they are closed.
+func (mr *MultiResolver) SetConnectionConfigs(cfgs []ConnectionConfig) error {
+	next := &MultiResolver{
+		resolvers:   make(resolverMap),
+		logger:      mr.logger,
+		feedFactory: mr.feedFactory,
+		ttl:         mr.ttl,
+		negativeTTL: mr.negativeTTL,
+	}
+	if len(cfgs) == 0 {
+		mr.logger.Info("name resolver: no name resolution service provided")
+	}
+	for _, c := range cfgs {
+		next.connectClient(c)
+	}
+
+	mr.mu.Lock()
+	prev, prevInflight := mr.resolvers, mr.inflight
+	mr.resolvers, mr.inflight = next.resolvers, new(sync.WaitGroup)
+	mr.cfgs = cfgs
+	mr.mu.Unlock()
+
+	prevInflight.Wait()
+	return closeResolvers(prev)
 }
 
 // Close all will call Close on all resolvers in all resolver chains.
 func (mr *MultiResolver) Close() error {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	return closeResolvers(mr.resolvers)
+}
+
+func closeResolvers(resolvers resolverMap) error {
 	errs := multierror.New()
 
-	for _, chain := range mr.resolvers {
+	for _, chain := range resolvers {
 		for _, r := range chain {
 			if err := r.Close(); err != nil {
 				errs.Append(err)
@@ -184,8 +323,68 @@ func getTLD(name string) string {
 	return path.Ext(strings.ToLower(name))
 }
 
-func (mr *MultiResolver) connectENSClient(tld, address, endpoint string) {
+// connectClient creates the resolver client selected by the scheme of the
+// endpoint and pushes it to the resolver chain of the TLD.
+func (mr *MultiResolver) connectClient(c ConnectionConfig) {
+	log := mr.logger
+
+	var (
+		cl   client.Interface
+		kind string
+		err  error
+	)
+	switch {
+	case strings.HasPrefix(c.Endpoint, dnslinkScheme):
+		kind = "dnslink"
+		cl, err = dnslink.NewClient(strings.TrimPrefix(c.Endpoint, dnslinkScheme))
+	case strings.HasPrefix(c.Endpoint, fileScheme):
+		kind = "static"
+		cl, err = static.NewClient(strings.TrimPrefix(c.Endpoint, fileScheme))
+	case strings.HasPrefix(c.Endpoint, feedScheme):
+		kind = "feed"
+		if mr.feedFactory == nil {
+			err = errors.New("feeds not available")
+			break
+		}
+		cl, err = feed.NewClient(strings.TrimPrefix(c.Endpoint, feedScheme), mr.feedFactory)
+	default:
+		mr.connectENSClient(c)
+		return
+	}
+	if err != nil {
+		log.Errorf("name resolver: resolver for %q domain on endpoint %q: %v", c.TLD, c.Endpoint, err)
+		return
+	}
+
+	log.Infof("name resolver: resolver for %q domain: using %s", c.TLD, c.Endpoint)
+	mr.pushClient(c, kind, cl)
+}
+
+// pushClient pushes the client to the resolver chain, wrapped in a cache
+// with the TTL of the connection config or the default TTL.
+func (mr *MultiResolver) pushClient(c ConnectionConfig, kind string, cl resolver.Interface) {
+	ttl := c.TTL
+	if ttl == 0 {
+		ttl = mr.ttl
+	}
+	cr := newCachedResolver(cl, ttl, mr.negativeTTL)
+	cr.kind = kind
+	cr.endpoint = redactEndpoint(c.Endpoint)
+	mr.PushResolver(c.TLD, cr)
+}
+
+// redactEndpoint returns the scheme and host of an URL endpoint, as the
+// rest of the URL may contain credentials, or the file name of a path.
+func redactEndpoint(endpoint string) string {
+	if u, err := url.Parse(endpoint); err == nil && u.Host != "" {
+		return u.Scheme + "://" + u.Host
+	}
+	return path.Base(endpoint)
+}
+
+func (mr *MultiResolver) connectENSClient(c ConnectionConfig) {
 	log := mr.logger
+	tld, address, endpoint := c.TLD, c.Address, c.Endpoint
 
 	if address == "" {
 		log.Debugf("name resolver: resolver for %q: connecting to endpoint %s", tld, endpoint)
@@ -198,6 +397,6 @@ func (mr *MultiResolver) connectENSClient(tld, address, endpoint string) {
 		log.Errorf("name resolver: resolver for %q domain on endpoint %q: %v", tld, endpoint, err)
 	} else {
 		log.Infof("name resolver: resolver for %q domain: connected to %s", tld, endpoint)
-		mr.PushResolver(tld, ensCl)
+		mr.pushClient(c, "ens", ensCl)
 	}
 }
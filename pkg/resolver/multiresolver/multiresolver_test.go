@@ -8,8 +8,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/ethersphere/bee/pkg/logging"
 	"github.com/ethersphere/bee/pkg/resolver"
@@ -260,3 +263,235 @@ func TestResolve(t *testing.T) {
 		}
 	})
 }
+
+func TestStaticResolverConfig(t *testing.T) {
+	addr := swarm.MustParseHexAddress("aaf2a3c9e8dcfc4c5ba9ccbba46a7dd8f1e4e5b4a4e5b2d93b1c1c4dab7c4e6f")
+
+	path := filepath.Join(t.TempDir(), "names")
+	if err := os.WriteFile(path, []byte("example.eth "+addr.String()+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	mr := multiresolver.NewMultiResolver(
+		multiresolver.WithConnectionConfigs([]multiresolver.ConnectionConfig{
+			{Endpoint: "file://" + path, TTL: time.Minute},
+			{Endpoint: "file://" + path + ".missing"},
+		}),
+	)
+	if got := mr.ChainCount(""); got != 1 {
+		t.Fatalf("got %d resolvers, want 1", got)
+	}
+
+	got, err := mr.Resolve("example.eth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(addr) {
+		t.Errorf("got address %s, want %s", got, addr)
+	}
+}
+
+func TestSetConnectionConfigs(t *testing.T) {
+	addr1 := swarm.MustParseHexAddress("aaf2a3c9e8dcfc4c5ba9ccbba46a7dd8f1e4e5b4a4e5b2d93b1c1c4dab7c4e6f")
+	addr2 := swarm.MustParseHexAddress("bbf2a3c9e8dcfc4c5ba9ccbba46a7dd8f1e4e5b4a4e5b2d93b1c1c4dab7c4e6f")
+
+	dir := t.TempDir()
+	path1 := filepath.Join(dir, "names1")
+	if err := os.WriteFile(path1, []byte("example.eth "+addr1.String()+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	path2 := filepath.Join(dir, "names2")
+	if err := os.WriteFile(path2, []byte("example.eth "+addr2.String()+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	mr := multiresolver.NewMultiResolver(
+		multiresolver.WithConnectionConfigs([]multiresolver.ConnectionConfig{
+			{Endpoint: "file://" + path1},
+		}),
+	)
+
+	got, err := mr.Resolve("example.eth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(addr1) {
+		t.Errorf("got address %s, want %s", got, addr1)
+	}
+
+	cfgs := []multiresolver.ConnectionConfig{
+		{Endpoint: "file://" + path2, TLD: ".eth"},
+	}
+	if err := mr.SetConnectionConfigs(cfgs); err != nil {
+		t.Fatal(err)
+	}
+	if got := mr.ChainCount(""); got != 0 {
+		t.Errorf("got %d default resolvers, want 0", got)
+	}
+	if got := mr.ChainCount(".eth"); got != 1 {
+		t.Errorf("got %d .eth resolvers, want 1", got)
+	}
+	if got := multiresolver.GetCfgs(mr); !reflect.DeepEqual(got, cfgs) {
+		t.Errorf("got configs %v, want %v", got, cfgs)
+	}
+
+	got, err = mr.Resolve("example.eth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(addr2) {
+		t.Errorf("got address %s, want %s", got, addr2)
+	}
+}
+
+func TestSetConnectionConfigsInflight(t *testing.T) {
+	addr := newAddr("aaaabbbbccccdddd")
+	started := make(chan struct{})
+	release := make(chan struct{})
+	r := mock.NewResolver(
+		mock.WithResolveFunc(func(string) (Address, error) {
+			close(started)
+			<-release
+			return addr, nil
+		}),
+	)
+
+	mr := multiresolver.NewMultiResolver()
+	mr.PushResolver("", r)
+
+	resolved := make(chan error, 1)
+	go func() {
+		_, err := mr.Resolve("example.eth")
+		resolved <- err
+	}()
+	<-started
+
+	set := make(chan error, 1)
+	go func() {
+		set <- mr.SetConnectionConfigs(nil)
+	}()
+	select {
+	case <-set:
+		t.Fatal("resolvers closed during a resolution")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if r.(*mock.Resolver).IsClosed {
+		t.Fatal("resolver closed during a resolution")
+	}
+
+	close(release)
+	if err := <-resolved; err != nil {
+		t.Fatal(err)
+	}
+	if err := <-set; err != nil {
+		t.Fatal(err)
+	}
+	if !r.(*mock.Resolver).IsClosed {
+		t.Fatal("replaced resolver not closed")
+	}
+}
+
+func TestCachedResolver(t *testing.T) {
+	addr := newAddr("aaaabbbbccccdddd")
+	errResolutionFailed := errors.New("name resolution failed")
+
+	calls := 0
+	fail := false
+	r := mock.NewResolver(
+		mock.WithResolveFunc(func(string) (Address, error) {
+			calls++
+			if fail {
+				return swarm.ZeroAddress, errResolutionFailed
+			}
+			return addr, nil
+		}),
+	)
+
+	now := time.Now()
+	cr := multiresolver.NewCachedResolver(r, time.Minute, 10*time.Second, func() time.Time { return now })
+
+	for i := 0; i < 3; i++ {
+		got, err := cr.Resolve("Example.eth")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(addr) {
+			t.Fatalf("got address %s, want %s", got, addr)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("got %d calls, want 1", calls)
+	}
+
+	// The entry expires after the TTL and the error is cached for the
+	// negative TTL.
+	now = now.Add(time.Minute)
+	fail = true
+	for i := 0; i < 2; i++ {
+		if _, err := cr.Resolve("example.eth"); !errors.Is(err, errResolutionFailed) {
+			t.Fatalf("got error %v, want %v", err, errResolutionFailed)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("got %d calls, want 2", calls)
+	}
+
+	now = now.Add(10 * time.Second)
+	fail = false
+	if _, err := cr.Resolve("example.eth"); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 {
+		t.Fatalf("got %d calls, want 3", calls)
+	}
+}
+
+func TestResolveWithInfo(t *testing.T) {
+	addr := swarm.MustParseHexAddress("aaf2a3c9e8dcfc4c5ba9ccbba46a7dd8f1e4e5b4a4e5b2d93b1c1c4dab7c4e6f")
+
+	dir := t.TempDir()
+	empty := filepath.Join(dir, "empty")
+	names := filepath.Join(dir, "names")
+	if err := os.WriteFile(empty, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(names, []byte("example.eth "+addr.String()+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	mr := multiresolver.NewMultiResolver(
+		multiresolver.WithConnectionConfigs([]multiresolver.ConnectionConfig{
+			{Endpoint: "file://" + empty},
+			{Endpoint: "file://" + names},
+		}),
+		multiresolver.WithCacheTTL(time.Minute, time.Minute),
+	)
+
+	res, err := mr.ResolveWithInfo("example.eth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Address.Equal(addr) {
+		t.Errorf("got address %s, want %s", res.Address, addr)
+	}
+	if res.Cached {
+		t.Error("got cached resolution")
+	}
+	if len(res.Chain) != 2 {
+		t.Fatalf("got chain length %d, want 2", len(res.Chain))
+	}
+	if step := res.Chain[0]; step.Resolver != "static" || step.Endpoint != "empty" || step.Err == nil {
+		t.Errorf("got first step %+v", step)
+	}
+	if step := res.Chain[1]; step.Resolver != "static" || step.Endpoint != "names" || step.Err != nil {
+		t.Errorf("got second step %+v", step)
+	}
+
+	res, err = mr.ResolveWithInfo("example.eth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Cached || !res.Chain[0].Cached || !res.Chain[1].Cached {
+		t.Errorf("got uncached resolution %+v", res)
+	}
+}
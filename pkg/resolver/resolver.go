@@ -6,6 +6,7 @@ package resolver
 
 import (
 	"io"
+	"time"
 
 	"github.com/ethersphere/bee/pkg/swarm"
 )
@@ -18,3 +19,27 @@ type Interface interface {
 	Resolve(url string) (Address, error)
 	io.Closer
 }
+
+// Inspector can resolve a name and describe how the name was resolved.
+type Inspector interface {
+	ResolveWithInfo(name string) (Resolution, error)
+}
+
+// Resolution describes the resolution of a name by a chain of resolvers.
+type Resolution struct {
+	Address Address
+	// Chain contains the resolvers which were asked, in order.
+	Chain []ResolutionStep
+	// Cached is true if the result was returned from the cache, which was
+	// populated CacheAge ago.
+	Cached   bool
+	CacheAge time.Duration
+}
+
+// ResolutionStep describes the result of a single resolver in the chain.
+type ResolutionStep struct {
+	Resolver string
+	Endpoint string
+	Cached   bool
+	Err      error
+}
@@ -0,0 +1,242 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package resumable keeps the sessions of resumable uploads, which are
+// uploaded in multiple requests and can be continued after the connection
+// drops or the node restarts.
+package resumable
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+const keyPrefix = "resumable_upload_"
+
+// Expiry is the time after the last change of a session after which it
+// expires and is deleted, like the Upload-Expires of the tus protocol.
+const Expiry = 7 * 24 * time.Hour
+
+var (
+	// ErrNotFound is returned when the session does not exist.
+	ErrNotFound = errors.New("resumable: session not found")
+	// ErrLocked is returned when the session is used by another request.
+	ErrLocked = errors.New("resumable: session locked")
+)
+
+// Session is a resumable upload.
+type Session struct {
+	ID string `json:"id"`
+	// Length is the number of bytes of the upload.
+	Length int64 `json:"length"`
+	// Offset is the number of bytes that were uploaded and checkpointed.
+	Offset int64 `json:"offset"`
+	// Checkpoint is the state of the pipeline at the offset. It holds the
+	// encryption keys of the buffered chunks of encrypted uploads, so it is
+	// stored encrypted with the secret of the Sessions.
+	Checkpoint []byte `json:"checkpoint,omitempty"`
+
+	BatchID    []byte `json:"batchID"`
+	TagUID     uint32 `json:"tagUID"`
+	TagCreated bool   `json:"tagCreated"`
+	Encrypt    bool   `json:"encrypt"`
+	// EncryptionKey is the key with which the chunks of the upload are
+	// encrypted. It is stored encrypted with the secret of the Sessions.
+	EncryptionKey []byte `json:"encryptionKey,omitempty"`
+	Deferred      bool   `json:"deferred"`
+	Pin           bool   `json:"pin"`
+	// Filename and ContentType are set for uploads which are stored with a
+	// manifest, like the files uploaded to the bzz endpoint.
+	Filename    string `json:"filename,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+
+	// Reference is set when the upload is complete.
+	Reference swarm.Address `json:"reference"`
+	CreatedAt time.Time     `json:"createdAt"`
+	// UpdatedAt is the time of the last change of the session.
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Done returns true if all bytes of the upload were uploaded.
+func (s *Session) Done() bool {
+	return s.Offset == s.Length
+}
+
+// ExpiresAt returns the time at which the session expires.
+func (s *Session) ExpiresAt() time.Time {
+	return s.UpdatedAt.Add(Expiry)
+}
+
+// Sessions stores the sessions in the state store.
+type Sessions struct {
+	store storage.StateStorer
+	aead  cipher.AEAD
+
+	mu     sync.Mutex
+	locked map[string]struct{}
+}
+
+// New creates a new Sessions. The encryption keys and the checkpoints of
+// the sessions are stored encrypted with a key derived from the secret, which must be kept
+// apart from the state store.
+func New(store storage.StateStorer, secret []byte) *Sessions {
+	key, _ := crypto.LegacyKeccak256(append([]byte("resumable"), secret...))
+	block, _ := aes.NewCipher(key) // the key has a valid size
+	aead, _ := cipher.NewGCM(block)
+	return &Sessions{
+		store:  store,
+		aead:   aead,
+		locked: make(map[string]struct{}),
+	}
+}
+
+// Create stores the session with a new random ID.
+func (s *Sessions) Create(session *Session) error {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return err
+	}
+	session.ID = hex.EncodeToString(id)
+	session.CreatedAt = time.Now().UTC()
+	return s.Save(session)
+}
+
+// Get returns the session with the ID. Expired sessions are not found.
+func (s *Sessions) Get(id string) (*Session, error) {
+	var session Session
+	if err := s.store.Get(keyPrefix+id, &session); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if time.Now().After(session.ExpiresAt()) {
+		return nil, ErrNotFound
+	}
+	if session.EncryptionKey != nil {
+		key, err := s.open(session.EncryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("resumable: decrypt encryption key: %w", err)
+		}
+		session.EncryptionKey = key
+	}
+	if session.Checkpoint != nil {
+		checkpoint, err := s.open(session.Checkpoint)
+		if err != nil {
+			return nil, fmt.Errorf("resumable: decrypt checkpoint: %w", err)
+		}
+		session.Checkpoint = checkpoint
+	}
+	return &session, nil
+}
+
+// Save stores the changes of the session.
+func (s *Sessions) Save(session *Session) error {
+	session.UpdatedAt = time.Now().UTC()
+
+	stored := *session
+	if session.EncryptionKey != nil {
+		key, err := s.seal(session.EncryptionKey)
+		if err != nil {
+			return fmt.Errorf("resumable: encrypt encryption key: %w", err)
+		}
+		stored.EncryptionKey = key
+	}
+	if session.Checkpoint != nil {
+		checkpoint, err := s.seal(session.Checkpoint)
+		if err != nil {
+			return fmt.Errorf("resumable: encrypt checkpoint: %w", err)
+		}
+		stored.Checkpoint = checkpoint
+	}
+	return s.store.Put(keyPrefix+session.ID, &stored)
+}
+
+// Delete deletes the session with the ID.
+func (s *Sessions) Delete(id string) error {
+	return s.store.Delete(keyPrefix + id)
+}
+
+// Sweep deletes the sessions which expired before the time, except for the
+// locked ones, and returns their number.
+func (s *Sessions) Sweep(before time.Time) (int, error) {
+	var expired []string
+	err := s.store.Iterate(keyPrefix, func(key, value []byte) (stop bool, err error) {
+		var session Session
+		if err := json.Unmarshal(value, &session); err != nil {
+			return true, fmt.Errorf("resumable: session %s: %w", key, err)
+		}
+		if session.ExpiresAt().Before(before) {
+			expired = append(expired, strings.TrimPrefix(string(key), keyPrefix))
+		}
+		return false, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for _, id := range expired {
+		if _, ok := s.locked[id]; ok {
+			continue
+		}
+		if err := s.store.Delete(keyPrefix + id); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// Lock locks the session with the ID for the exclusive use of a request,
+// until the returned function is called.
+func (s *Sessions) Lock(id string) (unlock func(), err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.locked[id]; ok {
+		return nil, ErrLocked
+	}
+	s.locked[id] = struct{}{}
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		delete(s.locked, id)
+	}, nil
+}
+
+// seal encrypts the data of a session, prefixed with the nonce.
+func (s *Sessions) seal(data []byte) ([]byte, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return s.aead.Seal(nonce, nonce, data, nil), nil
+}
+
+// open decrypts the data of a session sealed by seal.
+func (s *Sessions) open(sealed []byte) ([]byte, error) {
+	n := s.aead.NonceSize()
+	if len(sealed) < n {
+		return nil, errors.New("invalid length")
+	}
+	return s.aead.Open(nil, sealed[:n], sealed[n:], nil)
+}
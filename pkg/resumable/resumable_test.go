@@ -0,0 +1,207 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resumable_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/resumable"
+	"github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+func TestSessions(t *testing.T) {
+	store := mock.NewStateStore()
+	sessions := resumable.New(store, []byte("secret"))
+
+	session := &resumable.Session{
+		Length:   1000,
+		BatchID:  make([]byte, 32),
+		TagUID:   1,
+		Filename: "file.txt",
+	}
+	if err := sessions.Create(session); err != nil {
+		t.Fatal(err)
+	}
+	if session.ID == "" {
+		t.Fatal("session id not set")
+	}
+
+	session.Offset = 500
+	session.Checkpoint = []byte("state")
+	if err := sessions.Save(session); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := sessions.Get(session.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != session.ID || got.Length != session.Length || got.Offset != session.Offset ||
+		!bytes.Equal(got.Checkpoint, session.Checkpoint) || !bytes.Equal(got.BatchID, session.BatchID) ||
+		got.TagUID != session.TagUID || got.Filename != session.Filename || !got.CreatedAt.Equal(session.CreatedAt) {
+		t.Fatalf("got session %+v, want %+v", got, session)
+	}
+	if got.Done() {
+		t.Fatal("session done")
+	}
+
+	got.Offset = got.Length
+	got.Reference = swarm.MustParseHexAddress("aabbcc")
+	if err := sessions.Save(got); err != nil {
+		t.Fatal(err)
+	}
+	got, err = sessions.Get(session.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Done() || !got.Reference.Equal(swarm.MustParseHexAddress("aabbcc")) {
+		t.Fatalf("got session %+v, want done with reference", got)
+	}
+
+	if err := sessions.Delete(session.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sessions.Get(session.ID); !errors.Is(err, resumable.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, resumable.ErrNotFound)
+	}
+}
+
+func TestSessionsLock(t *testing.T) {
+	sessions := resumable.New(mock.NewStateStore(), nil)
+
+	unlock, err := sessions.Lock("id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sessions.Lock("id"); !errors.Is(err, resumable.ErrLocked) {
+		t.Fatalf("got error %v, want %v", err, resumable.ErrLocked)
+	}
+	if _, err := sessions.Lock("other"); err != nil {
+		t.Fatal(err)
+	}
+
+	unlock()
+	if _, err := sessions.Lock("id"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSessionsEncryptionKey(t *testing.T) {
+	store := mock.NewStateStore()
+	key := bytes.Repeat([]byte{1}, 32)
+
+	session := &resumable.Session{Length: 1000, EncryptionKey: key}
+	if err := resumable.New(store, []byte("secret")).Create(session); err != nil {
+		t.Fatal(err)
+	}
+
+	var stored resumable.Session
+	if err := store.Get("resumable_upload_"+session.ID, &stored); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(stored.EncryptionKey, key) {
+		t.Fatal("encryption key stored in plaintext")
+	}
+
+	got, err := resumable.New(store, []byte("secret")).Get(session.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.EncryptionKey, key) {
+		t.Fatalf("got encryption key %x, want %x", got.EncryptionKey, key)
+	}
+
+	if _, err := resumable.New(store, []byte("other")).Get(session.ID); err == nil {
+		t.Fatal("expected error with another secret")
+	}
+}
+
+func TestSessionsCheckpoint(t *testing.T) {
+	store := mock.NewStateStore()
+	// the buffered reference and encryption key of a chunk
+	key := bytes.Repeat([]byte{2}, 32)
+	checkpoint := append(bytes.Repeat([]byte{1}, 32), key...)
+
+	session := &resumable.Session{Length: 1000, Encrypt: true, Checkpoint: checkpoint}
+	if err := resumable.New(store, []byte("secret")).Create(session); err != nil {
+		t.Fatal(err)
+	}
+
+	var stored resumable.Session
+	if err := store.Get("resumable_upload_"+session.ID, &stored); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(stored.Checkpoint, key) {
+		t.Fatal("checkpoint stored in plaintext")
+	}
+
+	got, err := resumable.New(store, []byte("secret")).Get(session.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.Checkpoint, checkpoint) {
+		t.Fatalf("got checkpoint %x, want %x", got.Checkpoint, checkpoint)
+	}
+}
+
+func TestSessionsExpiry(t *testing.T) {
+	store := mock.NewStateStore()
+	sessions := resumable.New(store, nil)
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		session := &resumable.Session{Length: 1000}
+		if err := sessions.Create(session); err != nil {
+			t.Fatal(err)
+		}
+		if !session.ExpiresAt().Equal(session.UpdatedAt.Add(resumable.Expiry)) {
+			t.Fatalf("got expiry %v, want %v after %v", session.ExpiresAt(), resumable.Expiry, session.UpdatedAt)
+		}
+		ids = append(ids, session.ID)
+	}
+
+	// the first session expired, the second one is locked
+	var expired resumable.Session
+	if err := store.Get("resumable_upload_"+ids[0], &expired); err != nil {
+		t.Fatal(err)
+	}
+	expired.UpdatedAt = time.Now().Add(-resumable.Expiry - time.Minute)
+	if err := store.Put("resumable_upload_"+ids[0], &expired); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sessions.Get(ids[0]); !errors.Is(err, resumable.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, resumable.ErrNotFound)
+	}
+	unlock, err := sessions.Lock(ids[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+
+	n, err := sessions.Sweep(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("got %d swept sessions, want 1", n)
+	}
+	n, err = sessions.Sweep(time.Now().Add(resumable.Expiry + time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("got %d swept sessions, want 1", n)
+	}
+	if _, err := sessions.Get(ids[1]); err != nil {
+		t.Fatalf("locked session: %v", err)
+	}
+	if _, err := sessions.Get(ids[2]); !errors.Is(err, resumable.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, resumable.ErrNotFound)
+	}
+}
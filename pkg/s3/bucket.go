@@ -0,0 +1,429 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package s3
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/feeds"
+	"github.com/ethersphere/bee/pkg/feeds/sequence"
+	"github.com/ethersphere/bee/pkg/file/loadsave"
+	"github.com/ethersphere/bee/pkg/manifest"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/gorilla/mux"
+)
+
+const (
+	bucketKeyPrefix = "s3_bucket_"
+	// deletedBucketKeyPrefix prefixes the tombstones of the deleted buckets.
+	deletedBucketKeyPrefix = "s3_deleted_bucket_"
+)
+
+// The headers with the feed of the bucket.
+const (
+	FeedOwnerHeader         = "Swarm-Feed-Owner"
+	FeedTopicHeader         = "Swarm-Feed-Topic"
+	ManifestReferenceHeader = "Swarm-Manifest-Reference"
+)
+
+var (
+	errBucketNotFound    = errors.New("bucket not found")
+	errInvalidFeedUpdate = errors.New("invalid feed update")
+	// errUnchanged is returned by the changes of the manifests which
+	// leave the manifest as it is.
+	errUnchanged = errors.New("unchanged")
+)
+
+var bucketNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+
+// bucket is the record of a bucket.
+type bucket struct {
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Reference is the reference of the latest version of the manifest.
+	Reference swarm.Address `json:"reference"`
+	// NextIndex is the index of the next update of the feed.
+	NextIndex uint64 `json:"nextIndex"`
+}
+
+// deletedBucket is the tombstone of a deleted bucket. The feed of a bucket
+// which is created again with the same name continues at the next index, as
+// the updates at the indexes before it are already stored and can not be
+// replaced.
+type deletedBucket struct {
+	NextIndex uint64 `json:"nextIndex"`
+}
+
+// FeedTopic returns the topic of the feed to which the versions of the
+// manifest of the bucket are published.
+func FeedTopic(name string) ([]byte, error) {
+	return crypto.LegacyKeccak256([]byte("s3/" + name))
+}
+
+func validBucketName(name string) bool {
+	return bucketNameRegexp.MatchString(name) && !strings.Contains(name, "..")
+}
+
+func (s *Service) getBucket(name string) (*bucket, error) {
+	var b bucket
+	if err := s.store.Get(bucketKeyPrefix+name, &b); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, errBucketNotFound
+		}
+		return nil, err
+	}
+	return &b, nil
+}
+
+// updateBucket applies the change to the manifest of the bucket, stores the
+// new version of the manifest and publishes it to the feed of the bucket.
+func (s *Service) updateBucket(ctx context.Context, name string, change func(context.Context, manifest.Interface) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, err := s.getBucket(name)
+	if err != nil {
+		return err
+	}
+	unlock, err := s.lockFeed(ctx, b)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	putter, err := s.newPutter()
+	if err != nil {
+		return err
+	}
+	ls := loadsave.New(putter, pipelineFactory(ctx, putter))
+
+	m, err := manifest.NewDefaultManifestReference(b.Reference, ls)
+	if err != nil {
+		return fmt.Errorf("load manifest: %w", err)
+	}
+	if err := change(ctx, m); err != nil {
+		if errors.Is(err, errUnchanged) {
+			return nil
+		}
+		return err
+	}
+
+	return s.publish(ctx, putter, b, m)
+}
+
+// lockFeed locks the feed of the bucket until the returned function is
+// called and moves the bucket to the latest update of the feed, which may
+// have been published by another service of the node.
+func (s *Service) lockFeed(ctx context.Context, b *bucket) (unlock func(), err error) {
+	topic, err := FeedTopic(b.Name)
+	if err != nil {
+		return nil, err
+	}
+	owner, err := s.signer.EthereumAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	unlock = s.updates.Lock(topic)
+	getter := feeds.NewGetter(s.storer, feeds.New(topic, owner))
+	for {
+		ch, err := getter.Get(ctx, sequence.NewIndex(b.NextIndex))
+		if errors.Is(err, storage.ErrNotFound) {
+			return unlock, nil
+		}
+		if err != nil {
+			unlock()
+			return nil, fmt.Errorf("feed lookup: %w", err)
+		}
+		_, payload, err := feeds.FromChunk(ch)
+		if err != nil {
+			unlock()
+			return nil, fmt.Errorf("feed update: %w", err)
+		}
+		// the reference with the optional decryption key
+		if len(payload) != swarm.HashSize && len(payload) != 2*swarm.HashSize {
+			unlock()
+			return nil, errInvalidFeedUpdate
+		}
+		b.Reference = swarm.NewAddress(payload)
+		b.NextIndex++
+	}
+}
+
+// publish stores the manifest as the latest version of the bucket and
+// publishes its reference to the feed of the bucket, which must be locked.
+func (s *Service) publish(ctx context.Context, putter storage.Putter, b *bucket, m manifest.Interface) error {
+	ref, err := m.Store(ctx)
+	if err != nil {
+		return fmt.Errorf("store manifest: %w", err)
+	}
+
+	topic, err := FeedTopic(b.Name)
+	if err != nil {
+		return err
+	}
+	updater, err := sequence.NewUpdaterAt(putter, s.signer, topic, b.NextIndex)
+	if err != nil {
+		return err
+	}
+	if err := updater.Update(ctx, time.Now().Unix(), ref.Bytes()); err != nil {
+		return fmt.Errorf("feed update: %w", err)
+	}
+
+	b.Reference = ref
+	b.NextIndex++
+	return s.store.Put(bucketKeyPrefix+b.Name, b)
+}
+
+type owner struct {
+	ID          string `xml:"ID"`
+	DisplayName string `xml:"DisplayName"`
+}
+
+type bucketInfo struct {
+	Name         string `xml:"Name"`
+	CreationDate string `xml:"CreationDate"`
+}
+
+type listAllMyBucketsResult struct {
+	XMLName xml.Name     `xml:"ListAllMyBucketsResult"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Owner   owner        `xml:"Owner"`
+	Buckets []bucketInfo `xml:"Buckets>Bucket"`
+}
+
+func (s *Service) owner() (owner, error) {
+	addr, err := s.signer.EthereumAddress()
+	if err != nil {
+		return owner{}, err
+	}
+	id := hex.EncodeToString(addr.Bytes())
+	return owner{ID: id, DisplayName: id}, nil
+}
+
+// listBucketsHandler lists the buckets in the order of their names.
+func (s *Service) listBucketsHandler(w http.ResponseWriter, r *http.Request) {
+	o, err := s.owner()
+	if err != nil {
+		s.logger.Debugf("s3: list buckets: owner: %v", err)
+		s.logger.Error("s3: list buckets: owner")
+		writeError(w, r, errInternalError)
+		return
+	}
+
+	resp := listAllMyBucketsResult{
+		Xmlns:   xmlns,
+		Owner:   o,
+		Buckets: []bucketInfo{},
+	}
+	err = s.store.Iterate(bucketKeyPrefix, func(_, value []byte) (bool, error) {
+		var b bucket
+		if err := json.Unmarshal(value, &b); err != nil {
+			return true, err
+		}
+		resp.Buckets = append(resp.Buckets, bucketInfo{
+			Name:         b.Name,
+			CreationDate: b.CreatedAt.UTC().Format(timeFormat),
+		})
+		return false, nil
+	})
+	if err != nil {
+		s.logger.Debugf("s3: list buckets: %v", err)
+		s.logger.Error("s3: list buckets")
+		writeError(w, r, errInternalError)
+		return
+	}
+	sort.Slice(resp.Buckets, func(i, j int) bool {
+		return resp.Buckets[i].Name < resp.Buckets[j].Name
+	})
+
+	writeXML(w, http.StatusOK, resp)
+}
+
+// createBucketHandler creates the bucket with an empty manifest.
+func (s *Service) createBucketHandler(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["bucket"]
+	if !validBucketName(name) {
+		writeError(w, r, errInvalidBucketName)
+		return
+	}
+
+	err := func() error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if _, err := s.getBucket(name); err == nil {
+			return errBucketAlreadyOwned
+		} else if !errors.Is(err, errBucketNotFound) {
+			return err
+		}
+
+		b := &bucket{
+			Name:      name,
+			CreatedAt: time.Now().UTC(),
+		}
+		var d deletedBucket
+		switch err := s.store.Get(deletedBucketKeyPrefix+name, &d); {
+		case err == nil:
+			b.NextIndex = d.NextIndex
+		case !errors.Is(err, storage.ErrNotFound):
+			return err
+		}
+		unlock, err := s.lockFeed(r.Context(), b)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		putter, err := s.newPutter()
+		if err != nil {
+			return err
+		}
+		m, err := manifest.NewDefaultManifest(loadsave.New(putter, pipelineFactory(r.Context(), putter)), false)
+		if err != nil {
+			return err
+		}
+		if err := s.publish(r.Context(), putter, b, m); err != nil {
+			return err
+		}
+		return s.store.Delete(deletedBucketKeyPrefix + name)
+	}()
+	if err != nil {
+		s.logger.Debugf("s3: create bucket %s: %v", name, err)
+		s.logger.Errorf("s3: create bucket %s", name)
+		writeError(w, r, toAPIError(err))
+		return
+	}
+
+	w.Header().Set("Location", "/"+name)
+	w.WriteHeader(http.StatusOK)
+}
+
+// headBucketHandler responds with the feed of the bucket and the reference of
+// its latest version.
+func (s *Service) headBucketHandler(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["bucket"]
+	b, err := s.getBucket(name)
+	if err != nil {
+		s.logger.Debugf("s3: head bucket %s: %v", name, err)
+		writeError(w, r, toAPIError(err))
+		return
+	}
+
+	owner, err := s.signer.EthereumAddress()
+	if err != nil {
+		s.logger.Debugf("s3: head bucket %s: owner: %v", name, err)
+		s.logger.Error("s3: head bucket: owner")
+		writeError(w, r, errInternalError)
+		return
+	}
+	topic, err := FeedTopic(name)
+	if err != nil {
+		s.logger.Debugf("s3: head bucket %s: topic: %v", name, err)
+		s.logger.Error("s3: head bucket: topic")
+		writeError(w, r, errInternalError)
+		return
+	}
+
+	w.Header().Set(FeedOwnerHeader, hex.EncodeToString(owner.Bytes()))
+	w.Header().Set(FeedTopicHeader, hex.EncodeToString(topic))
+	w.Header().Set(ManifestReferenceHeader, b.Reference.String())
+	w.WriteHeader(http.StatusOK)
+}
+
+// deleteBucketHandler deletes the record of the empty bucket and leaves a
+// tombstone with the next index of its feed. The versions of the bucket
+// remain published to its feed.
+func (s *Service) deleteBucketHandler(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["bucket"]
+
+	err := func() error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		b, err := s.getBucket(name)
+		if err != nil {
+			return err
+		}
+		empty := true
+		err = manifest.WalkFiles(r.Context(), loadsave.NewReadonly(s.storer), b.Reference, "", func(string, manifest.Entry) error {
+			empty = false
+			return errUnchanged
+		})
+		if err != nil && !errors.Is(err, errUnchanged) {
+			return err
+		}
+		if !empty {
+			return errBucketNotEmpty
+		}
+		if err := s.store.Put(deletedBucketKeyPrefix+name, deletedBucket{NextIndex: b.NextIndex}); err != nil {
+			return err
+		}
+		return s.store.Delete(bucketKeyPrefix + name)
+	}()
+	if err != nil {
+		s.logger.Debugf("s3: delete bucket %s: %v", name, err)
+		s.logger.Errorf("s3: delete bucket %s", name)
+		writeError(w, r, toAPIError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type locationConstraint struct {
+	XMLName xml.Name `xml:"LocationConstraint"`
+	Xmlns   string   `xml:"xmlns,attr"`
+}
+
+// bucketGetHandler dispatches the GET requests of the bucket.
+func (s *Service) bucketGetHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	switch {
+	case q.Has("location"):
+		if _, err := s.getBucket(mux.Vars(r)["bucket"]); err != nil {
+			writeError(w, r, toAPIError(err))
+			return
+		}
+		writeXML(w, http.StatusOK, locationConstraint{Xmlns: xmlns})
+	case q.Has("uploads"), q.Has("versioning"), q.Has("acl"), q.Has("policy"), q.Has("tagging"):
+		writeError(w, r, errNotImplemented)
+	default:
+		s.listObjectsHandler(w, r)
+	}
+}
+
+// toAPIError returns the S3 API error of the error.
+func toAPIError(err error) *apiError {
+	var e *apiError
+	switch {
+	case errors.As(err, &e):
+		return e
+	case errors.Is(err, errBucketNotFound):
+		return errNoSuchBucket
+	case errors.Is(err, errUploadNotFound):
+		return errNoSuchUpload
+	case errors.Is(err, manifest.ErrNotFound):
+		return errNoSuchKey
+	case errors.Is(err, postage.ErrBucketFull):
+		return errPaymentRequired
+	case errors.Is(err, postage.ErrNotFound), errors.Is(err, postage.ErrNotUsable):
+		return errServiceUnavailable
+	}
+	return errInternalError
+}
@@ -0,0 +1,100 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package s3
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+)
+
+// maxChunkHeaderSize is the maximal size of a chunk header line of the
+// aws-chunked content encoding.
+const maxChunkHeaderSize = 4096
+
+// chunkedReader decodes the aws-chunked content encoding of the streaming
+// signed payloads, in which each chunk of the data is preceded by a line with
+// its hexadecimal size and signature. The signatures are not verified.
+type chunkedReader struct {
+	r    *bufio.Reader
+	left int64 // bytes left in the current chunk
+	done bool
+	err  error
+}
+
+func newChunkedReader(r io.Reader) *chunkedReader {
+	return &chunkedReader{r: bufio.NewReaderSize(r, maxChunkHeaderSize)}
+}
+
+func (c *chunkedReader) Read(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	for c.left == 0 {
+		if c.done {
+			c.err = io.EOF
+			return 0, c.err
+		}
+		if err := c.nextChunk(); err != nil {
+			c.err = err
+			return 0, err
+		}
+	}
+
+	if int64(len(p)) > c.left {
+		p = p[:c.left]
+	}
+	n, err := c.r.Read(p)
+	c.left -= int64(n)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err == nil && c.left == 0 {
+		err = c.readCRLF()
+	}
+	if err != nil {
+		c.err = err
+	}
+	return n, err
+}
+
+// nextChunk reads the header of the next chunk.
+func (c *chunkedReader) nextChunk() error {
+	line, err := c.r.ReadSlice('\n')
+	if err != nil {
+		if err == io.EOF || err == bufio.ErrBufferFull {
+			return errIncompleteBody
+		}
+		return err
+	}
+	line = bytes.TrimRight(line, "\r\n")
+	if i := bytes.IndexByte(line, ';'); i >= 0 {
+		line = line[:i]
+	}
+	size, err := strconv.ParseInt(string(line), 16, 64)
+	if err != nil || size < 0 {
+		return errIncompleteBody
+	}
+	if size == 0 {
+		// the final chunk is followed by an empty line or trailers
+		c.done = true
+		return nil
+	}
+	c.left = size
+	return nil
+}
+
+// readCRLF reads the line ending after the data of a chunk.
+func (c *chunkedReader) readCRLF() error {
+	var b [2]byte
+	if _, err := io.ReadFull(c.r, b[:]); err != nil {
+		return io.ErrUnexpectedEOF
+	}
+	if b[0] != '\r' || b[1] != '\n' {
+		return errIncompleteBody
+	}
+	return nil
+}
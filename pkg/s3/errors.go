@@ -0,0 +1,68 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package s3
+
+import (
+	"encoding/xml"
+	"net/http"
+)
+
+// apiError is an error of the S3 API.
+type apiError struct {
+	code    string
+	message string
+	status  int
+}
+
+func (e *apiError) Error() string {
+	return e.code + ": " + e.message
+}
+
+var (
+	errBadDigest              = &apiError{"BadDigest", "The Content-MD5 you specified did not match what we received.", http.StatusBadRequest}
+	errBucketAlreadyOwned     = &apiError{"BucketAlreadyOwnedByYou", "The bucket you tried to create already exists, and you own it.", http.StatusConflict}
+	errBucketNotEmpty         = &apiError{"BucketNotEmpty", "The bucket you tried to delete is not empty.", http.StatusConflict}
+	errIncompleteBody         = &apiError{"IncompleteBody", "You did not provide the number of bytes specified by the Content-Length HTTP header.", http.StatusBadRequest}
+	errInternalError          = &apiError{"InternalError", "We encountered an internal error. Please try again.", http.StatusInternalServerError}
+	errInvalidArgument        = &apiError{"InvalidArgument", "Invalid Argument.", http.StatusBadRequest}
+	errInvalidBucketName      = &apiError{"InvalidBucketName", "The specified bucket is not valid.", http.StatusBadRequest}
+	errInvalidDigest          = &apiError{"InvalidDigest", "The Content-MD5 you specified is not valid.", http.StatusBadRequest}
+	errInvalidPart            = &apiError{"InvalidPart", "One or more of the specified parts could not be found.", http.StatusBadRequest}
+	errInvalidPartOrder       = &apiError{"InvalidPartOrder", "The list of parts was not in ascending order.", http.StatusBadRequest}
+	errInvalidRequest         = &apiError{"InvalidRequest", "Invalid Request.", http.StatusBadRequest}
+	errMalformedXML           = &apiError{"MalformedXML", "The XML you provided was not well-formed or did not validate against our published schema.", http.StatusBadRequest}
+	errMethodNotAllowed       = &apiError{"MethodNotAllowed", "The specified method is not allowed against this resource.", http.StatusMethodNotAllowed}
+	errNoSuchBucket           = &apiError{"NoSuchBucket", "The specified bucket does not exist.", http.StatusNotFound}
+	errNoSuchKey              = &apiError{"NoSuchKey", "The specified key does not exist.", http.StatusNotFound}
+	errNoSuchUpload           = &apiError{"NoSuchUpload", "The specified multipart upload does not exist.", http.StatusNotFound}
+	errNotImplemented         = &apiError{"NotImplemented", "A header or query you provided implies functionality that is not implemented.", http.StatusNotImplemented}
+	errPaymentRequired        = &apiError{"PaymentRequired", "The postage batch of the gateway is overissued.", http.StatusPaymentRequired}
+	errServiceUnavailable     = &apiError{"ServiceUnavailable", "The postage batch of the gateway is not usable.", http.StatusServiceUnavailable}
+	errInvalidMaxKeys         = &apiError{"InvalidArgument", "The max-keys must be a non-negative integer.", http.StatusBadRequest}
+	errInvalidContinuationTok = &apiError{"InvalidArgument", "The continuation token provided is incorrect.", http.StatusBadRequest}
+)
+
+type errorResponse struct {
+	XMLName   xml.Name `xml:"Error"`
+	Code      string   `xml:"Code"`
+	Message   string   `xml:"Message"`
+	Resource  string   `xml:"Resource"`
+	RequestID string   `xml:"RequestId"`
+}
+
+// writeError writes the error response. The responses of HEAD requests have
+// no body.
+func writeError(w http.ResponseWriter, r *http.Request, e *apiError) {
+	if r.Method == http.MethodHead {
+		w.WriteHeader(e.status)
+		return
+	}
+	writeXML(w, e.status, errorResponse{
+		Code:      e.code,
+		Message:   e.message,
+		Resource:  r.URL.Path,
+		RequestID: w.Header().Get(requestIDHeader),
+	})
+}
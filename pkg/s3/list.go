@@ -0,0 +1,174 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package s3
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ethersphere/bee/pkg/file/loadsave"
+	"github.com/ethersphere/bee/pkg/manifest"
+	"github.com/gorilla/mux"
+)
+
+const maxListKeys = 1000
+
+// errListFull stops the walk of the manifest when the listing is full.
+var errListFull = errors.New("list full")
+
+type listEntry struct {
+	Key          string `xml:"Key"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int64  `xml:"Size"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+type commonPrefix struct {
+	Prefix string `xml:"Prefix"`
+}
+
+type listBucketResult struct {
+	XMLName        xml.Name       `xml:"ListBucketResult"`
+	Xmlns          string         `xml:"xmlns,attr"`
+	Name           string         `xml:"Name"`
+	Prefix         string         `xml:"Prefix"`
+	Delimiter      string         `xml:"Delimiter,omitempty"`
+	MaxKeys        int            `xml:"MaxKeys"`
+	IsTruncated    bool           `xml:"IsTruncated"`
+	Contents       []listEntry    `xml:"Contents"`
+	CommonPrefixes []commonPrefix `xml:"CommonPrefixes"`
+
+	// ListObjectsV2
+	KeyCount              *int   `xml:"KeyCount,omitempty"`
+	ContinuationToken     string `xml:"ContinuationToken,omitempty"`
+	NextContinuationToken string `xml:"NextContinuationToken,omitempty"`
+	StartAfter            string `xml:"StartAfter,omitempty"`
+
+	// ListObjects
+	Marker     *string `xml:"Marker,omitempty"`
+	NextMarker string  `xml:"NextMarker,omitempty"`
+}
+
+// listObjectsHandler lists the objects of the bucket in the order of their
+// keys, with the ListObjectsV2 or the ListObjects parameters. The keys which
+// contain the delimiter after the prefix are grouped by the common prefixes.
+func (s *Service) listObjectsHandler(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["bucket"]
+	q := r.URL.Query()
+	v2 := q.Get("list-type") == "2"
+
+	resp := listBucketResult{
+		Xmlns:     xmlns,
+		Name:      name,
+		Prefix:    q.Get("prefix"),
+		Delimiter: q.Get("delimiter"),
+		MaxKeys:   maxListKeys,
+	}
+	if v := q.Get("max-keys"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeError(w, r, errInvalidMaxKeys)
+			return
+		}
+		if n < maxListKeys {
+			resp.MaxKeys = n
+		}
+	}
+
+	// the entries after the marker are listed
+	var marker string
+	if v2 {
+		resp.StartAfter = q.Get("start-after")
+		marker = resp.StartAfter
+		if t := q.Get("continuation-token"); t != "" {
+			b, err := base64.RawURLEncoding.DecodeString(t)
+			if err != nil {
+				writeError(w, r, errInvalidContinuationTok)
+				return
+			}
+			resp.ContinuationToken = t
+			marker = string(b)
+		}
+	} else {
+		marker = q.Get("marker")
+		resp.Marker = &marker
+	}
+
+	b, err := s.getBucket(name)
+	if err != nil {
+		s.logger.Debugf("s3: list objects %s: %v", name, err)
+		writeError(w, r, toAPIError(err))
+		return
+	}
+
+	// the walk starts at the deepest directory containing the prefix
+	dir := resp.Prefix[:strings.LastIndex(resp.Prefix, "/")+1]
+
+	var last string
+	count := 0
+	err = manifest.WalkFiles(r.Context(), loadsave.NewReadonly(s.storer), b.Reference, dir, func(key string, e manifest.Entry) error {
+		if !strings.HasPrefix(key, resp.Prefix) || key <= marker || e.Reference().IsZero() {
+			return nil
+		}
+
+		prefix := ""
+		if resp.Delimiter != "" {
+			if i := strings.Index(key[len(resp.Prefix):], resp.Delimiter); i >= 0 {
+				prefix = key[:len(resp.Prefix)+i+len(resp.Delimiter)]
+			}
+		}
+		if prefix != "" && (prefix == last || prefix == marker) {
+			// the keys of a common prefix are contiguous
+			return nil
+		}
+
+		if count == resp.MaxKeys {
+			resp.IsTruncated = true
+			return errListFull
+		}
+		count++
+
+		if prefix != "" {
+			resp.CommonPrefixes = append(resp.CommonPrefixes, commonPrefix{Prefix: prefix})
+			last = prefix
+			return nil
+		}
+		o := newObject(key, e)
+		resp.Contents = append(resp.Contents, listEntry{
+			Key:          key,
+			LastModified: o.LastModified.UTC().Format(timeFormat),
+			ETag:         fmt.Sprintf("%q", o.ETag),
+			Size:         o.Size,
+			StorageClass: "STANDARD",
+		})
+		last = key
+		return nil
+	})
+	if err != nil && !errors.Is(err, errListFull) {
+		s.logger.Debugf("s3: list objects %s: %v", name, err)
+		s.logger.Errorf("s3: list objects %s", name)
+		writeError(w, r, toAPIError(err))
+		return
+	}
+
+	if resp.IsTruncated {
+		if v2 {
+			resp.NextContinuationToken = base64.RawURLEncoding.EncodeToString([]byte(last))
+		} else if resp.Delimiter != "" {
+			resp.NextMarker = last
+		}
+	}
+	if v2 {
+		resp.KeyCount = &count
+	}
+
+	writeXML(w, http.StatusOK, resp)
+}
@@ -0,0 +1,281 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package s3
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/file/joiner"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/gorilla/mux"
+)
+
+const (
+	multipartKeyPrefix = "s3_multipart_"
+
+	maxPartNumber = 10000
+)
+
+var errUploadNotFound = errors.New("multipart upload not found")
+
+// multipartUpload is the record of a multipart upload. The parts are stored
+// as separate files until the upload is completed.
+type multipartUpload struct {
+	UploadID  string                `json:"uploadId"`
+	Bucket    string                `json:"bucket"`
+	Key       string                `json:"key"`
+	Metadata  map[string]string     `json:"metadata"`
+	CreatedAt time.Time             `json:"createdAt"`
+	Parts     map[int]multipartPart `json:"parts"`
+}
+
+type multipartPart struct {
+	Reference swarm.Address `json:"reference"`
+	Size      int64         `json:"size"`
+	// ETag is the hex encoded MD5 digest of the part.
+	ETag string `json:"etag"`
+}
+
+func (s *Service) getUpload(bucket, key, id string) (*multipartUpload, error) {
+	var u multipartUpload
+	if err := s.store.Get(multipartKeyPrefix+id, &u); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, errUploadNotFound
+		}
+		return nil, err
+	}
+	if u.Bucket != bucket || u.Key != key {
+		return nil, errUploadNotFound
+	}
+	return &u, nil
+}
+
+type initiateMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	UploadID string   `xml:"UploadId"`
+}
+
+// createMultipartUploadHandler starts a multipart upload of the object with
+// the metadata from the headers of the request.
+func (s *Service) createMultipartUploadHandler(w http.ResponseWriter, r *http.Request) {
+	bucket, key := mux.Vars(r)["bucket"], mux.Vars(r)["key"]
+	if _, err := s.getBucket(bucket); err != nil {
+		writeError(w, r, toAPIError(err))
+		return
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		s.logger.Debugf("s3: create multipart upload %s/%s: upload id: %v", bucket, key, err)
+		s.logger.Errorf("s3: create multipart upload %s/%s", bucket, key)
+		writeError(w, r, errInternalError)
+		return
+	}
+	u := multipartUpload{
+		UploadID:  hex.EncodeToString(id),
+		Bucket:    bucket,
+		Key:       key,
+		Metadata:  objectMetadata(key, r.Header),
+		CreatedAt: time.Now().UTC(),
+		Parts:     make(map[int]multipartPart),
+	}
+	if err := s.store.Put(multipartKeyPrefix+u.UploadID, u); err != nil {
+		s.logger.Debugf("s3: create multipart upload %s/%s: %v", bucket, key, err)
+		s.logger.Errorf("s3: create multipart upload %s/%s", bucket, key)
+		writeError(w, r, errInternalError)
+		return
+	}
+
+	writeXML(w, http.StatusOK, initiateMultipartUploadResult{
+		Xmlns:    xmlns,
+		Bucket:   bucket,
+		Key:      key,
+		UploadID: u.UploadID,
+	})
+}
+
+// uploadPartHandler stores a part of the multipart upload. A part uploaded
+// again with the same number replaces the previous one.
+func (s *Service) uploadPartHandler(w http.ResponseWriter, r *http.Request) {
+	bucket, key := mux.Vars(r)["bucket"], mux.Vars(r)["key"]
+	q := r.URL.Query()
+	id := q.Get("uploadId")
+
+	number, err := strconv.Atoi(q.Get("partNumber"))
+	if err != nil || number < 1 || number > maxPartNumber {
+		writeError(w, r, errInvalidArgument)
+		return
+	}
+	if _, err := s.getUpload(bucket, key, id); err != nil {
+		s.logger.Debugf("s3: upload part %s/%s: %v", bucket, key, err)
+		writeError(w, r, toAPIError(err))
+		return
+	}
+
+	ref, size, sum, err := s.storeData(r.Context(), requestBody(r))
+	if err != nil {
+		s.logger.Debugf("s3: upload part %s/%s: store data: %v", bucket, key, err)
+		s.logger.Errorf("s3: upload part %s/%s: store data", bucket, key)
+		writeError(w, r, toAPIError(err))
+		return
+	}
+	part := multipartPart{Reference: ref, Size: size, ETag: hex.EncodeToString(sum)}
+
+	err = func() error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		// the upload may have been completed or aborted meanwhile
+		u, err := s.getUpload(bucket, key, id)
+		if err != nil {
+			return err
+		}
+		u.Parts[number] = part
+		return s.store.Put(multipartKeyPrefix+id, u)
+	}()
+	if err != nil {
+		s.logger.Debugf("s3: upload part %s/%s: %v", bucket, key, err)
+		s.logger.Errorf("s3: upload part %s/%s", bucket, key)
+		writeError(w, r, toAPIError(err))
+		return
+	}
+
+	w.Header().Set("ETag", fmt.Sprintf("%q", part.ETag))
+	w.WriteHeader(http.StatusOK)
+}
+
+type completeMultipartUpload struct {
+	XMLName xml.Name `xml:"CompleteMultipartUpload"`
+	Parts   []struct {
+		PartNumber int    `xml:"PartNumber"`
+		ETag       string `xml:"ETag"`
+	} `xml:"Part"`
+}
+
+type completeMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"CompleteMultipartUploadResult"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Location string   `xml:"Location"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	ETag     string   `xml:"ETag"`
+}
+
+// completeMultipartUploadHandler adds the object with the data of the listed
+// parts to the bucket. The data of the parts is split again as a single file.
+func (s *Service) completeMultipartUploadHandler(w http.ResponseWriter, r *http.Request) {
+	bucket, key := mux.Vars(r)["bucket"], mux.Vars(r)["key"]
+	id := r.URL.Query().Get("uploadId")
+
+	var req completeMultipartUpload
+	if err := xml.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Parts) == 0 {
+		writeError(w, r, errMalformedXML)
+		return
+	}
+
+	u, err := s.getUpload(bucket, key, id)
+	if err != nil {
+		s.logger.Debugf("s3: complete multipart upload %s/%s: %v", bucket, key, err)
+		writeError(w, r, toAPIError(err))
+		return
+	}
+
+	var (
+		readers = make([]io.Reader, 0, len(req.Parts))
+		sums    = md5.New()
+	)
+	for i, p := range req.Parts {
+		if i > 0 && p.PartNumber <= req.Parts[i-1].PartNumber {
+			writeError(w, r, errInvalidPartOrder)
+			return
+		}
+		part, ok := u.Parts[p.PartNumber]
+		if !ok || strings.Trim(p.ETag, `"`) != part.ETag {
+			writeError(w, r, errInvalidPart)
+			return
+		}
+		reader, _, err := joiner.New(r.Context(), s.storer, part.Reference)
+		if err != nil {
+			s.logger.Debugf("s3: complete multipart upload %s/%s: joiner: %v", bucket, key, err)
+			s.logger.Errorf("s3: complete multipart upload %s/%s", bucket, key)
+			writeError(w, r, errInternalError)
+			return
+		}
+		readers = append(readers, reader)
+		sum, _ := hex.DecodeString(part.ETag)
+		_, _ = sums.Write(sum)
+	}
+
+	ref, size, _, err := s.storeData(r.Context(), io.MultiReader(readers...))
+	if err != nil {
+		s.logger.Debugf("s3: complete multipart upload %s/%s: store data: %v", bucket, key, err)
+		s.logger.Errorf("s3: complete multipart upload %s/%s: store data", bucket, key)
+		writeError(w, r, toAPIError(err))
+		return
+	}
+
+	// the entity tag of the multipart objects is the digest of the digests of
+	// the parts with the number of the parts
+	etag := fmt.Sprintf("%s-%d", hex.EncodeToString(sums.Sum(nil)), len(req.Parts))
+	if err := s.addObject(r.Context(), bucket, key, ref, size, etag, u.Metadata); err != nil {
+		s.logger.Debugf("s3: complete multipart upload %s/%s: %v", bucket, key, err)
+		s.logger.Errorf("s3: complete multipart upload %s/%s", bucket, key)
+		writeError(w, r, toAPIError(err))
+		return
+	}
+	if err := s.deleteUpload(id); err != nil {
+		s.logger.Debugf("s3: complete multipart upload %s/%s: delete upload: %v", bucket, key, err)
+		s.logger.Errorf("s3: complete multipart upload %s/%s: delete upload", bucket, key)
+	}
+
+	writeXML(w, http.StatusOK, completeMultipartUploadResult{
+		Xmlns:    xmlns,
+		Location: "/" + bucket + "/" + key,
+		Bucket:   bucket,
+		Key:      key,
+		ETag:     fmt.Sprintf("%q", etag),
+	})
+}
+
+// abortMultipartUploadHandler deletes the record of the multipart upload.
+func (s *Service) abortMultipartUploadHandler(w http.ResponseWriter, r *http.Request) {
+	bucket, key := mux.Vars(r)["bucket"], mux.Vars(r)["key"]
+	id := r.URL.Query().Get("uploadId")
+
+	if _, err := s.getUpload(bucket, key, id); err != nil {
+		s.logger.Debugf("s3: abort multipart upload %s/%s: %v", bucket, key, err)
+		writeError(w, r, toAPIError(err))
+		return
+	}
+	if err := s.deleteUpload(id); err != nil {
+		s.logger.Debugf("s3: abort multipart upload %s/%s: %v", bucket, key, err)
+		s.logger.Errorf("s3: abort multipart upload %s/%s", bucket, key)
+		writeError(w, r, errInternalError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Service) deleteUpload(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.store.Delete(multipartKeyPrefix + id)
+}
@@ -0,0 +1,284 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/file/joiner"
+	"github.com/ethersphere/bee/pkg/file/loadsave"
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/manifest"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/ethersphere/bee/pkg/swarm"
+	"github.com/gorilla/mux"
+)
+
+// The metadata keys of the manifest entries of the objects.
+const (
+	metadataETagKey         = "S3-ETag"
+	metadataSizeKey         = "S3-Size"
+	metadataLastModifiedKey = "S3-Last-Modified"
+
+	// userMetadataPrefix is the prefix of the headers with the user-defined
+	// metadata of the objects, which is stored with the canonical header
+	// keys.
+	userMetadataPrefix = "X-Amz-Meta-"
+
+	defaultContentType = "binary/octet-stream"
+)
+
+// object is an object of a bucket.
+type object struct {
+	Key          string
+	Reference    swarm.Address
+	Size         int64
+	ETag         string
+	LastModified time.Time
+	Metadata     map[string]string
+}
+
+func newObject(key string, e manifest.Entry) *object {
+	o := &object{
+		Key:       key,
+		Reference: e.Reference(),
+		Metadata:  e.Metadata(),
+		ETag:      e.Metadata()[metadataETagKey],
+	}
+	o.Size, _ = strconv.ParseInt(o.Metadata[metadataSizeKey], 10, 64)
+	o.LastModified, _ = time.Parse(time.RFC3339, o.Metadata[metadataLastModifiedKey])
+	return o
+}
+
+// objectMetadata returns the metadata of the manifest entry of the object
+// with the content type and the user-defined metadata from the headers of the
+// request.
+func objectMetadata(key string, h http.Header) map[string]string {
+	contentType := h.Get("Content-Type")
+	if contentType == "" {
+		contentType = defaultContentType
+	}
+	metadata := map[string]string{
+		manifest.EntryMetadataContentTypeKey: contentType,
+		manifest.EntryMetadataFilenameKey:    path.Base(key),
+	}
+	for k, v := range h {
+		if strings.HasPrefix(k, userMetadataPrefix) && len(v) > 0 {
+			metadata[k] = v[0]
+		}
+	}
+	return metadata
+}
+
+// addObject adds the object with the data stored at the reference to the
+// bucket.
+func (s *Service) addObject(ctx context.Context, bucket, key string, ref swarm.Address, size int64, etag string, metadata map[string]string) error {
+	metadata[metadataETagKey] = etag
+	metadata[metadataSizeKey] = strconv.FormatInt(size, 10)
+	metadata[metadataLastModifiedKey] = time.Now().UTC().Format(time.RFC3339)
+
+	return s.updateBucket(ctx, bucket, func(ctx context.Context, m manifest.Interface) error {
+		return m.Add(ctx, key, manifest.NewEntry(ref, metadata))
+	})
+}
+
+// storeData splits the data and returns its reference, size and MD5 digest.
+func (s *Service) storeData(ctx context.Context, r io.Reader) (swarm.Address, int64, []byte, error) {
+	putter, err := s.newPutter()
+	if err != nil {
+		return swarm.ZeroAddress, 0, nil, err
+	}
+
+	h := md5.New()
+	cr := &countingReader{r: io.TeeReader(r, h)}
+	ref, err := builder.FeedPipeline(ctx, builder.NewPipelineBuilder(ctx, putter, storage.ModePutUpload, false), cr)
+	if err != nil {
+		return swarm.ZeroAddress, 0, nil, err
+	}
+	return ref, cr.n, h.Sum(nil), nil
+}
+
+// requestBody returns the body of the request, decoding the aws-chunked
+// content encoding of the streaming signed payloads.
+func requestBody(r *http.Request) io.Reader {
+	if strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		return newChunkedReader(r.Body)
+	}
+	return r.Body
+}
+
+// objectPutHandler stores the object or a part of a multipart upload.
+func (s *Service) objectPutHandler(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Has("uploadId") {
+		s.uploadPartHandler(w, r)
+		return
+	}
+	if r.Header.Get("X-Amz-Copy-Source") != "" {
+		writeError(w, r, errNotImplemented)
+		return
+	}
+
+	bucket, key := mux.Vars(r)["bucket"], mux.Vars(r)["key"]
+	if _, err := s.getBucket(bucket); err != nil {
+		writeError(w, r, toAPIError(err))
+		return
+	}
+
+	var wantMD5 []byte
+	if h := r.Header.Get("Content-Md5"); h != "" {
+		var err error
+		wantMD5, err = base64.StdEncoding.DecodeString(h)
+		if err != nil || len(wantMD5) != md5.Size {
+			writeError(w, r, errInvalidDigest)
+			return
+		}
+	}
+
+	ref, size, sum, err := s.storeData(r.Context(), requestBody(r))
+	if err != nil {
+		s.logger.Debugf("s3: put object %s/%s: store data: %v", bucket, key, err)
+		s.logger.Errorf("s3: put object %s/%s: store data", bucket, key)
+		writeError(w, r, toAPIError(err))
+		return
+	}
+	if wantMD5 != nil && !bytes.Equal(wantMD5, sum) {
+		writeError(w, r, errBadDigest)
+		return
+	}
+
+	etag := hex.EncodeToString(sum)
+	if err := s.addObject(r.Context(), bucket, key, ref, size, etag, objectMetadata(key, r.Header)); err != nil {
+		s.logger.Debugf("s3: put object %s/%s: %v", bucket, key, err)
+		s.logger.Errorf("s3: put object %s/%s", bucket, key)
+		writeError(w, r, toAPIError(err))
+		return
+	}
+
+	w.Header().Set("ETag", fmt.Sprintf("%q", etag))
+	w.WriteHeader(http.StatusOK)
+}
+
+// lookupObject returns the object of the latest version of the bucket.
+func (s *Service) lookupObject(ctx context.Context, bucket, key string) (*object, error) {
+	b, err := s.getBucket(bucket)
+	if err != nil {
+		return nil, err
+	}
+	m, err := manifest.NewDefaultManifestReference(b.Reference, loadsave.NewReadonly(s.storer))
+	if err != nil {
+		return nil, err
+	}
+	e, err := m.Lookup(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if e.Reference().IsZero() {
+		return nil, manifest.ErrNotFound
+	}
+	return newObject(key, e), nil
+}
+
+// getObjectHandler serves the content of the object with support for range
+// and conditional requests, or only its headers for HEAD requests.
+func (s *Service) getObjectHandler(w http.ResponseWriter, r *http.Request) {
+	bucket, key := mux.Vars(r)["bucket"], mux.Vars(r)["key"]
+	if r.Method == http.MethodGet && r.URL.Query().Has("uploadId") {
+		writeError(w, r, errNotImplemented)
+		return
+	}
+
+	o, err := s.lookupObject(r.Context(), bucket, key)
+	if err != nil {
+		s.logger.Debugf("s3: get object %s/%s: %v", bucket, key, err)
+		writeError(w, r, toAPIError(err))
+		return
+	}
+
+	reader, _, err := joiner.New(r.Context(), s.storer, o.Reference)
+	if err != nil {
+		s.logger.Debugf("s3: get object %s/%s: joiner: %v", bucket, key, err)
+		s.logger.Errorf("s3: get object %s/%s", bucket, key)
+		if errors.Is(err, storage.ErrNotFound) {
+			writeError(w, r, errNoSuchKey)
+			return
+		}
+		writeError(w, r, errInternalError)
+		return
+	}
+
+	for k, v := range o.Metadata {
+		if strings.HasPrefix(k, userMetadataPrefix) {
+			w.Header().Set(k, v)
+		}
+	}
+	w.Header().Set("Content-Type", o.Metadata[manifest.EntryMetadataContentTypeKey])
+	w.Header().Set("ETag", fmt.Sprintf("%q", o.ETag))
+	w.Header().Set("Accept-Ranges", "bytes")
+	http.ServeContent(w, r, "", o.LastModified, reader)
+}
+
+// objectDeleteHandler deletes the object or aborts a multipart upload. The
+// deletion of an object which does not exist succeeds.
+func (s *Service) objectDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Has("uploadId") {
+		s.abortMultipartUploadHandler(w, r)
+		return
+	}
+
+	bucket, key := mux.Vars(r)["bucket"], mux.Vars(r)["key"]
+	err := s.updateBucket(r.Context(), bucket, func(ctx context.Context, m manifest.Interface) error {
+		if err := m.Remove(ctx, key); err != nil {
+			if errors.Is(err, manifest.ErrNotFound) {
+				return errUnchanged
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Debugf("s3: delete object %s/%s: %v", bucket, key, err)
+		s.logger.Errorf("s3: delete object %s/%s", bucket, key)
+		writeError(w, r, toAPIError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// objectPostHandler dispatches the POST requests of the multipart uploads.
+func (s *Service) objectPostHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	switch {
+	case q.Has("uploads"):
+		s.createMultipartUploadHandler(w, r)
+	case q.Has("uploadId"):
+		s.completeMultipartUploadHandler(w, r)
+	default:
+		writeError(w, r, errNotImplemented)
+	}
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
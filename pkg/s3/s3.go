@@ -0,0 +1,162 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package s3 provides an HTTP gateway which serves a subset of the Amazon S3
+// API with path-style requests. Each bucket is a mantaray manifest with the
+// objects as its entries. Every change of a bucket stores a new version of
+// the manifest, whose reference is published to a sequence feed owned by the
+// node, and the chunks are stamped with the configured postage batch. The
+// feed of a bucket can also be updated through the WebDAV gateway, the
+// changes of a bucket are based on the latest update of its feed.
+//
+// The gateway does not authenticate requests, the signatures of the requests
+// are ignored.
+package s3
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/xml"
+	"net/http"
+	"sync"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/feeds"
+	"github.com/ethersphere/bee/pkg/file/pipeline"
+	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/logging"
+	"github.com/ethersphere/bee/pkg/postage"
+	"github.com/ethersphere/bee/pkg/storage"
+	"github.com/gorilla/mux"
+	"resenje.org/web"
+)
+
+const (
+	// xmlns is the namespace of the S3 API responses.
+	xmlns = "http://s3.amazonaws.com/doc/2006-03-01/"
+
+	// timeFormat is the format of the times in the S3 API responses.
+	timeFormat = "2006-01-02T15:04:05.000Z"
+
+	requestIDHeader = "X-Amz-Request-Id"
+)
+
+// Service is the S3 gateway HTTP handler.
+type Service struct {
+	http.Handler
+
+	storer  storage.Storer
+	post    postage.Service
+	signer  crypto.Signer
+	store   storage.StateStorer
+	batchID []byte
+	logger  logging.Logger
+
+	// mu serializes the changes of the buckets and the multipart uploads,
+	// which are read, modified and stored.
+	mu sync.Mutex
+	// updates serializes the updates of the feeds of the buckets with the
+	// other services of the node which update them.
+	updates *feeds.UpdateLocks
+}
+
+// New creates a new S3 gateway which stores the buckets and the objects with
+// storer, stamped with the postage batch batchID, and keeps the records of
+// the buckets and the multipart uploads in the state store. The updates of
+// the feeds of the buckets are serialized with the other services of the
+// node which update them by updates.
+func New(storer storage.Storer, post postage.Service, signer crypto.Signer, store storage.StateStorer, updates *feeds.UpdateLocks, batchID []byte, logger logging.Logger) *Service {
+	s := &Service{
+		storer:  storer,
+		post:    post,
+		signer:  signer,
+		store:   store,
+		updates: updates,
+		batchID: batchID,
+		logger:  logger,
+	}
+	s.setupRouting()
+	return s
+}
+
+func (s *Service) setupRouting() {
+	router := mux.NewRouter()
+	// the object keys are used as they are
+	router.SkipClean(true)
+	router.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, r, errInvalidRequest)
+	})
+
+	router.Handle("/", methodHandler{
+		"GET": s.listBucketsHandler,
+	})
+	router.Handle("/{bucket}", methodHandler{
+		"GET":    s.bucketGetHandler,
+		"HEAD":   s.headBucketHandler,
+		"PUT":    s.createBucketHandler,
+		"DELETE": s.deleteBucketHandler,
+	})
+	router.Handle("/{bucket}/", methodHandler{
+		"GET":    s.bucketGetHandler,
+		"HEAD":   s.headBucketHandler,
+		"PUT":    s.createBucketHandler,
+		"DELETE": s.deleteBucketHandler,
+	})
+	router.Handle("/{bucket}/{key:.+}", methodHandler{
+		"GET":    s.getObjectHandler,
+		"HEAD":   s.getObjectHandler,
+		"PUT":    s.objectPutHandler,
+		"POST":   s.objectPostHandler,
+		"DELETE": s.objectDeleteHandler,
+	})
+
+	s.Handler = web.ChainHandlers(
+		requestIDHandler,
+		web.FinalHandler(router),
+	)
+}
+
+// methodHandler dispatches the request to the handler of its method.
+type methodHandler map[string]http.HandlerFunc
+
+func (h methodHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f, ok := h[r.Method]
+	if !ok {
+		writeError(w, r, errMethodNotAllowed)
+		return
+	}
+	f(w, r)
+}
+
+// requestIDHandler sets a random request ID header which is also included in
+// the error responses.
+func requestIDHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := make([]byte, 8)
+		_, _ = rand.Read(id)
+		w.Header().Set(requestIDHeader, hex.EncodeToString(id))
+		h.ServeHTTP(w, r)
+	})
+}
+
+// newPutter returns a putter which stamps the chunks with the postage batch
+// of the gateway and stores them to be pushed to the network.
+func (s *Service) newPutter() (*postage.StamperPutter, error) {
+	return postage.NewStamperPutter(s.storer, s.post, s.signer, s.batchID)
+}
+
+// pipelineFactory returns the constructor of the pipelines which split the
+// data of the objects and the manifests.
+func pipelineFactory(ctx context.Context, putter storage.Putter) func() pipeline.Interface {
+	return builder.NewPipelineFactory(ctx, putter, storage.ModePutUpload, false)
+}
+
+// writeXML writes the response encoded as XML with the status code.
+func writeXML(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(xml.Header))
+	_ = xml.NewEncoder(w).Encode(v)
+}
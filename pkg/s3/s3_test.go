@@ -0,0 +1,485 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package s3_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/dav"
+	"github.com/ethersphere/bee/pkg/feeds"
+	"github.com/ethersphere/bee/pkg/feeds/sequence"
+	"github.com/ethersphere/bee/pkg/logging"
+	mockpost "github.com/ethersphere/bee/pkg/postage/mock"
+	"github.com/ethersphere/bee/pkg/s3"
+	statestore "github.com/ethersphere/bee/pkg/statestore/mock"
+	"github.com/ethersphere/bee/pkg/storage/mock"
+	"github.com/ethersphere/bee/pkg/swarm"
+)
+
+var batchID = bytes.Repeat([]byte{1}, 32)
+
+func newTestService(t *testing.T) (*s3.Service, *mock.MockStorer, crypto.Signer) {
+	t.Helper()
+
+	pk, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := crypto.NewDefaultSigner(pk)
+	storer := mock.NewStorer()
+	post := mockpost.New(mockpost.WithAcceptAll())
+	s := s3.New(storer, post, signer, statestore.NewStateStore(), feeds.NewUpdateLocks(), batchID, logging.New(io.Discard, 0))
+	return s, storer, signer
+}
+
+func request(t *testing.T, h http.Handler, method, url string, body io.Reader, header http.Header) *http.Response {
+	t.Helper()
+
+	r := httptest.NewRequest(method, url, body)
+	for k, v := range header {
+		r.Header[k] = v
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w.Result()
+}
+
+func expectStatus(t *testing.T, resp *http.Response, want int) []byte {
+	t.Helper()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != want {
+		t.Fatalf("got status %d, want %d: %s", resp.StatusCode, want, body)
+	}
+	return body
+}
+
+func expectErrorCode(t *testing.T, resp *http.Response, status int, code string) {
+	t.Helper()
+
+	body := expectStatus(t, resp, status)
+	var e struct {
+		Code string `xml:"Code"`
+	}
+	if err := xml.Unmarshal(body, &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Code != code {
+		t.Fatalf("got error code %q, want %q", e.Code, code)
+	}
+}
+
+func TestBuckets(t *testing.T) {
+	s, _, signer := newTestService(t)
+
+	expectStatus(t, request(t, s, http.MethodPut, "/bucket-a", nil, nil), http.StatusOK)
+	expectStatus(t, request(t, s, http.MethodPut, "/bucket-b/", nil, nil), http.StatusOK)
+	expectErrorCode(t, request(t, s, http.MethodPut, "/bucket-a", nil, nil), http.StatusConflict, "BucketAlreadyOwnedByYou")
+	expectErrorCode(t, request(t, s, http.MethodPut, "/Invalid_Bucket", nil, nil), http.StatusBadRequest, "InvalidBucketName")
+
+	t.Run("list", func(t *testing.T) {
+		body := expectStatus(t, request(t, s, http.MethodGet, "/", nil, nil), http.StatusOK)
+		var resp struct {
+			Buckets []string `xml:"Buckets>Bucket>Name"`
+		}
+		if err := xml.Unmarshal(body, &resp); err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Join(resp.Buckets, ","); got != "bucket-a,bucket-b" {
+			t.Fatalf("got buckets %q", got)
+		}
+	})
+
+	t.Run("head", func(t *testing.T) {
+		resp := request(t, s, http.MethodHead, "/bucket-a", nil, nil)
+		expectStatus(t, resp, http.StatusOK)
+
+		owner, err := signer.EthereumAddress()
+		if err != nil {
+			t.Fatal(err)
+		}
+		topic, err := s3.FeedTopic("bucket-a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := resp.Header.Get(s3.FeedOwnerHeader); got != hex.EncodeToString(owner.Bytes()) {
+			t.Fatalf("got feed owner %q", got)
+		}
+		if got := resp.Header.Get(s3.FeedTopicHeader); got != hex.EncodeToString(topic) {
+			t.Fatalf("got feed topic %q", got)
+		}
+		if resp.Header.Get(s3.ManifestReferenceHeader) == "" {
+			t.Fatal("missing manifest reference")
+		}
+
+		expectStatus(t, request(t, s, http.MethodHead, "/bucket-c", nil, nil), http.StatusNotFound)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		expectStatus(t, request(t, s, http.MethodPut, "/bucket-a/key", strings.NewReader("data"), nil), http.StatusOK)
+		expectErrorCode(t, request(t, s, http.MethodDelete, "/bucket-a", nil, nil), http.StatusConflict, "BucketNotEmpty")
+
+		expectStatus(t, request(t, s, http.MethodDelete, "/bucket-b", nil, nil), http.StatusNoContent)
+		expectErrorCode(t, request(t, s, http.MethodGet, "/bucket-b", nil, nil), http.StatusNotFound, "NoSuchBucket")
+	})
+}
+
+func TestObjects(t *testing.T) {
+	s, _, _ := newTestService(t)
+	expectStatus(t, request(t, s, http.MethodPut, "/bucket", nil, nil), http.StatusOK)
+
+	data := "hello s3 gateway"
+	sum := md5.Sum([]byte(data))
+	etag := fmt.Sprintf("%q", hex.EncodeToString(sum[:]))
+
+	resp := request(t, s, http.MethodPut, "/bucket/dir/hello.txt", strings.NewReader(data), http.Header{
+		"Content-Type":    {"text/plain"},
+		"Content-Md5":     {base64.StdEncoding.EncodeToString(sum[:])},
+		"X-Amz-Meta-Name": {"value"},
+	})
+	expectStatus(t, resp, http.StatusOK)
+	if got := resp.Header.Get("ETag"); got != etag {
+		t.Fatalf("got etag %s, want %s", got, etag)
+	}
+
+	t.Run("bad digest", func(t *testing.T) {
+		resp := request(t, s, http.MethodPut, "/bucket/other", strings.NewReader("other"), http.Header{
+			"Content-Md5": {base64.StdEncoding.EncodeToString(sum[:])},
+		})
+		expectErrorCode(t, resp, http.StatusBadRequest, "BadDigest")
+	})
+
+	t.Run("get", func(t *testing.T) {
+		resp := request(t, s, http.MethodGet, "/bucket/dir/hello.txt", nil, nil)
+		if got := string(expectStatus(t, resp, http.StatusOK)); got != data {
+			t.Fatalf("got data %q, want %q", got, data)
+		}
+		for k, want := range map[string]string{
+			"Content-Type":    "text/plain",
+			"Etag":            etag,
+			"X-Amz-Meta-Name": "value",
+		} {
+			if got := resp.Header.Get(k); got != want {
+				t.Fatalf("got header %s %q, want %q", k, got, want)
+			}
+		}
+	})
+
+	t.Run("head", func(t *testing.T) {
+		resp := request(t, s, http.MethodHead, "/bucket/dir/hello.txt", nil, nil)
+		expectStatus(t, resp, http.StatusOK)
+		if got := resp.Header.Get("Content-Length"); got != fmt.Sprint(len(data)) {
+			t.Fatalf("got content length %s", got)
+		}
+
+		expectStatus(t, request(t, s, http.MethodHead, "/bucket/dir/missing", nil, nil), http.StatusNotFound)
+	})
+
+	t.Run("range", func(t *testing.T) {
+		resp := request(t, s, http.MethodGet, "/bucket/dir/hello.txt", nil, http.Header{"Range": {"bytes=6-7"}})
+		if got := string(expectStatus(t, resp, http.StatusPartialContent)); got != "s3" {
+			t.Fatalf("got data %q", got)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		expectStatus(t, request(t, s, http.MethodPut, "/bucket/deleted", strings.NewReader("data"), nil), http.StatusOK)
+		expectStatus(t, request(t, s, http.MethodDelete, "/bucket/deleted", nil, nil), http.StatusNoContent)
+		expectErrorCode(t, request(t, s, http.MethodGet, "/bucket/deleted", nil, nil), http.StatusNotFound, "NoSuchKey")
+		// deleting a missing object succeeds
+		expectStatus(t, request(t, s, http.MethodDelete, "/bucket/deleted", nil, nil), http.StatusNoContent)
+	})
+}
+
+type listResult struct {
+	Keys                  []string `xml:"Contents>Key"`
+	CommonPrefixes        []string `xml:"CommonPrefixes>Prefix"`
+	IsTruncated           bool     `xml:"IsTruncated"`
+	KeyCount              int      `xml:"KeyCount"`
+	NextContinuationToken string   `xml:"NextContinuationToken"`
+}
+
+func TestListObjectsV2(t *testing.T) {
+	s, _, _ := newTestService(t)
+	expectStatus(t, request(t, s, http.MethodPut, "/bucket", nil, nil), http.StatusOK)
+	for _, key := range []string{"a.txt", "b/1.txt", "b/2.txt", "b/c/3.txt", "d.txt"} {
+		expectStatus(t, request(t, s, http.MethodPut, "/bucket/"+key, strings.NewReader(key), nil), http.StatusOK)
+	}
+
+	list := func(t *testing.T, query string) listResult {
+		t.Helper()
+
+		body := expectStatus(t, request(t, s, http.MethodGet, "/bucket?list-type=2&"+query, nil, nil), http.StatusOK)
+		var r listResult
+		if err := xml.Unmarshal(body, &r); err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+
+	for _, tc := range []struct {
+		query    string
+		keys     string
+		prefixes string
+	}{
+		{"", "a.txt,b/1.txt,b/2.txt,b/c/3.txt,d.txt", ""},
+		{"delimiter=/", "a.txt,d.txt", "b/"},
+		{"prefix=b/&delimiter=/", "b/1.txt,b/2.txt", "b/c/"},
+		{"prefix=b/c", "b/c/3.txt", ""},
+		{"start-after=b/2.txt", "b/c/3.txt,d.txt", ""},
+	} {
+		r := list(t, tc.query)
+		if got := strings.Join(r.Keys, ","); got != tc.keys {
+			t.Errorf("%s: got keys %q, want %q", tc.query, got, tc.keys)
+		}
+		if got := strings.Join(r.CommonPrefixes, ","); got != tc.prefixes {
+			t.Errorf("%s: got common prefixes %q, want %q", tc.query, got, tc.prefixes)
+		}
+	}
+
+	t.Run("pagination", func(t *testing.T) {
+		var (
+			got   []string
+			token string
+		)
+		for i := 0; ; i++ {
+			r := list(t, "delimiter=/&max-keys=2&continuation-token="+token)
+			got = append(got, r.Keys...)
+			got = append(got, r.CommonPrefixes...)
+			if r.KeyCount > 2 {
+				t.Fatalf("got %d keys", r.KeyCount)
+			}
+			if !r.IsTruncated {
+				break
+			}
+			if i > 3 {
+				t.Fatal("listing does not end")
+			}
+			token = r.NextContinuationToken
+		}
+		if got := strings.Join(got, ","); got != "a.txt,b/,d.txt" {
+			t.Fatalf("got %q", got)
+		}
+	})
+}
+
+func TestMultipartUpload(t *testing.T) {
+	s, _, _ := newTestService(t)
+	expectStatus(t, request(t, s, http.MethodPut, "/bucket", nil, nil), http.StatusOK)
+
+	body := expectStatus(t, request(t, s, http.MethodPost, "/bucket/large?uploads", nil, http.Header{"Content-Type": {"text/plain"}}), http.StatusOK)
+	var initiate struct {
+		UploadID string `xml:"UploadId"`
+	}
+	if err := xml.Unmarshal(body, &initiate); err != nil {
+		t.Fatal(err)
+	}
+
+	parts := []string{strings.Repeat("a", 5000), strings.Repeat("b", 6000), "c"}
+	etags := make([]string, len(parts))
+	sums := md5.New()
+	for i, p := range parts {
+		url := fmt.Sprintf("/bucket/large?partNumber=%d&uploadId=%s", i+1, initiate.UploadID)
+		resp := request(t, s, http.MethodPut, url, strings.NewReader(p), nil)
+		expectStatus(t, resp, http.StatusOK)
+		etags[i] = resp.Header.Get("ETag")
+		sum := md5.Sum([]byte(p))
+		_, _ = sums.Write(sum[:])
+	}
+
+	complete := func(order ...int) *http.Response {
+		var b strings.Builder
+		b.WriteString("<CompleteMultipartUpload>")
+		for _, n := range order {
+			fmt.Fprintf(&b, "<Part><PartNumber>%d</PartNumber><ETag>%s</ETag></Part>", n, etags[n-1])
+		}
+		b.WriteString("</CompleteMultipartUpload>")
+		return request(t, s, http.MethodPost, "/bucket/large?uploadId="+initiate.UploadID, strings.NewReader(b.String()), nil)
+	}
+
+	expectErrorCode(t, complete(2, 1, 3), http.StatusBadRequest, "InvalidPartOrder")
+	expectStatus(t, complete(1, 2, 3), http.StatusOK)
+	expectErrorCode(t, complete(1, 2, 3), http.StatusNotFound, "NoSuchUpload")
+
+	resp := request(t, s, http.MethodGet, "/bucket/large", nil, nil)
+	if got := string(expectStatus(t, resp, http.StatusOK)); got != strings.Join(parts, "") {
+		t.Fatal("got different data")
+	}
+	if got, want := resp.Header.Get("ETag"), fmt.Sprintf(`"%x-3"`, sums.Sum(nil)); got != want {
+		t.Fatalf("got etag %s, want %s", got, want)
+	}
+	if got := resp.Header.Get("Content-Type"); got != "text/plain" {
+		t.Fatalf("got content type %q", got)
+	}
+
+	t.Run("abort", func(t *testing.T) {
+		body := expectStatus(t, request(t, s, http.MethodPost, "/bucket/aborted?uploads", nil, nil), http.StatusOK)
+		var initiate struct {
+			UploadID string `xml:"UploadId"`
+		}
+		if err := xml.Unmarshal(body, &initiate); err != nil {
+			t.Fatal(err)
+		}
+		expectStatus(t, request(t, s, http.MethodDelete, "/bucket/aborted?uploadId="+initiate.UploadID, nil, nil), http.StatusNoContent)
+		expectErrorCode(t, request(t, s, http.MethodPut, "/bucket/aborted?partNumber=1&uploadId="+initiate.UploadID, strings.NewReader("data"), nil), http.StatusNotFound, "NoSuchUpload")
+	})
+}
+
+func TestChunkedBody(t *testing.T) {
+	s, _, _ := newTestService(t)
+	expectStatus(t, request(t, s, http.MethodPut, "/bucket", nil, nil), http.StatusOK)
+
+	body := "5;chunk-signature=aa\r\nhello\r\n6;chunk-signature=bb\r\n world\r\n0;chunk-signature=cc\r\n\r\n"
+	header := http.Header{"X-Amz-Content-Sha256": {"STREAMING-AWS4-HMAC-SHA256-PAYLOAD"}}
+	expectStatus(t, request(t, s, http.MethodPut, "/bucket/key", strings.NewReader(body), header), http.StatusOK)
+
+	got := expectStatus(t, request(t, s, http.MethodGet, "/bucket/key", nil, nil), http.StatusOK)
+	if string(got) != "hello world" {
+		t.Fatalf("got data %q", got)
+	}
+
+	expectErrorCode(t, request(t, s, http.MethodPut, "/bucket/key", strings.NewReader("x\r\nhello"), header), http.StatusBadRequest, "IncompleteBody")
+}
+
+func TestFeed(t *testing.T) {
+	s, storer, signer := newTestService(t)
+	expectStatus(t, request(t, s, http.MethodPut, "/bucket", nil, nil), http.StatusOK)
+	expectStatus(t, request(t, s, http.MethodPut, "/bucket/key", strings.NewReader("data"), nil), http.StatusOK)
+	expectStatus(t, request(t, s, http.MethodDelete, "/bucket/key", nil, nil), http.StatusNoContent)
+
+	resp := request(t, s, http.MethodHead, "/bucket", nil, nil)
+	expectStatus(t, resp, http.StatusOK)
+	ref, err := swarm.ParseHexAddress(resp.Header.Get(s3.ManifestReferenceHeader))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	owner, err := signer.EthereumAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	topic, err := s3.FeedTopic("bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	finder := sequence.NewFinder(storer, feeds.New(topic, owner))
+	ch, cur, _, err := finder.At(context.Background(), time.Now().Unix(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(cur); got != "2" {
+		t.Fatalf("got feed index %s, want 2", got)
+	}
+	_, payload, err := feeds.FromChunk(ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(payload, ref.Bytes()) {
+		t.Fatalf("got feed payload %x, want %s", payload, ref)
+	}
+}
+
+func TestRecreatedBucketFeed(t *testing.T) {
+	s, storer, signer := newTestService(t)
+	expectStatus(t, request(t, s, http.MethodPut, "/bucket", nil, nil), http.StatusOK)
+	expectStatus(t, request(t, s, http.MethodPut, "/bucket/key", strings.NewReader("old"), nil), http.StatusOK)
+	expectStatus(t, request(t, s, http.MethodDelete, "/bucket/key", nil, nil), http.StatusNoContent)
+	expectStatus(t, request(t, s, http.MethodDelete, "/bucket", nil, nil), http.StatusNoContent)
+
+	expectStatus(t, request(t, s, http.MethodPut, "/bucket", nil, nil), http.StatusOK)
+	expectStatus(t, request(t, s, http.MethodPut, "/bucket/key", strings.NewReader("new"), nil), http.StatusOK)
+
+	resp := request(t, s, http.MethodHead, "/bucket", nil, nil)
+	expectStatus(t, resp, http.StatusOK)
+	ref, err := swarm.ParseHexAddress(resp.Header.Get(s3.ManifestReferenceHeader))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	owner, err := signer.EthereumAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	topic, err := s3.FeedTopic("bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	finder := sequence.NewFinder(storer, feeds.New(topic, owner))
+	ch, cur, _, err := finder.At(context.Background(), time.Now().Unix(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the feed of the recreated bucket continues after the updates of the
+	// deleted one
+	if got := fmt.Sprint(cur); got != "4" {
+		t.Fatalf("got feed index %s, want 4", got)
+	}
+	_, payload, err := feeds.FromChunk(ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(payload, ref.Bytes()) {
+		t.Fatalf("got feed payload %x, want %s", payload, ref)
+	}
+}
+
+// TestFeedUpdatedByDAV checks that the changes of a bucket are based on the
+// versions of the manifest published by the WebDAV gateway.
+func TestFeedUpdatedByDAV(t *testing.T) {
+	pk, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := crypto.NewDefaultSigner(pk)
+	storer := mock.NewStorer()
+	post := mockpost.New(mockpost.WithAcceptAll())
+	updates := feeds.NewUpdateLocks()
+	s := s3.New(storer, post, signer, statestore.NewStateStore(), updates, batchID, logging.New(io.Discard, 0))
+	d := dav.New(storer, post, signer, updates, batchID, logging.New(io.Discard, 0))
+
+	owner, err := signer.EthereumAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	topic, err := s3.FeedTopic("bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefix := "/feeds/" + hex.EncodeToString(owner.Bytes()) + "/" + hex.EncodeToString(topic)
+
+	expectStatus(t, request(t, s, http.MethodPut, "/bucket", nil, nil), http.StatusOK)
+	expectStatus(t, request(t, s, http.MethodPut, "/bucket/a", strings.NewReader("a"), nil), http.StatusOK)
+	expectStatus(t, request(t, d, http.MethodPut, prefix+"/b", strings.NewReader("b"), nil), http.StatusCreated)
+	expectStatus(t, request(t, s, http.MethodPut, "/bucket/c", strings.NewReader("c"), nil), http.StatusOK)
+
+	// the latest version has the files of both gateways
+	resp := request(t, s, http.MethodHead, "/bucket", nil, nil)
+	expectStatus(t, resp, http.StatusOK)
+	ref := resp.Header.Get(s3.ManifestReferenceHeader)
+	for _, key := range []string{"a", "b", "c"} {
+		if got := expectStatus(t, request(t, s, http.MethodGet, "/bucket/"+key, nil, nil), http.StatusOK); string(got) != key {
+			t.Fatalf("got object %s %q", key, got)
+		}
+		if got := expectStatus(t, request(t, d, http.MethodGet, "/bzz/"+ref+"/"+key, nil, nil), http.StatusOK); string(got) != key {
+			t.Fatalf("got file %s %q", key, got)
+		}
+	}
+}
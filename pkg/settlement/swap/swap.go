@@ -11,6 +11,8 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/pkg/bigint"
+	"github.com/ethersphere/bee/pkg/events"
 	"github.com/ethersphere/bee/pkg/logging"
 	"github.com/ethersphere/bee/pkg/postage/postagecontract"
 	"github.com/ethersphere/bee/pkg/settlement"
@@ -59,10 +61,11 @@ type Service struct {
 	addressbook    Addressbook
 	networkID      uint64
 	cashoutAddress common.Address
+	events         *events.Bus
 }
 
 // New creates a new swap Service.
-func New(proto swapprotocol.Interface, logger logging.Logger, store storage.StateStorer, chequebook chequebook.Service, chequeStore chequebook.ChequeStore, addressbook Addressbook, networkID uint64, cashout chequebook.CashoutService, accounting settlement.Accounting, cashoutAddress common.Address) *Service {
+func New(proto swapprotocol.Interface, logger logging.Logger, store storage.StateStorer, chequebook chequebook.Service, chequeStore chequebook.ChequeStore, addressbook Addressbook, networkID uint64, cashout chequebook.CashoutService, accounting settlement.Accounting, cashoutAddress common.Address, eventBus *events.Bus) *Service {
 	return &Service{
 		proto:          proto,
 		logger:         logger,
@@ -75,6 +78,7 @@ func New(proto swapprotocol.Interface, logger logging.Logger, store storage.Stat
 		cashout:        cashout,
 		accounting:     accounting,
 		cashoutAddress: cashoutAddress,
+		events:         eventBus,
 	}
 }
 
@@ -115,6 +119,7 @@ func (s *Service) ReceiveCheque(ctx context.Context, peer swarm.Address, cheque
 	tot, _ := big.NewFloat(0).SetInt(receivedAmount).Float64()
 	s.metrics.TotalReceived.Add(tot)
 	s.metrics.ChequesReceived.Inc()
+	s.events.Publish(events.TypeChequeReceived, events.ChequeData{Peer: peer, Amount: bigint.Wrap(amount)})
 
 	return s.accounting.NotifyPaymentReceived(peer, amount)
 }
@@ -152,6 +157,7 @@ func (s *Service) Pay(ctx context.Context, peer swarm.Address, amount *big.Int)
 	amountFloat, _ := big.NewFloat(0).SetInt(amount).Float64()
 	s.metrics.TotalSent.Add(amountFloat)
 	s.metrics.ChequesSent.Inc()
+	s.events.Publish(events.TypeChequeSent, events.ChequeData{Peer: peer, Amount: bigint.Wrap(amount)})
 }
 
 func (s *Service) SetAccounting(accounting settlement.Accounting) {
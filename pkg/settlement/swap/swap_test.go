@@ -225,6 +225,7 @@ func TestReceiveCheque(t *testing.T) {
 		&cashoutMock{},
 		observer,
 		common.Address{},
+		nil,
 	)
 
 	err := swap.ReceiveCheque(context.Background(), peer, cheque, exchangeRate, deduction)
@@ -299,6 +300,7 @@ func TestReceiveChequeReject(t *testing.T) {
 		&cashoutMock{},
 		observer,
 		common.Address{},
+		nil,
 	)
 
 	err := swap.ReceiveCheque(context.Background(), peer, cheque, exchangeRate, deduction)
@@ -355,6 +357,7 @@ func TestReceiveChequeWrongChequebook(t *testing.T) {
 		&cashoutMock{},
 		observer,
 		common.Address{},
+		nil,
 	)
 
 	err := swapService.ReceiveCheque(context.Background(), peer, cheque, exchangeRate, deduction)
@@ -419,6 +422,7 @@ func TestPay(t *testing.T) {
 		&cashoutMock{},
 		observer,
 		common.Address{},
+		nil,
 	)
 
 	swap.Pay(context.Background(), peer, amount)
@@ -462,6 +466,7 @@ func TestPayIssueError(t *testing.T) {
 		&cashoutMock{},
 		nil,
 		common.Address{},
+		nil,
 	)
 
 	observer := newTestObserver()
@@ -513,6 +518,7 @@ func TestPayUnknownBeneficiary(t *testing.T) {
 		&cashoutMock{},
 		observer,
 		common.Address{},
+		nil,
 	)
 
 	swapService.Pay(context.Background(), peer, amount)
@@ -567,6 +573,7 @@ func TestHandshake(t *testing.T) {
 		&cashoutMock{},
 		nil,
 		common.Address{},
+		nil,
 	)
 
 	err := swapService.Handshake(peer, beneficiary)
@@ -614,6 +621,7 @@ func TestHandshakeNewPeer(t *testing.T) {
 		&cashoutMock{},
 		nil,
 		common.Address{},
+		nil,
 	)
 
 	err := swapService.Handshake(peer, beneficiary)
@@ -653,6 +661,7 @@ func TestMigratePeer(t *testing.T) {
 		&cashoutMock{},
 		nil,
 		common.Address{},
+		nil,
 	)
 
 	err := swapService.Handshake(peer, beneficiary)
@@ -699,6 +708,7 @@ func TestCashout(t *testing.T) {
 		},
 		nil,
 		ourChequebookAddress,
+		nil,
 	)
 
 	returnedHash, err := swapService.CashCheque(context.Background(), peer)
@@ -746,6 +756,7 @@ func TestCashoutStatus(t *testing.T) {
 		},
 		nil,
 		common.Address{},
+		nil,
 	)
 
 	returnedStatus, err := swapService.CashoutStatus(context.Background(), peer)
@@ -15,7 +15,7 @@ To use the tracing package a Tracer instance must be created:
 
 	tracer, tracerCloser, err := tracing.NewTracer(&tracing.Options{
 		Enabled:     true,
-		Endpoint:    "127.0.0.1:6831",
+		Endpoint:    "127.0.0.1:4318",
 		ServiceName: "bee",
 	})
 	if err != nil {
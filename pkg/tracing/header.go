@@ -0,0 +1,88 @@
+// Copyright 2022 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tracing
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// The span context is propagated in p2p Headers in the binary format of the
+// Jaeger client, which was used before the tracing was based on
+// OpenTelemetry, so that the nodes of both versions understand each other.
+// The format is the trace id, the span id and the parent span id, followed
+// by the flags and the baggage items. The baggage is not propagated.
+const (
+	headerTraceIDSize = 16
+	headerSpanIDSize  = 8
+	// headerMinSize is the size of the header without baggage items.
+	headerMinSize = headerTraceIDSize + 2*headerSpanIDSize + 1 + 4
+
+	headerFlagSampled = 1
+)
+
+var errInvalidHeader = errors.New("invalid tracing header")
+
+// encodeHeader encodes the span context given as the value of a W3C
+// traceparent header in the binary p2p header format.
+func encodeHeader(traceParent string) ([]byte, error) {
+	// version-traceid-parentid-flags
+	parts := strings.Split(traceParent, "-")
+	if len(parts) != 4 {
+		return nil, errInvalidHeader
+	}
+	traceID, err := hex.DecodeString(parts[1])
+	if err != nil || len(traceID) != headerTraceIDSize {
+		return nil, errInvalidHeader
+	}
+	spanID, err := hex.DecodeString(parts[2])
+	if err != nil || len(spanID) != headerSpanIDSize {
+		return nil, errInvalidHeader
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil || len(flags) != 1 {
+		return nil, errInvalidHeader
+	}
+
+	b := make([]byte, headerMinSize)
+	copy(b, traceID)
+	copy(b[headerTraceIDSize:], spanID)
+	// the parent span id is not known and the baggage is empty
+	b[headerTraceIDSize+2*headerSpanIDSize] = flags[0] & headerFlagSampled
+	return b, nil
+}
+
+// decodeHeader decodes the span context in the binary p2p header format and
+// returns it as the value of a W3C traceparent header.
+func decodeHeader(b []byte) (string, error) {
+	if len(b) < headerMinSize {
+		return "", errInvalidHeader
+	}
+	traceID := b[:headerTraceIDSize]
+	spanID := b[headerTraceIDSize : headerTraceIDSize+headerSpanIDSize]
+	flags := b[headerTraceIDSize+2*headerSpanIDSize]
+
+	// the baggage items are validated, but not used
+	n := binary.BigEndian.Uint32(b[headerMinSize-4:])
+	rest := b[headerMinSize:]
+	for i := uint32(0); i < 2*n; i++ {
+		if len(rest) < 4 {
+			return "", errInvalidHeader
+		}
+		l := binary.BigEndian.Uint32(rest)
+		if uint64(len(rest)-4) < uint64(l) {
+			return "", errInvalidHeader
+		}
+		rest = rest[4+l:]
+	}
+	if len(rest) != 0 {
+		return "", errInvalidHeader
+	}
+
+	return fmt.Sprintf("00-%x-%x-%02x", traceID, spanID, flags&headerFlagSampled), nil
+}
@@ -5,20 +5,23 @@
 package tracing
 
 import (
-	"bufio"
-	"bytes"
 	"context"
 	"errors"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/ethersphere/bee/pkg/logging"
 	"github.com/ethersphere/bee/pkg/p2p"
 	"github.com/opentracing/opentracing-go"
 	"github.com/sirupsen/logrus"
-	"github.com/uber/jaeger-client-go"
-	"github.com/uber/jaeger-client-go/config"
+	otelbridge "go.opentelemetry.io/otel/bridge/opentracing"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
 )
 
 var (
@@ -28,31 +31,45 @@ var (
 
 	// noopTracer is the tracer that does nothing to handle a nil Tracer usage.
 	noopTracer = &Tracer{tracer: new(opentracing.NoopTracer)}
+
+	// propagator encodes span contexts in the W3C trace context format.
+	propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+	// traceIDInjector injects span contexts in order to read their trace
+	// ids, which are not exposed by the OpenTracing bridge.
+	traceIDInjector = newBridgeTracer()
 )
 
 // contextKey is used to reference a tracing context span as context value.
 type contextKey struct{}
 
+// tracerContextKey is used to reference the Tracer which started the span of
+// the tracing context as context value.
+type tracerContextKey struct{}
+
 // LogField is the key in log message field that holds tracing id value.
-const LogField = "traceID"
+const LogField = logging.TraceIDField
 
 const (
-	// TraceContextHeaderName is the http header name used to propagate tracing context.
-	TraceContextHeaderName = "swarm-trace-id"
+	// TraceParentHeaderName is the http header name used to propagate
+	// tracing context in the W3C trace context format.
+	TraceParentHeaderName = "traceparent"
 
-	// TraceBaggageHeaderPrefix is the prefix for http headers used to propagate baggage.
-	TraceBaggageHeaderPrefix = "swarmctx-"
+	// shutdownTimeout is the time to export the remaining spans on close.
+	shutdownTimeout = 5 * time.Second
 )
 
-// Tracer connect to a tracing server and handles tracing spans and contexts
-// by using opentracing Tracer.
+// Tracer exports tracing spans to an OpenTelemetry collector and handles
+// tracing contexts by using the OpenTracing API bridged to OpenTelemetry.
 type Tracer struct {
 	tracer opentracing.Tracer
 }
 
 // Options are optional parameters for Tracer constructor.
 type Options struct {
-	Enabled     bool
+	Enabled bool
+	// Endpoint is the host and port of the OTLP HTTP receiver of a
+	// collector.
 	Endpoint    string
 	ServiceName string
 }
@@ -63,32 +80,43 @@ func NewTracer(o *Options) (*Tracer, io.Closer, error) {
 	if o == nil {
 		o = new(Options)
 	}
-
-	cfg := config.Configuration{
-		Disabled:    !o.Enabled,
-		ServiceName: o.ServiceName,
-		Sampler: &config.SamplerConfig{
-			Type:  jaeger.SamplerTypeConst,
-			Param: 1,
-		},
-		Reporter: &config.ReporterConfig{
-			LogSpans:            true,
-			BufferFlushInterval: 1 * time.Second,
-			LocalAgentHostPort:  o.Endpoint,
-		},
-		Headers: &jaeger.HeadersConfig{
-			TraceContextHeaderName:   TraceContextHeaderName,
-			TraceBaggageHeaderPrefix: TraceBaggageHeaderPrefix,
-		},
+	if !o.Enabled {
+		return noopTracer, closerFunc(func() error { return nil }), nil
 	}
 
-	t, closer, err := cfg.NewTracer()
+	exporter, err := otlptracehttp.New(context.Background(),
+		otlptracehttp.WithEndpoint(o.Endpoint),
+		otlptracehttp.WithInsecure(),
+	)
 	if err != nil {
 		return nil, nil, err
 	}
-	return &Tracer{tracer: t}, closer, nil
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter, sdktrace.WithBatchTimeout(time.Second)),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(o.ServiceName))),
+	)
+
+	bridge, _ := otelbridge.NewTracerPair(provider.Tracer("github.com/ethersphere/bee"))
+	bridge.SetTextMapPropagator(propagator)
+
+	closer := closerFunc(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return provider.Shutdown(ctx)
+	})
+	return &Tracer{tracer: bridge}, closer, nil
 }
 
+func newBridgeTracer() *otelbridge.BridgeTracer {
+	t := otelbridge.NewBridgeTracer()
+	t.SetTextMapPropagator(propagator)
+	return t
+}
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error { return f() }
+
 // StartSpanFromContext starts a new tracing span that is either a root one or a
 // child of existing one from the provided Context. If logger is provided, a new
 // log Entry will be returned with "traceID" log field.
@@ -105,7 +133,27 @@ func (t *Tracer) StartSpanFromContext(ctx context.Context, operationName string,
 		span = t.tracer.StartSpan(operationName, opts...)
 	}
 	sc := span.Context()
-	return span, loggerWithTraceID(sc, l), WithContext(ctx, sc)
+	return span, loggerWithTraceID(sc, l), t.withContext(ctx, sc)
+}
+
+// IsRecording reports whether the span is recorded, so that the tags which
+// are expensive to build are set only if they are used.
+func IsRecording(span opentracing.Span) bool {
+	_, noop := span.Tracer().(opentracing.NoopTracer)
+	return !noop
+}
+
+// StartSpanFromParent starts a new tracing span that is a child of the span
+// of the provided Context, by using the Tracer which started it. A span which
+// does nothing is returned if there is no span in the Context, so that
+// operations are traced only as a part of a traced request.
+func StartSpanFromParent(ctx context.Context, operationName string, opts ...opentracing.StartSpanOption) (opentracing.Span, context.Context) {
+	t, ok := ctx.Value(tracerContextKey{}).(*Tracer)
+	if !ok || FromContext(ctx) == nil {
+		return noopTracer.tracer.StartSpan(operationName), ctx
+	}
+	span, _, ctx := t.StartSpanFromContext(ctx, operationName, nil, opts...)
+	return span, ctx
 }
 
 // AddContextHeader adds a tracing span context to provided p2p Headers from
@@ -121,16 +169,23 @@ func (t *Tracer) AddContextHeader(ctx context.Context, headers p2p.Headers) erro
 		return ErrContextNotFound
 	}
 
-	var b bytes.Buffer
-	w := bufio.NewWriter(&b)
-	if err := t.tracer.Inject(c, opentracing.Binary, w); err != nil {
+	h := make(http.Header)
+	if err := t.tracer.Inject(c, opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(h)); err != nil {
+		if errors.Is(err, opentracing.ErrInvalidSpanContext) {
+			return ErrContextNotFound
+		}
 		return err
 	}
-	if err := w.Flush(); err != nil {
-		return err
+	if h.Get(TraceParentHeaderName) == "" {
+		// the span context of a tracer which does nothing
+		return ErrContextNotFound
 	}
 
-	headers[p2p.HeaderNameTracingSpanContext] = b.Bytes()
+	b, err := encodeHeader(h.Get(TraceParentHeaderName))
+	if err != nil {
+		return err
+	}
+	headers[p2p.HeaderNameTracingSpanContext] = b
 
 	return nil
 }
@@ -146,7 +201,13 @@ func (t *Tracer) FromHeaders(headers p2p.Headers) (opentracing.SpanContext, erro
 	if v == nil {
 		return nil, ErrContextNotFound
 	}
-	c, err := t.tracer.Extract(opentracing.Binary, bytes.NewReader(v))
+	traceParent, err := decodeHeader(v)
+	if err != nil {
+		return nil, err
+	}
+	h := make(http.Header)
+	h.Set(TraceParentHeaderName, traceParent)
+	c, err := t.tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(h))
 	if err != nil {
 		if errors.Is(err, opentracing.ErrSpanContextNotFound) {
 			return nil, ErrContextNotFound
@@ -169,7 +230,7 @@ func (t *Tracer) WithContextFromHeaders(ctx context.Context, headers p2p.Headers
 	if err != nil {
 		return ctx, err
 	}
-	return WithContext(ctx, c), nil
+	return t.withContext(ctx, c), nil
 }
 
 // AddContextHTTPHeader adds a tracing span context to provided HTTP headers
@@ -186,7 +247,13 @@ func (t *Tracer) AddContextHTTPHeader(ctx context.Context, headers http.Header)
 	}
 
 	carrier := opentracing.HTTPHeadersCarrier(headers)
-	return t.tracer.Inject(c, opentracing.HTTPHeaders, carrier)
+	if err := t.tracer.Inject(c, opentracing.HTTPHeaders, carrier); err != nil {
+		if errors.Is(err, opentracing.ErrInvalidSpanContext) {
+			return ErrContextNotFound
+		}
+		return err
+	}
+	return nil
 }
 
 // FromHTTPHeaders returns tracing span context from HTTP headers. If the tracing
@@ -221,7 +288,7 @@ func (t *Tracer) WithContextFromHTTPHeaders(ctx context.Context, headers http.He
 		return ctx, err
 	}
 
-	return WithContext(ctx, c), nil
+	return t.withContext(ctx, c), nil
 }
 
 // WithContext adds tracing span context to go context.
@@ -229,6 +296,11 @@ func WithContext(ctx context.Context, c opentracing.SpanContext) context.Context
 	return context.WithValue(ctx, contextKey{}, c)
 }
 
+// withContext adds tracing span context and the Tracer to go context.
+func (t *Tracer) withContext(ctx context.Context, c opentracing.SpanContext) context.Context {
+	return context.WithValue(WithContext(ctx, c), tracerContextKey{}, t)
+}
+
 // FromContext return tracing span context from go context. If the tracing span
 // context is not present in go context, nil is returned.
 func FromContext(ctx context.Context) opentracing.SpanContext {
@@ -249,13 +321,27 @@ func loggerWithTraceID(sc opentracing.SpanContext, l logging.Logger) *logrus.Ent
 	if l == nil {
 		return nil
 	}
-	jsc, ok := sc.(jaeger.SpanContext)
-	if !ok {
+	traceID := TraceID(sc)
+	if traceID == "" {
 		return l.NewEntry()
 	}
-	traceID := jsc.TraceID()
-	if !traceID.IsValid() {
-		return l.NewEntry()
+	return l.WithField(LogField, traceID)
+}
+
+// TraceID returns the hex encoded trace id of the span context, or an empty
+// string if the span context is not recorded.
+func TraceID(sc opentracing.SpanContext) string {
+	if sc == nil {
+		return ""
+	}
+	h := make(http.Header)
+	if err := traceIDInjector.Inject(sc, opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(h)); err != nil {
+		return ""
+	}
+	// version-traceid-parentid-flags
+	parts := strings.Split(h.Get(TraceParentHeaderName), "-")
+	if len(parts) != 4 {
+		return ""
 	}
-	return l.WithField(LogField, traceID.String())
+	return parts[1]
 }
@@ -6,14 +6,20 @@ package tracing_test
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/ethersphere/bee/pkg/logging"
 	"github.com/ethersphere/bee/pkg/p2p"
 	"github.com/ethersphere/bee/pkg/tracing"
-	"github.com/uber/jaeger-client-go"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestSpanFromHeaders(t *testing.T) {
@@ -42,7 +48,7 @@ func TestSpanFromHeaders(t *testing.T) {
 		t.Fatal("got empty start span context")
 	}
 
-	if fmt.Sprint(gotSpanContext) != fmt.Sprint(wantSpanContext) {
+	if tracing.TraceID(gotSpanContext) != tracing.TraceID(wantSpanContext) {
 		t.Errorf("got span context %+v, want %+v", gotSpanContext, wantSpanContext)
 	}
 }
@@ -74,7 +80,7 @@ func TestSpanWithContextFromHeaders(t *testing.T) {
 		t.Fatal("got empty start span context")
 	}
 
-	if fmt.Sprint(gotSpanContext) != fmt.Sprint(wantSpanContext) {
+	if tracing.TraceID(gotSpanContext) != tracing.TraceID(wantSpanContext) {
 		t.Errorf("got span context %+v, want %+v", gotSpanContext, wantSpanContext)
 	}
 }
@@ -96,7 +102,7 @@ func TestFromContext(t *testing.T) {
 		t.Fatal("got empty span context")
 	}
 
-	if fmt.Sprint(gotSpanContext) != fmt.Sprint(wantSpanContext) {
+	if tracing.TraceID(gotSpanContext) != tracing.TraceID(wantSpanContext) {
 		t.Errorf("got span context %+v, want %+v", gotSpanContext, wantSpanContext)
 	}
 }
@@ -120,7 +126,7 @@ func TestWithContext(t *testing.T) {
 		t.Fatal("got empty span context")
 	}
 
-	if fmt.Sprint(gotSpanContext) != fmt.Sprint(wantSpanContext) {
+	if tracing.TraceID(gotSpanContext) != tracing.TraceID(wantSpanContext) {
 		t.Errorf("got span context %+v, want %+v", gotSpanContext, wantSpanContext)
 	}
 }
@@ -132,7 +138,10 @@ func TestStartSpanFromContext_logger(t *testing.T) {
 	span, logger, _ := tracer.StartSpanFromContext(context.Background(), "some-operation", logging.New(io.Discard, 0))
 	defer span.Finish()
 
-	wantTraceID := span.Context().(jaeger.SpanContext).TraceID()
+	wantTraceID := tracing.TraceID(span.Context())
+	if wantTraceID == "" {
+		t.Fatal("got empty trace id")
+	}
 
 	v, ok := logger.Data[tracing.LogField]
 	if !ok {
@@ -144,8 +153,8 @@ func TestStartSpanFromContext_logger(t *testing.T) {
 		t.Fatalf("log field %q is not string", tracing.LogField)
 	}
 
-	if gotTraceID != wantTraceID.String() {
-		t.Errorf("got trace id %q, want %q", gotTraceID, wantTraceID.String())
+	if gotTraceID != wantTraceID {
+		t.Errorf("got trace id %q, want %q", gotTraceID, wantTraceID)
 	}
 }
 
@@ -170,7 +179,10 @@ func TestNewLoggerWithTraceID(t *testing.T) {
 
 	logger := tracing.NewLoggerWithTraceID(ctx, logging.New(io.Discard, 0))
 
-	wantTraceID := span.Context().(jaeger.SpanContext).TraceID()
+	wantTraceID := tracing.TraceID(span.Context())
+	if wantTraceID == "" {
+		t.Fatal("got empty trace id")
+	}
 
 	v, ok := logger.Data[tracing.LogField]
 	if !ok {
@@ -182,8 +194,8 @@ func TestNewLoggerWithTraceID(t *testing.T) {
 		t.Fatalf("log field %q is not string", tracing.LogField)
 	}
 
-	if gotTraceID != wantTraceID.String() {
-		t.Errorf("got trace id %q, want %q", gotTraceID, wantTraceID.String())
+	if gotTraceID != wantTraceID {
+		t.Errorf("got trace id %q, want %q", gotTraceID, wantTraceID)
 	}
 }
 
@@ -201,16 +213,162 @@ func TestNewLoggerWithTraceID_nilLogger(t *testing.T) {
 	}
 }
 
+func TestExport(t *testing.T) {
+	tracer, closer, spans := newTracerWithCollector(t)
+
+	span, _, ctx := tracer.StartSpanFromContext(context.Background(), "parent-operation", nil)
+	child, _ := tracing.StartSpanFromParent(ctx, "child-operation")
+	child.Finish()
+	span.Finish()
+
+	if err := closer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]string)
+	for _, s := range spans() {
+		got[s.name] = s.traceID
+	}
+	traceID := tracing.TraceID(span.Context())
+	for _, name := range []string{"parent-operation", "child-operation"} {
+		if got[name] != traceID {
+			t.Errorf("span %q: got trace id %q, want %q", name, got[name], traceID)
+		}
+	}
+}
+
+func TestStartSpanFromParent_noParent(t *testing.T) {
+	span, ctx := tracing.StartSpanFromParent(context.Background(), "some-operation")
+	defer span.Finish()
+
+	if tracing.FromContext(ctx) != nil {
+		t.Error("span context without a parent span")
+	}
+}
+
+// TestSpanHeaderFormat checks that the span context is propagated in p2p
+// Headers in the binary format of the Jaeger client, which is understood by
+// the nodes which trace with it.
+func TestSpanHeaderFormat(t *testing.T) {
+	tracer, closer := newTracer(t)
+	defer closer.Close()
+
+	span, _, ctx := tracer.StartSpanFromContext(context.Background(), "some-operation", nil)
+	defer span.Finish()
+
+	headers := make(p2p.Headers)
+	if err := tracer.AddContextHeader(ctx, headers); err != nil {
+		t.Fatal(err)
+	}
+	v := headers[p2p.HeaderNameTracingSpanContext]
+	if len(v) != 37 {
+		t.Fatalf("got header of %d bytes, want 37", len(v))
+	}
+	if got, want := hex.EncodeToString(v[:16]), tracing.TraceID(span.Context()); got != want {
+		t.Fatalf("got trace id %s, want %s", got, want)
+	}
+
+	// trace id, span id, parent span id, sampled flag and a baggage item
+	v, _ = hex.DecodeString("0102030405060708090a0b0c0d0e0f10" + "1112131415161718" + "0000000000000000" + "01" +
+		"00000001" + "00000003" + hex.EncodeToString([]byte("key")) + "00000005" + hex.EncodeToString([]byte("value")))
+	sc, err := tracer.FromHeaders(p2p.Headers{p2p.HeaderNameTracingSpanContext: v})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tracing.TraceID(sc), "0102030405060708090a0b0c0d0e0f10"; got != want {
+		t.Fatalf("got trace id %s, want %s", got, want)
+	}
+
+	for _, v := range [][]byte{v[:36], append(v, 0), []byte("traceparent: 00-0102030405060708090a0b0c0d0e0f10-1112131415161718-01")} {
+		if _, err := tracer.FromHeaders(p2p.Headers{p2p.HeaderNameTracingSpanContext: v}); err == nil {
+			t.Fatalf("got no error for header %x", v)
+		}
+	}
+}
+
+func TestIsRecording(t *testing.T) {
+	tracer, closer := newTracer(t)
+	defer closer.Close()
+
+	span, _, ctx := tracer.StartSpanFromContext(context.Background(), "some-operation", nil)
+	defer span.Finish()
+	if !tracing.IsRecording(span) {
+		t.Fatal("span is not recording")
+	}
+
+	child, _ := tracing.StartSpanFromParent(ctx, "child-operation")
+	defer child.Finish()
+	if !tracing.IsRecording(child) {
+		t.Fatal("child span is not recording")
+	}
+
+	noParent, _ := tracing.StartSpanFromParent(context.Background(), "some-operation")
+	if tracing.IsRecording(noParent) {
+		t.Fatal("span without parent is recording")
+	}
+}
+
 func newTracer(t *testing.T) (*tracing.Tracer, io.Closer) {
 	t.Helper()
 
+	tracer, closer, _ := newTracerWithCollector(t)
+	return tracer, closer
+}
+
+type exportedSpan struct {
+	name    string
+	traceID string
+}
+
+// newTracerWithCollector returns a tracer which exports the spans to a
+// collector stub and a function returning the spans it received.
+func newTracerWithCollector(t *testing.T) (*tracing.Tracer, io.Closer, func() []exportedSpan) {
+	t.Helper()
+
+	var (
+		mu    sync.Mutex
+		spans []exportedSpan
+	)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" {
+			http.NotFound(w, r)
+			return
+		}
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var req collectortrace.ExportTraceServiceRequest
+		if err := proto.Unmarshal(b, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				for _, s := range ss.Spans {
+					spans = append(spans, exportedSpan{name: s.Name, traceID: hex.EncodeToString(s.TraceId)})
+				}
+			}
+		}
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/x-protobuf")
+	}))
+	t.Cleanup(collector.Close)
+
 	tracer, closer, err := tracing.NewTracer(&tracing.Options{
 		Enabled:     true,
+		Endpoint:    strings.TrimPrefix(collector.URL, "http://"),
 		ServiceName: "test",
 	})
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	return tracer, closer
+	return tracer, closer, func() []exportedSpan {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]exportedSpan(nil), spans...)
+	}
 }
@@ -39,9 +39,12 @@ var (
 	ErrGreylisted               = errors.New("overlay and transaction greylisted")
 	ErrBlockHashMismatch        = errors.New("block hash mismatch")
 	ErrOverlayMismatch          = errors.New("overlay mismatch")
+	ErrInvalidNonce             = errors.New("invalid overlay nonce")
 )
 
 type overlayVerification struct {
+	// NextBlockHash is the hash the overlay is derived from, which is the
+	// mined nonce for mined overlays.
 	NextBlockHash []byte
 	Verified      bool
 	TimeStamp     time.Time
@@ -68,7 +71,7 @@ func (m *Matcher) greylist(senderOverlay swarm.Address, incomingTx common.Hash,
 	return err
 }
 
-func (m *Matcher) Matches(ctx context.Context, tx []byte, networkID uint64, senderOverlay swarm.Address, ignoreGreylist bool) ([]byte, error) {
+func (m *Matcher) Matches(ctx context.Context, tx []byte, networkID uint64, senderOverlay swarm.Address, nonce []byte, ignoreGreylist bool) ([]byte, error) {
 	if !m.chainEnabled {
 		return make([]byte, swarm.HashSize), nil
 	}
@@ -88,6 +91,11 @@ func (m *Matcher) Matches(ctx context.Context, tx []byte, networkID uint64, send
 		return nil, ErrGreylisted
 	}
 
+	// a mined nonce replaces the block hash, so it has the same length
+	if len(nonce) != 0 && len(nonce) != swarm.HashSize {
+		return nil, m.greylist(senderOverlay, incomingTx, ErrInvalidNonce)
+	}
+
 	nTx, isPending, err := m.backend.TransactionByHash(ctx, incomingTx)
 	if err != nil {
 		return nil, m.greylist(senderOverlay, incomingTx, fmt.Errorf("%v: %w", err, ErrTransactionNotFound))
@@ -116,24 +124,29 @@ func (m *Matcher) Matches(ctx context.Context, tx []byte, networkID uint64, send
 		return nil, m.greylist(senderOverlay, incomingTx, err)
 	}
 
-	nextBlock, err := m.backend.HeaderByNumber(ctx, big.NewInt(0).Add(receipt.BlockNumber, big.NewInt(1)))
-	if err != nil {
-		return nil, m.greylist(senderOverlay, incomingTx, err)
-	}
+	// a mined overlay is derived from the nonce instead of the block hash
+	overlayHash := nonce
+	if len(overlayHash) == 0 {
+		nextBlock, err := m.backend.HeaderByNumber(ctx, big.NewInt(0).Add(receipt.BlockNumber, big.NewInt(1)))
+		if err != nil {
+			return nil, m.greylist(senderOverlay, incomingTx, err)
+		}
 
-	receiptBlockHash := receipt.BlockHash.Bytes()
-	nextBlockParentHash := nextBlock.ParentHash.Bytes()
-	nextBlockHash := nextBlock.Hash().Bytes()
+		receiptBlockHash := receipt.BlockHash.Bytes()
+		nextBlockParentHash := nextBlock.ParentHash.Bytes()
+		nextBlockHash := nextBlock.Hash().Bytes()
 
-	if !bytes.Equal(receiptBlockHash, nextBlockParentHash) {
-		return nil, m.greylist(
-			senderOverlay,
-			incomingTx,
-			fmt.Errorf("receipt hash %x does not match block's parent hash %x: %w", receiptBlockHash, nextBlockParentHash, ErrBlockHashMismatch),
-		)
+		if !bytes.Equal(receiptBlockHash, nextBlockParentHash) {
+			return nil, m.greylist(
+				senderOverlay,
+				incomingTx,
+				fmt.Errorf("receipt hash %x does not match block's parent hash %x: %w", receiptBlockHash, nextBlockParentHash, ErrBlockHashMismatch),
+			)
+		}
+		overlayHash = nextBlockHash
 	}
 
-	expectedRemoteBzzAddress := crypto.NewOverlayFromEthereumAddress(attestedOverlay.Bytes(), networkID, nextBlockHash)
+	expectedRemoteBzzAddress := crypto.NewOverlayFromEthereumAddress(attestedOverlay.Bytes(), networkID, overlayHash)
 
 	if !expectedRemoteBzzAddress.Equal(senderOverlay) {
 		return nil, m.greylist(senderOverlay, incomingTx, ErrOverlayMismatch)
@@ -142,12 +155,12 @@ func (m *Matcher) Matches(ctx context.Context, tx []byte, networkID uint64, send
 	err = m.storage.Put(peerOverlayKey(senderOverlay, incomingTx), &overlayVerification{
 		TimeStamp:     m.timeNow(),
 		Verified:      true,
-		NextBlockHash: nextBlockHash,
+		NextBlockHash: overlayHash,
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	return nextBlockHash, nil
+	return overlayHash, nil
 }
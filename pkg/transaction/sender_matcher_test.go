@@ -1,6 +1,7 @@
 package transaction_test
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"math/big"
@@ -40,7 +41,7 @@ func TestMatchesSender(t *testing.T) {
 
 		matcher := transaction.NewMatcher(backendmock.New(txByHash), nil, statestore.NewStateStore(), true)
 
-		_, err := matcher.Matches(context.Background(), trx, 0, swarm.NewAddress([]byte{}), false)
+		_, err := matcher.Matches(context.Background(), trx, 0, swarm.NewAddress([]byte{}), nil, false)
 		if !errors.Is(err, transaction.ErrTransactionNotFound) {
 			t.Fatalf("bad error type, want %v, got %v", transaction.ErrTransactionNotFound, err)
 		}
@@ -53,7 +54,7 @@ func TestMatchesSender(t *testing.T) {
 
 		matcher := transaction.NewMatcher(backendmock.New(txByHash), nil, statestore.NewStateStore(), true)
 
-		_, err := matcher.Matches(context.Background(), trx, 0, swarm.NewAddress([]byte{}), false)
+		_, err := matcher.Matches(context.Background(), trx, 0, swarm.NewAddress([]byte{}), nil, false)
 		if !errors.Is(err, transaction.ErrTransactionPending) {
 			t.Fatalf("bad error type, want %v, got %v", transaction.ErrTransactionPending, err)
 		}
@@ -69,7 +70,7 @@ func TestMatchesSender(t *testing.T) {
 		}
 		matcher := transaction.NewMatcher(backendmock.New(txByHash), signer, statestore.NewStateStore(), true)
 
-		_, err := matcher.Matches(context.Background(), trx, 0, swarm.NewAddress([]byte{}), false)
+		_, err := matcher.Matches(context.Background(), trx, 0, swarm.NewAddress([]byte{}), nil, false)
 		if !errors.Is(err, transaction.ErrTransactionSenderInvalid) {
 			t.Fatalf("bad error type, want %v, got %v", transaction.ErrTransactionSenderInvalid, err)
 		}
@@ -103,7 +104,7 @@ func TestMatchesSender(t *testing.T) {
 
 		matcher := transaction.NewMatcher(backendmock.New(txByHash, trxReceipt, headerByNum), signer, statestore.NewStateStore(), true)
 
-		_, err := matcher.Matches(context.Background(), trx, 0, swarm.NewAddress([]byte{}), false)
+		_, err := matcher.Matches(context.Background(), trx, 0, swarm.NewAddress([]byte{}), nil, false)
 		if err == nil {
 			t.Fatalf("expected no match")
 		}
@@ -139,12 +140,59 @@ func TestMatchesSender(t *testing.T) {
 
 		senderOverlay := crypto.NewOverlayFromEthereumAddress(signer.addr.Bytes(), 0, nextBlockHeader.Hash().Bytes())
 
-		_, err := matcher.Matches(context.Background(), trx, 0, senderOverlay, false)
+		_, err := matcher.Matches(context.Background(), trx, 0, senderOverlay, nil, false)
 		if err != nil {
 			t.Fatalf("expected match")
 		}
 	})
 
+	t.Run("sender matches mined nonce", func(t *testing.T) {
+		trxReceipt := backendmock.WithTransactionReceiptFunc(func(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+			return &types.Receipt{
+				BlockNumber: big.NewInt(0),
+				BlockHash:   common.HexToHash("0x2"),
+			}, nil
+		})
+
+		txByHash := backendmock.WithTransactionByHashFunc(func(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error) {
+			return signedTx, false, nil
+		})
+
+		signer := &mockSigner{
+			addr: common.HexToAddress("0xff"),
+		}
+
+		matcher := transaction.NewMatcher(backendmock.New(trxReceipt, txByHash), signer, statestore.NewStateStore(), true)
+
+		overlayNonce := common.HexToHash("0x3").Bytes()
+		senderOverlay := crypto.NewOverlayFromEthereumAddress(signer.addr.Bytes(), 0, overlayNonce)
+
+		hash, err := matcher.Matches(context.Background(), trx, 0, senderOverlay, overlayNonce, false)
+		if err != nil {
+			t.Fatalf("expected match. got %v", err)
+		}
+		if !bytes.Equal(hash, overlayNonce) {
+			t.Fatalf("got hash %x, want nonce %x", hash, overlayNonce)
+		}
+
+		shortNonce := overlayNonce[:16]
+		_, err = matcher.Matches(context.Background(), trx, 0, crypto.NewOverlayFromEthereumAddress(signer.addr.Bytes(), 0, shortNonce), shortNonce, false)
+		if !errors.Is(err, transaction.ErrInvalidNonce) {
+			t.Fatalf("got error %v, want %v", err, transaction.ErrInvalidNonce)
+		}
+		// the peer is greylisted
+		_, err = matcher.Matches(context.Background(), trx, 0, crypto.NewOverlayFromEthereumAddress(signer.addr.Bytes(), 0, shortNonce), overlayNonce, false)
+		if !errors.Is(err, transaction.ErrGreylisted) {
+			t.Fatalf("got error %v, want %v", err, transaction.ErrGreylisted)
+		}
+
+		otherNonce := common.HexToHash("0x4").Bytes()
+		_, err = matcher.Matches(context.Background(), trx, 0, crypto.NewOverlayFromEthereumAddress(signer.addr.Bytes(), 0, otherNonce), overlayNonce, false)
+		if !errors.Is(err, transaction.ErrOverlayMismatch) {
+			t.Fatalf("got error %v, want %v", err, transaction.ErrOverlayMismatch)
+		}
+	})
+
 	t.Run("sender matches data type", func(t *testing.T) {
 		trxBlock := common.HexToHash("0x2")
 		nextBlockHeader := &types.Header{
@@ -178,14 +226,14 @@ func TestMatchesSender(t *testing.T) {
 
 		senderOverlay := crypto.NewOverlayFromEthereumAddress(overlayEth.Bytes(), 0, nextBlockHeader.Hash().Bytes())
 
-		_, err := matcher.Matches(context.Background(), trx, 0, senderOverlay, false)
+		_, err := matcher.Matches(context.Background(), trx, 0, senderOverlay, nil, false)
 		if err != nil {
 			t.Fatalf("expected match. got %v", err)
 		}
 
 		senderOverlay = crypto.NewOverlayFromEthereumAddress(signer.addr.Bytes(), 0, nextBlockHeader.Hash().Bytes())
 
-		_, err = matcher.Matches(context.Background(), trx, 0, senderOverlay, false)
+		_, err = matcher.Matches(context.Background(), trx, 0, senderOverlay, nil, false)
 		if err == nil {
 			t.Fatalf("matched signer for data tx")
 		}
@@ -226,12 +274,12 @@ func TestMatchesSender(t *testing.T) {
 
 		senderOverlay := crypto.NewOverlayFromEthereumAddress(signer.addr.Bytes(), 0, nextBlockHeader.Hash().Bytes())
 
-		_, err := matcher.Matches(context.Background(), trx, 0, senderOverlay, false)
+		_, err := matcher.Matches(context.Background(), trx, 0, senderOverlay, nil, false)
 		if err != nil {
 			t.Fatalf("expected match")
 		}
 
-		_, err = matcher.Matches(context.Background(), trx, 0, senderOverlay, false)
+		_, err = matcher.Matches(context.Background(), trx, 0, senderOverlay, nil, false)
 		if err != nil {
 			t.Fatalf("expected match")
 		}
@@ -250,12 +298,12 @@ func TestMatchesSender(t *testing.T) {
 		m := matcher.(*transaction.Matcher)
 		m.SetTime(0)
 
-		_, err := matcher.Matches(context.Background(), trx, 0, swarm.NewAddress([]byte{}), false)
+		_, err := matcher.Matches(context.Background(), trx, 0, swarm.NewAddress([]byte{}), nil, false)
 		if !errors.Is(err, transaction.ErrTransactionNotFound) {
 			t.Fatalf("bad error type, want %v, got %v", transaction.ErrTransactionNotFound, err)
 		}
 
-		_, err = matcher.Matches(context.Background(), trx, 0, swarm.NewAddress([]byte{}), false)
+		_, err = matcher.Matches(context.Background(), trx, 0, swarm.NewAddress([]byte{}), nil, false)
 		if !errors.Is(err, transaction.ErrGreylisted) {
 			t.Fatalf("bad error type, want %v, got %v", transaction.ErrGreylisted, err)
 		}
@@ -264,7 +312,7 @@ func TestMatchesSender(t *testing.T) {
 		m2 := matcher.(*transaction.Matcher)
 		m2.SetTime(5 * 60)
 
-		_, err = matcher.Matches(context.Background(), trx, 0, swarm.NewAddress([]byte{}), false)
+		_, err = matcher.Matches(context.Background(), trx, 0, swarm.NewAddress([]byte{}), nil, false)
 		if !errors.Is(err, transaction.ErrTransactionNotFound) {
 			t.Fatalf("bad error type, want %v, got %v", transaction.ErrTransactionNotFound, err)
 		}
@@ -280,12 +328,12 @@ func TestMatchesSender(t *testing.T) {
 		m := matcher.(*transaction.Matcher)
 		m.SetTime(0)
 
-		_, err := matcher.Matches(context.Background(), trx, 0, swarm.NewAddress([]byte{}), false)
+		_, err := matcher.Matches(context.Background(), trx, 0, swarm.NewAddress([]byte{}), nil, false)
 		if !errors.Is(err, transaction.ErrTransactionNotFound) {
 			t.Fatalf("bad error type, want %v, got %v", transaction.ErrTransactionNotFound, err)
 		}
 
-		_, err = matcher.Matches(context.Background(), trx, 0, swarm.NewAddress([]byte{}), true)
+		_, err = matcher.Matches(context.Background(), trx, 0, swarm.NewAddress([]byte{}), nil, true)
 		if !errors.Is(err, transaction.ErrTransactionNotFound) {
 			t.Fatalf("bad error type, want %v, got %v", transaction.ErrTransactionNotFound, err)
 		}
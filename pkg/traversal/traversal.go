@@ -44,8 +44,16 @@ type service struct {
 
 // Traverse implements Traverser.Traverse method.
 func (s *service) Traverse(ctx context.Context, addr swarm.Address, iterFn swarm.AddressIterFunc) error {
+	// content uploaded with content defined chunking is marked in the
+	// metadata of its manifest entries
+	cdcRefs := make(map[string]bool)
+
 	processBytes := func(ref swarm.Address) error {
-		j, _, err := joiner.New(ctx, s.store, ref)
+		newJoiner := joiner.New
+		if cdcRefs[ref.ByteString()] {
+			newJoiner = joiner.NewCDC
+		}
+		j, _, err := newJoiner(ctx, s.store, ref)
 		if err != nil {
 			return fmt.Errorf("traversal: joiner error on %q: %w", ref, err)
 		}
@@ -63,7 +71,18 @@ func (s *service) Traverse(ctx context.Context, addr swarm.Address, iterFn swarm
 	case err != nil:
 		return fmt.Errorf("traversal: unable to create manifest reference for %q: %w", addr, err)
 	default:
-		err := mf.IterateAddresses(ctx, processBytes)
+		err := mantaray.NewNodeRef(addr.Bytes()).WalkNode(ctx, []byte{}, ls, func(_ []byte, node *mantaray.Node, err error) error {
+			if err != nil {
+				return err
+			}
+			if node.IsValueType() && node.Metadata()[manifest.EntryMetadataChunkingKey] == manifest.ChunkingCDC {
+				cdcRefs[string(node.Entry())] = true
+			}
+			return nil
+		})
+		if err == nil {
+			err = mf.IterateAddresses(ctx, processBytes)
+		}
 		if errors.Is(err, mantaray.ErrTooShort) || errors.Is(err, mantaray.ErrInvalidVersionHash) {
 			// Based on the returned errors we conclude that it might
 			// not be a manifest, so we try non-manifest processing.
@@ -17,6 +17,7 @@ import (
 	"github.com/ethersphere/bee/pkg/file/loadsave"
 	"github.com/ethersphere/bee/pkg/file/pipeline"
 	"github.com/ethersphere/bee/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/pkg/file/pipeline/cdc"
 	"github.com/ethersphere/bee/pkg/manifest"
 	"github.com/ethersphere/bee/pkg/storage"
 	"github.com/ethersphere/bee/pkg/storage/mock"
@@ -463,3 +464,93 @@ func pipelineFactory(s storage.Putter, mode storage.ModePut, encrypt bool) func(
 		return builder.NewPipelineBuilder(context.Background(), s, mode, encrypt)
 	}
 }
+
+// recordingStorer records the addresses of all stored chunks.
+type recordingStorer struct {
+	storage.Storer
+	addrs map[string]bool
+}
+
+func (s *recordingStorer) Put(ctx context.Context, mode storage.ModePut, chs ...swarm.Chunk) ([]bool, error) {
+	for _, ch := range chs {
+		s.addrs[ch.Address().String()] = true
+	}
+	return s.Storer.Put(ctx, mode, chs...)
+}
+
+// TestTraversalCDC checks that the content uploaded with content defined
+// chunking is traversed if its manifest entry is marked with the chunking
+// metadata, and only then.
+func TestTraversalCDC(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// traverse returns the addresses which the traversal of the manifest
+	// with the file with the data and the metadata iterates over, and the
+	// addresses of the stored chunks.
+	traverse := func(t *testing.T, newPipeline func(storage.Putter) pipeline.Interface, data []byte, mtdt map[string]string) (seen, stored map[string]bool) {
+		t.Helper()
+
+		iter := newAddressIterator(true)
+		storerMock := &recordingStorer{Storer: mock.NewStorer(), addrs: make(map[string]bool)}
+
+		fr, err := builder.FeedPipeline(ctx, newPipeline(storerMock), bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ls := loadsave.New(storerMock, pipelineFactory(storerMock, storage.ModePutUpload, false))
+		m, err := manifest.NewDefaultManifest(ls, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Add(ctx, "file", manifest.NewEntry(fr, mtdt)); err != nil {
+			t.Fatal(err)
+		}
+		address, err := m.Store(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := traversal.New(storerMock).Traverse(ctx, address, iter.Next); err != nil {
+			t.Fatal(err)
+		}
+		return iter.seen, storerMock.addrs
+	}
+
+	t.Run("cdc", func(t *testing.T) {
+		newPipeline := func(s storage.Putter) pipeline.Interface {
+			return builder.NewCDCPipelineBuilder(pipelineFactory(s, storage.ModePutUpload, false))
+		}
+		seen, stored := traverse(t, newPipeline, generateSample(1024*1024), map[string]string{
+			manifest.EntryMetadataChunkingKey: manifest.ChunkingCDC,
+		})
+
+		if have, want := len(seen), len(stored); have != want {
+			t.Fatalf("hash count mismatch: have %d; want %d", have, want)
+		}
+		for hash := range stored {
+			if !seen[hash] {
+				t.Fatalf("hash check: want %q; have none", hash)
+			}
+		}
+	})
+
+	t.Run("data like an index", func(t *testing.T) {
+		// the data is not parsed as an index without the chunking metadata
+		data, err := cdc.MarshalIndex(swarm.HashSize, []cdc.Segment{{
+			Span:      10,
+			Reference: swarm.MustParseHexAddress("0000000000000000000000000000000000000000000000000000000000000001"),
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		newPipeline := func(s storage.Putter) pipeline.Interface {
+			return builder.NewPipelineBuilder(ctx, s, storage.ModePutUpload, false)
+		}
+		seen, stored := traverse(t, newPipeline, data, nil)
+
+		if have, want := len(seen), len(stored); have != want {
+			t.Fatalf("hash count mismatch: have %d; want %d", have, want)
+		}
+	})
+}